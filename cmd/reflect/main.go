@@ -3,10 +3,13 @@ package main
 import (
 	"context"
 	"flag"
-	"log"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -14,90 +17,209 @@ import (
 	"github.com/bnprtr/reflect/internal/descriptor"
 	"github.com/bnprtr/reflect/internal/server"
 	"github.com/bnprtr/reflect/internal/server/theme"
+	"github.com/bnprtr/reflect/internal/version"
 	"github.com/bnprtr/reflect/internal/watcher"
 )
 
 func main() {
 	addr := flag.String("addr", ":8080", "listen address")
 	protoRoot := flag.String("proto-root", "", "root directory containing .proto files")
+	protoFile := flag.String("proto-file", "", "single .proto file to document; imports resolve via --proto-include and the file's directory")
 	themeName := flag.String("theme", "default", "theme name (default, minimal, high-contrast, ocean, forest, sunset, monochrome)")
-	themeFile := flag.String("theme-file", "", "path to custom theme file (JSON or YAML)")
+	var themeFiles []string
+	flag.Func("theme-file", "path to custom theme file (JSON or YAML); can be specified multiple times, later files override earlier ones", func(value string) error {
+		themeFiles = append(themeFiles, value)
+		return nil
+	})
 	configPath := flag.String("config", "", "path to reflect.yaml configuration file (optional)")
 	var protoIncludes []string
 	flag.Func("proto-include", "include path for proto imports (can be specified multiple times)", func(value string) error {
 		protoIncludes = append(protoIncludes, value)
 		return nil
 	})
+	var protoIgnores []string
+	flag.Func("proto-ignore", "glob for proto files or directories to skip when loading, matched against the path relative to --proto-root or the base name; supports ** (can be specified multiple times)", func(value string) error {
+		protoIgnores = append(protoIgnores, value)
+		return nil
+	})
+	var protoIncludeGlobs []string
+	flag.Func("proto-include-glob", "only load proto files whose path relative to --proto-root matches this glob; supports ** (can be specified multiple times)", func(value string) error {
+		protoIncludeGlobs = append(protoIncludeGlobs, value)
+		return nil
+	})
+	var protoExcludeGlobs []string
+	flag.Func("proto-exclude-glob", "skip proto files or directories whose path relative to --proto-root matches this glob; same syntax as --proto-ignore (can be specified multiple times)", func(value string) error {
+		protoExcludeGlobs = append(protoExcludeGlobs, value)
+		return nil
+	})
+	protoAllowDuplicates := flag.Bool("proto-allow-duplicates", false, "when two proto files define the same symbol, skip the earlier file instead of failing")
+	protoBestEffort := flag.Bool("proto-best-effort", false, "skip proto files that fail to parse, and the files importing them, instead of failing to load")
+	cacheDir := flag.String("cache-dir", "", "directory for caching parsed descriptors between startups; reused while the proto files are unchanged")
+	reflectTarget := flag.String("reflect-target", "", "load descriptors from a live server via gRPC reflection (e.g. localhost:9090)")
+	reflectPlaintext := flag.Bool("reflect-plaintext", false, "use plaintext (no TLS) when connecting to --reflect-target")
+	reflectInsecure := flag.Bool("reflect-insecure", false, "skip TLS certificate verification for --reflect-target")
+	reflectHeaders := make(map[string]string)
+	flag.Func("reflect-header", "header sent with reflection requests as \"Name: value\" (can be specified multiple times)", func(value string) error {
+		name, headerValue, ok := strings.Cut(value, ":")
+		if !ok {
+			return fmt.Errorf("expected \"Name: value\", got %q", value)
+		}
+		reflectHeaders[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(headerValue)
+		return nil
+	})
 	devMode := flag.Bool("dev", false, "enable development mode with hot reloading")
+	exportHTML := flag.String("export-html", "", "render the documentation to a single self-contained HTML file and exit")
+	logFormat := flag.String("log-format", "text", "log output format: text or json")
+	logLevel := flag.String("log-level", "info", "minimum log level: debug, info, warn, or error")
+	tlsCert := flag.String("tls-cert", "", "PEM certificate file for serving the UI over HTTPS (requires --tls-key)")
+	tlsKey := flag.String("tls-key", "", "PEM private key file for --tls-cert")
+	tlsClientCA := flag.String("tls-client-ca", "", "PEM CA bundle; when set, clients must present a certificate it signed (mutual TLS, requires --tls-cert)")
 	flag.Parse()
 
+	logger, err := newLogger(os.Stderr, *logFormat, *logLevel)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	slog.SetDefault(logger)
+
+	logger.Info("Reflect starting", "version", version.Get().String())
+
 	ctx := context.Background()
 
+	sources := 0
+	for _, source := range []string{*protoRoot, *protoFile, *reflectTarget} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources > 1 {
+		fatal("--proto-root, --proto-file, and --reflect-target are mutually exclusive")
+	}
+
+	if (*tlsCert == "") != (*tlsKey == "") {
+		fatal("--tls-cert and --tls-key must be set together")
+	}
+	if *tlsClientCA != "" && *tlsCert == "" {
+		fatal("--tls-client-ca requires --tls-cert and --tls-key")
+	}
+
 	// Load configuration if specified
 	var cfg *config.Config
 	if *configPath != "" {
-		var err error
 		cfg, err = config.Load(*configPath)
 		if err != nil {
-			log.Fatalf("Failed to load config from %q: %v", *configPath, err)
+			fatal("Failed to load config", "path", *configPath, "error", err)
+		}
+		logger.Info("Loaded configuration", "path", *configPath, "environments", len(cfg.Environments))
+		for _, warning := range cfg.Warnings() {
+			logger.Warn(warning)
 		}
-		log.Printf("Loaded configuration from %q with %d environment(s)", *configPath, len(cfg.Environments))
 	}
 
 	// Load protobuf descriptors if proto-root is specified
 	var reg *descriptor.Registry
+	loadOpts := descriptor.LoadOptions{
+		IncludePaths:    protoIncludes,
+		IgnorePatterns:  protoIgnores,
+		IncludeGlobs:    protoIncludeGlobs,
+		ExcludeGlobs:    protoExcludeGlobs,
+		AllowDuplicates: *protoAllowDuplicates,
+		BestEffort:      *protoBestEffort,
+		CacheDir:        *cacheDir,
+	}
 	if *protoRoot != "" {
-		var err error
-		reg, err = descriptor.LoadDirectory(ctx, *protoRoot, protoIncludes)
+		reg, err = descriptor.LoadDirectoryWithOptions(ctx, *protoRoot, loadOpts)
 		if err != nil {
-			log.Fatalf("Failed to load proto files from %q: %v", *protoRoot, err)
+			fatal("Failed to load proto files", "path", *protoRoot, "error", err)
 		}
-		log.Printf("Loaded proto files from %q", *protoRoot)
+		logger.Info("Loaded proto files", "path", *protoRoot, "cached", reg.Cached)
+		logLoadErrors(logger, reg)
+	}
+
+	// Load a single proto file if proto-file is specified
+	if *protoFile != "" {
+		reg, err = descriptor.LoadFile(ctx, *protoFile, protoIncludes)
+		if err != nil {
+			fatal("Failed to load proto file", "path", *protoFile, "error", err)
+		}
+		logger.Info("Loaded proto file", "path", *protoFile)
+	}
+
+	// Load protobuf descriptors from a live server if reflect-target is specified.
+	// Reflection carries no source info, so the docs will not include comments.
+	if *reflectTarget != "" {
+		reg, err = descriptor.LoadFromReflection(ctx, *reflectTarget, descriptor.ReflectionOptions{
+			Plaintext:          *reflectPlaintext,
+			InsecureSkipVerify: *reflectInsecure,
+			Headers:            reflectHeaders,
+		})
+		if err != nil {
+			fatal("Failed to load descriptors via reflection", "target", *reflectTarget, "error", err)
+		}
+		logger.Info("Loaded descriptors via reflection", "target", *reflectTarget, "services", len(reg.ServicesByName))
 	}
 
 	// Load theme
 	var selectedTheme *theme.Theme
-	var err error
 
-	if *themeFile != "" {
-		// Load theme from file
-		selectedTheme, err = theme.LoadThemeFromFile(*themeFile)
+	if len(themeFiles) > 0 {
+		// Load and merge theme files
+		selectedTheme, err = theme.LoadThemeFromFiles(themeFiles)
 		if err != nil {
-			log.Fatalf("Failed to load theme from file %q: %v", *themeFile, err)
+			fatal("Failed to load theme files", "files", themeFiles, "error", err)
 		}
-		log.Printf("Loaded theme %q from file: %s", selectedTheme.Name, *themeFile)
+		logger.Info("Loaded theme from files", "theme", selectedTheme.Name, "files", themeFiles)
 	} else {
 		// Load built-in theme
 		selectedTheme = theme.GetThemeByName(*themeName)
-		log.Printf("Using theme: %s", selectedTheme.Name)
+		logger.Info("Using theme", "theme", selectedTheme.Name)
 	}
 
 	srv, err := server.NewWithTheme(reg, selectedTheme, cfg)
 	if err != nil {
-		log.Fatal(err)
+		fatal("Failed to create server", "error", err)
+	}
+	srv.SetLogger(logger)
+	if *protoRoot != "" {
+		srv.SetLoadOptions(*protoRoot, loadOpts)
+	}
+
+	// Export documentation to a single HTML file instead of serving
+	if *exportHTML != "" {
+		if err := exportToFile(srv, *exportHTML); err != nil {
+			fatal("Failed to export HTML", "path", *exportHTML, "error", err)
+		}
+		logger.Info("Exported documentation", "path", *exportHTML)
+		return
 	}
 
 	// Setup hot reloading if in dev mode and proto-root is specified
 	if *devMode && *protoRoot != "" {
-		log.Println("Dev mode enabled - watching for proto file changes")
+		logger.Info("Dev mode enabled - watching for proto file changes", "path", *protoRoot)
 
 		// Create context for watcher
 		watcherCtx, cancelWatcher := context.WithCancel(ctx)
 		defer cancelWatcher()
 
 		// Create watcher with reload function
-		w, err := watcher.New(*protoRoot, func() {
-			// Reload proto files
-			newReg, err := descriptor.LoadDirectory(ctx, *protoRoot, protoIncludes)
+		w, err := watcher.New(*protoRoot, func() error {
+			// Reload proto files, keeping the previous registry on failure
+			newReg, err := descriptor.LoadDirectoryWithOptions(ctx, *protoRoot, loadOpts)
 			if err != nil {
-				log.Printf("Failed to reload proto files: %v", err)
-				return
+				err = fmt.Errorf("failed to reload proto files in %s: %w", *protoRoot, err)
+				srv.SetReloadError("proto", err)
+				return err
 			}
 			// Update server with new registry
 			srv.SetRegistry(newReg)
+			srv.SetReloadError("proto", nil)
+			logger.Info("Proto files reloaded successfully", "path", *protoRoot)
+			logLoadErrors(logger, newReg)
+			return nil
 		})
 		if err != nil {
-			log.Fatalf("Failed to create file watcher: %v", err)
+			fatal("Failed to create file watcher", "error", err)
 		}
 		defer w.Close()
 
@@ -105,11 +227,77 @@ func main() {
 		go w.Start(watcherCtx)
 	}
 
+	// Reload the proto file on change if in dev mode. Only the file itself is
+	// watched, not its imports.
+	if *devMode && *protoFile != "" {
+		logger.Info("Dev mode enabled - watching proto file for changes", "path", *protoFile)
+
+		protoWatcherCtx, cancelProtoWatcher := context.WithCancel(ctx)
+		defer cancelProtoWatcher()
+
+		w, err := watcher.NewFile(*protoFile, func() error {
+			newReg, err := descriptor.LoadFile(ctx, *protoFile, protoIncludes)
+			if err != nil {
+				err = fmt.Errorf("failed to reload proto file %s: %w", *protoFile, err)
+				srv.SetReloadError("proto", err)
+				return err
+			}
+			srv.SetRegistry(newReg)
+			srv.SetReloadError("proto", nil)
+			logger.Info("Proto file reloaded successfully", "path", *protoFile)
+			return nil
+		})
+		if err != nil {
+			fatal("Failed to create proto file watcher", "error", err)
+		}
+		defer w.Close()
+
+		go w.Start(protoWatcherCtx)
+	}
+
+	// Reload the theme files on change if in dev mode
+	if *devMode && len(themeFiles) > 0 {
+		logger.Info("Dev mode enabled - watching theme files for changes", "files", themeFiles)
+
+		themeWatcherCtx, cancelThemeWatcher := context.WithCancel(ctx)
+		defer cancelThemeWatcher()
+
+		reloadTheme := func() error {
+			newTheme, err := theme.LoadThemeFromFiles(themeFiles)
+			if err != nil {
+				// Keep serving the previous theme until the files are fixed
+				err = fmt.Errorf("failed to reload theme files: %w", err)
+				srv.SetReloadError("theme", err)
+				return err
+			}
+			srv.SetTheme(newTheme)
+			srv.SetReloadError("theme", nil)
+			logger.Info("Reloaded theme", "theme", newTheme.Name)
+			return nil
+		}
+
+		for _, themeFile := range themeFiles {
+			w, err := watcher.NewFile(themeFile, reloadTheme)
+			if err != nil {
+				fatal("Failed to create theme file watcher", "error", err)
+			}
+			defer w.Close()
+
+			go w.Start(themeWatcherCtx)
+		}
+	}
+
 	// Setup graceful shutdown
 	httpServer := &http.Server{
 		Addr:    *addr,
 		Handler: srv,
 	}
+	if *tlsCert != "" {
+		httpServer.TLSConfig, err = server.NewTLSConfig(*tlsCert, *tlsKey, *tlsClientCA)
+		if err != nil {
+			fatal("Failed to configure TLS", "error", err)
+		}
+	}
 
 	// Channel to listen for interrupt signals
 	stop := make(chan os.Signal, 1)
@@ -117,23 +305,76 @@ func main() {
 
 	// Start server in a goroutine
 	go func() {
-		log.Printf("listening on %s", *addr)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server error: %v", err)
+		var err error
+		if httpServer.TLSConfig != nil {
+			logger.Info("Listening", "addr", *addr, "tls", true, "clientAuth", *tlsClientCA != "")
+			// The certificate is already in TLSConfig
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			logger.Info("Listening", "addr", *addr)
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			fatal("Server error", "error", err)
 		}
 	}()
 
 	// Wait for interrupt signal
 	<-stop
-	log.Println("Shutting down server...")
+	logger.Info("Shutting down server...")
 
 	// Shutdown with timeout
 	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancelShutdown()
 
 	if err := httpServer.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("Server shutdown failed: %v", err)
+		fatal("Server shutdown failed", "error", err)
 	}
 
-	log.Println("Server stopped")
+	logger.Info("Server stopped")
+}
+
+// newLogger returns a logger writing to w in the given format ("text" or
+// "json"), dropping records below the given level.
+func newLogger(w io.Writer, format, level string) (*slog.Logger, error) {
+	var minLevel slog.Level
+	if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid --log-level %q: must be debug, info, warn, or error", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: minLevel}
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid --log-format %q: must be text or json", format)
+	}
+}
+
+// fatal logs msg and its attributes at error level, then exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// logLoadErrors warns about each proto file a best-effort load skipped.
+func logLoadErrors(logger *slog.Logger, reg *descriptor.Registry) {
+	for _, loadErr := range reg.LoadErrors {
+		logger.Warn("Skipped proto file that failed to parse", "file", loadErr.File, "error", loadErr.Err)
+	}
+}
+
+// exportToFile writes the single-page HTML export to path.
+func exportToFile(srv *server.Server, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := srv.ExportHTML(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
 }
@@ -4,9 +4,18 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
 
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/net/http/httpguts"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"gopkg.in/yaml.v3"
 )
 
@@ -15,10 +24,28 @@ type Config struct {
 	// Environments defines named upstream environments for "Try It" functionality.
 	Environments []Environment `yaml:"environments"`
 
+	// Include lists further config files, relative to the including file,
+	// whose environments are appended to this file's. Included files may
+	// include others, but may only set environments and include.
+	// Default: empty.
+	Include []string `yaml:"include"`
+
+	// StrictEnv makes Load fail if environment values reference variables
+	// that are unset (other than ${VAR:-default} forms), listing all of them
+	// in one error instead of silently expanding them to empty strings.
+	// Default: false.
+	StrictEnv bool `yaml:"strictEnv"`
+
 	// HeaderAllowlist specifies which HTTP headers can be sent to upstream services.
 	// This prevents accidentally leaking sensitive headers.
 	HeaderAllowlist []string `yaml:"headerAllowlist"`
 
+	// GlobalDefaultHeaders are included with requests to every environment,
+	// beneath each environment's DefaultHeaders (which win on conflict).
+	// Supports environment variable expansion.
+	// Example: "user-agent: reflect/${REFLECT_VERSION:-dev}"
+	GlobalDefaultHeaders map[string]string `yaml:"globalDefaultHeaders"`
+
 	// MaxRequestBodyBytes limits the size of request bodies for "Try It" invocations.
 	// Default: 1048576 (1 MB).
 	MaxRequestBodyBytes int64 `yaml:"maxRequestBodyBytes"`
@@ -26,6 +53,151 @@ type Config struct {
 	// RequestTimeoutSeconds sets the timeout for upstream RPC calls.
 	// Default: 15 seconds.
 	RequestTimeoutSeconds int `yaml:"requestTimeoutSeconds"`
+
+	// PrettyPrintMaxBytes is the size, in bytes of compact JSON, above which
+	// "Try It" response messages are shown compact instead of indented.
+	// Indenting very large responses is slow and memory-heavy.
+	// Default: 262144 (256 KB).
+	PrettyPrintMaxBytes int64 `yaml:"prettyPrintMaxBytes"`
+
+	// TemplatesDir is the directory where named "Try It" request templates
+	// are saved (method, body, and header names; never header values).
+	// Default: empty (templates are disabled).
+	TemplatesDir string `yaml:"templatesDir"`
+
+	// HideInternal excludes symbols marked internal-only (via a custom option or
+	// an "internal" package segment) from the index, search, and doc pages.
+	// Default: false.
+	HideInternal bool `yaml:"hideInternal"`
+
+	// SearchCommentSummaries indexes only the first line of each comment for
+	// search, bounding per-item memory for very large registries. Name search
+	// is unaffected.
+	// Default: false.
+	SearchCommentSummaries bool `yaml:"searchCommentSummaries"`
+
+	// PlainComments shows proto comments as plain text instead of rendering
+	// them as Markdown.
+	// Default: false.
+	PlainComments bool `yaml:"plainComments"`
+
+	// DefaultColorMode is the color mode for visitors who have not chosen
+	// one. Valid values: "light", "dark", "system" (follow the browser).
+	// Default: "system".
+	DefaultColorMode string `yaml:"defaultColorMode"`
+
+	// ProductionKeywords are words in an environment's name or base URL host that
+	// mark it as production. Used to warn about insecure production settings.
+	// Default: ["prod", "production"].
+	ProductionKeywords []string `yaml:"productionKeywords"`
+
+	// ServiceConfig is the path to a gRPC service config JSON file. Per-method
+	// timeouts and retry policies from it are shown on method pages.
+	// Default: empty (no service config).
+	ServiceConfig string `yaml:"serviceConfig"`
+
+	// AnyTypeHints maps google.protobuf.Any fields (by full field name, e.g.
+	// "acme.v1.Event.payload") to the full name of the message they usually
+	// hold. Examples for those fields use that message instead of a
+	// placeholder.
+	// Default: empty.
+	AnyTypeHints map[string]string `yaml:"anyTypeHints"`
+
+	// CommentOption is the full name of a string field option (e.g.
+	// "acme.v1.description") whose value documents a field that has no
+	// source comment. The option's extension must be part of the loaded
+	// protos.
+	// Default: empty (only source comments are shown).
+	CommentOption string `yaml:"commentOption"`
+
+	// MethodTagOption is the full name of a string method option (e.g.
+	// "acme.v1.tags") whose values tag methods; service pages group methods
+	// by tag. The option's extension must be part of the loaded protos.
+	// Default: empty (the built-in reflect.tags option).
+	MethodTagOption string `yaml:"methodTagOption"`
+
+	// ExampleOption is the full name of a string field option (e.g.
+	// "acme.v1.example") whose value is used for the field in generated
+	// examples. The option's extension must be part of the loaded protos.
+	// Default: empty (the built-in reflect.example option).
+	ExampleOption string `yaml:"exampleOption"`
+
+	// ServiceGroups sections the home page by team or domain. It maps a
+	// section name (e.g., "Billing") to globs (path.Match syntax) matched
+	// against full service names (e.g., "billing.*.InvoiceService").
+	// Services matching no group are listed under "Other".
+	// Default: empty (services are listed without sections).
+	ServiceGroups map[string][]string `yaml:"serviceGroups"`
+
+	// Metrics serves Prometheus metrics for Try It invocations and HTTP
+	// requests at /metrics.
+	// Default: false.
+	Metrics bool `yaml:"metrics"`
+
+	// PublicURL is the external URL the docs are served at, including any
+	// base path behind a proxy (e.g., "https://docs.example.com/api").
+	// Absolute links in /sitemap.xml are built from it.
+	// Default: empty (use the scheme and host of each request).
+	PublicURL string `yaml:"publicURL"`
+
+	// ReloadToken enables POST /api/reload, which reloads the proto files
+	// from disk, for requests sending it as "Authorization: Bearer <token>".
+	// Supports ${VAR} expansion.
+	// Default: empty (the endpoint is disabled).
+	ReloadToken string `yaml:"reloadToken"`
+
+	// CORS allows browsers on other origins to call the /api endpoints.
+	// Default: empty (same-origin only, no CORS headers are sent).
+	CORS CORSConfig `yaml:"cors"`
+
+	// UI contains settings for access to the docs, Try It, and the API.
+	UI UIConfig `yaml:"ui"`
+}
+
+// UIConfig contains settings for access to the server.
+type UIConfig struct {
+	// Auth requires credentials on every route except /healthz.
+	// Default: empty (no authentication).
+	Auth UIAuthConfig `yaml:"auth"`
+}
+
+// Auth types for UI.Auth.
+const (
+	UIAuthTypeBasic  = "basic"
+	UIAuthTypeBearer = "bearer"
+)
+
+// UIAuthConfig contains the credentials clients must send to use the server.
+// All string fields support environment variable expansion.
+type UIAuthConfig struct {
+	// Type is "basic" (HTTP basic auth) or "bearer" (a static token).
+	// Default: empty (no authentication).
+	Type string `yaml:"type"`
+
+	// Username and PasswordHash are the basic auth credentials. The password
+	// is stored as a bcrypt hash (e.g. from "htpasswd -nbB user password").
+	Username     string `yaml:"username"`
+	PasswordHash string `yaml:"passwordHash"`
+
+	// Token is the token clients send as "Bearer <token>" (type bearer).
+	// Example: "${REFLECT_UI_TOKEN}"
+	Token string `yaml:"token"`
+}
+
+// CORSConfig contains cross-origin settings for the /api endpoints.
+type CORSConfig struct {
+	// AllowedOrigins lists origins (e.g., "https://dashboard.example.com") that
+	// may call the API. "*" allows any origin.
+	AllowedOrigins []string `yaml:"allowedOrigins"`
+
+	// AllowedMethods lists the HTTP methods allowed for cross-origin requests.
+	// Default: ["GET", "POST"].
+	AllowedMethods []string `yaml:"allowedMethods"`
+
+	// AllowCredentials allows cookies and HTTP authentication on cross-origin
+	// requests. Cannot be combined with the "*" origin.
+	// Default: false.
+	AllowCredentials bool `yaml:"allowCredentials"`
 }
 
 // Environment represents a named upstream environment configuration.
@@ -34,7 +206,10 @@ type Environment struct {
 	Name string `yaml:"name"`
 
 	// BaseURL is the upstream service URL. All RPCs to this environment will be proxied
-	// to this base URL. This acts as an SSRF allowlist.
+	// to this base URL. This acts as an SSRF allowlist: requests must stay on
+	// its scheme, host, and port, and redirects elsewhere are not followed.
+	// A unix:///path/to/socket URL reaches a local server on a Unix domain
+	// socket (connect and grpc transports only).
 	BaseURL string `yaml:"baseURL"`
 
 	// Transport specifies the default RPC transport for this environment.
@@ -49,6 +224,64 @@ type Environment struct {
 	// request to this environment. Supports environment variable expansion.
 	// Example: "x-api-key: ${REFLECT_DEV_API_KEY}"
 	DefaultHeaders map[string]string `yaml:"defaultHeaders"`
+
+	// RequestTimeoutSeconds overrides the global request timeout for this environment.
+	// Default: 0 (use the global RequestTimeoutSeconds).
+	RequestTimeoutSeconds int `yaml:"requestTimeoutSeconds"`
+
+	// Proxy is an optional proxy URL used to reach this environment
+	// (e.g., "http://proxy.corp:3128" or "socks5://127.0.0.1:1080").
+	// Default: empty (respect HTTP_PROXY/HTTPS_PROXY environment variables).
+	Proxy string `yaml:"proxy"`
+
+	// AllowPrivateNetworks permits Try It requests to upstreams that resolve
+	// to loopback, private, or link-local addresses, such as localhost.
+	// Unix socket base URLs are always allowed.
+	// Default: false.
+	AllowPrivateNetworks bool `yaml:"allowPrivateNetworks"`
+
+	// Services restricts which methods can be invoked against this environment.
+	// Each entry is a glob (path.Match syntax) matched against the service name
+	// (e.g., "users.v1.*") or the full method name (e.g., "users.v1.UserService/Get*").
+	// Default: empty (all methods are allowed).
+	Services []string `yaml:"services"`
+
+	// Auth configures credentials sent as the Authorization header with every
+	// request to this environment, unless the request sets its own.
+	Auth AuthConfig `yaml:"auth"`
+
+	// UseProtoNames sends and displays JSON with proto field names (e.g.
+	// user_id) instead of lowerCamelCase, for servers that expect them.
+	// Default: false.
+	UseProtoNames bool `yaml:"useProtoNames"`
+}
+
+// Auth types for Environment.Auth.
+const (
+	AuthTypeBearer                  = "bearer"
+	AuthTypeOAuth2ClientCredentials = "oauth2_client_credentials"
+)
+
+// AuthConfig contains authentication settings for an environment. All string
+// fields support environment variable expansion.
+type AuthConfig struct {
+	// Type is "bearer" or "oauth2_client_credentials".
+	// Default: empty (no authentication).
+	Type string `yaml:"type"`
+
+	// Token is the static token sent as "Bearer <token>" (type bearer).
+	// Example: "${REFLECT_DEV_TOKEN}"
+	Token string `yaml:"token"`
+
+	// TokenURL is the OAuth2 token endpoint (type oauth2_client_credentials).
+	TokenURL string `yaml:"tokenURL"`
+
+	// ClientID and ClientSecret are the OAuth2 client credentials.
+	ClientID     string `yaml:"clientID"`
+	ClientSecret string `yaml:"clientSecret"`
+
+	// Scopes are the OAuth2 scopes to request.
+	Scopes []string `yaml:"scopes"`
 }
 
 // TLSConfig contains TLS-specific settings for an environment.
@@ -62,20 +295,35 @@ type TLSConfig struct {
 const (
 	DefaultMaxRequestBodyBytes    = 1048576 // 1 MB
 	DefaultRequestTimeoutSeconds  = 15
+	DefaultPrettyPrintMaxBytes    = 262144 // 256 KB
 	DefaultTransport              = "connect"
+	DefaultColorMode              = ColorModeSystem
 )
 
-// Load reads and parses a Reflect configuration file.
-// It performs validation and applies default values.
-func Load(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("read config file: %w", err)
-	}
+// Color modes for the documentation UI.
+const (
+	ColorModeLight  = "light"
+	ColorModeDark   = "dark"
+	ColorModeSystem = "system"
+)
+
+// ValidColorMode reports whether mode is a known color mode.
+func ValidColorMode(mode string) bool {
+	return mode == ColorModeLight || mode == ColorModeDark || mode == ColorModeSystem
+}
 
+// DefaultCORSMethods are used when CORS.AllowedMethods is not set.
+var DefaultCORSMethods = []string{"GET", "POST"}
+
+// DefaultProductionKeywords are used when ProductionKeywords is not set.
+var DefaultProductionKeywords = []string{"prod", "production"}
+
+// Load reads and parses a Reflect configuration file, along with any files
+// it includes. It performs validation and applies default values.
+func Load(path string) (*Config, error) {
 	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("parse config YAML: %w", err)
+	if err := loadFile(path, &cfg, nil); err != nil {
+		return nil, err
 	}
 
 	// Apply defaults
@@ -85,6 +333,15 @@ func Load(path string) (*Config, error) {
 	if cfg.RequestTimeoutSeconds == 0 {
 		cfg.RequestTimeoutSeconds = DefaultRequestTimeoutSeconds
 	}
+	if cfg.PrettyPrintMaxBytes == 0 {
+		cfg.PrettyPrintMaxBytes = DefaultPrettyPrintMaxBytes
+	}
+	if len(cfg.ProductionKeywords) == 0 {
+		cfg.ProductionKeywords = DefaultProductionKeywords
+	}
+	if len(cfg.CORS.AllowedOrigins) > 0 && len(cfg.CORS.AllowedMethods) == 0 {
+		cfg.CORS.AllowedMethods = DefaultCORSMethods
+	}
 
 	// Expand environment variables in all config values
 	if err := cfg.expandEnvVars(); err != nil {
@@ -99,22 +356,189 @@ func Load(path string) (*Config, error) {
 	return &cfg, nil
 }
 
-// expandEnvVars expands environment variables in all string fields of the config.
+// includeKeys are the top-level keys an included file may set.
+var includeKeys = map[string]bool{"environments": true, "include": true}
+
+// loadFile parses the config file at path into cfg, then appends the
+// environments of each included file in order. stack holds the files
+// currently being loaded, to detect include cycles; it is nil for the main
+// file, which is the only one allowed to set other settings.
+func loadFile(path string, cfg *Config, stack []string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolve config path %q: %w", path, err)
+	}
+	for i, including := range stack {
+		if including == absPath {
+			cycle := append(slices.Clone(stack[i:]), absPath)
+			return fmt.Errorf("include cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	stack = append(stack, absPath)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("parse config YAML %q: %w", path, err)
+	}
+
+	if len(stack) > 1 {
+		var keys map[string]any
+		if err := yaml.Unmarshal(data, &keys); err != nil {
+			return fmt.Errorf("parse config YAML %q: %w", path, err)
+		}
+		for key := range keys {
+			if !includeKeys[key] {
+				return fmt.Errorf("included config %q: %q must be set in the main config file", path, key)
+			}
+		}
+	}
+
+	for _, include := range cfg.Include {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+
+		var included Config
+		if err := loadFile(include, &included, stack); err != nil {
+			return err
+		}
+		for _, env := range included.Environments {
+			i := slices.IndexFunc(cfg.Environments, func(e Environment) bool { return e.Name == env.Name })
+			if i < 0 {
+				cfg.Environments = append(cfg.Environments, env)
+				continue
+			}
+			// The same environment reached twice, e.g. through two includes
+			// of one fragment, is harmless; conflicting definitions are not
+			if !reflect.DeepEqual(cfg.Environments[i], env) {
+				return fmt.Errorf("environment %q in %q conflicts with an earlier definition", env.Name, include)
+			}
+		}
+	}
+	return nil
+}
+
+// expandEnvVars expands environment variables in all string fields of the
+// config, using the syntax described by expandEnv. With StrictEnv set, any
+// unset variables are reported together in one error.
 func (c *Config) expandEnvVars() error {
+	var unset []string
+	for key, value := range c.GlobalDefaultHeaders {
+		unset = append(unset, unsetEnvVars(value)...)
+		expanded, err := expandEnv(value)
+		if err != nil {
+			return fmt.Errorf("globalDefaultHeaders: header %q: %w", key, err)
+		}
+		c.GlobalDefaultHeaders[key] = expanded
+	}
+
+	unset = append(unset, unsetEnvVars(c.ReloadToken)...)
+	reloadToken, err := expandEnv(c.ReloadToken)
+	if err != nil {
+		return fmt.Errorf("reloadToken: %w", err)
+	}
+	c.ReloadToken = reloadToken
+
+	for _, field := range []*string{&c.UI.Auth.Username, &c.UI.Auth.PasswordHash, &c.UI.Auth.Token} {
+		unset = append(unset, unsetEnvVars(*field)...)
+		expanded, err := expandEnv(*field)
+		if err != nil {
+			return fmt.Errorf("ui.auth: %w", err)
+		}
+		*field = expanded
+	}
+
 	for i := range c.Environments {
 		env := &c.Environments[i]
 
-		// Expand base URL
-		env.BaseURL = os.Expand(env.BaseURL, os.Getenv)
+		fields := []*string{
+			&env.BaseURL,
+			&env.Auth.Token,
+			&env.Auth.TokenURL,
+			&env.Auth.ClientID,
+			&env.Auth.ClientSecret,
+		}
+		for _, field := range fields {
+			unset = append(unset, unsetEnvVars(*field)...)
+			expanded, err := expandEnv(*field)
+			if err != nil {
+				return fmt.Errorf("environment %q: %w", env.Name, err)
+			}
+			*field = expanded
+		}
 
 		// Expand default headers
 		for key, value := range env.DefaultHeaders {
-			env.DefaultHeaders[key] = os.Expand(value, os.Getenv)
+			unset = append(unset, unsetEnvVars(value)...)
+			expanded, err := expandEnv(value)
+			if err != nil {
+				return fmt.Errorf("environment %q: header %q: %w", env.Name, key, err)
+			}
+			env.DefaultHeaders[key] = expanded
 		}
 	}
+
+	if c.StrictEnv && len(unset) > 0 {
+		sort.Strings(unset)
+		return fmt.Errorf("strictEnv: undefined environment variables: %s", strings.Join(slices.Compact(unset), ", "))
+	}
 	return nil
 }
 
+// unsetEnvVars returns the variables referenced in s that are unset, ignoring
+// ${VAR:-default} and ${VAR:?message} forms, which handle that case themselves.
+func unsetEnvVars(s string) []string {
+	var unset []string
+	os.Expand(s, func(name string) string {
+		if name == "$" || strings.Contains(name, ":-") || strings.Contains(name, ":?") {
+			return ""
+		}
+		if _, ok := os.LookupEnv(name); !ok {
+			unset = append(unset, name)
+		}
+		return ""
+	})
+	return unset
+}
+
+// expandEnv expands $VAR and ${VAR} references in s, like a shell:
+// ${VAR:-default} uses default when VAR is unset or empty, ${VAR:?message}
+// fails with message when VAR is unset or empty, and $$ is a literal $.
+func expandEnv(s string) (string, error) {
+	var err error
+	expanded := os.Expand(s, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+
+		key, op, arg := name, "", ""
+		if i := strings.Index(name, ":"); i >= 0 && i+1 < len(name) && (name[i+1] == '-' || name[i+1] == '?') {
+			key, op, arg = name[:i], name[i:i+2], name[i+2:]
+		}
+
+		value := os.Getenv(key)
+		if value != "" {
+			return value
+		}
+		switch op {
+		case ":-":
+			return arg
+		case ":?":
+			if err == nil {
+				if arg == "" {
+					arg = "is not set"
+				}
+				err = fmt.Errorf("${%s}: %s", key, arg)
+			}
+		}
+		return ""
+	})
+	return expanded, err
+}
+
 // Validate checks that the configuration is valid.
 func (c *Config) Validate() error {
 	// Check for duplicate environment names
@@ -139,10 +563,118 @@ func (c *Config) Validate() error {
 	if c.RequestTimeoutSeconds < 0 {
 		return fmt.Errorf("requestTimeoutSeconds must be non-negative, got %d", c.RequestTimeoutSeconds)
 	}
+	if c.PrettyPrintMaxBytes < 0 {
+		return fmt.Errorf("prettyPrintMaxBytes must be non-negative, got %d", c.PrettyPrintMaxBytes)
+	}
+
+	if c.PublicURL != "" {
+		parsedURL, err := url.Parse(c.PublicURL)
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" || parsedURL.RawQuery != "" || parsedURL.Fragment != "" {
+			return fmt.Errorf("invalid publicURL %q, must be an http or https URL without a query or fragment", c.PublicURL)
+		}
+	}
+
+	if err := c.CORS.Validate(); err != nil {
+		return fmt.Errorf("cors: %w", err)
+	}
+	if err := c.UI.Auth.Validate(); err != nil {
+		return fmt.Errorf("ui.auth: %w", err)
+	}
+
+	if c.DefaultColorMode == "" {
+		c.DefaultColorMode = DefaultColorMode
+	} else if !ValidColorMode(c.DefaultColorMode) {
+		return fmt.Errorf("invalid defaultColorMode %q, must be one of: light, dark, system", c.DefaultColorMode)
+	}
+
+	if c.CommentOption != "" && !protoreflect.FullName(c.CommentOption).IsValid() {
+		return fmt.Errorf("invalid commentOption %q, must be a fully-qualified extension name such as \"acme.v1.description\"", c.CommentOption)
+	}
+	if c.MethodTagOption != "" && !protoreflect.FullName(c.MethodTagOption).IsValid() {
+		return fmt.Errorf("invalid methodTagOption %q, must be a fully-qualified extension name such as \"acme.v1.tags\"", c.MethodTagOption)
+	}
+	if c.ExampleOption != "" && !protoreflect.FullName(c.ExampleOption).IsValid() {
+		return fmt.Errorf("invalid exampleOption %q, must be a fully-qualified extension name such as \"acme.v1.example\"", c.ExampleOption)
+	}
+
+	for key := range c.GlobalDefaultHeaders {
+		if !httpguts.ValidHeaderFieldName(key) {
+			return fmt.Errorf("globalDefaultHeaders: invalid header name %q", key)
+		}
+	}
+
+	for field, message := range c.AnyTypeHints {
+		if field == "" || message == "" {
+			return fmt.Errorf("anyTypeHints: field and message names must not be empty (got %q: %q)", field, message)
+		}
+	}
+
+	for group, patterns := range c.ServiceGroups {
+		if strings.TrimSpace(group) == "" {
+			return fmt.Errorf("serviceGroups: group names must not be empty")
+		}
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("serviceGroups: invalid pattern %q in group %q: %w", pattern, group, err)
+			}
+		}
+	}
 
 	return nil
 }
 
+// Validate checks that a CORS configuration is valid.
+func (c *CORSConfig) Validate() error {
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			if c.AllowCredentials {
+				return fmt.Errorf("allowCredentials cannot be used with the \"*\" origin")
+			}
+			continue
+		}
+		parsedURL, err := url.Parse(origin)
+		if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" || strings.TrimPrefix(parsedURL.Path, "/") != "" {
+			return fmt.Errorf("invalid allowed origin %q, must be \"*\" or scheme://host[:port]", origin)
+		}
+	}
+	return nil
+}
+
+// Validate checks that a UI auth configuration is complete for its type.
+func (a *UIAuthConfig) Validate() error {
+	switch a.Type {
+	case "":
+		return nil
+	case UIAuthTypeBasic:
+		if a.Username == "" || a.PasswordHash == "" {
+			return fmt.Errorf("username and passwordHash are required for type %q", a.Type)
+		}
+		if _, err := bcrypt.Cost([]byte(a.PasswordHash)); err != nil {
+			return fmt.Errorf("passwordHash must be a bcrypt hash: %w", err)
+		}
+	case UIAuthTypeBearer:
+		if a.Token == "" {
+			return fmt.Errorf("token is required for type %q", a.Type)
+		}
+	default:
+		return fmt.Errorf("invalid type %q, must be one of: %s, %s", a.Type, UIAuthTypeBasic, UIAuthTypeBearer)
+	}
+	return nil
+}
+
+// AllowsOrigin reports whether a request Origin header value is allowed.
+func (c *CORSConfig) AllowsOrigin(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate checks that an environment configuration is valid.
 func (e *Environment) Validate() error {
 	if e.Name == "" {
@@ -161,14 +693,60 @@ func (e *Environment) Validate() error {
 
 	// Ensure base URL has a scheme
 	if parsedURL.Scheme == "" {
-		return fmt.Errorf("baseURL must include a scheme (http:// or https://)")
+		return fmt.Errorf("baseURL must include a scheme (http://, https://, or unix://)")
 	}
 
-	// Ensure base URL has a host
-	if parsedURL.Host == "" {
+	if parsedURL.Scheme == "unix" {
+		// Unix socket URLs carry a socket path instead of a host
+		if parsedURL.Host != "" || !strings.HasPrefix(parsedURL.Path, "/") {
+			return fmt.Errorf("unix baseURL must be unix:///absolute/path/to/socket")
+		}
+		if e.Transport == "grpc-web" {
+			return fmt.Errorf("unix baseURL is not supported by the grpc-web transport")
+		}
+		if e.Proxy != "" {
+			return fmt.Errorf("proxy cannot be used with a unix baseURL")
+		}
+	} else if parsedURL.Host == "" {
+		// Ensure base URL has a host
 		return fmt.Errorf("baseURL must include a host")
 	}
 
+	if e.RequestTimeoutSeconds < 0 {
+		return fmt.Errorf("requestTimeoutSeconds must be non-negative, got %d", e.RequestTimeoutSeconds)
+	}
+
+	// Validate proxy URL if specified
+	if e.Proxy != "" {
+		proxyURL, err := url.Parse(e.Proxy)
+		if err != nil {
+			return fmt.Errorf("invalid proxy: %w", err)
+		}
+		validProxySchemes := map[string]bool{
+			"http":    true,
+			"https":   true,
+			"socks5":  true,
+			"socks5h": true,
+		}
+		if !validProxySchemes[proxyURL.Scheme] {
+			return fmt.Errorf("invalid proxy scheme %q, must be one of: http, https, socks5, socks5h", proxyURL.Scheme)
+		}
+		if proxyURL.Host == "" {
+			return fmt.Errorf("proxy must include a host")
+		}
+	}
+
+	// Validate service patterns
+	for _, pattern := range e.Services {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid services pattern %q: %w", pattern, err)
+		}
+	}
+
+	if err := e.Auth.Validate(); err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+
 	// Validate transport if specified
 	if e.Transport != "" {
 		validTransports := map[string]bool{
@@ -187,6 +765,76 @@ func (e *Environment) Validate() error {
 	return nil
 }
 
+// Validate checks that an auth configuration is complete for its type.
+func (a *AuthConfig) Validate() error {
+	switch a.Type {
+	case "":
+		return nil
+	case AuthTypeBearer:
+		if a.Token == "" {
+			return fmt.Errorf("token is required for type %q", a.Type)
+		}
+	case AuthTypeOAuth2ClientCredentials:
+		if a.TokenURL == "" {
+			return fmt.Errorf("tokenURL is required for type %q", a.Type)
+		}
+		parsedURL, err := url.Parse(a.TokenURL)
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+			return fmt.Errorf("invalid tokenURL %q, must be an http:// or https:// URL", a.TokenURL)
+		}
+		if a.ClientID == "" || a.ClientSecret == "" {
+			return fmt.Errorf("clientID and clientSecret are required for type %q", a.Type)
+		}
+	default:
+		return fmt.Errorf("invalid type %q, must be one of: %s, %s", a.Type, AuthTypeBearer, AuthTypeOAuth2ClientCredentials)
+	}
+	return nil
+}
+
+// Warnings returns non-fatal problems with the configuration, such as
+// insecureSkipVerify being enabled for an environment that looks like production.
+func (c *Config) Warnings() []string {
+	keywords := c.ProductionKeywords
+	if len(keywords) == 0 {
+		keywords = DefaultProductionKeywords
+	}
+
+	var warnings []string
+	for i := range c.Environments {
+		env := &c.Environments[i]
+		if env.TLS.InsecureSkipVerify && env.looksLikeProduction(keywords) {
+			warnings = append(warnings, fmt.Sprintf("environment %q: tls.insecureSkipVerify is enabled for what looks like a production environment", env.Name))
+		}
+	}
+	return warnings
+}
+
+// looksLikeProduction reports whether any word in the environment's name or
+// base URL host matches one of the keywords (case-insensitive). Whole words are
+// compared so that e.g. "product-api" does not match "prod".
+func (e *Environment) looksLikeProduction(keywords []string) bool {
+	words := splitWords(e.Name)
+	if parsedURL, err := url.Parse(e.BaseURL); err == nil {
+		words = append(words, splitWords(parsedURL.Hostname())...)
+	}
+
+	for _, word := range words {
+		for _, keyword := range keywords {
+			if strings.EqualFold(word, keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitWords splits s on any character that is not a letter or digit.
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
 // GetEnvironment retrieves an environment by name.
 func (c *Config) GetEnvironment(name string) (*Environment, error) {
 	for i := range c.Environments {
@@ -218,3 +866,31 @@ func (c *Config) IsHeaderAllowed(header string) bool {
 func (c *Config) GetTimeout() time.Duration {
 	return time.Duration(c.RequestTimeoutSeconds) * time.Second
 }
+
+// AllowsMethod reports whether a method (format "pkg.Service/Method") can be
+// invoked against this environment. Returns true if no Services are configured.
+func (e *Environment) AllowsMethod(methodFullName string) bool {
+	if len(e.Services) == 0 {
+		return true
+	}
+
+	serviceName, _, _ := strings.Cut(methodFullName, "/")
+	for _, pattern := range e.Services {
+		if ok, _ := path.Match(pattern, serviceName); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, methodFullName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTimeout returns the environment's request timeout as a time.Duration,
+// falling back to defaultSeconds when no override is set.
+func (e *Environment) GetTimeout(defaultSeconds int) time.Duration {
+	if e.RequestTimeoutSeconds > 0 {
+		return time.Duration(e.RequestTimeoutSeconds) * time.Second
+	}
+	return time.Duration(defaultSeconds) * time.Second
+}
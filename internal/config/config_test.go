@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -30,6 +31,7 @@ environments:
   - name: prod
     baseURL: https://api.example.com
     transport: grpc
+    requestTimeoutSeconds: 5
 headerAllowlist:
   - authorization
   - x-api-key
@@ -53,6 +55,12 @@ requestTimeoutSeconds: 30
 				if cfg.RequestTimeoutSeconds != 30 {
 					t.Errorf("expected requestTimeoutSeconds 30, got %d", cfg.RequestTimeoutSeconds)
 				}
+				if got := cfg.Environments[0].GetTimeout(cfg.RequestTimeoutSeconds); got != 30*time.Second {
+					t.Errorf("expected dev to use global timeout 30s, got %v", got)
+				}
+				if got := cfg.Environments[1].GetTimeout(cfg.RequestTimeoutSeconds); got != 5*time.Second {
+					t.Errorf("expected prod timeout override 5s, got %v", got)
+				}
 				if len(cfg.HeaderAllowlist) != 2 {
 					t.Errorf("expected 2 allowed headers, got %d", len(cfg.HeaderAllowlist))
 				}
@@ -73,9 +81,15 @@ environments:
 				if cfg.RequestTimeoutSeconds != DefaultRequestTimeoutSeconds {
 					t.Errorf("expected default requestTimeoutSeconds %d, got %d", DefaultRequestTimeoutSeconds, cfg.RequestTimeoutSeconds)
 				}
+				if cfg.PrettyPrintMaxBytes != DefaultPrettyPrintMaxBytes {
+					t.Errorf("expected default prettyPrintMaxBytes %d, got %d", DefaultPrettyPrintMaxBytes, cfg.PrettyPrintMaxBytes)
+				}
 				if cfg.Environments[0].Transport != DefaultTransport {
 					t.Errorf("expected default transport %q, got %q", DefaultTransport, cfg.Environments[0].Transport)
 				}
+				if cfg.DefaultColorMode != DefaultColorMode {
+					t.Errorf("expected default defaultColorMode %q, got %q", DefaultColorMode, cfg.DefaultColorMode)
+				}
 			},
 		},
 		{
@@ -105,6 +119,70 @@ environments:
 				}
 			},
 		},
+		{
+			name: "auth environment variable expansion",
+			yamlConfig: `
+environments:
+  - name: dev
+    baseURL: https://dev.example.com
+    auth:
+      type: oauth2_client_credentials
+      tokenURL: https://auth.example.com/token
+      clientID: reflect
+      clientSecret: ${TEST_CLIENT_SECRET}
+      scopes: [read, write]
+`,
+			envVars: map[string]string{
+				"TEST_CLIENT_SECRET": "s3cret",
+			},
+			wantErr: false,
+			validate: func(t *testing.T, cfg *Config) {
+				auth := cfg.Environments[0].Auth
+				if auth.Type != AuthTypeOAuth2ClientCredentials {
+					t.Errorf("expected auth type %q, got %q", AuthTypeOAuth2ClientCredentials, auth.Type)
+				}
+				if auth.ClientSecret != "s3cret" {
+					t.Errorf("expected expanded client secret, got %q", auth.ClientSecret)
+				}
+				if len(auth.Scopes) != 2 || auth.Scopes[1] != "write" {
+					t.Errorf("expected scopes [read write], got %v", auth.Scopes)
+				}
+			},
+		},
+		{
+			name: "global default headers expansion",
+			yamlConfig: `
+globalDefaultHeaders:
+  user-agent: reflect/${TEST_GLOBAL_VERSION}
+  x-trace-sampled: "1"
+environments:
+  - name: dev
+    baseURL: https://dev.example.com
+`,
+			envVars: map[string]string{
+				"TEST_GLOBAL_VERSION": "1.2.3",
+			},
+			wantErr: false,
+			validate: func(t *testing.T, cfg *Config) {
+				if got := cfg.GlobalDefaultHeaders["user-agent"]; got != "reflect/1.2.3" {
+					t.Errorf("expected expanded global header, got %q", got)
+				}
+				if got := cfg.GlobalDefaultHeaders["x-trace-sampled"]; got != "1" {
+					t.Errorf("expected static global header, got %q", got)
+				}
+			},
+		},
+		{
+			name: "invalid global default header name",
+			yamlConfig: `
+globalDefaultHeaders:
+  "x bad header": value
+environments:
+  - name: dev
+    baseURL: https://dev.example.com
+`,
+			wantErr: true,
+		},
 		{
 			name: "duplicate environment names",
 			yamlConfig: `
@@ -177,6 +255,30 @@ environments:
   - name: dev
     baseURL: https://dev.api.example.com
 requestTimeoutSeconds: -1
+`,
+			wantErr: true,
+		},
+		{
+			name: "reload token from environment",
+			yamlConfig: `
+environments:
+  - name: dev
+    baseURL: https://dev.api.example.com
+reloadToken: ${TEST_RELOAD_TOKEN}
+`,
+			envVars: map[string]string{"TEST_RELOAD_TOKEN": "s3cret"},
+			validate: func(t *testing.T, cfg *Config) {
+				if cfg.ReloadToken != "s3cret" {
+					t.Errorf("ReloadToken = %q, want %q", cfg.ReloadToken, "s3cret")
+				}
+			},
+		},
+		{
+			name: "required environment variable unset",
+			yamlConfig: `
+environments:
+  - name: dev
+    baseURL: https://${TEST_UNSET_HOST:?set TEST_UNSET_HOST to the dev API host}
 `,
 			wantErr: true,
 		},
@@ -231,6 +333,203 @@ func TestLoadInvalidYAML(t *testing.T) {
 	}
 }
 
+func TestLoadInclude(t *testing.T) {
+	writeFiles := func(t *testing.T, files map[string]string) string {
+		t.Helper()
+		dir := t.TempDir()
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatalf("failed to create directory: %v", err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("failed to write test config: %v", err)
+			}
+		}
+		return dir
+	}
+
+	t.Run("base with two fragments", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"reflect.yaml": `
+include:
+  - envs/staging.yaml
+  - envs/prod.yaml
+environments:
+  - name: dev
+    baseURL: https://dev.example.com
+requestTimeoutSeconds: 20
+`,
+			"envs/staging.yaml": `
+environments:
+  - name: staging
+    baseURL: https://staging.example.com
+`,
+			// Relative to the including fragment, not the base file
+			"envs/prod.yaml": `
+include: [shared.yaml]
+environments:
+  - name: prod
+    baseURL: https://api.example.com
+    transport: grpc
+`,
+			"envs/shared.yaml": `
+environments:
+  - name: shared
+    baseURL: https://shared.example.com
+`,
+		})
+
+		cfg, err := Load(filepath.Join(dir, "reflect.yaml"))
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		var names []string
+		for _, env := range cfg.Environments {
+			names = append(names, env.Name)
+		}
+		if got, want := strings.Join(names, ","), "dev,staging,prod,shared"; got != want {
+			t.Errorf("expected environments %s, got %s", want, got)
+		}
+		if env, err := cfg.GetEnvironment("prod"); err != nil || env.Transport != "grpc" {
+			t.Errorf("expected prod environment from fragment, got %+v", env)
+		}
+		if env, err := cfg.GetEnvironment("staging"); err != nil || env.Transport != DefaultTransport {
+			t.Errorf("expected defaults applied to included environments, got %+v", env)
+		}
+		if cfg.RequestTimeoutSeconds != 20 {
+			t.Errorf("expected main file settings to be kept, got requestTimeoutSeconds %d", cfg.RequestTimeoutSeconds)
+		}
+	})
+
+	t.Run("same fragment included twice", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"reflect.yaml": "include: [a.yaml, b.yaml]\n",
+			"a.yaml":       "include: [shared.yaml]\n",
+			"b.yaml":       "include: [shared.yaml]\n",
+			"shared.yaml": `
+environments:
+  - name: shared
+    baseURL: https://shared.example.com
+`,
+		})
+
+		cfg, err := Load(filepath.Join(dir, "reflect.yaml"))
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if len(cfg.Environments) != 1 {
+			t.Errorf("expected the shared environment once, got %d environments", len(cfg.Environments))
+		}
+	})
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "conflicting environment",
+			files: map[string]string{
+				"reflect.yaml": `
+include: [other.yaml]
+environments:
+  - name: dev
+    baseURL: https://dev.example.com
+`,
+				"other.yaml": `
+environments:
+  - name: dev
+    baseURL: https://other.example.com
+`,
+			},
+			wantErr: `environment "dev"`,
+		},
+		{
+			name: "include cycle",
+			files: map[string]string{
+				"reflect.yaml": "include: [a.yaml]\n",
+				"a.yaml":       "include: [b.yaml]\n",
+				"b.yaml":       "include: [a.yaml]\n",
+			},
+			wantErr: "include cycle",
+		},
+		{
+			name: "fragment sets other settings",
+			files: map[string]string{
+				"reflect.yaml": "include: [a.yaml]\n",
+				"a.yaml":       "requestTimeoutSeconds: 5\n",
+			},
+			wantErr: `"requestTimeoutSeconds" must be set in the main config file`,
+		},
+		{
+			name: "missing include",
+			files: map[string]string{
+				"reflect.yaml": "include: [missing.yaml]\n",
+			},
+			wantErr: "read config file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, tt.files)
+			_, err := Load(filepath.Join(dir, "reflect.yaml"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadStrictEnv(t *testing.T) {
+	t.Setenv("TEST_STRICT_SET", "set")
+
+	yamlConfig := `
+strictEnv: %v
+environments:
+  - name: dev
+    baseURL: https://dev.example.com/${TEST_STRICT_MISSING_PREFIX}
+    defaultHeaders:
+      x-api-key: ${TEST_STRICT_MISSING_KEY}
+      x-set: ${TEST_STRICT_SET}
+      x-default: ${TEST_STRICT_MISSING_OPTIONAL:-fallback}
+  - name: staging
+    baseURL: https://staging.example.com/${TEST_STRICT_MISSING_PREFIX}
+`
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "reflect.yaml")
+
+	// Without strictEnv, unset variables silently expand to empty strings
+	if err := os.WriteFile(configPath, []byte(fmt.Sprintf(yamlConfig, false)), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	if _, err := Load(configPath); err != nil {
+		t.Fatalf("expected lenient load to succeed, got %v", err)
+	}
+
+	if err := os.WriteFile(configPath, []byte(fmt.Sprintf(yamlConfig, true)), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	_, err := Load(configPath)
+	if err == nil {
+		t.Fatal("expected error for undefined variables with strictEnv, got nil")
+	}
+	for _, name := range []string{"TEST_STRICT_MISSING_PREFIX", "TEST_STRICT_MISSING_KEY"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("expected error to mention %s, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"TEST_STRICT_SET", "TEST_STRICT_MISSING_OPTIONAL"} {
+		if strings.Contains(err.Error(), name) {
+			t.Errorf("expected error not to mention %s, got %v", name, err)
+		}
+	}
+	if strings.Count(err.Error(), "TEST_STRICT_MISSING_PREFIX") != 1 {
+		t.Errorf("expected each variable to be listed once, got %v", err)
+	}
+}
+
 func TestGetEnvironment(t *testing.T) {
 	cfg := &Config{
 		Environments: []Environment{
@@ -330,6 +629,97 @@ func TestGetTimeout(t *testing.T) {
 	}
 }
 
+func TestEnvironmentGetTimeout(t *testing.T) {
+	tests := []struct {
+		name           string
+		envSeconds     int
+		defaultSeconds int
+		expected       time.Duration
+	}{
+		{
+			name:           "environment override takes precedence",
+			envSeconds:     5,
+			defaultSeconds: 30,
+			expected:       5 * time.Second,
+		},
+		{
+			name:           "falls back to default when unset",
+			envSeconds:     0,
+			defaultSeconds: 30,
+			expected:       30 * time.Second,
+		},
+		{
+			name:           "override may exceed default",
+			envSeconds:     60,
+			defaultSeconds: 15,
+			expected:       60 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &Environment{RequestTimeoutSeconds: tt.envSeconds}
+			if got := env.GetTimeout(tt.defaultSeconds); got != tt.expected {
+				t.Errorf("GetTimeout(%d) = %v, want %v", tt.defaultSeconds, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEnvironmentAllowsMethod(t *testing.T) {
+	tests := []struct {
+		name     string
+		services []string
+		method   string
+		want     bool
+	}{
+		{
+			name:   "no restriction allows all methods",
+			method: "users.v1.UserService/GetUser",
+			want:   true,
+		},
+		{
+			name:     "package glob allows service",
+			services: []string{"users.v1.*"},
+			method:   "users.v1.UserService/GetUser",
+			want:     true,
+		},
+		{
+			name:     "package glob rejects other package",
+			services: []string{"users.v1.*"},
+			method:   "orders.v1.OrderService/GetOrder",
+			want:     false,
+		},
+		{
+			name:     "exact service name",
+			services: []string{"orders.v1.OrderService"},
+			method:   "orders.v1.OrderService/GetOrder",
+			want:     true,
+		},
+		{
+			name:     "method glob allows matching method",
+			services: []string{"orders.v1.OrderService/Get*"},
+			method:   "orders.v1.OrderService/GetOrder",
+			want:     true,
+		},
+		{
+			name:     "method glob rejects other method",
+			services: []string{"orders.v1.OrderService/Get*"},
+			method:   "orders.v1.OrderService/DeleteOrder",
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &Environment{Name: "test", Services: tt.services}
+			if got := env.AllowsMethod(tt.method); got != tt.want {
+				t.Errorf("AllowsMethod(%q) = %v, want %v", tt.method, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestEnvironmentValidate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -418,6 +808,182 @@ func TestEnvironmentValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid request timeout override",
+			env: Environment{
+				Name:                  "prod",
+				BaseURL:               "https://api.example.com",
+				RequestTimeoutSeconds: 5,
+			},
+			wantErr: false,
+		},
+		{
+			name: "negative request timeout",
+			env: Environment{
+				Name:                  "prod",
+				BaseURL:               "https://api.example.com",
+				RequestTimeoutSeconds: -1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid http proxy",
+			env: Environment{
+				Name:    "corp",
+				BaseURL: "https://api.example.com",
+				Proxy:   "http://proxy.corp.example.com:3128",
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid socks5 proxy",
+			env: Environment{
+				Name:    "corp",
+				BaseURL: "https://api.example.com",
+				Proxy:   "socks5://127.0.0.1:1080",
+			},
+			wantErr: false,
+		},
+		{
+			name: "unsupported proxy scheme",
+			env: Environment{
+				Name:    "corp",
+				BaseURL: "https://api.example.com",
+				Proxy:   "ftp://proxy.example.com",
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid services patterns",
+			env: Environment{
+				Name:     "users",
+				BaseURL:  "https://api.example.com",
+				Services: []string{"users.v1.*", "orders.v1.OrderService/Get*"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "malformed services pattern",
+			env: Environment{
+				Name:     "users",
+				BaseURL:  "https://api.example.com",
+				Services: []string{"users.v1.["},
+			},
+			wantErr: true,
+		},
+		{
+			name: "proxy without host",
+			env: Environment{
+				Name:    "corp",
+				BaseURL: "https://api.example.com",
+				Proxy:   "http://",
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid unix socket",
+			env: Environment{
+				Name:      "local",
+				BaseURL:   "unix:///tmp/reflect.sock",
+				Transport: "grpc",
+			},
+			wantErr: false,
+		},
+		{
+			name: "unix socket without absolute path",
+			env: Environment{
+				Name:    "local",
+				BaseURL: "unix://reflect.sock",
+			},
+			wantErr: true,
+		},
+		{
+			name: "unix socket with grpc-web transport",
+			env: Environment{
+				Name:      "local",
+				BaseURL:   "unix:///tmp/reflect.sock",
+				Transport: "grpc-web",
+			},
+			wantErr: true,
+		},
+		{
+			name: "unix socket with proxy",
+			env: Environment{
+				Name:    "local",
+				BaseURL: "unix:///tmp/reflect.sock",
+				Proxy:   "http://proxy.example.com:3128",
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid bearer auth",
+			env: Environment{
+				Name:    "dev",
+				BaseURL: "https://api.example.com",
+				Auth:    AuthConfig{Type: AuthTypeBearer, Token: "abc"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "bearer auth without token",
+			env: Environment{
+				Name:    "dev",
+				BaseURL: "https://api.example.com",
+				Auth:    AuthConfig{Type: AuthTypeBearer},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid oauth2 client credentials auth",
+			env: Environment{
+				Name:    "dev",
+				BaseURL: "https://api.example.com",
+				Auth: AuthConfig{
+					Type:         AuthTypeOAuth2ClientCredentials,
+					TokenURL:     "https://auth.example.com/oauth/token",
+					ClientID:     "reflect",
+					ClientSecret: "secret",
+					Scopes:       []string{"read"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "oauth2 auth without client secret",
+			env: Environment{
+				Name:    "dev",
+				BaseURL: "https://api.example.com",
+				Auth: AuthConfig{
+					Type:     AuthTypeOAuth2ClientCredentials,
+					TokenURL: "https://auth.example.com/oauth/token",
+					ClientID: "reflect",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "oauth2 auth with invalid token URL",
+			env: Environment{
+				Name:    "dev",
+				BaseURL: "https://api.example.com",
+				Auth: AuthConfig{
+					Type:         AuthTypeOAuth2ClientCredentials,
+					TokenURL:     "auth.example.com/token",
+					ClientID:     "reflect",
+					ClientSecret: "secret",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown auth type",
+			env: Environment{
+				Name:    "dev",
+				BaseURL: "https://api.example.com",
+				Auth:    AuthConfig{Type: "basic"},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -511,6 +1077,165 @@ func TestConfigValidate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "requestTimeoutSeconds must be non-negative",
 		},
+		{
+			name: "negative pretty-print limit",
+			cfg: Config{
+				Environments: []Environment{
+					{Name: "dev", BaseURL: "https://dev.example.com", Transport: "connect"},
+				},
+				PrettyPrintMaxBytes: -1,
+			},
+			wantErr: true,
+			errMsg:  "prettyPrintMaxBytes must be non-negative",
+		},
+		{
+			name: "valid service groups",
+			cfg: Config{
+				ServiceGroups: map[string][]string{"Billing": {"billing.*", "payments.v1.PaymentService"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid service group pattern",
+			cfg: Config{
+				ServiceGroups: map[string][]string{"Billing": {"billing.["}},
+			},
+			wantErr: true,
+			errMsg:  "serviceGroups: invalid pattern",
+		},
+		{
+			name: "valid cors",
+			cfg: Config{
+				CORS: CORSConfig{
+					AllowedOrigins:   []string{"https://dashboard.example.com", "http://localhost:3000"},
+					AllowCredentials: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "cors origin with path",
+			cfg: Config{
+				CORS: CORSConfig{AllowedOrigins: []string{"https://dashboard.example.com/app"}},
+			},
+			wantErr: true,
+			errMsg:  "invalid allowed origin",
+		},
+		{
+			name: "cors wildcard with credentials",
+			cfg: Config{
+				CORS: CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			},
+			wantErr: true,
+			errMsg:  "allowCredentials cannot be used",
+		},
+		{
+			name: "valid ui basic auth",
+			cfg: Config{
+				UI: UIConfig{Auth: UIAuthConfig{Type: UIAuthTypeBasic, Username: "admin", PasswordHash: "$2a$04$wR3TYgUEh.WvThxkEqpvmuMKBygn5MW.Jpyh9Kz5DHYfdUqH5TRF."}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ui basic auth with plain password",
+			cfg: Config{
+				UI: UIConfig{Auth: UIAuthConfig{Type: UIAuthTypeBasic, Username: "admin", PasswordHash: "hunter2"}},
+			},
+			wantErr: true,
+			errMsg:  "passwordHash must be a bcrypt hash",
+		},
+		{
+			name: "ui bearer auth without token",
+			cfg: Config{
+				UI: UIConfig{Auth: UIAuthConfig{Type: UIAuthTypeBearer}},
+			},
+			wantErr: true,
+			errMsg:  "token is required",
+		},
+		{
+			name: "invalid ui auth type",
+			cfg: Config{
+				UI: UIConfig{Auth: UIAuthConfig{Type: "digest"}},
+			},
+			wantErr: true,
+			errMsg:  "ui.auth: invalid type",
+		},
+		{
+			name: "valid any type hints",
+			cfg: Config{
+				AnyTypeHints: map[string]string{"acme.v1.Event.payload": "acme.v1.OrderCreated"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty any type hint",
+			cfg: Config{
+				AnyTypeHints: map[string]string{"acme.v1.Event.payload": ""},
+			},
+			wantErr: true,
+			errMsg:  "anyTypeHints",
+		},
+		{
+			name:    "valid comment option",
+			cfg:     Config{CommentOption: "acme.v1.description"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid comment option",
+			cfg:     Config{CommentOption: "(acme.v1.description)"},
+			wantErr: true,
+			errMsg:  "invalid commentOption",
+		},
+		{
+			name:    "valid default color mode",
+			cfg:     Config{DefaultColorMode: "dark"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid default color mode",
+			cfg:     Config{DefaultColorMode: "sepia"},
+			wantErr: true,
+			errMsg:  "invalid defaultColorMode",
+		},
+		{
+			name:    "valid method tag option",
+			cfg:     Config{MethodTagOption: "acme.v1.tags"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid method tag option",
+			cfg:     Config{MethodTagOption: "acme.v1.tags!"},
+			wantErr: true,
+			errMsg:  "invalid methodTagOption",
+		},
+		{
+			name:    "valid example option",
+			cfg:     Config{ExampleOption: "acme.v1.example"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid example option",
+			cfg:     Config{ExampleOption: "acme..example"},
+			wantErr: true,
+			errMsg:  "invalid exampleOption",
+		},
+		{
+			name:    "valid public URL with base path",
+			cfg:     Config{PublicURL: "https://docs.example.com/api/"},
+			wantErr: false,
+		},
+		{
+			name:    "relative public URL",
+			cfg:     Config{PublicURL: "/docs"},
+			wantErr: true,
+			errMsg:  "invalid publicURL",
+		},
+		{
+			name:    "public URL with query",
+			cfg:     Config{PublicURL: "https://docs.example.com/?v=1"},
+			wantErr: true,
+			errMsg:  "invalid publicURL",
+		},
 	}
 
 	for _, tt := range tests {
@@ -525,3 +1250,118 @@ func TestConfigValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestWarnings(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          Config
+		wantWarnings int
+	}{
+		{
+			name: "insecure prod-named environment",
+			cfg: Config{
+				Environments: []Environment{
+					{Name: "prod", BaseURL: "https://api.example.com", TLS: TLSConfig{InsecureSkipVerify: true}},
+				},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name: "insecure environment with production host",
+			cfg: Config{
+				Environments: []Environment{
+					{Name: "main", BaseURL: "https://api.production.example.com", TLS: TLSConfig{InsecureSkipVerify: true}},
+				},
+			},
+			wantWarnings: 1,
+		},
+		{
+			name: "insecure dev environment",
+			cfg: Config{
+				Environments: []Environment{
+					{Name: "dev", BaseURL: "https://dev.api.example.com", TLS: TLSConfig{InsecureSkipVerify: true}},
+				},
+			},
+			wantWarnings: 0,
+		},
+		{
+			name: "secure prod environment",
+			cfg: Config{
+				Environments: []Environment{
+					{Name: "prod", BaseURL: "https://api.example.com"},
+				},
+			},
+			wantWarnings: 0,
+		},
+		{
+			name: "keyword must match a whole word",
+			cfg: Config{
+				Environments: []Environment{
+					{Name: "product-catalog", BaseURL: "https://products.example.com", TLS: TLSConfig{InsecureSkipVerify: true}},
+				},
+			},
+			wantWarnings: 0,
+		},
+		{
+			name: "custom production keywords",
+			cfg: Config{
+				ProductionKeywords: []string{"live"},
+				Environments: []Environment{
+					{Name: "live", BaseURL: "https://api.example.com", TLS: TLSConfig{InsecureSkipVerify: true}},
+					{Name: "prod", BaseURL: "https://api.example.com", TLS: TLSConfig{InsecureSkipVerify: true}},
+				},
+			},
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := tt.cfg.Warnings()
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("Warnings() = %v, want %d warning(s)", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("TEST_EXPAND_HOST", "api.example.com")
+	t.Setenv("TEST_EXPAND_EMPTY", "")
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{name: "plain", input: "https://${TEST_EXPAND_HOST}/v1", want: "https://api.example.com/v1"},
+		{name: "bare", input: "https://$TEST_EXPAND_HOST", want: "https://api.example.com"},
+		{name: "plain unset", input: "https://${TEST_EXPAND_MISSING}", want: "https://"},
+		{name: "default unused", input: "${TEST_EXPAND_HOST:-localhost}", want: "api.example.com"},
+		{name: "default when unset", input: "http://${TEST_EXPAND_MISSING:-localhost:8080}", want: "http://localhost:8080"},
+		{name: "default when empty", input: "${TEST_EXPAND_EMPTY:-fallback}", want: "fallback"},
+		{name: "required set", input: "${TEST_EXPAND_HOST:?host is required}", want: "api.example.com"},
+		{name: "required unset", input: "${TEST_EXPAND_MISSING:?host is required}", wantErr: "${TEST_EXPAND_MISSING}: host is required"},
+		{name: "required empty without message", input: "${TEST_EXPAND_EMPTY:?}", wantErr: "${TEST_EXPAND_EMPTY}: is not set"},
+		{name: "escaped dollar", input: "pa$$word-$${TEST_EXPAND_HOST}", want: "pa$word-${TEST_EXPAND_HOST}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandEnv(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("expandEnv(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandEnv(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("expandEnv(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package descriptor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// cacheFormat versions the cache key, so entries written by an incompatible
+// build are never read.
+const cacheFormat = "reflect-descriptor-cache/v1"
+
+// cacheEntry is a cached descriptor set, stored as JSON in the cache directory.
+type cacheEntry struct {
+	// Imports maps files pulled in from the include paths (rather than
+	// discovered under the root) to the SHA-256 of their contents, so
+	// changes to them also invalidate the entry.
+	Imports map[string]string `json:"imports"`
+
+	// Descriptors is the serialized FileDescriptorSet, with source info.
+	Descriptors []byte `json:"descriptors"`
+}
+
+// descriptorCacheKey hashes everything a directory load depends on before
+// parsing: the discovered files and their contents, the include paths, the
+// load options that change the result, and the built-in imports.
+func descriptorCacheKey(protoFiles, includePaths []string, opts LoadOptions) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\nallowDuplicates=%v\n", cacheFormat, opts.AllowDuplicates)
+	for _, includePath := range includePaths {
+		fmt.Fprintf(h, "include %q\n", includePath)
+	}
+	for _, file := range protoFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "file %q %d\n", file, len(data))
+		h.Write(data)
+	}
+	fmt.Fprintf(h, "builtin %q %d\n", reflectOptionsPath, len(reflectOptionsProto))
+	io.WriteString(h, reflectOptionsProto)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// readDescriptorCache returns the cached descriptors for key, or ok=false if
+// there is no usable entry (missing, unreadable, or an import has changed).
+func readDescriptorCache(cacheDir, key string, includePaths []string) (*protoregistry.Files, *descriptorpb.FileDescriptorSet, bool) {
+	data, err := os.ReadFile(filepath.Join(cacheDir, key+".json"))
+	if err != nil {
+		return nil, nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, nil, false
+	}
+	for name, digest := range entry.Imports {
+		if current, ok := importDigest(name, includePaths); !ok || current != digest {
+			return nil, nil, false
+		}
+	}
+
+	fdSet := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(entry.Descriptors, fdSet); err != nil {
+		return nil, nil, false
+	}
+	files, err := protodesc.NewFiles(fdSet)
+	if err != nil {
+		return nil, nil, false
+	}
+	return files, fdSet, true
+}
+
+// writeDescriptorCache stores fdSet under key. The entry is written to a
+// temporary file and renamed, so concurrent readers never see a partial one.
+func writeDescriptorCache(cacheDir, key string, fdSet *descriptorpb.FileDescriptorSet, protoFiles, includePaths []string) error {
+	descriptors, err := proto.Marshal(fdSet)
+	if err != nil {
+		return err
+	}
+
+	loaded := make(map[string]bool, len(protoFiles))
+	for _, file := range protoFiles {
+		if relPath, err := findRelativePath(file, includePaths); err == nil {
+			loaded[relPath] = true
+		}
+	}
+	entry := cacheEntry{Imports: make(map[string]string), Descriptors: descriptors}
+	for _, file := range fdSet.File {
+		if loaded[file.GetName()] {
+			continue
+		}
+		// Built-in and well-known imports are not on disk and need no check
+		if digest, ok := importDigest(file.GetName(), includePaths); ok {
+			entry.Imports[file.GetName()] = digest
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(cacheDir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(cacheDir, key+".json"))
+}
+
+// importDigest returns the SHA-256 of the first file named name on the
+// include paths, mirroring how imports are resolved.
+func importDigest(name string, includePaths []string) (string, bool) {
+	for _, includePath := range includePaths {
+		data, err := os.ReadFile(filepath.Join(includePath, name))
+		if err == nil {
+			sum := sha256.Sum256(data)
+			return hex.EncodeToString(sum[:]), true
+		}
+	}
+	return "", false
+}
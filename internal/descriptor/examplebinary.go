@@ -0,0 +1,29 @@
+package descriptor
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// GenerateExampleBinary generates a protobuf wire-format example for a message
+// type. The example holds the same values as GenerateExampleJSON: they are
+// loaded into a dynamic message, which is then marshaled deterministically.
+func GenerateExampleBinary(msg protoreflect.MessageDescriptor, options ExampleOptions) ([]byte, error) {
+	if msg == nil {
+		return nil, fmt.Errorf("message descriptor is nil")
+	}
+
+	message, _, err := exampleMessage(msg, options)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	return data, nil
+}
@@ -3,7 +3,10 @@ package descriptor
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"strings"
 
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
@@ -14,8 +17,48 @@ type ExampleOptions struct {
 	IncludeComments bool // Whether to include field comments as JSON comments (default: false)
 	MaxDepth        int  // Maximum recursion depth to prevent cycles (default: 5)
 	MinimalMode     bool // Only include required fields (default: false)
+	EmitDefaults    bool // Include every field with its zero value instead of example values (default: false)
+	Realistic       bool // Pick plausible values based on field names, e.g. emails and URLs (default: false)
+	UseProtoNames   bool // Key fields by proto name (user_id) instead of JSON name (userId) (default: false)
+
+	RepeatedCount        int // Items generated for repeated scalar and enum fields, at most maxRepeatedCount (default: 2)
+	RepeatedMessageCount int // Items generated for repeated message fields, at most maxRepeatedCount (default: 1)
+
+	// AnyTypes is the concrete message to use for google.protobuf.Any fields,
+	// keyed by field full name (default: none, a StringValue placeholder)
+	AnyTypes map[string]protoreflect.MessageDescriptor `json:"-"`
+
+	// ExampleOption is the string field option read for explicit example
+	// values (default: nil, the built-in reflect.example). Message and map
+	// fields take a JSON object, repeated fields a JSON array (or, for
+	// scalars, a plain value used for each item), and other fields a plain
+	// value.
+	ExampleOption protoreflect.ExtensionDescriptor `json:"-"`
+
+	// CycleStrategy is what a field gets when its message is already being
+	// generated further up, as in a self-referential tree node (default:
+	// CycleOmit)
+	CycleStrategy CycleStrategy
 }
 
+// CycleStrategy controls how example generation handles recursive messages.
+type CycleStrategy string
+
+const (
+	// CycleOmit leaves recursive fields out, keeping the example valid JSON
+	// for the message.
+	CycleOmit CycleStrategy = "omit"
+	// CycleNull sets recursive fields to null, which protojson reads as unset.
+	CycleNull CycleStrategy = "null"
+	// CyclePlaceholder sets recursive fields to {"<recursive>": true}. It
+	// shows where the cycle is, but protojson rejects the example.
+	CyclePlaceholder CycleStrategy = "placeholder"
+)
+
+// errCycle reports that a message is already being generated further up.
+// The field holding the message handles it according to the CycleStrategy.
+var errCycle = errors.New("recursive message")
+
 // DefaultExampleOptions returns sensible defaults for example generation.
 func DefaultExampleOptions() ExampleOptions {
 	return ExampleOptions{
@@ -23,9 +66,29 @@ func DefaultExampleOptions() ExampleOptions {
 		IncludeComments: false,
 		MaxDepth:        5,
 		MinimalMode:     false,
+
+		RepeatedCount:        2,
+		RepeatedMessageCount: 1,
 	}
 }
 
+// maxRepeatedCount bounds RepeatedCount and RepeatedMessageCount. Counts
+// multiply through nested repeated fields, so larger ones quickly produce
+// huge examples.
+const maxRepeatedCount = 10
+
+// repeatedItemCount returns how many items to generate for a repeated field.
+func repeatedItemCount(field protoreflect.FieldDescriptor, options ExampleOptions) int {
+	count, fallback := options.RepeatedCount, 2
+	if field.Kind() == protoreflect.MessageKind || field.Kind() == protoreflect.GroupKind {
+		count, fallback = options.RepeatedMessageCount, 1
+	}
+	if count <= 0 {
+		return fallback
+	}
+	return min(count, maxRepeatedCount)
+}
+
 // GenerateExampleJSON generates a formatted JSON example for a message type.
 func GenerateExampleJSON(msg protoreflect.MessageDescriptor, options ExampleOptions) (string, error) {
 	if msg == nil {
@@ -38,7 +101,7 @@ func GenerateExampleJSON(msg protoreflect.MessageDescriptor, options ExampleOpti
 	}
 
 	visited := make(map[string]bool)
-	value, err := generateMessageValue(msg, options, visited, 0)
+	value, err := generateMessageFieldValue(msg, options, visited, 0)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate message value: %w", err)
 	}
@@ -51,6 +114,95 @@ func GenerateExampleJSON(msg protoreflect.MessageDescriptor, options ExampleOpti
 	return string(jsonBytes), nil
 }
 
+// GenerateExampleJSONSequence generates count formatted JSON examples for a
+// streamed message type. The first example matches GenerateExampleJSON; each
+// later one increments the first numeric field (searching nested messages in
+// declaration order), or failing that, suffixes the first string field, so
+// the messages are distinguishable.
+func GenerateExampleJSONSequence(msg protoreflect.MessageDescriptor, options ExampleOptions, count int) ([]string, error) {
+	if msg == nil {
+		return nil, fmt.Errorf("message descriptor is nil")
+	}
+
+	// Set defaults for unset options
+	if options.MaxDepth == 0 {
+		options.MaxDepth = 5
+	}
+
+	examples := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		value, err := generateMessageFieldValue(msg, options, make(map[string]bool), 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate message value: %w", err)
+		}
+
+		if fields, ok := value.(map[string]any); ok && i > 0 {
+			if !varyExample(fields, msg, options, i, true) {
+				varyExample(fields, msg, options, i, false)
+			}
+		}
+
+		jsonBytes, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		examples = append(examples, string(jsonBytes))
+	}
+
+	return examples, nil
+}
+
+// varyExample changes the first numeric field (or, if numeric is false, the
+// first string field) of a generated message value by step, recursing into
+// nested messages. Reports whether a field was changed.
+func varyExample(value map[string]any, msg protoreflect.MessageDescriptor, options ExampleOptions, step int, numeric bool) bool {
+	for i := 0; i < msg.Fields().Len(); i++ {
+		field := msg.Fields().Get(i)
+		v, ok := value[fieldKey(field, options)]
+		if !ok || field.IsList() || field.IsMap() {
+			continue
+		}
+
+		if nested, ok := v.(map[string]any); ok && field.Kind() == protoreflect.MessageKind {
+			if varyExample(nested, field.Message(), options, step, numeric) {
+				return true
+			}
+			continue
+		}
+
+		if !numeric {
+			if s, ok := v.(string); ok && field.Kind() == protoreflect.StringKind {
+				value[fieldKey(field, options)] = fmt.Sprintf("%s_%d", s, step+1)
+				return true
+			}
+			continue
+		}
+
+		var varied any
+		switch n := v.(type) {
+		case int:
+			varied = n + step
+		case int32:
+			varied = n + int32(step)
+		case int64:
+			varied = n + int64(step)
+		case uint32:
+			varied = n + uint32(step)
+		case uint64:
+			varied = n + uint64(step)
+		case float32:
+			varied = n + float32(step)
+		case float64:
+			varied = n + float64(step)
+		default:
+			continue
+		}
+		value[fieldKey(field, options)] = varied
+		return true
+	}
+	return false
+}
+
 // generateMessageValue generates example values for a message type.
 func generateMessageValue(msg protoreflect.MessageDescriptor, options ExampleOptions, visited map[string]bool, depth int) (map[string]any, error) {
 	if depth >= options.MaxDepth {
@@ -59,7 +211,10 @@ func generateMessageValue(msg protoreflect.MessageDescriptor, options ExampleOpt
 
 	msgName := string(msg.FullName())
 	if visited[msgName] {
-		return map[string]any{"<recursive>": true}, nil
+		if options.CycleStrategy == CyclePlaceholder {
+			return map[string]any{"<recursive>": true}, nil
+		}
+		return nil, errCycle
 	}
 
 	visited[msgName] = true
@@ -67,36 +222,87 @@ func generateMessageValue(msg protoreflect.MessageDescriptor, options ExampleOpt
 
 	result := make(map[string]any)
 
-	// Handle well-known types specially
-	if wktValue := generateWellKnownType(msg); wktValue != nil {
-		return wktValue, nil
+	// Handle well-known types specially; with EmitDefaults their fields are
+	// zero-valued like any other message
+	if !options.EmitDefaults {
+		if wktValue := generateWellKnownType(msg); wktValue != nil {
+			return wktValue, nil
+		}
 	}
 
 	// Generate fields
 	for i := 0; i < msg.Fields().Len(); i++ {
 		field := msg.Fields().Get(i)
 
+		// Only the first member of a oneof is emitted
+		if oneof := field.ContainingOneof(); oneof != nil && oneof.Fields().Get(0) != field {
+			continue
+		}
+
 		// Skip fields based on options
 		if !shouldIncludeField(field, options) {
 			continue
 		}
 
-		fieldValue, err := generateFieldValue(field, options, visited, depth)
+		var fieldValue any
+		var err error
+		if options.EmitDefaults {
+			fieldValue, err = generateDefaultValue(field, options, visited, depth)
+		} else {
+			fieldValue, err = generateFieldValue(field, options, visited, depth)
+		}
+		if errors.Is(err, errCycle) {
+			if options.CycleStrategy == CycleNull {
+				result[fieldKey(field, options)] = nil
+			}
+			continue
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate value for field %s: %w", field.Name(), err)
 		}
 
 		// Skip nil values unless they're explicitly set
 		if fieldValue != nil {
-			result[string(field.JSONName())] = fieldValue
+			result[fieldKey(field, options)] = fieldValue
 		}
 	}
 
 	return result, nil
 }
 
+// fieldKey returns the JSON object key for a field.
+func fieldKey(field protoreflect.FieldDescriptor, options ExampleOptions) string {
+	if options.UseProtoNames {
+		return string(field.Name())
+	}
+	return field.JSONName()
+}
+
+// generateMessageFieldValue generates a value for a message, using the
+// non-object JSON shape of google.protobuf.Value and ListValue when applicable.
+func generateMessageFieldValue(msg protoreflect.MessageDescriptor, options ExampleOptions, visited map[string]bool, depth int) (any, error) {
+	if !options.EmitDefaults {
+		if value, ok := generateWellKnownValue(msg); ok {
+			return value, nil
+		}
+	}
+	return generateMessageValue(msg, options, visited, depth)
+}
+
 // generateFieldValue generates an appropriate value for a field based on its type.
 func generateFieldValue(field protoreflect.FieldDescriptor, options ExampleOptions, visited map[string]bool, depth int) (any, error) {
+	// An explicit example option wins over any heuristic. A plain-text
+	// example on a repeated field is used for each generated item.
+	if example, ok := exampleOption(field, options.ExampleOption); ok {
+		option := exampleOptionFullName(options.ExampleOption)
+		if isJSONExample(field, example) {
+			return parseJSONExample(field, option, example)
+		}
+		if !field.IsList() {
+			return parseExampleOption(field, option, example)
+		}
+	}
+
 	switch {
 	case field.IsMap():
 		return generateMapValue(field, options, visited, depth)
@@ -111,6 +317,24 @@ func generateFieldValue(field protoreflect.FieldDescriptor, options ExampleOptio
 
 // generateScalarValue generates a value for a scalar field.
 func generateScalarValue(field protoreflect.FieldDescriptor, options ExampleOptions, visited map[string]bool, depth int) (any, error) {
+	// An explicit example option wins over any heuristic; repeated fields
+	// get here with the option checked per item
+	if example, ok := exampleOption(field, options.ExampleOption); ok {
+		return parseExampleOption(field, exampleOptionFullName(options.ExampleOption), example)
+	}
+
+	// A declared proto2 default is what a reader of the field sees when it
+	// is unset, so it makes the most representative example
+	if field.HasDefault() {
+		return declaredDefaultValue(field), nil
+	}
+
+	if options.Realistic {
+		if value, ok := realisticScalarValue(field); ok {
+			return value, nil
+		}
+	}
+
 	switch field.Kind() {
 	case protoreflect.BoolKind:
 		return true, nil
@@ -132,22 +356,201 @@ func generateScalarValue(field protoreflect.FieldDescriptor, options ExampleOpti
 		return base64.StdEncoding.EncodeToString([]byte("example data")), nil
 	case protoreflect.EnumKind:
 		return generateEnumValue(field.Enum())
-	case protoreflect.MessageKind:
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if hinted := options.AnyTypes[string(field.FullName())]; hinted != nil && field.Message().FullName() == "google.protobuf.Any" {
+			return generateAnyValue(hinted, options, visited, depth+1)
+		}
+		return generateMessageFieldValue(field.Message(), options, visited, depth+1)
+	default:
+		return nil, fmt.Errorf("unsupported field kind: %v", field.Kind())
+	}
+}
+
+// generateAnyValue generates a google.protobuf.Any holding an example of the
+// given message. Regular messages are inlined next to "@type"; well-known
+// types keep their own JSON form under "value", as protojson expects.
+func generateAnyValue(msg protoreflect.MessageDescriptor, options ExampleOptions, visited map[string]bool, depth int) (any, error) {
+	value, err := generateMessageFieldValue(msg, options, visited, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	typeURL := "type.googleapis.com/" + string(msg.FullName())
+	fields, isObject := value.(map[string]any)
+	if !isObject || msg.ParentFile().Package() == "google.protobuf" {
+		return map[string]any{"@type": typeURL, "value": value}, nil
+	}
+
+	result := map[string]any{"@type": typeURL}
+	for name, v := range fields {
+		result[name] = v
+	}
+	return result, nil
+}
+
+// generateDefaultValue generates the zero value for a field: 0, "", false, the
+// zero enum value, an empty array/map, or a message with all fields defaulted.
+func generateDefaultValue(field protoreflect.FieldDescriptor, options ExampleOptions, visited map[string]bool, depth int) (any, error) {
+	switch {
+	case field.IsMap():
+		return map[string]any{}, nil
+	case field.Cardinality() == protoreflect.Repeated:
+		return []any{}, nil
+	}
+	if field.HasDefault() {
+		return declaredDefaultValue(field), nil
+	}
+
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return false, nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return 0, nil
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return 0.0, nil
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return "", nil
+	case protoreflect.EnumKind:
+		if value := field.Enum().Values().ByNumber(0); value != nil {
+			return string(value.Name()), nil
+		}
+		return generateEnumValue(field.Enum())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
 		return generateMessageValue(field.Message(), options, visited, depth+1)
 	default:
 		return nil, fmt.Errorf("unsupported field kind: %v", field.Kind())
 	}
 }
 
-// generateRepeatedValue generates an array value for a repeated field.
-func generateRepeatedValue(field protoreflect.FieldDescriptor, options ExampleOptions, visited map[string]bool, depth int) (any, error) {
-	// Generate 1-2 example items
-	itemCount := 2
-	if field.Kind() == protoreflect.MessageKind {
-		// For complex message types, just generate 1 item
-		itemCount = 1
+// declaredDefaultValue returns the field's declared default (e.g.
+// [default = 5]) in its protojson form. Non-finite floats become the
+// strings protojson uses for them.
+func declaredDefaultValue(field protoreflect.FieldDescriptor) any {
+	value := field.Default()
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return value.Bool()
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return int32(value.Int())
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return value.Int()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return uint32(value.Uint())
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return value.Uint()
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		f := value.Float()
+		switch {
+		case math.IsNaN(f):
+			return "NaN"
+		case math.IsInf(f, 1):
+			return "Infinity"
+		case math.IsInf(f, -1):
+			return "-Infinity"
+		}
+		if field.Kind() == protoreflect.FloatKind {
+			return float32(f)
+		}
+		return f
+	case protoreflect.StringKind:
+		return value.String()
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(value.Bytes())
+	case protoreflect.EnumKind:
+		return string(field.DefaultEnumValue().Name())
+	default:
+		return nil
 	}
+}
+
+// realisticStringValues maps field-name words to plausible string values,
+// checked in order so more specific words win (e.g. "first_name" is "Jane").
+var realisticStringValues = []struct {
+	words []string
+	value string
+}{
+	{[]string{"email"}, "user@example.com"},
+	{[]string{"url", "uri", "website", "link", "href"}, "https://example.com"},
+	{[]string{"uuid", "guid", "id"}, "123e4567-e89b-12d3-a456-426614174000"},
+	{[]string{"phone"}, "+1-555-0100"},
+	{[]string{"first"}, "Jane"},
+	{[]string{"last", "surname"}, "Doe"},
+	{[]string{"username", "login"}, "jane.doe"},
+	{[]string{"name"}, "Jane Doe"},
+	{[]string{"country"}, "US"},
+	{[]string{"timezone", "tz"}, "America/New_York"},
+	{[]string{"language", "locale", "lang"}, "en-US"},
+	{[]string{"currency"}, "USD"},
+	{[]string{"city"}, "San Francisco"},
+	{[]string{"postal", "zip"}, "94105"},
+	{[]string{"ip"}, "192.0.2.1"},
+	{[]string{"date"}, "2022-01-01"},
+}
+
+// realisticScalarValue returns a plausible value for a string or numeric field
+// based on its name, or false if no heuristic applies. Values are fixed so
+// output stays deterministic.
+func realisticScalarValue(field protoreflect.FieldDescriptor) (any, bool) {
+	words := strings.Split(strings.ToLower(string(field.Name())), "_")
+	hasWord := func(candidates ...string) bool {
+		for _, word := range words {
+			for _, candidate := range candidates {
+				if word == candidate {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		for _, entry := range realisticStringValues {
+			if hasWord(entry.words...) {
+				return entry.value, true
+			}
+		}
+
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		switch {
+		case hasWord("ms", "millis", "milliseconds"):
+			return int64(1640995200000), true // 2022-01-01 00:00:00 UTC
+		case hasWord("at", "time", "timestamp", "epoch", "seconds"):
+			return int64(1640995200), true
+		}
 
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		switch {
+		case hasWord("size", "limit"):
+			return 20, true
+		case hasWord("age"):
+			return 30, true
+		case hasWord("port"):
+			return 8080, true
+		case hasWord("year"):
+			return 2022, true
+		}
+
+	case protoreflect.DoubleKind, protoreflect.FloatKind:
+		switch {
+		case hasWord("latitude", "lat"):
+			return 37.7749, true
+		case hasWord("longitude", "lng", "lon"):
+			return -122.4194, true
+		}
+	}
+
+	return nil, false
+}
+
+// generateRepeatedValue generates an array value for a repeated field.
+func generateRepeatedValue(field protoreflect.FieldDescriptor, options ExampleOptions, visited map[string]bool, depth int) (any, error) {
+	itemCount := repeatedItemCount(field, options)
 	result := make([]any, 0, itemCount)
 	for i := 0; i < itemCount; i++ {
 		itemValue, err := generateScalarValue(field, options, visited, depth)
@@ -162,34 +565,43 @@ func generateRepeatedValue(field protoreflect.FieldDescriptor, options ExampleOp
 
 // generateMapValue generates a map value for a map field.
 func generateMapValue(field protoreflect.FieldDescriptor, options ExampleOptions, visited map[string]bool, depth int) (any, error) {
-	// Generate 1-2 example key-value pairs
+	// Generate 2 example key-value pairs. encoding/json sorts map keys, so the
+	// output order is deterministic.
 	result := make(map[string]any)
 
-	keyField := field.MapKey()
-	valueField := field.MapValue()
+	keys, err := generateMapKeys(field.MapKey(), options, visited, depth)
+	if err != nil {
+		return nil, err
+	}
 
-	// Generate example keys and values
-	for i := 0; i < 2; i++ {
-		keyValue, err := generateScalarValue(keyField, options, visited, depth)
+	for _, key := range keys {
+		value, err := generateScalarValue(field.MapValue(), options, visited, depth)
 		if err != nil {
 			return nil, err
 		}
+		result[key] = value
+	}
 
-		valueValue, err := generateScalarValue(valueField, options, visited, depth)
+	return result, nil
+}
+
+// generateMapKeys generates two distinct map keys in their protojson form:
+// quoted integers for integer keys, "true"/"false" for bool keys.
+func generateMapKeys(keyField protoreflect.FieldDescriptor, options ExampleOptions, visited map[string]bool, depth int) ([]string, error) {
+	switch keyField.Kind() {
+	case protoreflect.BoolKind:
+		return []string{"false", "true"}, nil
+	case protoreflect.StringKind:
+		value, err := generateScalarValue(keyField, options, visited, depth)
 		if err != nil {
 			return nil, err
 		}
-
-		// Convert key to string for JSON
-		keyStr := fmt.Sprintf("%v", keyValue)
-		if i > 0 {
-			keyStr = fmt.Sprintf("%s_%d", keyStr, i)
-		}
-
-		result[keyStr] = valueValue
+		key := fmt.Sprintf("%v", value)
+		return []string{key, key + "_1"}, nil
+	default:
+		// All remaining key kinds are integers
+		return []string{"1", "2"}, nil
 	}
-
-	return result, nil
 }
 
 // generateOneofValue generates a value for a oneof field.
@@ -206,20 +618,27 @@ func generateOneofValue(field protoreflect.FieldDescriptor, options ExampleOptio
 
 // generateEnumValue generates an example value for an enum.
 func generateEnumValue(enum protoreflect.EnumDescriptor) (any, error) {
-	// Try to find the first non-zero value, otherwise use zero
+	if value := exampleEnumValue(enum); value != nil {
+		return string(value.Name()), nil
+	}
+	return "UNKNOWN", nil
+}
+
+// exampleEnumValue picks the enum value used in examples: the first non-zero
+// value, otherwise the first value. Returns nil for an empty enum.
+func exampleEnumValue(enum protoreflect.EnumDescriptor) protoreflect.EnumValueDescriptor {
 	for i := 0; i < enum.Values().Len(); i++ {
 		value := enum.Values().Get(i)
 		if value.Number() != 0 {
-			return string(value.Name()), nil
+			return value
 		}
 	}
 
-	// If no non-zero value found, use the first value
 	if enum.Values().Len() > 0 {
-		return string(enum.Values().Get(0).Name()), nil
+		return enum.Values().Get(0)
 	}
 
-	return "UNKNOWN", nil
+	return nil
 }
 
 // generateWellKnownType generates examples for well-known protobuf types.
@@ -295,13 +714,47 @@ func generateWellKnownType(msg protoreflect.MessageDescriptor) map[string]any {
 		return map[string]any{}
 	case "google.protobuf.NullValue":
 		return nil
+	case "google.type.Money":
+		return map[string]any{
+			"currencyCode": "USD",
+			"units":        "42", // int64 is a JSON string in protojson
+			"nanos":        990000000,
+		}
+	case "google.type.LatLng":
+		return map[string]any{
+			"latitude":  37.7749,
+			"longitude": -122.4194,
+		}
+	case "google.type.Date":
+		return map[string]any{
+			"year":  2024,
+			"month": 1,
+			"day":   15,
+		}
 	}
 
 	return nil
 }
 
+// generateWellKnownValue generates examples for well-known types whose JSON
+// form is not an object. Returns false for all other messages.
+func generateWellKnownValue(msg protoreflect.MessageDescriptor) (any, bool) {
+	switch msg.FullName() {
+	case "google.protobuf.Value":
+		return "example value", true
+	case "google.protobuf.ListValue":
+		return []any{"example value", 42}, true
+	}
+	return nil, false
+}
+
 // shouldIncludeField determines whether a field should be included in the example.
 func shouldIncludeField(field protoreflect.FieldDescriptor, options ExampleOptions) bool {
+	// EmitDefaults shows the complete schema
+	if options.EmitDefaults {
+		return true
+	}
+
 	// In minimal mode, only include required fields
 	if options.MinimalMode {
 		return field.Cardinality() == protoreflect.Required
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -51,12 +52,54 @@ func TestGenerateExampleJSON_Golden(t *testing.T) {
 			options:  DefaultExampleOptions(),
 			filename: "list_users_request.json",
 		},
+		{
+			name:     "user message emit defaults",
+			msgName:  "users.v1.User",
+			options:  ExampleOptions{EmitDefaults: true, MaxDepth: 3},
+			filename: "user_emit_defaults.json",
+		},
+		{
+			name:     "minimal mode ignored with emit defaults",
+			msgName:  "users.v1.CreateUserRequest",
+			options:  ExampleOptions{EmitDefaults: true, MinimalMode: true},
+			filename: "create_user_request_emit_defaults.json",
+		},
+		{
+			name:     "oneof message",
+			msgName:  "users.v1.SyncUsersRequest",
+			options:  DefaultExampleOptions(),
+			filename: "sync_users_request.json",
+		},
+		{
+			name:     "oneof message emit defaults",
+			msgName:  "users.v1.SyncUsersRequest",
+			options:  ExampleOptions{EmitDefaults: true, MaxDepth: 3},
+			filename: "sync_users_request_emit_defaults.json",
+		},
+		{
+			name:     "user message realistic",
+			msgName:  "users.v1.User",
+			options:  ExampleOptions{Realistic: true, IncludeOptional: true, MaxDepth: 5},
+			filename: "user_realistic.json",
+		},
 		{
 			name:     "timestamp message",
 			msgName:  "testdata.wkt.UsesTimestamp",
 			options:  DefaultExampleOptions(),
 			filename: "uses_timestamp.json",
 		},
+		{
+			name:     "common types message",
+			msgName:  "commontypes.v1.Listing",
+			options:  DefaultExampleOptions(),
+			filename: "listing.json",
+		},
+		{
+			name:     "typed map keys",
+			msgName:  "commontypes.v1.Inventory",
+			options:  DefaultExampleOptions(),
+			filename: "inventory.json",
+		},
 	}
 
 	// Load test registries
@@ -75,6 +118,11 @@ func TestGenerateExampleJSON_Golden(t *testing.T) {
 		t.Fatalf("Failed to load WKT test registry: %v", err)
 	}
 
+	commonTypesRegistry, err := LoadDirectory(context.Background(), "testdata/commontypes", []string{"../third_party/googleapis"})
+	if err != nil {
+		t.Fatalf("Failed to load common types test registry: %v", err)
+	}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Select appropriate registry
@@ -84,6 +132,8 @@ func TestGenerateExampleJSON_Golden(t *testing.T) {
 				registry = basicRegistry
 			case tt.msgName == "testdata.wkt.UsesTimestamp":
 				registry = wktRegistry
+			case strings.HasPrefix(tt.msgName, "commontypes.v1."):
+				registry = commonTypesRegistry
 			default:
 				registry = comprehensiveRegistry
 			}
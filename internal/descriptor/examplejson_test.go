@@ -2,9 +2,17 @@ package descriptor
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 func TestGenerateExampleJSON(t *testing.T) {
@@ -256,6 +264,10 @@ func TestDefaultExampleOptions(t *testing.T) {
 	if options.MinimalMode {
 		t.Error("Expected MinimalMode to be false by default")
 	}
+
+	if options.RepeatedCount != 2 || options.RepeatedMessageCount != 1 {
+		t.Error("Expected RepeatedCount 2 and RepeatedMessageCount 1 by default")
+	}
 }
 
 func TestGenerateWellKnownType(t *testing.T) {
@@ -285,3 +297,601 @@ func TestGenerateWellKnownType(t *testing.T) {
 
 	t.Logf("Generated JSON for WKT message:\n%s", result)
 }
+
+func TestGenerateExampleJSON_CommonTypesParse(t *testing.T) {
+	registry, err := LoadDirectory(context.Background(), "testdata/commontypes", []string{"../third_party/googleapis"})
+	if err != nil {
+		t.Fatalf("Failed to load common types test registry: %v", err)
+	}
+
+	msg, exists := registry.FindMessage("commontypes.v1.Listing")
+	if !exists {
+		t.Fatal("Message commontypes.v1.Listing not found")
+	}
+
+	result, err := GenerateExampleJSON(msg, DefaultExampleOptions())
+	if err != nil {
+		t.Fatalf("GenerateExampleJSON() error = %v", err)
+	}
+
+	// The example must be accepted by protojson as-is
+	if err := protojson.Unmarshal([]byte(result), dynamicpb.NewMessage(msg)); err != nil {
+		t.Errorf("Generated JSON is not valid protojson: %v\nJSON: %s", err, result)
+	}
+}
+
+func TestGenerateExampleJSON_MapKeys(t *testing.T) {
+	registry, err := LoadDirectory(context.Background(), "testdata/commontypes", []string{"../third_party/googleapis"})
+	if err != nil {
+		t.Fatalf("Failed to load common types test registry: %v", err)
+	}
+
+	msg, exists := registry.FindMessage("commontypes.v1.Inventory")
+	if !exists {
+		t.Fatal("Message commontypes.v1.Inventory not found")
+	}
+
+	result, err := GenerateExampleJSON(msg, DefaultExampleOptions())
+	if err != nil {
+		t.Fatalf("GenerateExampleJSON() error = %v", err)
+	}
+
+	// Output must be identical across runs
+	for i := 0; i < 5; i++ {
+		again, err := GenerateExampleJSON(msg, DefaultExampleOptions())
+		if err != nil {
+			t.Fatalf("GenerateExampleJSON() error = %v", err)
+		}
+		if again != result {
+			t.Fatalf("Expected deterministic output, got:\n%s\nthen:\n%s", result, again)
+		}
+	}
+
+	var data map[string]map[string]any
+	if err := json.Unmarshal([]byte(result), &data); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	tests := []struct {
+		field    string
+		wantKeys []string
+	}{
+		{"stockBySku", []string{"example_key", "example_key_1"}},
+		{"titlesById", []string{"1", "2"}},
+		{"pricesByQuantity", []string{"1", "2"}},
+		{"labelsByFlag", []string{"false", "true"}},
+	}
+	for _, tt := range tests {
+		entries := data[tt.field]
+		if len(entries) != len(tt.wantKeys) {
+			t.Errorf("%s: expected keys %v, got %v", tt.field, tt.wantKeys, entries)
+			continue
+		}
+		for _, key := range tt.wantKeys {
+			if _, ok := entries[key]; !ok {
+				t.Errorf("%s: expected key %q, got %v", tt.field, key, entries)
+			}
+		}
+	}
+
+	// Keys must be accepted by protojson for their key type
+	if err := protojson.Unmarshal([]byte(result), dynamicpb.NewMessage(msg)); err != nil {
+		t.Errorf("Generated JSON is not valid protojson: %v\nJSON: %s", err, result)
+	}
+}
+
+func TestGenerateExampleJSON_ExampleOption(t *testing.T) {
+	// reflect/options.proto is built in, so no include path is needed for it
+	registry, err := LoadDirectory(context.Background(), "testdata/examples", nil)
+	if err != nil {
+		t.Fatalf("Failed to load examples test registry: %v", err)
+	}
+
+	msg, exists := registry.FindMessage("examples.v1.Account")
+	if !exists {
+		t.Fatal("Message examples.v1.Account not found")
+	}
+
+	result, err := GenerateExampleJSON(msg, DefaultExampleOptions())
+	if err != nil {
+		t.Fatalf("GenerateExampleJSON() error = %v", err)
+	}
+
+	var data map[string]any
+	if err := json.Unmarshal([]byte(result), &data); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	want := map[string]any{
+		"email":       "foo@bar.com",
+		"seats":       float64(25),
+		"balance":     1024.5,
+		"verified":    false,
+		"status":      "ACCOUNT_STATUS_SUSPENDED",
+		"avatar":      base64.StdEncoding.EncodeToString([]byte("png")),
+		"labels":      []any{"beta", "beta"},
+		"displayName": "example_display_name",
+	}
+	if !reflect.DeepEqual(data, want) {
+		t.Errorf("Expected %v, got %v", want, data)
+	}
+
+	// The example must be accepted by protojson as-is
+	if err := protojson.Unmarshal([]byte(result), dynamicpb.NewMessage(msg)); err != nil {
+		t.Errorf("Generated JSON is not valid protojson: %v\nJSON: %s", err, result)
+	}
+}
+
+func TestParseExampleOption(t *testing.T) {
+	registry, err := LoadDirectory(context.Background(), "testdata/examples", nil)
+	if err != nil {
+		t.Fatalf("Failed to load examples test registry: %v", err)
+	}
+	msg, _ := registry.FindMessage("examples.v1.Account")
+
+	tests := []struct {
+		field, value string
+	}{
+		{"seats", "many"},
+		{"seats", "3000000000"},
+		{"verified", "yes please"},
+		{"status", "ACCOUNT_STATUS_DELETED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field+"="+tt.value, func(t *testing.T) {
+			field := msg.Fields().ByName(protoreflect.Name(tt.field))
+			if _, err := parseExampleOption(field, exampleOptionName, tt.value); err == nil {
+				t.Errorf("Expected an error for %q on field %s", tt.value, tt.field)
+			}
+		})
+	}
+}
+
+func TestGenerateExampleJSON_AnyTypeHints(t *testing.T) {
+	registry, err := LoadDirectory(context.Background(), "testdata/examples", nil)
+	if err != nil {
+		t.Fatalf("Failed to load examples test registry: %v", err)
+	}
+	registry = registry.WithAnyTypeHints(map[string]string{
+		"examples.v1.AccountEvent.payload": "examples.v1.Account",
+		"examples.v1.AccountEvent.context": "examples.v1.Missing",
+	})
+
+	msg, exists := registry.FindMessage("examples.v1.AccountEvent")
+	if !exists {
+		t.Fatal("Message examples.v1.AccountEvent not found")
+	}
+
+	result, err := GenerateExampleJSON(msg, registry.ExampleOptions())
+	if err != nil {
+		t.Fatalf("GenerateExampleJSON() error = %v", err)
+	}
+
+	var data struct {
+		Payload map[string]any `json:"payload"`
+		Context map[string]any `json:"context"`
+	}
+	if err := json.Unmarshal([]byte(result), &data); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v\nJSON: %s", err, result)
+	}
+
+	payload := data.Payload
+	if payload["@type"] != "type.googleapis.com/examples.v1.Account" {
+		t.Errorf("Expected hinted @type, got %v", payload["@type"])
+	}
+	if payload["email"] != "foo@bar.com" || payload["status"] != "ACCOUNT_STATUS_SUSPENDED" {
+		t.Errorf("Expected Account fields in the Any example, got %v", payload)
+	}
+
+	// A hint naming an unknown message falls back to the placeholder
+	if data.Context["@type"] != "type.googleapis.com/google.protobuf.StringValue" {
+		t.Errorf("Expected placeholder @type for unhinted field, got %v", data.Context["@type"])
+	}
+
+	// The hinted Any must be accepted by protojson
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("Failed to marshal payload: %v", err)
+	}
+	unmarshal := protojson.UnmarshalOptions{Resolver: registry.Resolver()}
+	if err := unmarshal.Unmarshal(payloadJSON, &anypb.Any{}); err != nil {
+		t.Errorf("Generated Any is not valid protojson: %v\nJSON: %s", err, payloadJSON)
+	}
+}
+
+func TestGenerateExampleJSON_UseProtoNames(t *testing.T) {
+	registry, err := LoadDirectory(context.Background(), "testdata/examples", nil)
+	if err != nil {
+		t.Fatalf("Failed to load examples test registry: %v", err)
+	}
+	msg, exists := registry.FindMessage("examples.v1.Account")
+	if !exists {
+		t.Fatal("Message examples.v1.Account not found")
+	}
+
+	tests := []struct {
+		name          string
+		useProtoNames bool
+		want, notWant string
+	}{
+		{name: "JSON names", useProtoNames: false, want: "displayName", notWant: "display_name"},
+		{name: "proto names", useProtoNames: true, want: "display_name", notWant: "displayName"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultExampleOptions()
+			options.UseProtoNames = tt.useProtoNames
+			result, err := GenerateExampleJSON(msg, options)
+			if err != nil {
+				t.Fatalf("GenerateExampleJSON() error = %v", err)
+			}
+
+			var data map[string]any
+			if err := json.Unmarshal([]byte(result), &data); err != nil {
+				t.Fatalf("Generated JSON is invalid: %v\nJSON: %s", err, result)
+			}
+			if _, ok := data[tt.want]; !ok {
+				t.Errorf("Expected key %q, got %s", tt.want, result)
+			}
+			if _, ok := data[tt.notWant]; ok {
+				t.Errorf("Expected no key %q, got %s", tt.notWant, result)
+			}
+
+			// Either naming parses back into the message
+			if err := protojson.Unmarshal([]byte(result), dynamicpb.NewMessage(msg)); err != nil {
+				t.Errorf("Generated JSON does not parse: %v\nJSON: %s", err, result)
+			}
+		})
+	}
+}
+
+func TestGenerateExampleJSONSequence(t *testing.T) {
+	registry, err := LoadDirectory(context.Background(), "testdata/examples", nil)
+	if err != nil {
+		t.Fatalf("Failed to load examples test registry: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		msgName string
+		key     string
+		want    []any
+	}{
+		{name: "increments numeric field", msgName: "examples.v1.Account", key: "seats", want: []any{25.0, 26.0, 27.0}},
+		{name: "suffixes string field", msgName: "examples.v1.AccountEvent", key: "id", want: []any{"example_id", "example_id_2", "example_id_3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, exists := registry.FindMessage(tt.msgName)
+			if !exists {
+				t.Fatalf("Message %s not found", tt.msgName)
+			}
+
+			examples, err := GenerateExampleJSONSequence(msg, DefaultExampleOptions(), 3)
+			if err != nil {
+				t.Fatalf("GenerateExampleJSONSequence() error = %v", err)
+			}
+			single, _ := GenerateExampleJSON(msg, DefaultExampleOptions())
+			if len(examples) != 3 || examples[0] != single {
+				t.Fatalf("Expected 3 examples starting with the single example, got %v", examples)
+			}
+
+			var got []any
+			for _, example := range examples {
+				var data map[string]any
+				if err := json.Unmarshal([]byte(example), &data); err != nil {
+					t.Fatalf("Generated JSON is invalid: %v\nJSON: %s", err, example)
+				}
+				got = append(got, data[tt.key])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %s values %v, got %v", tt.key, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGenerateExampleJSON_CycleStrategy(t *testing.T) {
+	registry, err := LoadDirectory(context.Background(), "testdata/examples", nil)
+	if err != nil {
+		t.Fatalf("Failed to load examples test registry: %v", err)
+	}
+
+	msg, exists := registry.FindMessage("examples.v1.TreeNode")
+	if !exists {
+		t.Fatal("Message examples.v1.TreeNode not found")
+	}
+
+	tests := []struct {
+		name     string
+		strategy CycleStrategy
+		want     map[string]any
+	}{
+		{name: "default omits", strategy: "", want: map[string]any{"name": "example_name"}},
+		{name: "omit", strategy: CycleOmit, want: map[string]any{"name": "example_name"}},
+		{name: "null", strategy: CycleNull, want: map[string]any{
+			"name":           "example_name",
+			"parent":         nil,
+			"children":       nil,
+			"childrenByName": nil,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultExampleOptions()
+			options.CycleStrategy = tt.strategy
+
+			result, err := GenerateExampleJSON(msg, options)
+			if err != nil {
+				t.Fatalf("GenerateExampleJSON() error = %v", err)
+			}
+
+			var data map[string]any
+			if err := json.Unmarshal([]byte(result), &data); err != nil {
+				t.Fatalf("Generated JSON is invalid: %v\nJSON: %s", err, result)
+			}
+			if !reflect.DeepEqual(data, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, data)
+			}
+
+			// The example must be accepted by protojson as-is
+			if err := protojson.Unmarshal([]byte(result), dynamicpb.NewMessage(msg)); err != nil {
+				t.Errorf("Generated JSON is not valid protojson: %v\nJSON: %s", err, result)
+			}
+		})
+	}
+
+	t.Run("placeholder", func(t *testing.T) {
+		options := DefaultExampleOptions()
+		options.CycleStrategy = CyclePlaceholder
+
+		result, err := GenerateExampleJSON(msg, options)
+		if err != nil {
+			t.Fatalf("GenerateExampleJSON() error = %v", err)
+		}
+		if !strings.Contains(result, "recursive") {
+			t.Errorf("Expected recursive placeholder, got %s", result)
+		}
+	})
+}
+
+func TestGenerateExampleJSON_CustomExampleOption(t *testing.T) {
+	registry, err := LoadDirectory(context.Background(), "testdata/examples", nil)
+	if err != nil {
+		t.Fatalf("Failed to load examples test registry: %v", err)
+	}
+
+	msg, exists := registry.FindMessage("examples.v1.Company")
+	if !exists {
+		t.Fatal("Message examples.v1.Company not found")
+	}
+
+	tests := []struct {
+		name     string
+		registry *Registry
+		want     map[string]any
+	}{
+		{
+			name:     "built-in option",
+			registry: registry,
+			want: map[string]any{
+				"name":         "example_name",
+				"headquarters": map[string]any{"city": "example_city", "postalCode": "example_postal_code"},
+				"branches":     []any{map[string]any{"city": "example_city", "postalCode": "example_postal_code"}},
+				"tags":         []any{"example_tags", "example_tags"},
+				"headcount":    map[string]any{"example_key": float64(42), "example_key_1": float64(42)},
+				"founded":      float64(42),
+				"parentOffice": map[string]any{"city": "Capital City"},
+			},
+		},
+		{
+			name:     "configured option",
+			registry: registry.WithExampleOption("examples.v1.sample"),
+			want: map[string]any{
+				"name":         "ACME Corp",
+				"headquarters": map[string]any{"city": "Springfield", "postalCode": "49007"},
+				"branches":     []any{map[string]any{"city": "Shelbyville"}},
+				"tags":         []any{"b2b", "saas"},
+				"headcount":    map[string]any{"engineering": float64(40)},
+				"founded":      float64(42),
+				"parentOffice": map[string]any{"city": "example_city", "postalCode": "example_postal_code"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GenerateExampleJSON(msg, tt.registry.ExampleOptions())
+			if err != nil {
+				t.Fatalf("GenerateExampleJSON() error = %v", err)
+			}
+
+			var data map[string]any
+			if err := json.Unmarshal([]byte(result), &data); err != nil {
+				t.Fatalf("Generated JSON is invalid: %v\nJSON: %s", err, result)
+			}
+			if !reflect.DeepEqual(data, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, data)
+			}
+
+			// The example must be accepted by protojson as-is
+			if err := protojson.Unmarshal([]byte(result), dynamicpb.NewMessage(msg)); err != nil {
+				t.Errorf("Generated JSON is not valid protojson: %v\nJSON: %s", err, result)
+			}
+		})
+	}
+}
+
+func TestParseJSONExample(t *testing.T) {
+	registry, err := LoadDirectory(context.Background(), "testdata/examples", nil)
+	if err != nil {
+		t.Fatalf("Failed to load examples test registry: %v", err)
+	}
+	msg, _ := registry.FindMessage("examples.v1.Company")
+
+	tests := []struct {
+		field, value string
+	}{
+		{"headquarters", "Springfield"},
+		{"headquarters", `["Springfield"]`},
+		{"branches", `{"city": "Shelbyville"}`},
+		{"headcount", `[40]`},
+		{"tags", `["b2b"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field+"="+tt.value, func(t *testing.T) {
+			field := msg.Fields().ByName(protoreflect.Name(tt.field))
+			if _, err := parseJSONExample(field, exampleOptionName, tt.value); err == nil {
+				t.Errorf("Expected an error for %q on field %s", tt.value, tt.field)
+			}
+		})
+	}
+}
+
+func TestGenerateExampleJSON_RepeatedCount(t *testing.T) {
+	registry, err := LoadDirectory(context.Background(), "testdata/comprehensive", nil)
+	if err != nil {
+		t.Fatalf("Failed to load comprehensive test registry: %v", err)
+	}
+	msg, exists := registry.FindMessage("notifications.v1.Notification")
+	if !exists {
+		t.Fatal("Message notifications.v1.Notification not found")
+	}
+
+	tests := []struct {
+		name                 string
+		repeatedCount        int
+		repeatedMessageCount int
+		wantChannels         int
+		wantActions          int
+	}{
+		{name: "defaults", wantChannels: 2, wantActions: 1},
+		{name: "custom counts", repeatedCount: 5, repeatedMessageCount: 3, wantChannels: 5, wantActions: 3},
+		{name: "clamped", repeatedCount: 1000, repeatedMessageCount: 1000, wantChannels: maxRepeatedCount, wantActions: maxRepeatedCount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultExampleOptions()
+			options.RepeatedCount = tt.repeatedCount
+			options.RepeatedMessageCount = tt.repeatedMessageCount
+
+			result, err := GenerateExampleJSON(msg, options)
+			if err != nil {
+				t.Fatalf("GenerateExampleJSON() error = %v", err)
+			}
+			var data map[string]any
+			if err := json.Unmarshal([]byte(result), &data); err != nil {
+				t.Fatalf("Generated JSON is invalid: %v", err)
+			}
+
+			if got := len(data["channels"].([]any)); got != tt.wantChannels {
+				t.Errorf("Expected %d channels, got %d", tt.wantChannels, got)
+			}
+			if got := len(data["actions"].([]any)); got != tt.wantActions {
+				t.Errorf("Expected %d actions, got %d", tt.wantActions, got)
+			}
+
+			// The binary example generates the same number of items
+			wire, err := GenerateExampleBinary(msg, options)
+			if err != nil {
+				t.Fatalf("GenerateExampleBinary() error = %v", err)
+			}
+			decoded := dynamicpb.NewMessage(msg)
+			if err := proto.Unmarshal(wire, decoded); err != nil {
+				t.Fatalf("Generated bytes do not unmarshal: %v", err)
+			}
+			if got := decoded.Get(msg.Fields().ByName("actions")).List().Len(); got != tt.wantActions {
+				t.Errorf("Expected %d binary actions, got %d", tt.wantActions, got)
+			}
+		})
+	}
+}
+
+func TestGenerateExampleJSON_RepeatedCountMaxDepth(t *testing.T) {
+	registry, err := LoadDirectory(context.Background(), "testdata/comprehensive", nil)
+	if err != nil {
+		t.Fatalf("Failed to load comprehensive test registry: %v", err)
+	}
+	msg, exists := registry.FindMessage("notifications.v1.Notification")
+	if !exists {
+		t.Fatal("Message notifications.v1.Notification not found")
+	}
+
+	options := DefaultExampleOptions()
+	options.MaxDepth = 1
+	options.RepeatedMessageCount = 4
+
+	result, err := GenerateExampleJSON(msg, options)
+	if err != nil {
+		t.Fatalf("GenerateExampleJSON() error = %v", err)
+	}
+	var data map[string]any
+	if err := json.Unmarshal([]byte(result), &data); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	// Every item is still generated, but none descends past MaxDepth
+	actions := data["actions"].([]any)
+	if len(actions) != 4 {
+		t.Fatalf("Expected 4 actions, got %d", len(actions))
+	}
+	for i, action := range actions {
+		want := map[string]any{"<max_depth_reached>": true}
+		if !reflect.DeepEqual(action, want) {
+			t.Errorf("Expected action %d to stop at max depth, got %v", i, action)
+		}
+	}
+}
+
+func TestGenerateExampleJSON_Proto2Defaults(t *testing.T) {
+	registry, err := LoadDirectory(context.Background(), "testdata/proto2", nil)
+	if err != nil {
+		t.Fatalf("Failed to load proto2 test registry: %v", err)
+	}
+
+	msg, exists := registry.FindMessage("legacy.v1.Account")
+	if !exists {
+		t.Fatal("Message legacy.v1.Account not found")
+	}
+
+	for _, emitDefaults := range []bool{false, true} {
+		options := DefaultExampleOptions()
+		options.EmitDefaults = emitDefaults
+		options.Realistic = true
+
+		result, err := GenerateExampleJSON(msg, options)
+		if err != nil {
+			t.Fatalf("GenerateExampleJSON() error = %v", err)
+		}
+
+		var data map[string]any
+		if err := json.Unmarshal([]byte(result), &data); err != nil {
+			t.Fatalf("Generated JSON is invalid: %v", err)
+		}
+
+		want := map[string]any{
+			"name":        "anonymous",
+			"maxSessions": float64(5),
+			"active":      true,
+			"tier":        "TIER_BASIC",
+			"ratio":       "Infinity",
+		}
+		for field, value := range want {
+			if data[field] != value {
+				t.Errorf("EmitDefaults=%v: %s = %v, want the declared default %v", emitDefaults, field, data[field], value)
+			}
+		}
+		if address, ok := data["address"].(map[string]any); !ok || address["city"] != "Springfield" {
+			t.Errorf("EmitDefaults=%v: expected the group to use its declared default, got %v", emitDefaults, data["address"])
+		}
+
+		// The example must parse back into the message
+		if err := protojson.Unmarshal([]byte(result), dynamicpb.NewMessage(msg)); err != nil {
+			t.Errorf("EmitDefaults=%v: protojson.Unmarshal() error = %v", emitDefaults, err)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package descriptor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// GenerateExampleTextProto generates a protobuf text format example for a
+// message type. The example holds the same values as GenerateExampleJSON:
+// they are loaded into a dynamic message, which is then marshaled as text.
+// Enums are written by name and Any fields are expanded.
+func GenerateExampleTextProto(msg protoreflect.MessageDescriptor, options ExampleOptions) (string, error) {
+	if msg == nil {
+		return "", fmt.Errorf("message descriptor is nil")
+	}
+
+	message, resolver, err := exampleMessage(msg, options)
+	if err != nil {
+		return "", err
+	}
+
+	text, err := prototext.MarshalOptions{Multiline: true, Indent: "  ", Resolver: resolver}.Marshal(message)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal text format: %w", err)
+	}
+
+	return stabilizeTextProto(string(text)), nil
+}
+
+// exampleMessage builds a dynamic message holding the values of
+// GenerateExampleJSON, along with the resolver for its Any fields, so every
+// example format shows the same values.
+func exampleMessage(msg protoreflect.MessageDescriptor, options ExampleOptions) (*dynamicpb.Message, exampleResolver, error) {
+	resolver := newExampleResolver(msg, options)
+
+	exampleJSON, err := GenerateExampleJSON(msg, options)
+	if err != nil {
+		return nil, resolver, err
+	}
+	decoder := json.NewDecoder(strings.NewReader(exampleJSON))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, resolver, fmt.Errorf("failed to decode example: %w", err)
+	}
+
+	message := dynamicpb.NewMessage(msg)
+	if err := loadExampleValue(message, value, resolver); err != nil {
+		return nil, resolver, fmt.Errorf("failed to load example into message: %w", err)
+	}
+	return message, resolver, nil
+}
+
+// loadExampleValue sets a decoded JSON example on a message. Examples are
+// mostly protojson, but well-known types are written field by field (e.g. a
+// Timestamp as {"seconds": ...}), so an object protojson rejects is loaded
+// one field at a time instead. Keys that are not fields, such as the depth
+// and cycle placeholders, are skipped.
+func loadExampleValue(msg protoreflect.Message, value any, resolver exampleResolver) error {
+	unmarshal := protojson.UnmarshalOptions{DiscardUnknown: true, Resolver: resolver}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	err = unmarshal.Unmarshal(data, msg.Interface())
+	object, ok := value.(map[string]any)
+	if err == nil || !ok {
+		return err
+	}
+
+	proto.Reset(msg.Interface())
+	fields := msg.Descriptor().Fields()
+	for key, fieldValue := range object {
+		field := fields.ByJSONName(key)
+		if field == nil {
+			field = fields.ByName(protoreflect.Name(key))
+		}
+		if field == nil || fieldValue == nil {
+			continue
+		}
+		if err := loadExampleField(msg, field, fieldValue, resolver); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name(), err)
+		}
+	}
+	return nil
+}
+
+// loadExampleField sets one field of a message from its decoded JSON example.
+func loadExampleField(msg protoreflect.Message, field protoreflect.FieldDescriptor, value any, resolver exampleResolver) error {
+	switch {
+	case field.IsMap():
+		entries, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected a JSON object, got %T", value)
+		}
+		m := msg.Mutable(field).Map()
+		for key, entry := range entries {
+			mapKey, err := exampleScalarValue(field.MapKey(), key)
+			if err != nil {
+				return err
+			}
+			item, err := exampleFieldValue(field.MapValue(), entry, m.NewValue, resolver)
+			if err != nil {
+				return err
+			}
+			m.Set(mapKey.MapKey(), item)
+		}
+	case field.IsList():
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("expected a JSON array, got %T", value)
+		}
+		list := msg.Mutable(field).List()
+		for _, entry := range items {
+			item, err := exampleFieldValue(field, entry, list.NewElement, resolver)
+			if err != nil {
+				return err
+			}
+			list.Append(item)
+		}
+	default:
+		item, err := exampleFieldValue(field, value, func() protoreflect.Value { return msg.NewField(field) }, resolver)
+		if err != nil {
+			return err
+		}
+		msg.Set(field, item)
+	}
+	return nil
+}
+
+// exampleFieldValue converts one decoded JSON value of a field (or of one of
+// its items) to a protobuf value. newMessage returns an empty message value
+// for message fields.
+func exampleFieldValue(field protoreflect.FieldDescriptor, value any, newMessage func() protoreflect.Value, resolver exampleResolver) (protoreflect.Value, error) {
+	if field.Message() == nil {
+		return exampleScalarValue(field, value)
+	}
+	item := newMessage()
+	if err := loadExampleValue(item.Message(), value, resolver); err != nil {
+		return protoreflect.Value{}, err
+	}
+	return item, nil
+}
+
+// exampleScalarValue converts a decoded JSON value to the protobuf value of a
+// non-message field. Numbers may be JSON numbers or strings, as protojson
+// writes 64-bit integers and map keys as strings.
+func exampleScalarValue(field protoreflect.FieldDescriptor, value any) (protoreflect.Value, error) {
+	text := fmt.Sprint(value)
+	invalid := func(err error) (protoreflect.Value, error) {
+		return protoreflect.Value{}, fmt.Errorf("invalid %s value %q: %w", field.Kind(), text, err)
+	}
+
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(text)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfBool(v), nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(text, 10, 32)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfInt32(int32(v)), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfInt64(v), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := strconv.ParseUint(text, 10, 32)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfUint32(uint32(v)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfUint64(v), nil
+	case protoreflect.FloatKind:
+		v, err := strconv.ParseFloat(text, 32)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfFloat32(float32(v)), nil
+	case protoreflect.DoubleKind:
+		v, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfFloat64(v), nil
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(text), nil
+	case protoreflect.BytesKind:
+		v, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return invalid(err)
+		}
+		return protoreflect.ValueOfBytes(v), nil
+	case protoreflect.EnumKind:
+		if enumValue := field.Enum().Values().ByName(protoreflect.Name(text)); enumValue != nil {
+			return protoreflect.ValueOfEnum(enumValue.Number()), nil
+		}
+		v, err := strconv.ParseInt(text, 10, 32)
+		if err != nil {
+			return invalid(fmt.Errorf("no such value in %s", field.Enum().FullName()))
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v)), nil
+	default:
+		return invalid(fmt.Errorf("unsupported field kind"))
+	}
+}
+
+// exampleResolver resolves the message types an example may refer to in Any
+// fields: those in the files behind the example and its Any type hints, then
+// the linked-in well-known types.
+type exampleResolver struct {
+	*dynamicpb.Types
+}
+
+// newExampleResolver returns the resolver for examples of msg.
+func newExampleResolver(msg protoreflect.MessageDescriptor, options ExampleOptions) exampleResolver {
+	files := new(protoregistry.Files)
+	registerFile(files, msg.ParentFile())
+	for _, hinted := range options.AnyTypes {
+		registerFile(files, hinted.ParentFile())
+	}
+	return exampleResolver{dynamicpb.NewTypes(files)}
+}
+
+// registerFile adds a file and its imports to files, skipping files already
+// registered.
+func registerFile(files *protoregistry.Files, file protoreflect.FileDescriptor) {
+	if _, err := files.FindFileByPath(file.Path()); err == nil {
+		return
+	}
+	if err := files.RegisterFile(file); err != nil {
+		return
+	}
+	imports := file.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		registerFile(files, imports.Get(i).FileDescriptor)
+	}
+}
+
+// FindMessageByURL resolves a type URL, falling back to the global types.
+func (r exampleResolver) FindMessageByURL(url string) (protoreflect.MessageType, error) {
+	if mt, err := r.Types.FindMessageByURL(url); err == nil {
+		return mt, nil
+	}
+	return protoregistry.GlobalTypes.FindMessageByURL(url)
+}
+
+// FindMessageByName resolves a message name, falling back to the global types.
+func (r exampleResolver) FindMessageByName(name protoreflect.FullName) (protoreflect.MessageType, error) {
+	if mt, err := r.Types.FindMessageByName(name); err == nil {
+		return mt, nil
+	}
+	return protoregistry.GlobalTypes.FindMessageByName(name)
+}
+
+// stabilizeTextProto removes the extra space prototext randomly writes after
+// field names, so the same example always yields the same text.
+func stabilizeTextProto(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		name, rest, ok := strings.Cut(line, ":")
+		if ok && !strings.ContainsAny(name, `"'`) {
+			lines[i] = name + ": " + strings.TrimLeft(rest, " ")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,106 @@
+package descriptor
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestGenerateExampleTextProto_Golden(t *testing.T) {
+	tests := []struct {
+		name     string
+		dir      string
+		msgName  string
+		filename string
+	}{
+		{
+			name:     "basic echo request",
+			dir:      "testdata/basic",
+			msgName:  "echo.v1.EchoRequest",
+			filename: "basic_echo_request.textproto",
+		},
+		{
+			name:     "enums, maps, and timestamps",
+			dir:      "testdata/comprehensive",
+			msgName:  "notifications.v1.Notification",
+			filename: "notification.textproto",
+		},
+		{
+			name:     "oneof message",
+			dir:      "testdata/comprehensive",
+			msgName:  "users.v1.SyncUsersRequest",
+			filename: "sync_users_request.textproto",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry, err := LoadDirectory(context.Background(), tt.dir, nil)
+			if err != nil {
+				t.Fatalf("Failed to load test registry: %v", err)
+			}
+			msg, exists := registry.FindMessage(tt.msgName)
+			if !exists {
+				t.Fatalf("Message %s not found", tt.msgName)
+			}
+
+			result, err := GenerateExampleTextProto(msg, DefaultExampleOptions())
+			if err != nil {
+				t.Fatalf("GenerateExampleTextProto() error = %v", err)
+			}
+
+			// The example must read back as text format
+			if err := prototext.Unmarshal([]byte(result), dynamicpb.NewMessage(msg)); err != nil {
+				t.Errorf("Generated text is not valid text format: %v\nText: %s", err, result)
+			}
+
+			compareWithGolden(t, result, filepath.Join("testdata/golden", tt.filename))
+		})
+	}
+}
+
+func TestGenerateExampleTextProto_AnyTypeHints(t *testing.T) {
+	registry, err := LoadDirectory(context.Background(), "testdata/examples", nil)
+	if err != nil {
+		t.Fatalf("Failed to load examples test registry: %v", err)
+	}
+	msg, _ := registry.FindMessage("examples.v1.AccountEvent")
+	account, _ := registry.FindMessage("examples.v1.Account")
+
+	options := DefaultExampleOptions()
+	options.AnyTypes = map[string]protoreflect.MessageDescriptor{"examples.v1.AccountEvent.payload": account}
+	result, err := GenerateExampleTextProto(msg, options)
+	if err != nil {
+		t.Fatalf("GenerateExampleTextProto() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"[type.googleapis.com/examples.v1.Account]: {",
+		`email: "foo@bar.com"`,
+		"status: ACCOUNT_STATUS_SUSPENDED",
+		"[type.googleapis.com/google.protobuf.StringValue]: {",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected text to contain %q, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestGenerateExampleTextProto_NilMessage(t *testing.T) {
+	if _, err := GenerateExampleTextProto(nil, DefaultExampleOptions()); err == nil {
+		t.Error("Expected an error for a nil message")
+	}
+}
+
+func TestStabilizeTextProto(t *testing.T) {
+	input := "name:  \"a:  b\"\nnested:  {\n  count:  42\n}\n"
+	want := "name: \"a:  b\"\nnested: {\n  count: 42\n}\n"
+	if got := stabilizeTextProto(input); got != want {
+		t.Errorf("stabilizeTextProto() = %q, want %q", got, want)
+	}
+}
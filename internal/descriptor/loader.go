@@ -2,15 +2,72 @@ package descriptor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
+// LoadOptions configures how LoadDirectoryWithOptions discovers and parses files.
+type LoadOptions struct {
+	// IncludePaths are additional directories for import resolution.
+	IncludePaths []string
+
+	// IgnorePatterns are globs for files or directories to exclude from
+	// loading. Patterns use path.Match syntax per segment, plus "**" to match
+	// any number of directories. Each pattern is matched against the
+	// slash-separated path relative to root and against the base name, so
+	// "templates" skips a directory, "*.partial.proto" skips matching files
+	// anywhere, and "vendor/**" skips a tree. Ignored files take precedence
+	// over IncludeGlobs and can still be imported by other files.
+	IgnorePatterns []string
+
+	// IncludeGlobs, if set, limits loading to files whose slash-separated path
+	// relative to root matches at least one pattern, using the same syntax as
+	// IgnorePatterns, so "**/v1/*.proto" selects every v1 package.
+	IncludeGlobs []string
+
+	// ExcludeGlobs skips files and directories whose slash-separated path
+	// relative to root matches any pattern, using the same syntax as
+	// IgnorePatterns but never matching the base name alone (e.g.
+	// "vendor/**"). Exclusions take precedence over inclusions, and excluded
+	// files can still be imported by other files.
+	ExcludeGlobs []string
+
+	// AllowDuplicates loads directories where two files define the same
+	// symbol, as happens with vendored copies of a file, by skipping the
+	// earlier file in load order so the last definition wins. The whole
+	// earlier file is skipped, and loading still fails if another file
+	// imports it. By default, duplicates fail with a *DuplicateSymbolError.
+	AllowDuplicates bool
+
+	// BestEffort loads the files that parse when others do not, rather than
+	// failing the whole load. Each file that cannot be parsed, including one
+	// whose imports cannot be, is skipped and reported in
+	// Registry.LoadErrors. Loading fails only if no file parses.
+	BestEffort bool
+
+	// CacheDir, if set, caches the parsed descriptors there, keyed by a hash
+	// of the proto file contents, include paths, and options. A later load of
+	// unchanged files reads the cache instead of parsing. Entries are also
+	// invalidated when an imported file on the include paths changes.
+	CacheDir string
+}
+
 // LoadDirectory discovers and parses all .proto files in the given root directory.
 // It uses the provided includePaths for import resolution, plus the root directory itself.
 func LoadDirectory(ctx context.Context, root string, includePaths []string) (*Registry, error) {
+	return LoadDirectoryWithOptions(ctx, root, LoadOptions{IncludePaths: includePaths})
+}
+
+// LoadDirectoryWithOptions is like LoadDirectory but also filters the files it
+// loads with the configured ignore patterns and include/exclude globs.
+func LoadDirectoryWithOptions(ctx context.Context, root string, opts LoadOptions) (*Registry, error) {
+	includePaths := opts.IncludePaths
 	if root == "" {
 		return nil, fmt.Errorf("root directory cannot be empty")
 	}
@@ -25,7 +82,7 @@ func LoadDirectory(ctx context.Context, root string, includePaths []string) (*Re
 	}
 
 	// Discover all .proto files recursively
-	protoFiles, err := discoverProtoFiles(root)
+	protoFiles, err := discoverProtoFiles(root, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover proto files: %w", err)
 	}
@@ -37,46 +94,302 @@ func LoadDirectory(ctx context.Context, root string, includePaths []string) (*Re
 	// Build include paths: dedupe(append(includePaths, root))
 	allIncludePaths := dedupeStrings(append(includePaths, root))
 
+	// Reuse the descriptors from an earlier load of the same files
+	var cacheKey string
+	if opts.CacheDir != "" {
+		cacheKey, err = descriptorCacheKey(protoFiles, allIncludePaths, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash proto files: %w", err)
+		}
+		if files, fdSet, ok := readDescriptorCache(opts.CacheDir, cacheKey, allIncludePaths); ok {
+			registry, err := buildRegistry(files, fdSet)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build registry: %w", err)
+			}
+			registry.Cached = true
+			registry.Sources = readSources(fdSet, allIncludePaths)
+			return registry, nil
+		}
+	}
+
 	// Parse the files
 	files, fdSet, err := parseFiles(ctx, protoFiles, allIncludePaths)
+
+	// Skip the files that do not parse on their own, with their importers
+	var loadErrors []LoadError
+	if err != nil && opts.BestEffort {
+		protoFiles, loadErrors = parseableFiles(ctx, protoFiles, allIncludePaths)
+		if len(protoFiles) == 0 {
+			return nil, fmt.Errorf("failed to parse proto files: %w", err)
+		}
+		files, fdSet, err = parseFiles(ctx, protoFiles, allIncludePaths)
+	}
+
+	// Last write wins: skip the earlier of two files defining the same symbol
+	var dupErr *DuplicateSymbolError
+	for opts.AllowDuplicates && errors.As(err, &dupErr) {
+		remaining := withoutFile(protoFiles, dupErr.Files[0], allIncludePaths)
+		if len(remaining) == len(protoFiles) {
+			break
+		}
+		protoFiles = remaining
+		files, fdSet, err = parseFiles(ctx, protoFiles, allIncludePaths)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse proto files: %w", err)
 	}
 
+	// The cache only speeds up later loads, so a failed write is not an
+	// error. Partial loads are not cached, so a hit means every file parsed.
+	if cacheKey != "" && len(loadErrors) == 0 {
+		_ = writeDescriptorCache(opts.CacheDir, cacheKey, fdSet, protoFiles, allIncludePaths)
+	}
+
 	// Build the registry
 	registry, err := buildRegistry(files, fdSet)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build registry: %w", err)
 	}
+	registry.Sources = readSources(fdSet, allIncludePaths)
+	registry.LoadErrors = loadErrors
 
 	return registry, nil
 }
 
-// discoverProtoFiles recursively finds all .proto files in the given directory.
-func discoverProtoFiles(root string) ([]string, error) {
+// LoadError reports a proto file skipped by a best-effort load (see
+// LoadOptions.BestEffort).
+type LoadError struct {
+	// File is the file's import path (e.g. "echo/v1/echo.proto").
+	File string
+	Err  error
+}
+
+func (e LoadError) Error() string {
+	return fmt.Sprintf("%s: %v", e.File, e.Err)
+}
+
+func (e LoadError) Unwrap() error {
+	return e.Err
+}
+
+// parseableFiles parses each file on its own, with its imports, and splits
+// off those that fail. A file importing a broken file fails too, so the
+// remaining files link together.
+func parseableFiles(ctx context.Context, protoFiles []string, includePaths []string) ([]string, []LoadError) {
+	var parsed []string
+	var loadErrors []LoadError
+	for _, file := range protoFiles {
+		if _, _, err := parseFiles(ctx, []string{file}, includePaths); err != nil {
+			name, relErr := findRelativePath(file, includePaths)
+			if relErr != nil {
+				name = file
+			}
+			loadErrors = append(loadErrors, LoadError{File: name, Err: err})
+			continue
+		}
+		parsed = append(parsed, file)
+	}
+	return parsed, loadErrors
+}
+
+// LoadFile parses a single .proto file and the files it imports. Imports are
+// resolved using includePaths, plus the file's own directory.
+func LoadFile(ctx context.Context, path string, includePaths []string) (*Registry, error) {
+	if path == "" {
+		return nil, fmt.Errorf("proto file path cannot be empty")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat proto file %q: %w", path, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("proto file path %q is a directory", path)
+	}
+	if !strings.HasSuffix(strings.ToLower(path), ".proto") {
+		return nil, fmt.Errorf("proto file path %q does not have a .proto extension", path)
+	}
+
+	// Include paths come first so a file under one keeps its usual import name
+	allIncludePaths := dedupeStrings(append(includePaths, filepath.Dir(path)))
+
+	files, fdSet, err := parseFiles(ctx, []string{path}, allIncludePaths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proto file: %w", err)
+	}
+
+	registry, err := buildRegistry(files, fdSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build registry: %w", err)
+	}
+	registry.Sources = readSources(fdSet, allIncludePaths)
+
+	return registry, nil
+}
+
+// readSources reads the source of each parsed file, keyed by import path,
+// from the first include path holding it. Files found on no include path,
+// such as the well-known types, are left out; the built-in
+// reflect/options.proto is included from its embedded copy.
+func readSources(fdSet *descriptorpb.FileDescriptorSet, includePaths []string) map[string]string {
+	sources := make(map[string]string)
+	for _, file := range fdSet.GetFile() {
+		name := file.GetName()
+		for _, includePath := range includePaths {
+			data, err := os.ReadFile(filepath.Join(includePath, filepath.FromSlash(name)))
+			if err == nil {
+				sources[name] = string(data)
+				break
+			}
+		}
+		if _, ok := sources[name]; !ok && name == reflectOptionsPath {
+			sources[name] = reflectOptionsProto
+		}
+	}
+	return sources
+}
+
+// withoutFile returns protoFiles minus the file with the given import name.
+func withoutFile(protoFiles []string, name string, includePaths []string) []string {
+	var remaining []string
+	for _, file := range protoFiles {
+		if relPath, err := findRelativePath(file, includePaths); err == nil && relPath == name {
+			continue
+		}
+		remaining = append(remaining, file)
+	}
+	return remaining
+}
+
+// discoverProtoFiles recursively finds all .proto files in the given directory,
+// skipping files and directories that match any of the ignore patterns or
+// exclude globs, and files that match none of the include globs.
+func discoverProtoFiles(root string, opts LoadOptions) ([]string, error) {
+	for _, pattern := range opts.IgnorePatterns {
+		if err := validateGlob(pattern); err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+		}
+	}
+	for _, pattern := range opts.IncludeGlobs {
+		if err := validateGlob(pattern); err != nil {
+			return nil, fmt.Errorf("invalid include glob %q: %w", pattern, err)
+		}
+	}
+	for _, pattern := range opts.ExcludeGlobs {
+		if err := validateGlob(pattern); err != nil {
+			return nil, fmt.Errorf("invalid exclude glob %q: %w", pattern, err)
+		}
+	}
+
 	var protoFiles []string
 
-	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(root, func(filePath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
+		if filePath != root && (isIgnored(root, filePath, opts.IgnorePatterns) || matchesAnyGlob(root, filePath, opts.ExcludeGlobs)) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		// Skip directories
 		if info.IsDir() {
 			return nil
 		}
 
 		// Check if it's a .proto file
-		if strings.HasSuffix(strings.ToLower(path), ".proto") {
-			protoFiles = append(protoFiles, path)
+		if !strings.HasSuffix(strings.ToLower(filePath), ".proto") {
+			return nil
+		}
+
+		if len(opts.IncludeGlobs) > 0 && !matchesAnyGlob(root, filePath, opts.IncludeGlobs) {
+			return nil
 		}
 
+		protoFiles = append(protoFiles, filePath)
 		return nil
 	})
 
 	return protoFiles, err
 }
 
+// isIgnored reports whether filePath matches any ignore pattern, either by its
+// path relative to root or by its base name.
+func isIgnored(root, filePath string, ignorePatterns []string) bool {
+	relPath, err := filepath.Rel(root, filePath)
+	if err != nil {
+		return false
+	}
+	relPath = filepath.ToSlash(relPath)
+	base := path.Base(relPath)
+
+	for _, pattern := range ignorePatterns {
+		if matchGlob(pattern, relPath) || matchGlob(pattern, base) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAnyGlob reports whether filePath, relative to root, matches any of
+// the globs.
+func matchesAnyGlob(root, filePath string, globs []string) bool {
+	relPath, err := filepath.Rel(root, filePath)
+	if err != nil {
+		return false
+	}
+	relPath = filepath.ToSlash(relPath)
+
+	for _, glob := range globs {
+		if matchGlob(glob, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateGlob checks that each segment of a glob is a valid path.Match
+// pattern.
+func validateGlob(glob string) error {
+	for _, segment := range strings.Split(glob, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchGlob matches a slash-separated path against a glob. Segments are
+// matched with path.Match, and a "**" segment matches zero or more segments.
+func matchGlob(glob, name string) bool {
+	return matchSegments(strings.Split(glob, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Try every possible number of segments for "**"
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
 // dedupeStrings removes duplicate strings from a slice while preserving order.
 func dedupeStrings(strs []string) []string {
 	seen := make(map[string]bool)
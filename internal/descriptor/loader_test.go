@@ -2,7 +2,11 @@ package descriptor
 
 import (
 	"context"
+	"errors"
+	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -129,6 +133,60 @@ func TestLoadDirectory(t *testing.T) {
 	}
 }
 
+func TestLoadFile(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("single file", func(t *testing.T) {
+		reg, err := LoadFile(ctx, filepath.Join("testdata", "basic", "echo.proto"), nil)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if _, exists := reg.FindService("echo.v1.EchoService"); !exists {
+			t.Fatal("EchoService not found")
+		}
+		if _, exists := reg.FindMethod("echo.v1.EchoService/Echo"); !exists {
+			t.Fatal("Echo method not found")
+		}
+		if reg.CommentIndex["echo.v1.EchoService"] == "" {
+			t.Error("Expected comments to be indexed")
+		}
+	})
+
+	t.Run("imports from include paths", func(t *testing.T) {
+		root := filepath.Join("testdata", "comprehensive")
+		reg, err := LoadFile(ctx, filepath.Join(root, "users", "users.proto"), []string{root})
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+		if _, exists := reg.FindService("users.v1.UserService"); !exists {
+			t.Fatal("UserService not found")
+		}
+		// Other files under the include path are not loaded
+		if _, exists := reg.FindService("orders.v1.OrderService"); exists {
+			t.Error("Expected only the given file's services")
+		}
+	})
+
+	t.Run("missing import", func(t *testing.T) {
+		path := filepath.Join("testdata", "comprehensive", "users", "users.proto")
+		if _, err := LoadFile(ctx, path, nil); err == nil {
+			t.Error("Expected error when imports cannot be resolved")
+		}
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		if _, err := LoadFile(ctx, filepath.Join("testdata", "basic"), nil); err == nil {
+			t.Error("Expected error for a directory")
+		}
+	})
+
+	t.Run("nonexistent", func(t *testing.T) {
+		if _, err := LoadFile(ctx, filepath.Join("testdata", "missing.proto"), nil); err == nil {
+			t.Error("Expected error for a missing file")
+		}
+	})
+}
+
 func TestDiscoverProtoFiles(t *testing.T) {
 	testDataDir := "testdata"
 
@@ -159,14 +217,14 @@ func TestDiscoverProtoFiles(t *testing.T) {
 		{
 			name:      "entire testdata directory",
 			root:      testDataDir,
-			wantCount: 10, // All proto files including http, comprehensive/*
+			wantCount: 24, // All proto files including http, commontypes, comments, cycle, duplicate, examples, proto2, comprehensive/*, options/*, tags/*, visibility/*
 			wantError: false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			files, err := discoverProtoFiles(tt.root)
+			files, err := discoverProtoFiles(tt.root, LoadOptions{})
 			if tt.wantError {
 				if err == nil {
 					t.Fatal("Expected error but got none")
@@ -225,3 +283,357 @@ func TestDedupeStrings(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadDirectoryWithIgnorePatterns(t *testing.T) {
+	root := t.TempDir()
+
+	files := map[string]string{
+		"good.proto": `syntax = "proto3";
+package good.v1;
+message Good { string name = 1; }
+`,
+		// Files that do not parse standalone
+		"broken.partial.proto": `syntax = "proto3";
+package good.v1;
+message {{.Name}} {}
+`,
+		"templates/service.proto": `syntax = "proto3";
+service {{.Service}} {}
+`,
+	}
+	for name, content := range files {
+		fullPath := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	ctx := context.Background()
+
+	if _, err := LoadDirectory(ctx, root, nil); err == nil {
+		t.Fatal("Expected loading without ignore patterns to fail on unparseable files")
+	}
+
+	reg, err := LoadDirectoryWithOptions(ctx, root, LoadOptions{
+		IgnorePatterns: []string{"*.partial.proto", "templates"},
+	})
+	if err != nil {
+		t.Fatalf("LoadDirectoryWithOptions() error = %v", err)
+	}
+	if _, exists := reg.FindMessage("good.v1.Good"); !exists {
+		t.Error("Expected good.v1.Good to be loaded")
+	}
+
+	if _, err := LoadDirectoryWithOptions(ctx, root, LoadOptions{IgnorePatterns: []string{"["}}); err == nil {
+		t.Error("Expected error for malformed ignore pattern")
+	}
+}
+
+func TestLoadDirectoryWithGlobs(t *testing.T) {
+	root := t.TempDir()
+
+	files := map[string]string{
+		"api/users/v1/users.proto": `syntax = "proto3";
+package users.v1;
+message User { string id = 1; }
+`,
+		"api/users/v2/users.proto": `syntax = "proto3";
+package users.v2;
+message User { string id = 1; }
+`,
+		"billing/v1/billing.proto": `syntax = "proto3";
+package billing.v1;
+message Invoice { string id = 1; }
+`,
+		// Does not parse, so loading fails unless the vendor tree is excluded
+		"vendor/github.com/acme/v1/acme.proto": `syntax = "proto3";
+message {
+`,
+	}
+	for name, content := range files {
+		fullPath := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	ctx := context.Background()
+
+	t.Run("exclude vendor", func(t *testing.T) {
+		reg, err := LoadDirectoryWithOptions(ctx, root, LoadOptions{
+			ExcludeGlobs: []string{"vendor/**"},
+		})
+		if err != nil {
+			t.Fatalf("LoadDirectoryWithOptions() error = %v", err)
+		}
+		for _, name := range []string{"users.v1.User", "users.v2.User", "billing.v1.Invoice"} {
+			if _, exists := reg.FindMessage(name); !exists {
+				t.Errorf("Expected %s to be loaded", name)
+			}
+		}
+	})
+
+	t.Run("include v1 only", func(t *testing.T) {
+		reg, err := LoadDirectoryWithOptions(ctx, root, LoadOptions{
+			IncludeGlobs: []string{"**/v1/*.proto"},
+			ExcludeGlobs: []string{"vendor/**"},
+		})
+		if err != nil {
+			t.Fatalf("LoadDirectoryWithOptions() error = %v", err)
+		}
+		for _, name := range []string{"users.v1.User", "billing.v1.Invoice"} {
+			if _, exists := reg.FindMessage(name); !exists {
+				t.Errorf("Expected %s to be loaded", name)
+			}
+		}
+		if _, exists := reg.FindMessage("users.v2.User"); exists {
+			t.Error("Expected users.v2.User to be skipped")
+		}
+	})
+
+	t.Run("ignore vendor tree", func(t *testing.T) {
+		if _, err := LoadDirectoryWithOptions(ctx, root, LoadOptions{IgnorePatterns: []string{"vendor/**"}}); err != nil {
+			t.Fatalf("LoadDirectoryWithOptions() error = %v", err)
+		}
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		if _, err := LoadDirectoryWithOptions(ctx, root, LoadOptions{IncludeGlobs: []string{"**/v3/*.proto"}}); err == nil {
+			t.Error("Expected error when no files match the include globs")
+		}
+	})
+
+	t.Run("invalid glob", func(t *testing.T) {
+		if _, err := LoadDirectoryWithOptions(ctx, root, LoadOptions{IncludeGlobs: []string{"api/["}}); err == nil {
+			t.Error("Expected error for malformed include glob")
+		}
+		if _, err := LoadDirectoryWithOptions(ctx, root, LoadOptions{ExcludeGlobs: []string{"vendor/["}}); err == nil {
+			t.Error("Expected error for malformed exclude glob")
+		}
+	})
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		glob, name string
+		want       bool
+	}{
+		{"**/v1/*.proto", "v1/a.proto", true},
+		{"**/v1/*.proto", "api/users/v1/a.proto", true},
+		{"**/v1/*.proto", "api/users/v1/nested/a.proto", false},
+		{"**/v1/*.proto", "api/users/v2/a.proto", false},
+		{"vendor/**", "vendor", true},
+		{"vendor/**", "vendor/github.com/acme/a.proto", true},
+		{"vendor/**", "api/vendor/a.proto", false},
+		{"**", "anything/at/all.proto", true},
+		{"api/*.proto", "api/a.proto", true},
+		{"api/*.proto", "api/v1/a.proto", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.glob, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.glob, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadDirectoryImportCycle(t *testing.T) {
+	_, err := LoadDirectory(context.Background(), filepath.Join("testdata", "cycle"), nil)
+	if err == nil {
+		t.Fatal("Expected error for mutually importing files")
+	}
+
+	var cycleErr *ImportCycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected ImportCycleError, got %v", err)
+	}
+	want := []string{"a.proto", "b.proto", "a.proto"}
+	if !reflect.DeepEqual(cycleErr.Cycle, want) {
+		t.Errorf("Expected cycle %v, got %v", want, cycleErr.Cycle)
+	}
+	if !strings.Contains(err.Error(), "import cycle: a.proto → b.proto → a.proto") {
+		t.Errorf("Expected readable cycle path in error, got %q", err.Error())
+	}
+}
+
+func TestLoadDirectoryDuplicateSymbol(t *testing.T) {
+	dir := filepath.Join("testdata", "duplicate")
+
+	_, err := LoadDirectory(context.Background(), dir, nil)
+	if err == nil {
+		t.Fatal("Expected error for files defining the same message")
+	}
+
+	var dupErr *DuplicateSymbolError
+	if !errors.As(err, &dupErr) {
+		t.Fatalf("Expected DuplicateSymbolError, got %v", err)
+	}
+	if dupErr.Symbol != "dup.v1.Thing" {
+		t.Errorf("Expected symbol dup.v1.Thing, got %q", dupErr.Symbol)
+	}
+	if !strings.Contains(err.Error(), `symbol "dup.v1.Thing" is defined in both a.proto and b.proto`) {
+		t.Errorf("Expected error naming both files, got %q", err.Error())
+	}
+
+	// With AllowDuplicates, the later file's definition wins
+	reg, err := LoadDirectoryWithOptions(context.Background(), dir, LoadOptions{AllowDuplicates: true})
+	if err != nil {
+		t.Fatalf("LoadDirectoryWithOptions() error = %v", err)
+	}
+	thing, ok := reg.FindMessage("dup.v1.Thing")
+	if !ok {
+		t.Fatal("Expected dup.v1.Thing to be loaded")
+	}
+	if thing.Fields().ByName("name") == nil {
+		t.Errorf("Expected the definition from b.proto, got fields of %s", thing.ParentFile().Path())
+	}
+}
+
+func TestLoadDirectoryBestEffort(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	writeFile("good.proto", "syntax = \"proto3\";\npackage good.v1;\nmessage Good { string id = 1; }\n")
+	writeFile("broken.proto", "syntax = \"proto3\";\npackage broken.v1;\nmessage Broken { string id = 1 }\n")
+	writeFile("importer.proto", "syntax = \"proto3\";\npackage importer.v1;\nimport \"broken.proto\";\nmessage Importer { broken.v1.Broken broken = 1; }\n")
+
+	if _, err := LoadDirectory(ctx, root, nil); err == nil {
+		t.Fatal("Expected a broken file to fail the load by default")
+	}
+
+	cacheDir := t.TempDir()
+	reg, err := LoadDirectoryWithOptions(ctx, root, LoadOptions{BestEffort: true, CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("LoadDirectoryWithOptions() error = %v", err)
+	}
+	if _, ok := reg.FindMessage("good.v1.Good"); !ok {
+		t.Error("Expected good.v1.Good to be loaded")
+	}
+	if _, ok := reg.FindMessage("importer.v1.Importer"); ok {
+		t.Error("Expected the file importing the broken file to be skipped")
+	}
+
+	// The broken file and its importer are reported, in load order
+	var skipped []string
+	for _, loadErr := range reg.LoadErrors {
+		skipped = append(skipped, loadErr.File)
+		if loadErr.Err == nil || !strings.Contains(loadErr.Error(), "broken.proto:3") {
+			t.Errorf("Expected %s to report the syntax error in broken.proto, got %v", loadErr.File, loadErr)
+		}
+	}
+	if want := []string{"broken.proto", "importer.proto"}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("Expected skipped files %v, got %v", want, skipped)
+	}
+
+	// Partial loads are not cached
+	if entries, _ := os.ReadDir(cacheDir); len(entries) != 0 {
+		t.Errorf("Expected no cache entries after a partial load, got %d", len(entries))
+	}
+
+	// Loading fails when no file parses
+	if err := os.Remove(filepath.Join(root, "good.proto")); err != nil {
+		t.Fatalf("Failed to remove good.proto: %v", err)
+	}
+	if _, err := LoadDirectoryWithOptions(ctx, root, LoadOptions{BestEffort: true}); err == nil {
+		t.Error("Expected an error when every file fails to parse")
+	}
+}
+
+func TestLoadDirectoryCache(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	include := t.TempDir()
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	writeFile(filepath.Join(include, "shared.proto"), "syntax = \"proto3\";\npackage shared.v1;\nmessage Ref { string id = 1; }\n")
+	writeFile(filepath.Join(root, "thing.proto"), "syntax = \"proto3\";\npackage cache.v1;\nimport \"shared.proto\";\n// A thing.\nmessage Thing { shared.v1.Ref ref = 1; }\n")
+
+	opts := LoadOptions{IncludePaths: []string{include}, CacheDir: cacheDir}
+	load := func() *Registry {
+		t.Helper()
+		reg, err := LoadDirectoryWithOptions(ctx, root, opts)
+		if err != nil {
+			t.Fatalf("LoadDirectoryWithOptions() error = %v", err)
+		}
+		return reg
+	}
+
+	if reg := load(); reg.Cached {
+		t.Error("Expected the first load to parse the files")
+	}
+
+	reg := load()
+	if !reg.Cached {
+		t.Error("Expected the second load to use the cache")
+	}
+	if _, ok := reg.FindMessage("cache.v1.Thing"); !ok {
+		t.Error("Expected cached registry to contain cache.v1.Thing")
+	}
+	if reg.CommentIndex["cache.v1.Thing"] != "A thing." {
+		t.Errorf("Expected comments to survive the cache, got %q", reg.CommentIndex["cache.v1.Thing"])
+	}
+
+	// Changing a loaded file busts the cache
+	writeFile(filepath.Join(root, "thing.proto"), "syntax = \"proto3\";\npackage cache.v1;\nimport \"shared.proto\";\nmessage Thing { shared.v1.Ref ref = 1; string name = 2; }\n")
+	reg = load()
+	if reg.Cached {
+		t.Error("Expected a modified file to bust the cache")
+	}
+	if thing, _ := reg.FindMessage("cache.v1.Thing"); thing == nil || thing.Fields().ByName("name") == nil {
+		t.Error("Expected the modified message after the cache was busted")
+	}
+	if !load().Cached {
+		t.Error("Expected the modified files to be cached again")
+	}
+
+	// So does changing an import from the include paths
+	writeFile(filepath.Join(include, "shared.proto"), "syntax = \"proto3\";\npackage shared.v1;\nmessage Ref { string id = 1; string kind = 2; }\n")
+	reg = load()
+	if reg.Cached {
+		t.Error("Expected a modified import to bust the cache")
+	}
+	if ref, _ := reg.FindMessage("shared.v1.Ref"); ref == nil || ref.Fields().ByName("kind") == nil {
+		t.Error("Expected the modified import after the cache was busted")
+	}
+}
+
+func TestLoadDirectorySources(t *testing.T) {
+	registry, err := LoadDirectory(context.Background(), "testdata/examples", nil)
+	if err != nil {
+		t.Fatalf("LoadDirectory() error = %v", err)
+	}
+
+	want, err := os.ReadFile(filepath.Join("testdata", "examples", "examples.proto"))
+	if err != nil {
+		t.Fatalf("Failed to read examples.proto: %v", err)
+	}
+	if source, ok := registry.Source("examples.proto"); !ok || source != string(want) {
+		t.Errorf("Expected the source of examples.proto, got %q (found %v)", source, ok)
+	}
+
+	// The built-in options file has an embedded source; the well-known types
+	// are on no include path, so they have none
+	if _, ok := registry.Source(reflectOptionsPath); !ok {
+		t.Errorf("Expected the source of %s", reflectOptionsPath)
+	}
+	if _, ok := registry.Source("google/protobuf/any.proto"); ok {
+		t.Error("Expected no source for google/protobuf/any.proto")
+	}
+}
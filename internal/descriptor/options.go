@@ -0,0 +1,281 @@
+package descriptor
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// reflectOptionsPath is the import path of the built-in options file.
+const reflectOptionsPath = "reflect/options.proto"
+
+// reflectOptionsProto defines Reflect's custom options, such as (reflect.example).
+//
+//go:embed reflect/options.proto
+var reflectOptionsProto string
+
+// exampleOptionName and exampleOptionNumber identify the (reflect.example)
+// field option.
+const (
+	exampleOptionName   protoreflect.FullName = "reflect.example"
+	exampleOptionNumber protowire.Number      = 50100
+)
+
+// DefaultTagOption is the method option read for method tags unless the
+// registry names another.
+const DefaultTagOption = "reflect.tags"
+
+// lookupBuiltinImport resolves imports of Reflect's built-in proto files. It is
+// only consulted for imports not found on the include paths, so a local copy
+// of the file takes precedence.
+func lookupBuiltinImport(path string) (*descriptorpb.FileDescriptorProto, error) {
+	if path != reflectOptionsPath {
+		return nil, fmt.Errorf("file %q not found: %w", path, os.ErrNotExist)
+	}
+
+	parser := protoparse.Parser{
+		Accessor:              protoparse.FileContentsFromMap(map[string]string{path: reflectOptionsProto}),
+		IncludeSourceCodeInfo: true,
+	}
+	fds, err := parser.ParseFilesButDoNotLink(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse built-in %s: %w", path, err)
+	}
+	return fds[0], nil
+}
+
+// exampleOption returns the example option set on a field, if any: the given
+// string field option, or (reflect.example) if ext is nil. Like the internal
+// markers, the option may be a resolved extension or left as an unknown
+// field, so both are checked.
+func exampleOption(field protoreflect.FieldDescriptor, ext protoreflect.ExtensionDescriptor) (string, bool) {
+	name, number := exampleOptionName, exampleOptionNumber
+	if ext != nil {
+		name, number = ext.FullName(), ext.Number()
+	}
+
+	opts := field.Options()
+	if opts == nil {
+		return "", false
+	}
+	m := opts.ProtoReflect()
+	if !m.IsValid() {
+		return "", false
+	}
+
+	var value string
+	found := false
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.IsExtension() && fd.FullName() == name {
+			value, found = v.String(), true
+			return false
+		}
+		return true
+	})
+	if found {
+		return value, true
+	}
+
+	b := m.GetUnknown()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return "", false
+		}
+		b = b[n:]
+
+		if num == number && typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return "", false
+			}
+			return string(v), true
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return "", false
+		}
+		b = b[n:]
+	}
+
+	return "", false
+}
+
+// MethodTags returns the tags set on a method through the registry's tag
+// option, in declaration order. The option must be a string (or repeated
+// string) extension of google.protobuf.MethodOptions known to the registry;
+// otherwise there are no tags.
+func (r *Registry) MethodTags(method protoreflect.MethodDescriptor) []string {
+	name := r.TagOption
+	if name == "" {
+		name = DefaultTagOption
+	}
+	d, err := r.Files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil
+	}
+	ext, ok := d.(protoreflect.ExtensionDescriptor)
+	if !ok || ext.Kind() != protoreflect.StringKind || ext.ContainingMessage().FullName() != "google.protobuf.MethodOptions" {
+		return nil
+	}
+
+	// Marshaling yields the option whether it was resolved as an extension
+	// or kept as an unknown field
+	b, err := proto.Marshal(method.Options())
+	if err != nil {
+		return nil
+	}
+	var tags []string
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return tags
+		}
+		b = b[n:]
+
+		if num == ext.Number() && typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return tags
+			}
+			if tag := strings.TrimSpace(string(v)); tag != "" && !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+			b = b[n:]
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return tags
+		}
+		b = b[n:]
+	}
+	return tags
+}
+
+// exampleOptionFullName returns the name of the option read by exampleOption.
+func exampleOptionFullName(ext protoreflect.ExtensionDescriptor) protoreflect.FullName {
+	if ext == nil {
+		return exampleOptionName
+	}
+	return ext.FullName()
+}
+
+// isJSONExample reports whether a field's example is written as JSON rather
+// than plain text: always for message and map fields, and for repeated scalar
+// fields whose example is a JSON array.
+func isJSONExample(field protoreflect.FieldDescriptor, value string) bool {
+	switch {
+	case field.IsMap(), field.Message() != nil:
+		return true
+	case field.IsList():
+		return strings.HasPrefix(strings.TrimSpace(value), "[")
+	default:
+		return false
+	}
+}
+
+// parseJSONExample decodes an example written as JSON, failing unless it is an
+// object for message and map fields or an array for repeated fields. The value
+// is used as written; it is not checked against the field's message type.
+func parseJSONExample(field protoreflect.FieldDescriptor, option protoreflect.FullName, value string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid (%s) %q for field %s: %w", option, value, field.FullName(), err)
+	}
+	switch v.(type) {
+	case []any:
+		if field.IsList() {
+			return v, nil
+		}
+	case map[string]any:
+		if !field.IsList() {
+			return v, nil
+		}
+	}
+	want := "a JSON object"
+	if field.IsList() {
+		want = "a JSON array"
+	}
+	return nil, fmt.Errorf("invalid (%s) %q for field %s: must be %s", option, value, field.FullName(), want)
+}
+
+// parseExampleOption converts a plain-text example option value to the JSON
+// value for the field's kind, failing if the value does not fit the kind.
+func parseExampleOption(field protoreflect.FieldDescriptor, option protoreflect.FullName, value string) (any, error) {
+	invalid := func(err error) error {
+		return fmt.Errorf("invalid (%s) %q for %s field %s: %w", option, value, field.Kind(), field.FullName(), err)
+	}
+
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return v, nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return int32(v), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return v, nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return uint32(v), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return v, nil
+	case protoreflect.FloatKind:
+		v, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return float32(v), nil
+	case protoreflect.DoubleKind:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return v, nil
+	case protoreflect.StringKind:
+		return value, nil
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString([]byte(value)), nil
+	case protoreflect.EnumKind:
+		if field.Enum().Values().ByName(protoreflect.Name(value)) == nil {
+			return nil, invalid(fmt.Errorf("no such value in %s", field.Enum().FullName()))
+		}
+		return value, nil
+	default:
+		return nil, invalid(fmt.Errorf("examples of message fields must be JSON objects"))
+	}
+}
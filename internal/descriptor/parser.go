@@ -3,6 +3,8 @@ package descriptor
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -20,6 +22,8 @@ func parseFiles(ctx context.Context, protoFiles []string, includePaths []string)
 		ImportPaths: includePaths,
 		// Enable stdlib resolver for WKTs like google/protobuf/timestamp.proto
 		IncludeSourceCodeInfo: true,
+		// Serve reflect/options.proto when it is not on the include paths
+		LookupImportProto: lookupBuiltinImport,
 	}
 
 	// Convert absolute paths to relative paths for protoparse
@@ -36,6 +40,13 @@ func parseFiles(ctx context.Context, protoFiles []string, includePaths []string)
 	// Parse the files
 	fileDescriptors, err := parser.ParseFiles(fileNames...)
 	if err != nil {
+		// protoparse's cycle errors are hard to read; report the cycle plainly
+		if cycle := findImportCycle(fileNames, includePaths); cycle != nil {
+			return nil, nil, &ImportCycleError{Cycle: cycle}
+		}
+		if dup := findDuplicateSymbol(fileNames, includePaths); dup != nil {
+			return nil, nil, dup
+		}
 		return nil, nil, fmt.Errorf("failed to parse proto files: %w", err)
 	}
 
@@ -54,6 +65,159 @@ func parseFiles(ctx context.Context, protoFiles []string, includePaths []string)
 	return files, fdSet, nil
 }
 
+// ImportCycleError reports proto files that import each other, directly or
+// through other files.
+type ImportCycleError struct {
+	// Cycle is the import path, starting and ending with the same file.
+	Cycle []string
+}
+
+func (e *ImportCycleError) Error() string {
+	return fmt.Sprintf("import cycle: %s (move the shared definitions into a file that both can import)",
+		strings.Join(e.Cycle, " → "))
+}
+
+// DuplicateSymbolError reports a fully-qualified name defined by more than
+// one proto file.
+type DuplicateSymbolError struct {
+	// Symbol is the fully-qualified name that is defined twice.
+	Symbol string
+
+	// Files are the two files that define it, in load order.
+	Files [2]string
+}
+
+func (e *DuplicateSymbolError) Error() string {
+	return fmt.Sprintf("symbol %q is defined in both %s and %s (remove one definition or exclude one of the files)",
+		e.Symbol, e.Files[0], e.Files[1])
+}
+
+// unlinkedParser returns a parser for reading files without linking them.
+// Unlinked parsing ignores ImportPaths, so names resolve through an accessor.
+func unlinkedParser(includePaths []string) protoparse.Parser {
+	return protoparse.Parser{
+		Accessor: func(name string) (io.ReadCloser, error) {
+			for _, includePath := range includePaths {
+				if f, err := os.Open(filepath.Join(includePath, name)); err == nil {
+					return f, nil
+				}
+			}
+			return nil, fmt.Errorf("file %q not found in include paths: %w", name, os.ErrNotExist)
+		},
+	}
+}
+
+// findDuplicateSymbol looks for a symbol defined by two of the given files by
+// reading their declarations without linking. Returns nil if there is none or
+// the files cannot be parsed.
+func findDuplicateSymbol(fileNames []string, includePaths []string) *DuplicateSymbolError {
+	parser := unlinkedParser(includePaths)
+	fds, err := parser.ParseFilesButDoNotLink(fileNames...)
+	if err != nil {
+		return nil
+	}
+
+	definedIn := make(map[string]string)
+	for i, fd := range fds {
+		var dup *DuplicateSymbolError
+		define := func(name string) {
+			if dup != nil {
+				return
+			}
+			if other, ok := definedIn[name]; ok && other != fileNames[i] {
+				dup = &DuplicateSymbolError{Symbol: name, Files: [2]string{other, fileNames[i]}}
+				return
+			}
+			definedIn[name] = fileNames[i]
+		}
+
+		prefix := ""
+		if fd.GetPackage() != "" {
+			prefix = fd.GetPackage() + "."
+		}
+		for _, msg := range fd.GetMessageType() {
+			defineMessage(prefix, msg, define)
+		}
+		for _, enum := range fd.GetEnumType() {
+			define(prefix + enum.GetName())
+		}
+		for _, service := range fd.GetService() {
+			define(prefix + service.GetName())
+		}
+		for _, ext := range fd.GetExtension() {
+			define(prefix + ext.GetName())
+		}
+		if dup != nil {
+			return dup
+		}
+	}
+	return nil
+}
+
+// defineMessage reports a message and its nested declarations to define.
+func defineMessage(prefix string, msg *descriptorpb.DescriptorProto, define func(name string)) {
+	name := prefix + msg.GetName()
+	define(name)
+	for _, nested := range msg.GetNestedType() {
+		defineMessage(name+".", nested, define)
+	}
+	for _, enum := range msg.GetEnumType() {
+		define(name + "." + enum.GetName())
+	}
+}
+
+// findImportCycle looks for an import cycle among the given files by reading
+// their imports without linking. Returns nil if there is none or the files
+// cannot be parsed.
+func findImportCycle(fileNames []string, includePaths []string) []string {
+	parser := unlinkedParser(includePaths)
+	fds, err := parser.ParseFilesButDoNotLink(fileNames...)
+	if err != nil {
+		return nil
+	}
+
+	imports := make(map[string][]string, len(fds))
+	for i, fd := range fds {
+		imports[fileNames[i]] = fd.GetDependency()
+	}
+
+	// Depth-first search; a file seen again while still on the stack closes a cycle
+	onStack := make(map[string]bool)
+	done := make(map[string]bool)
+	var stack []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		if onStack[name] {
+			for i, file := range stack {
+				if file == name {
+					return append(append([]string{}, stack[i:]...), name)
+				}
+			}
+		}
+		if done[name] {
+			return nil
+		}
+		onStack[name] = true
+		stack = append(stack, name)
+		for _, dep := range imports[name] {
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		stack = stack[:len(stack)-1]
+		onStack[name] = false
+		done[name] = true
+		return nil
+	}
+
+	for _, name := range fileNames {
+		if cycle := visit(name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
 // findRelativePath finds the relative path of a file given a list of include paths.
 func findRelativePath(absPath string, includePaths []string) (string, error) {
 	for _, includePath := range includePaths {
@@ -0,0 +1,295 @@
+package descriptor
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// ReflectionOptions configures how LoadFromReflection connects to a server.
+type ReflectionOptions struct {
+	// Plaintext disables TLS. Targets with an "http://" scheme are always
+	// plaintext and targets with an "https://" scheme always use TLS.
+	Plaintext bool
+
+	// InsecureSkipVerify disables TLS certificate verification.
+	// WARNING: Only use for development/testing with self-signed certs.
+	InsecureSkipVerify bool
+
+	// Headers are sent as gRPC metadata with every reflection request
+	// (e.g. "authorization").
+	Headers map[string]string
+}
+
+// LoadFromReflection builds a Registry from a live server using the gRPC server
+// reflection service. The v1 reflection API is tried first, falling back to
+// v1alpha for servers that only implement the older service.
+//
+// Reflection does not carry source info, so the resulting registry has no
+// comments.
+func LoadFromReflection(ctx context.Context, target string, opts ReflectionOptions) (*Registry, error) {
+	if target == "" {
+		return nil, fmt.Errorf("reflection target cannot be empty")
+	}
+
+	address, creds := reflectionDialTarget(target, opts)
+	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %q: %w", target, err)
+	}
+	defer conn.Close()
+
+	if len(opts.Headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(opts.Headers))
+	}
+
+	fdSet, err := fetchFileDescriptorSet(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := protodesc.NewFiles(fdSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create protoregistry.Files: %w", err)
+	}
+
+	registry, err := buildRegistry(files, fdSet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build registry: %w", err)
+	}
+
+	return registry, nil
+}
+
+// reflectionDialTarget strips any URL scheme from target and picks transport
+// credentials for it.
+func reflectionDialTarget(target string, opts ReflectionOptions) (string, credentials.TransportCredentials) {
+	plaintext := opts.Plaintext
+	if strings.HasPrefix(target, "http://") {
+		target = strings.TrimPrefix(target, "http://")
+		plaintext = true
+	} else if strings.HasPrefix(target, "https://") {
+		target = strings.TrimPrefix(target, "https://")
+		plaintext = false
+	}
+
+	if plaintext {
+		return target, insecure.NewCredentials()
+	}
+	return target, credentials.NewTLS(&tls.Config{
+		InsecureSkipVerify: opts.InsecureSkipVerify,
+	})
+}
+
+// reflectionStream is a bidirectional reflection stream speaking the v1 API.
+type reflectionStream interface {
+	Send(*reflectionv1.ServerReflectionRequest) error
+	Recv() (*reflectionv1.ServerReflectionResponse, error)
+	CloseSend() error
+}
+
+// v1alphaStream adapts a v1alpha stream to the v1 API. The two versions use
+// identical messages, so requests and responses are converted via the wire format.
+type v1alphaStream struct {
+	reflectionv1alpha.ServerReflection_ServerReflectionInfoClient
+}
+
+func (s v1alphaStream) Send(req *reflectionv1.ServerReflectionRequest) error {
+	var alpha reflectionv1alpha.ServerReflectionRequest
+	if err := convertMessage(req, &alpha); err != nil {
+		return err
+	}
+	return s.ServerReflection_ServerReflectionInfoClient.Send(&alpha)
+}
+
+func (s v1alphaStream) Recv() (*reflectionv1.ServerReflectionResponse, error) {
+	alpha, err := s.ServerReflection_ServerReflectionInfoClient.Recv()
+	if err != nil {
+		return nil, err
+	}
+	var resp reflectionv1.ServerReflectionResponse
+	if err := convertMessage(alpha, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// convertMessage copies src into dst through the wire format.
+func convertMessage(src, dst proto.Message) error {
+	b, err := proto.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(b, dst)
+}
+
+// fetchFileDescriptorSet lists the server's services and downloads the files
+// defining them along with all of their dependencies.
+func fetchFileDescriptorSet(ctx context.Context, conn *grpc.ClientConn) (*descriptorpb.FileDescriptorSet, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := reflectionv1.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reflection stream: %w", err)
+	}
+
+	services, err := listServices(stream)
+	if status.Code(err) == codes.Unimplemented {
+		stream.CloseSend()
+		alpha, alphaErr := reflectionv1alpha.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+		if alphaErr != nil {
+			return nil, fmt.Errorf("failed to open v1alpha reflection stream: %w", alphaErr)
+		}
+		stream = v1alphaStream{alpha}
+		services, err = listServices(stream)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	defer stream.CloseSend()
+
+	fetched := make(map[string]*descriptorpb.FileDescriptorProto)
+	for _, service := range services {
+		if isReflectionService(service) {
+			continue
+		}
+		fds, err := requestFiles(stream, &reflectionv1.ServerReflectionRequest{
+			MessageRequest: &reflectionv1.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch descriptor for %q: %w", service, err)
+		}
+		if err := addMissingDependencies(stream, fds, fetched); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(fetched) == 0 {
+		return nil, fmt.Errorf("server did not expose any services via reflection")
+	}
+
+	names := make([]string, 0, len(fetched))
+	for name := range fetched {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fdSet := &descriptorpb.FileDescriptorSet{}
+	for _, name := range names {
+		fdSet.File = append(fdSet.File, fetched[name])
+	}
+	return fdSet, nil
+}
+
+// addMissingDependencies records fds in fetched and requests any dependency
+// the server did not already send.
+func addMissingDependencies(stream reflectionStream, fds []*descriptorpb.FileDescriptorProto, fetched map[string]*descriptorpb.FileDescriptorProto) error {
+	queue := fds
+	for len(queue) > 0 {
+		fd := queue[0]
+		queue = queue[1:]
+		if _, exists := fetched[fd.GetName()]; exists {
+			continue
+		}
+		fetched[fd.GetName()] = fd
+
+		for _, dep := range fd.GetDependency() {
+			if _, exists := fetched[dep]; exists {
+				continue
+			}
+			depFiles, err := requestFiles(stream, &reflectionv1.ServerReflectionRequest{
+				MessageRequest: &reflectionv1.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to fetch dependency %q: %w", dep, err)
+			}
+			queue = append(queue, depFiles...)
+		}
+	}
+	return nil
+}
+
+// listServices returns the fully-qualified names of all services on the server.
+func listServices(stream reflectionStream) ([]string, error) {
+	resp, err := roundTrip(stream, &reflectionv1.ServerReflectionRequest{
+		MessageRequest: &reflectionv1.ServerReflectionRequest_ListServices{ListServices: "*"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := resp.GetListServicesResponse()
+	if list == nil {
+		return nil, fmt.Errorf("unexpected reflection response type %T", resp.GetMessageResponse())
+	}
+
+	var services []string
+	for _, service := range list.GetService() {
+		services = append(services, service.GetName())
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// requestFiles sends a file request and decodes the returned descriptors.
+func requestFiles(stream reflectionStream, req *reflectionv1.ServerReflectionRequest) ([]*descriptorpb.FileDescriptorProto, error) {
+	resp, err := roundTrip(stream, req)
+	if err != nil {
+		return nil, err
+	}
+
+	fileResp := resp.GetFileDescriptorResponse()
+	if fileResp == nil {
+		return nil, fmt.Errorf("unexpected reflection response type %T", resp.GetMessageResponse())
+	}
+
+	var fds []*descriptorpb.FileDescriptorProto
+	for _, b := range fileResp.GetFileDescriptorProto() {
+		fd := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(b, fd); err != nil {
+			return nil, fmt.Errorf("failed to decode file descriptor: %w", err)
+		}
+		fds = append(fds, fd)
+	}
+	return fds, nil
+}
+
+// roundTrip sends a single request and waits for its response, converting
+// reflection error responses into gRPC status errors.
+func roundTrip(stream reflectionStream, req *reflectionv1.ServerReflectionRequest) (*reflectionv1.ServerReflectionResponse, error) {
+	// Send reports io.EOF when the server has closed the stream; the actual
+	// status (e.g. Unimplemented) is surfaced by Recv.
+	if err := stream.Send(req); err != nil && err != io.EOF {
+		return nil, err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if errResp := resp.GetErrorResponse(); errResp != nil {
+		return nil, status.Error(codes.Code(errResp.GetErrorCode()), errResp.GetErrorMessage())
+	}
+	return resp, nil
+}
+
+// isReflectionService reports whether a service is one of the reflection
+// services themselves, which are not part of the documented API.
+func isReflectionService(name string) bool {
+	return strings.HasPrefix(name, "grpc.reflection.")
+}
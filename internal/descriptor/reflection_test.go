@@ -0,0 +1,116 @@
+package descriptor
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoadFromReflection(t *testing.T) {
+	tests := []struct {
+		name     string
+		register func(s *grpc.Server)
+	}{
+		{
+			name:     "v1 and v1alpha",
+			register: func(s *grpc.Server) { reflection.Register(s) },
+		},
+		{
+			name: "v1alpha only",
+			register: func(s *grpc.Server) {
+				reflectionv1alpha.RegisterServerReflectionServer(s, reflection.NewServer(reflection.ServerOptions{Services: s}))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := startReflectionServer(t, tt.register)
+
+			registry, err := LoadFromReflection(context.Background(), target, ReflectionOptions{Plaintext: true})
+			if err != nil {
+				t.Fatalf("LoadFromReflection() error = %v", err)
+			}
+
+			if _, exists := registry.FindService("grpc.health.v1.Health"); !exists {
+				t.Error("Expected grpc.health.v1.Health service to be loaded")
+			}
+			if _, exists := registry.FindMethod("grpc.health.v1.Health/Check"); !exists {
+				t.Error("Expected grpc.health.v1.Health/Check method to be loaded")
+			}
+			if _, exists := registry.FindMessage("grpc.health.v1.HealthCheckResponse"); !exists {
+				t.Error("Expected grpc.health.v1.HealthCheckResponse message to be loaded")
+			}
+			for name := range registry.ServicesByName {
+				if isReflectionService(name) {
+					t.Errorf("Expected reflection service %q to be skipped", name)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadFromReflection_Headers(t *testing.T) {
+	requireAuth := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if len(md.Get("authorization")) == 0 || md.Get("authorization")[0] != "Bearer secret" {
+			return status.Error(codes.Unauthenticated, "missing token")
+		}
+		return nil
+	}
+
+	target := startReflectionServer(t, func(s *grpc.Server) { reflection.Register(s) },
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := requireAuth(ss.Context()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	)
+
+	if _, err := LoadFromReflection(context.Background(), target, ReflectionOptions{Plaintext: true}); err == nil {
+		t.Error("Expected error without authorization header")
+	}
+
+	_, err := LoadFromReflection(context.Background(), "http://"+target, ReflectionOptions{
+		Headers: map[string]string{"authorization": "Bearer secret"},
+	})
+	if err != nil {
+		t.Errorf("LoadFromReflection() with headers error = %v", err)
+	}
+}
+
+func TestLoadFromReflection_EmptyTarget(t *testing.T) {
+	if _, err := LoadFromReflection(context.Background(), "", ReflectionOptions{}); err == nil {
+		t.Error("Expected error for empty target")
+	}
+}
+
+// startReflectionServer starts a plaintext gRPC server exposing the health
+// service and returns its address.
+func startReflectionServer(t *testing.T, register func(s *grpc.Server), opts ...grpc.ServerOption) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	s := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	register(s)
+
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	return lis.Addr().String()
+}
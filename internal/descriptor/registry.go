@@ -2,10 +2,12 @@ package descriptor
 
 import (
 	"fmt"
+	"strings"
 
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 // Registry holds parsed protobuf descriptors with fast lookup capabilities.
@@ -14,13 +16,39 @@ type Registry struct {
 	Types *protoregistry.Types
 	// FileDescriptorSet for comment extraction
 	FileDescriptorSet *descriptorpb.FileDescriptorSet
-	// Comment index for documentation
+	// Comment index for documentation, with whitespace cleaned up
 	CommentIndex map[string]string
+	// Comments exactly as they appear in SourceCodeInfo
+	RawCommentIndex map[string]string
+	// Leading, trailing, and detached comments for each symbol, unprocessed
+	CommentEntries map[string]CommentEntry
+	// Symbols marked internal-only, by fully-qualified name
+	InternalSymbols map[string]bool
 	// Fast lookups by fully-qualified name
 	ServicesByName map[string]protoreflect.ServiceDescriptor
 	MethodsByName  map[string]protoreflect.MethodDescriptor
 	MessagesByName map[string]protoreflect.MessageDescriptor
 	EnumsByName    map[string]protoreflect.EnumDescriptor
+	// Concrete message names for google.protobuf.Any fields, by field full name
+	AnyTypeHints map[string]string
+	// Full name of a string field option used as the comment of fields
+	// without a source comment
+	CommentOption string
+	// Full name of a string method option holding method tags; empty means
+	// DefaultTagOption
+	TagOption string
+	// Full name of a string field option holding example values; empty
+	// means the built-in reflect.example
+	ExampleOption string
+	// Cached reports that the descriptors were read from the descriptor
+	// cache (see LoadOptions.CacheDir) rather than parsed from source
+	Cached bool
+	// Proto source text by import path (e.g. "echo/v1/echo.proto"); nil
+	// when the descriptors were not loaded from source, as with server
+	// reflection
+	Sources map[string]string
+	// Files skipped by a best-effort load (see LoadOptions.BestEffort)
+	LoadErrors []LoadError
 }
 
 // FindService returns a service descriptor by its fully-qualified name.
@@ -48,6 +76,82 @@ func (r *Registry) FindEnum(fullName string) (protoreflect.EnumDescriptor, bool)
 	return enum, exists
 }
 
+// Source returns the proto source text of the file with the given import
+// path, if it was loaded from source.
+func (r *Registry) Source(path string) (string, bool) {
+	source, ok := r.Sources[path]
+	return source, ok
+}
+
+// WithRawComments returns a shallow copy of the registry whose CommentIndex
+// holds the raw, uncleaned comments.
+func (r *Registry) WithRawComments() *Registry {
+	raw := *r
+	raw.CommentIndex = r.RawCommentIndex
+	return &raw
+}
+
+// WithAnyTypeHints returns a shallow copy of the registry that uses the given
+// hints for google.protobuf.Any fields in examples.
+func (r *Registry) WithAnyTypeHints(hints map[string]string) *Registry {
+	hinted := *r
+	hinted.AnyTypeHints = hints
+	return &hinted
+}
+
+// WithCommentOption returns a shallow copy of the registry that documents
+// fields without a source comment using the named string field option.
+func (r *Registry) WithCommentOption(name string) *Registry {
+	withOption := *r
+	withOption.CommentOption = name
+	return &withOption
+}
+
+// WithTagOption returns a shallow copy of the registry that reads method tags
+// from the named string method option.
+func (r *Registry) WithTagOption(name string) *Registry {
+	withOption := *r
+	withOption.TagOption = name
+	return &withOption
+}
+
+// WithExampleOption returns a shallow copy of the registry that reads example
+// values from the named string field option.
+func (r *Registry) WithExampleOption(name string) *Registry {
+	withOption := *r
+	withOption.ExampleOption = name
+	return &withOption
+}
+
+// ExampleOptions returns the default example options with the registry's Any
+// type hints and example option resolved. Hints naming unknown messages are
+// skipped, as is an example option that is not a string field option.
+func (r *Registry) ExampleOptions() ExampleOptions {
+	options := DefaultExampleOptions()
+	if r.ExampleOption != "" {
+		if d, err := r.Files.FindDescriptorByName(protoreflect.FullName(r.ExampleOption)); err == nil {
+			if ext, ok := d.(protoreflect.ExtensionDescriptor); ok && ext.Kind() == protoreflect.StringKind && ext.ContainingMessage().FullName() == "google.protobuf.FieldOptions" {
+				options.ExampleOption = ext
+			}
+		}
+	}
+	for field, message := range r.AnyTypeHints {
+		if msg, ok := r.FindMessage(message); ok {
+			if options.AnyTypes == nil {
+				options.AnyTypes = make(map[string]protoreflect.MessageDescriptor)
+			}
+			options.AnyTypes[field] = msg
+		}
+	}
+	return options
+}
+
+// Resolver returns a type resolver over all messages and extensions in the
+// registry, for use with protojson (e.g. to expand google.protobuf.Any values).
+func (r *Registry) Resolver() *dynamicpb.Types {
+	return dynamicpb.NewTypes(r.Files)
+}
+
 // buildRegistry creates a Registry from parsed files.
 func buildRegistry(files *protoregistry.Files, fdSet *descriptorpb.FileDescriptorSet) (*Registry, error) {
 	registry := &Registry{
@@ -55,6 +159,9 @@ func buildRegistry(files *protoregistry.Files, fdSet *descriptorpb.FileDescripto
 		Types:             &protoregistry.Types{},
 		FileDescriptorSet: fdSet,
 		CommentIndex:      make(map[string]string),
+		RawCommentIndex:   make(map[string]string),
+		CommentEntries:    make(map[string]CommentEntry),
+		InternalSymbols:   make(map[string]bool),
 		ServicesByName:    make(map[string]protoreflect.ServiceDescriptor),
 		MethodsByName:     make(map[string]protoreflect.MethodDescriptor),
 		MessagesByName:    make(map[string]protoreflect.MessageDescriptor),
@@ -89,6 +196,9 @@ func buildRegistry(files *protoregistry.Files, fdSet *descriptorpb.FileDescripto
 	// Build comment index
 	buildCommentIndex(fdSet, registry)
 
+	// Mark internal-only symbols
+	indexInternalSymbols(files, registry)
+
 	return registry, nil
 }
 
@@ -126,20 +236,20 @@ func buildCommentIndex(fdSet *descriptorpb.FileDescriptorSet, registry *Registry
 		for i, service := range file.Service {
 			servicePath := []int32{6, int32(i)} // 6 = service
 			comment := extractComment(file.SourceCodeInfo, servicePath)
-			if comment != "" {
+			if !comment.isEmpty() {
 				// Use full name instead of just name
 				serviceFullName := fmt.Sprintf("%s.%s", file.GetPackage(), *service.Name)
-				registry.CommentIndex[serviceFullName] = comment
+				registry.setComment(serviceFullName, comment)
 			}
 
 			// Extract comments for methods
 			for j, method := range service.Method {
 				methodPath := []int32{6, int32(i), 2, int32(j)} // 6 = service, 2 = method
 				comment := extractComment(file.SourceCodeInfo, methodPath)
-				if comment != "" {
+				if !comment.isEmpty() {
 					// Use full name format
 					methodName := fmt.Sprintf("%s.%s/%s", file.GetPackage(), *service.Name, *method.Name)
-					registry.CommentIndex[methodName] = comment
+					registry.setComment(methodName, comment)
 				}
 			}
 		}
@@ -156,24 +266,34 @@ func buildCommentIndex(fdSet *descriptorpb.FileDescriptorSet, registry *Registry
 	}
 }
 
-// extractMessageComments recursively extracts comments from message types and their fields.
+// extractMessageComments recursively extracts comments from message types, their fields, and their oneofs.
 func extractMessageComments(sourceInfo *descriptorpb.SourceCodeInfo, message *descriptorpb.DescriptorProto, registry *Registry, path []int32, packageName string) {
 	// Extract comment for the message itself
 	comment := extractComment(sourceInfo, path)
-	if comment != "" {
+	if !comment.isEmpty() {
 		// Use full name
 		messageFullName := fmt.Sprintf("%s.%s", packageName, *message.Name)
-		registry.CommentIndex[messageFullName] = comment
+		registry.setComment(messageFullName, comment)
 	}
 
 	// Extract comments for fields
 	for i, field := range message.Field {
 		fieldPath := append(path, 2, int32(i)) // 2 = field
 		comment := extractComment(sourceInfo, fieldPath)
-		if comment != "" {
+		if !comment.isEmpty() {
 			// Use full name
 			fieldName := fmt.Sprintf("%s.%s.%s", packageName, *message.Name, *field.Name)
-			registry.CommentIndex[fieldName] = comment
+			registry.setComment(fieldName, comment)
+		}
+	}
+
+	// Extract comments for oneofs
+	for i, oneof := range message.OneofDecl {
+		oneofPath := append(path, 8, int32(i)) // 8 = oneof_decl
+		comment := extractComment(sourceInfo, oneofPath)
+		if !comment.isEmpty() {
+			oneofName := fmt.Sprintf("%s.%s.%s", packageName, *message.Name, *oneof.Name)
+			registry.setComment(oneofName, comment)
 		}
 	}
 
@@ -194,38 +314,165 @@ func extractMessageComments(sourceInfo *descriptorpb.SourceCodeInfo, message *de
 func extractEnumComments(sourceInfo *descriptorpb.SourceCodeInfo, enum *descriptorpb.EnumDescriptorProto, registry *Registry, path []int32, packageName string) {
 	// Extract comment for the enum itself
 	comment := extractComment(sourceInfo, path)
-	if comment != "" {
+	if !comment.isEmpty() {
 		// Use full name
 		enumFullName := fmt.Sprintf("%s.%s", packageName, *enum.Name)
-		registry.CommentIndex[enumFullName] = comment
+		registry.setComment(enumFullName, comment)
 	}
 
 	// Extract comments for enum values
 	for i, value := range enum.Value {
 		valuePath := append(path, 2, int32(i)) // 2 = value
 		comment := extractComment(sourceInfo, valuePath)
-		if comment != "" {
+		if !comment.isEmpty() {
 			// Use full name
 			valueName := fmt.Sprintf("%s.%s.%s", packageName, *enum.Name, *value.Name)
-			registry.CommentIndex[valueName] = comment
+			registry.setComment(valueName, comment)
+		}
+	}
+}
+
+// CommentEntry holds the comments attached to a symbol in SourceCodeInfo.
+type CommentEntry struct {
+	// Leading is the comment directly above the symbol.
+	Leading string
+	// Trailing is the comment after the symbol, on the same line or the next.
+	Trailing string
+	// LeadingDetached are comments above the symbol separated from it by a
+	// blank line. They are kept here but not shown in the docs.
+	LeadingDetached []string
+}
+
+// isEmpty reports whether the entry holds no comments.
+func (e CommentEntry) isEmpty() bool {
+	return e.Leading == "" && e.Trailing == "" && len(e.LeadingDetached) == 0
+}
+
+// text returns the comment shown in the docs: the leading comment followed by
+// the trailing comment, so symbols documented only with a trailing comment
+// (FOO = 1; // description) still have one.
+func (e CommentEntry) text() string {
+	switch {
+	case e.Leading == "":
+		return e.Trailing
+	case e.Trailing == "":
+		return e.Leading
+	default:
+		return strings.TrimRight(e.Leading, "\n") + "\n\n" + e.Trailing
+	}
+}
+
+// setComment records a symbol's comments, its displayed comment, and the
+// cleaned form of that comment.
+func (r *Registry) setComment(fullName string, entry CommentEntry) {
+	r.CommentEntries[fullName] = entry
+	if raw := entry.text(); raw != "" {
+		r.RawCommentIndex[fullName] = raw
+		r.CommentIndex[fullName] = cleanComment(raw)
+	}
+}
+
+// cleanComment normalizes a comment for display. It drops the lone "*" that
+// a /** block leaves on its first line, trims blank lines around the comment,
+// strips trailing whitespace, and removes the indentation common to all
+// lines. The lines of each paragraph are then joined, with blank lines kept as
+// paragraph breaks; paragraphs with indented lines or list items, such as
+// code samples, keep their line breaks.
+func cleanComment(raw string) string {
+	lines := strings.Split(raw, "\n")
+	if len(lines) > 0 && strings.Trim(lines[0], "* \t") == "" {
+		lines[0] = ""
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	indent := -1
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+		if lines[i] == "" {
+			continue
+		}
+		n := len(lines[i]) - len(strings.TrimLeft(lines[i], " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[i] = line[indent:]
+		}
+	}
+
+	return joinParagraphs(lines)
+}
+
+// joinParagraphs joins the lines of each blank-line-separated paragraph into
+// one line, leaving preformatted paragraphs as written. Runs of blank lines
+// become a single paragraph break.
+func joinParagraphs(lines []string) string {
+	var paragraphs []string
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		if isPreformatted(paragraph) {
+			paragraphs = append(paragraphs, strings.Join(paragraph, "\n"))
+		} else {
+			paragraphs = append(paragraphs, strings.Join(paragraph, " "))
+		}
+		paragraph = nil
+	}
+
+	for _, line := range lines {
+		if line == "" {
+			flush()
+			continue
+		}
+		paragraph = append(paragraph, line)
+	}
+	flush()
+
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// isPreformatted reports whether a paragraph has indented lines or list
+// items, whose line breaks are significant.
+func isPreformatted(paragraph []string) bool {
+	for _, line := range paragraph {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			return true
+		}
+		if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") || strings.HasPrefix(line, "+ ") {
+			return true
+		}
+		if digits := len(line) - len(strings.TrimLeft(line, "0123456789")); digits > 0 && strings.HasPrefix(line[digits:], ". ") {
+			return true
 		}
 	}
+	return false
 }
 
-// extractComment extracts the leading comment from SourceCodeInfo for a given path.
-func extractComment(sourceInfo *descriptorpb.SourceCodeInfo, path []int32) string {
+// extractComment extracts the comments from SourceCodeInfo for a given path.
+func extractComment(sourceInfo *descriptorpb.SourceCodeInfo, path []int32) CommentEntry {
 	if sourceInfo == nil {
-		return ""
+		return CommentEntry{}
 	}
 
 	for _, location := range sourceInfo.Location {
 		if pathEqual(location.Path, path) {
-			if location.LeadingComments != nil && *location.LeadingComments != "" {
-				return *location.LeadingComments
+			return CommentEntry{
+				Leading:         location.GetLeadingComments(),
+				Trailing:        location.GetTrailingComments(),
+				LeadingDetached: location.LeadingDetachedComments,
 			}
 		}
 	}
-	return ""
+	return CommentEntry{}
 }
 
 // pathEqual compares two path slices for equality.
@@ -3,6 +3,8 @@ package descriptor
 import (
 	"context"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"google.golang.org/protobuf/reflect/protoreflect"
@@ -225,3 +227,158 @@ func TestRegistryWithWKTs(t *testing.T) {
 		}
 	}
 }
+
+func TestRegistryCommentIndexes(t *testing.T) {
+	reg, err := LoadDirectory(context.Background(), filepath.Join("testdata", "basic"), []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test data: %v", err)
+	}
+
+	tests := []struct {
+		fullName  string
+		wantRaw   string
+		wantClean string
+	}{
+		{
+			fullName:  "echo.v1.EchoService",
+			wantRaw:   " EchoService provides simple echo functionality.\n",
+			wantClean: "EchoService provides simple echo functionality.",
+		},
+		{
+			fullName:  "echo.v1.Status", // block comment
+			wantRaw:   "\n Status represents the status of an operation.\n\n Values other than STATUS_SUCCESS indicate the echo was not delivered.\n",
+			wantClean: "Status represents the status of an operation.\n\nValues other than STATUS_SUCCESS indicate the echo was not delivered.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fullName, func(t *testing.T) {
+			if got := reg.RawCommentIndex[tt.fullName]; got != tt.wantRaw {
+				t.Errorf("RawCommentIndex = %q, want %q", got, tt.wantRaw)
+			}
+			if got := reg.CommentIndex[tt.fullName]; got != tt.wantClean {
+				t.Errorf("CommentIndex = %q, want %q", got, tt.wantClean)
+			}
+		})
+	}
+
+	if len(reg.RawCommentIndex) != len(reg.CommentIndex) {
+		t.Errorf("Expected indexes to cover the same symbols, got %d raw and %d cleaned", len(reg.RawCommentIndex), len(reg.CommentIndex))
+	}
+
+	raw := reg.WithRawComments()
+	if raw.CommentIndex["echo.v1.Status"] != reg.RawCommentIndex["echo.v1.Status"] {
+		t.Error("Expected WithRawComments to expose raw comments via CommentIndex")
+	}
+	if reg.CommentIndex["echo.v1.Status"] == reg.RawCommentIndex["echo.v1.Status"] {
+		t.Error("Expected WithRawComments to leave the original registry unchanged")
+	}
+}
+
+func TestRegistryTrailingComments(t *testing.T) {
+	reg, err := LoadDirectory(context.Background(), filepath.Join("testdata", "comments"), []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test data: %v", err)
+	}
+
+	tests := []struct {
+		fullName  string
+		wantClean string
+	}{
+		{fullName: "comments.v1.Color.COLOR_UNSPECIFIED", wantClean: "Not set."},
+		{fullName: "comments.v1.Color.COLOR_RED", wantClean: "The color of fire."},
+		{fullName: "comments.v1.Color.COLOR_GREEN", wantClean: "Leading comment on green.\n\nTrailing comment on green."},
+		{fullName: "comments.v1.Color.COLOR_BLUE", wantClean: ""},
+		{fullName: "comments.v1.Paint.color", wantClean: "Main color."},
+		{fullName: "comments.v1.Color", wantClean: "Color is documented with a leading comment."},
+		{fullName: "comments.v1.Paint", wantClean: "Paint is a can of paint.\n\nIt comes in one color, chosen when mixed."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fullName, func(t *testing.T) {
+			if got := reg.CommentIndex[tt.fullName]; got != tt.wantClean {
+				t.Errorf("CommentIndex = %q, want %q", got, tt.wantClean)
+			}
+		})
+	}
+
+	green := reg.CommentEntries["comments.v1.Color.COLOR_GREEN"]
+	if green.Leading != " Leading comment on green.\n" || green.Trailing != " Trailing comment on green.\n" {
+		t.Errorf("Expected separate leading and trailing comments, got %+v", green)
+	}
+
+	color := reg.CommentEntries["comments.v1.Color"]
+	if want := []string{" Detached note about the section below; not attached to Color.\n"}; !reflect.DeepEqual(color.LeadingDetached, want) {
+		t.Errorf("LeadingDetached = %q, want %q", color.LeadingDetached, want)
+	}
+	if strings.Contains(reg.CommentIndex["comments.v1.Color"], "Detached") {
+		t.Error("Expected detached comments to be left out of CommentIndex")
+	}
+}
+
+func TestCleanComment(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "single line",
+			raw:  " Returns a user.\n",
+			want: "Returns a user.",
+		},
+		{
+			name: "multi-line // block",
+			raw:  " GetUser returns a user by ID.\n If the user does not exist,\n NOT_FOUND is returned.\n",
+			want: "GetUser returns a user by ID. If the user does not exist, NOT_FOUND is returned.",
+		},
+		{
+			name: "paragraphs",
+			raw:  " First paragraph,\n wrapped.\n\n\n Second paragraph.\n",
+			want: "First paragraph, wrapped.\n\nSecond paragraph.",
+		},
+		{
+			name: "/* */ block",
+			raw:  "\n Status of an operation.\n\n Values other than OK\n indicate failure.\n",
+			want: "Status of an operation.\n\nValues other than OK indicate failure.",
+		},
+		{
+			name: "/** */ block",
+			raw:  "*\n Paint is a can of paint.\n\n It comes in one color.\n",
+			want: "Paint is a can of paint.\n\nIt comes in one color.",
+		},
+		{
+			name: "inline /* */ block",
+			raw:  " Inline block. ",
+			want: "Inline block.",
+		},
+		{
+			name: "code sample keeps line breaks",
+			raw:  " Example:\n\n   rpc Get(Req) returns (Resp);\n   rpc List(Req) returns (Resp);\n",
+			want: "Example:\n\n  rpc Get(Req) returns (Resp);\n  rpc List(Req) returns (Resp);",
+		},
+		{
+			name: "list keeps line breaks",
+			raw:  " Modes:\n - FAST skips checks\n - SAFE runs them\n",
+			want: "Modes:\n- FAST skips checks\n- SAFE runs them",
+		},
+		{
+			name: "numbered list keeps line breaks",
+			raw:  " 1. Create\n 2. Delete\n",
+			want: "1. Create\n2. Delete",
+		},
+		{
+			name: "empty",
+			raw:  "\n  \n",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cleanComment(tt.raw); got != tt.want {
+				t.Errorf("cleanComment(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package descriptor
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// IsInternal reports whether the symbol with the given fully-qualified name is
+// marked internal-only. Method names use the format "pkg.Service/Method".
+func (r *Registry) IsInternal(fullName string) bool {
+	return r.InternalSymbols[fullName]
+}
+
+// internalOptions holds the field numbers of custom options that mark a symbol
+// as internal, keyed by the options message they extend.
+type internalOptions map[protoreflect.FullName]map[protoreflect.FieldNumber]bool
+
+// indexInternalSymbols marks services, methods, messages, and enums as internal.
+// A symbol is internal when:
+//   - it sets a bool custom option whose name has an "internal" word
+//     (e.g. "internal", "method_internal") to true, or
+//   - its package has an "internal" segment (e.g. "acme.internal.v1"), or
+//   - it is nested in (or is a method of) an internal symbol, or
+//   - it is a method with a google.api.method_visibility restriction.
+func indexInternalSymbols(files *protoregistry.Files, registry *Registry) {
+	options := collectInternalOptions(files)
+
+	for name, service := range registry.ServicesByName {
+		serviceInternal := isInternalPackage(service.ParentFile().Package()) || hasInternalOption(service.Options(), options)
+		if serviceInternal {
+			registry.InternalSymbols[name] = true
+		}
+
+		for i := 0; i < service.Methods().Len(); i++ {
+			method := service.Methods().Get(i)
+			if serviceInternal || hasInternalOption(method.Options(), options) || hasVisibilityRestriction(method.Options()) {
+				registry.InternalSymbols[name+"/"+string(method.Name())] = true
+			}
+		}
+	}
+
+	for name, message := range registry.MessagesByName {
+		if isInternalDescriptor(message, options) {
+			registry.InternalSymbols[name] = true
+		}
+	}
+
+	for name, enum := range registry.EnumsByName {
+		if isInternalDescriptor(enum, options) {
+			registry.InternalSymbols[name] = true
+		}
+	}
+}
+
+// isInternalDescriptor checks a message or enum and all of its parent messages.
+func isInternalDescriptor(desc protoreflect.Descriptor, options internalOptions) bool {
+	if isInternalPackage(desc.ParentFile().Package()) {
+		return true
+	}
+	for d := desc; d != nil; d = d.Parent() {
+		if _, isFile := d.(protoreflect.FileDescriptor); isFile {
+			break
+		}
+		if hasInternalOption(d.Options(), options) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInternalPackage reports whether a package has an "internal" segment.
+func isInternalPackage(pkg protoreflect.FullName) bool {
+	for _, segment := range strings.Split(string(pkg), ".") {
+		if segment == "internal" {
+			return true
+		}
+	}
+	return false
+}
+
+// isInternalOptionName reports whether an option name has an "internal" word.
+func isInternalOptionName(name protoreflect.Name) bool {
+	for _, word := range strings.Split(string(name), "_") {
+		if word == "internal" {
+			return true
+		}
+	}
+	return false
+}
+
+// collectInternalOptions finds bool extensions that look like internal markers.
+func collectInternalOptions(files *protoregistry.Files) internalOptions {
+	options := make(internalOptions)
+
+	var collect func(exts protoreflect.ExtensionDescriptors)
+	collect = func(exts protoreflect.ExtensionDescriptors) {
+		for i := 0; i < exts.Len(); i++ {
+			ext := exts.Get(i)
+			if ext.Kind() != protoreflect.BoolKind || !isInternalOptionName(ext.Name()) {
+				continue
+			}
+			extendee := ext.ContainingMessage().FullName()
+			if options[extendee] == nil {
+				options[extendee] = make(map[protoreflect.FieldNumber]bool)
+			}
+			options[extendee][ext.Number()] = true
+		}
+	}
+
+	var collectMessages func(msgs protoreflect.MessageDescriptors)
+	collectMessages = func(msgs protoreflect.MessageDescriptors) {
+		for i := 0; i < msgs.Len(); i++ {
+			collect(msgs.Get(i).Extensions())
+			collectMessages(msgs.Get(i).Messages())
+		}
+	}
+
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		collect(fd.Extensions())
+		collectMessages(fd.Messages())
+		return true
+	})
+
+	return options
+}
+
+// hasInternalOption reports whether an options message sets an internal marker.
+// Custom options are usually unresolved and kept as unknown fields, so both
+// known extensions and raw unknown fields are checked.
+func hasInternalOption(opts protoreflect.ProtoMessage, options internalOptions) bool {
+	if opts == nil {
+		return false
+	}
+	m := opts.ProtoReflect()
+	if !m.IsValid() {
+		return false
+	}
+
+	found := false
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.IsExtension() && fd.Kind() == protoreflect.BoolKind && isInternalOptionName(fd.Name()) && v.Bool() {
+			found = true
+			return false
+		}
+		return true
+	})
+	if found {
+		return true
+	}
+
+	numbers := options[m.Descriptor().FullName()]
+	if len(numbers) == 0 {
+		return false
+	}
+
+	b := m.GetUnknown()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+
+		if typ == protowire.VarintType {
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return false
+			}
+			if numbers[num] && v != 0 {
+				return true
+			}
+			b = b[n:]
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+	}
+
+	return false
+}
+
+// methodVisibilityNumber is the field number of the google.api.method_visibility
+// extension, and visibilityRestrictionNumber that of its restriction field.
+const (
+	methodVisibilityNumber      protowire.Number = 72295727
+	visibilityRestrictionNumber protowire.Number = 2
+)
+
+// hasVisibilityRestriction reports whether method options set
+// google.api.method_visibility with a non-empty restriction (e.g.
+// "INTERNAL"), which limits the method to some consumers. The option is read
+// from the wire format, so google/api/visibility.proto need not be loaded.
+func hasVisibilityRestriction(opts protoreflect.ProtoMessage) bool {
+	if opts == nil {
+		return false
+	}
+	b, err := proto.Marshal(opts)
+	if err != nil {
+		return false
+	}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+
+		if num == methodVisibilityNumber && typ == protowire.BytesType {
+			rule, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return false
+			}
+			if restriction, ok := stringField(rule, visibilityRestrictionNumber); ok && strings.TrimSpace(restriction) != "" {
+				return true
+			}
+			b = b[n:]
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+	}
+	return false
+}
+
+// stringField returns the last value of a string field in an encoded message.
+func stringField(b []byte, field protowire.Number) (string, bool) {
+	var value string
+	found := false
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return "", false
+		}
+		b = b[n:]
+
+		if num == field && typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return "", false
+			}
+			value, found = string(v), true
+			b = b[n:]
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return "", false
+		}
+		b = b[n:]
+	}
+	return value, found
+}
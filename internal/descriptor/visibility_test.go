@@ -0,0 +1,78 @@
+package descriptor
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRegistryInternalSymbols(t *testing.T) {
+	reg, err := LoadDirectory(context.Background(), filepath.Join("testdata", "visibility"), nil)
+	if err != nil {
+		t.Fatalf("Failed to load test data: %v", err)
+	}
+
+	tests := []struct {
+		fullName string
+		internal bool
+	}{
+		{"visibility.v1.PublicService", false},
+		{"visibility.v1.PublicService/Ping", false},
+		{"visibility.v1.PublicService/Debug", true},
+		{"visibility.v1.AdminService", true},
+		{"visibility.v1.AdminService/Reset", true},
+		{"visibility.v1.PingRequest", false},
+		{"visibility.v1.DebugState", true},
+		{"visibility.internal.v1.OpsService", true},
+		{"visibility.internal.v1.OpsService/Drain", true},
+		{"visibility.internal.v1.DrainRequest", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fullName, func(t *testing.T) {
+			if got := reg.IsInternal(tt.fullName); got != tt.internal {
+				t.Errorf("IsInternal(%q) = %v, want %v", tt.fullName, got, tt.internal)
+			}
+		})
+	}
+}
+
+func TestRegistryMethodTagsAndVisibility(t *testing.T) {
+	reg, err := LoadDirectory(context.Background(), filepath.Join("testdata", "tags"), nil)
+	if err != nil {
+		t.Fatalf("Failed to load test data: %v", err)
+	}
+
+	tests := []struct {
+		method   string
+		tags     []string
+		internal bool
+	}{
+		{"tags.v1.AccountService/GetAccount", []string{"accounts"}, false},
+		{"tags.v1.AccountService/ChargeAccount", []string{"billing", "accounts"}, false},
+		{"tags.v1.AccountService/Reindex", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			method, ok := reg.FindMethod(tt.method)
+			if !ok {
+				t.Fatalf("Method %s not found", tt.method)
+			}
+			if got := reg.MethodTags(method); !slices.Equal(got, tt.tags) {
+				t.Errorf("MethodTags() = %v, want %v", got, tt.tags)
+			}
+			if got := reg.IsInternal(tt.method); got != tt.internal {
+				t.Errorf("IsInternal() = %v, want %v", got, tt.internal)
+			}
+		})
+	}
+
+	t.Run("unknown tag option", func(t *testing.T) {
+		method, _ := reg.FindMethod("tags.v1.AccountService/GetAccount")
+		if got := reg.WithTagOption("tags.v1.missing").MethodTags(method); got != nil {
+			t.Errorf("MethodTags() = %v, want none", got)
+		}
+	})
+}
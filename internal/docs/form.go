@@ -0,0 +1,280 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FormKindJSON is the kind of fields entered as raw JSON: maps, recursive
+// messages, dynamic well-known types, and repeated fields nested in repeated
+// messages.
+const FormKindJSON = "json"
+
+// maxFormDepth bounds how deeply nested messages are expanded into inputs.
+const maxFormDepth = 4
+
+// FormSpec describes an HTML form for entering a request message, as an
+// alternative to editing its JSON in the Try It panel.
+type FormSpec struct {
+	MessageType string
+	Fields      []FormField
+}
+
+// FormField describes one input, or a group of inputs for a message field.
+type FormField struct {
+	Name     string // proto field name
+	JSONName string
+	// Input is the input name, the field's JSON path (e.g. address.city).
+	// Repeated fields reuse the same name for each row, so rows are matched
+	// by position.
+	Input       string
+	Type        string // display type, e.g. string, pkg.Msg, google.protobuf.Timestamp
+	Kind        string // protoreflect kind name (string, int64, bool, enum, message, ...) or FormKindJSON
+	Repeated    bool
+	Oneof       string
+	Placeholder string
+	EnumOptions []string    // value names, for enum fields
+	Fields      []FormField // for message fields
+}
+
+// wellKnownFormKinds maps well-known types whose JSON form is a scalar to the
+// kind of input used for them.
+var wellKnownFormKinds = map[protoreflect.FullName]struct{ kind, placeholder string }{
+	"google.protobuf.Timestamp":   {"string", "2024-01-01T00:00:00Z"},
+	"google.protobuf.Duration":    {"string", "1.5s"},
+	"google.protobuf.FieldMask":   {"string", "field1,field2.nested"},
+	"google.protobuf.StringValue": {"string", ""},
+	"google.protobuf.BytesValue":  {"bytes", "base64"},
+	"google.protobuf.BoolValue":   {"bool", ""},
+	"google.protobuf.Int32Value":  {"int32", ""},
+	"google.protobuf.Int64Value":  {"int64", ""},
+	"google.protobuf.UInt32Value": {"uint32", ""},
+	"google.protobuf.UInt64Value": {"uint64", ""},
+	"google.protobuf.FloatValue":  {"float", ""},
+	"google.protobuf.DoubleValue": {"double", ""},
+}
+
+// BuildInputForm describes a form with an input for each field of msg.
+func BuildInputForm(msg protoreflect.MessageDescriptor) *FormSpec {
+	return &FormSpec{
+		MessageType: string(msg.FullName()),
+		Fields:      buildFormFields(msg, "", false, map[protoreflect.FullName]bool{msg.FullName(): true}, 0),
+	}
+}
+
+// buildFormFields describes the fields of msg. inRepeated is set inside
+// repeated messages, where nested repeated fields fall back to JSON because
+// rows are matched by position.
+func buildFormFields(msg protoreflect.MessageDescriptor, prefix string, inRepeated bool, visited map[protoreflect.FullName]bool, depth int) []FormField {
+	var fields []FormField
+	for i := 0; i < msg.Fields().Len(); i++ {
+		field := msg.Fields().Get(i)
+		f := FormField{
+			Name:     string(field.Name()),
+			JSONName: field.JSONName(),
+			Input:    prefix + field.JSONName(),
+			Type:     formatFieldType(field),
+			Kind:     field.Kind().String(),
+			Repeated: field.IsList(),
+			Oneof:    formatOneofName(field),
+		}
+
+		switch {
+		case field.IsMap(), field.IsList() && inRepeated:
+			f.Kind = FormKindJSON
+			f.Repeated = false
+			f.Placeholder = "JSON"
+		case field.Kind() == protoreflect.EnumKind:
+			values := field.Enum().Values()
+			for j := 0; j < values.Len(); j++ {
+				f.EnumOptions = append(f.EnumOptions, string(values.Get(j).Name()))
+			}
+		case field.Kind() == protoreflect.BytesKind:
+			f.Placeholder = "base64"
+		case field.Message() != nil:
+			message := field.Message()
+			if wkt, ok := wellKnownFormKinds[message.FullName()]; ok {
+				f.Kind, f.Placeholder = wkt.kind, wkt.placeholder
+				break
+			}
+			if visited[message.FullName()] || depth+1 >= maxFormDepth || message.ParentFile().Package() == "google.protobuf" {
+				f.Kind = FormKindJSON
+				f.Placeholder = "JSON"
+				break
+			}
+			f.Kind = "message" // groups too
+			visited[message.FullName()] = true
+			f.Fields = buildFormFields(message, f.Input+".", inRepeated || f.Repeated, visited, depth+1)
+			delete(visited, message.FullName())
+		}
+
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// EncodeJSON builds the request JSON from submitted form values. Empty inputs
+// are left unset.
+func (s *FormSpec) EncodeJSON(values url.Values) (string, error) {
+	object, err := encodeFormFields(s.Fields, values, 0)
+	if err != nil {
+		return "", err
+	}
+	if object == nil {
+		object = map[string]any{}
+	}
+	data, err := json.Marshal(object)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// encodeFormFields encodes one message's fields. row selects the value for
+// inputs that repeat once per row of an enclosing repeated message. Returns
+// nil if no field is set.
+func encodeFormFields(fields []FormField, values url.Values, row int) (map[string]any, error) {
+	object := make(map[string]any)
+	for _, field := range fields {
+		inputs := values[field.Input]
+
+		switch {
+		case field.Repeated && field.Kind == "message":
+			// Each row's inputs share names, so the row count is the
+			// number of values submitted for any one of them
+			var list []any
+			for i := 0; i < formRowCount(field.Fields, values); i++ {
+				item, err := encodeFormFields(field.Fields, values, i)
+				if err != nil {
+					return nil, err
+				}
+				if item != nil {
+					list = append(list, item)
+				}
+			}
+			if list != nil {
+				object[field.JSONName] = list
+			}
+		case field.Repeated:
+			var list []any
+			for _, input := range inputs {
+				if input == "" {
+					continue
+				}
+				v, err := encodeFormValue(field, input)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, v)
+			}
+			if list != nil {
+				object[field.JSONName] = list
+			}
+		case field.Kind == "message":
+			item, err := encodeFormFields(field.Fields, values, row)
+			if err != nil {
+				return nil, err
+			}
+			if item != nil {
+				object[field.JSONName] = item
+			}
+		default:
+			if row >= len(inputs) || inputs[row] == "" {
+				continue
+			}
+			v, err := encodeFormValue(field, inputs[row])
+			if err != nil {
+				return nil, err
+			}
+			object[field.JSONName] = v
+		}
+	}
+
+	if len(object) == 0 {
+		return nil, nil
+	}
+	return object, nil
+}
+
+// formRowCount returns the number of rows submitted for a repeated message.
+func formRowCount(fields []FormField, values url.Values) int {
+	for _, field := range fields {
+		if field.Kind == "message" {
+			if n := formRowCount(field.Fields, values); n > 0 {
+				return n
+			}
+			continue
+		}
+		if n := len(values[field.Input]); n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// encodeFormValue converts one input to its JSON value. 64-bit integers stay
+// strings, as in protojson, so they keep full precision.
+func encodeFormValue(field FormField, input string) (any, error) {
+	invalid := func(err error) error {
+		return fmt.Errorf("field %s: invalid %s value %q: %w", field.Input, field.Kind, input, err)
+	}
+
+	switch field.Kind {
+	case "bool":
+		v, err := strconv.ParseBool(input)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return v, nil
+	case "int32", "sint32", "sfixed32":
+		v, err := strconv.ParseInt(input, 10, 32)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return v, nil
+	case "uint32", "fixed32":
+		v, err := strconv.ParseUint(input, 10, 32)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return v, nil
+	case "int64", "sint64", "sfixed64":
+		if _, err := strconv.ParseInt(input, 10, 64); err != nil {
+			return nil, invalid(err)
+		}
+		return input, nil
+	case "uint64", "fixed64":
+		if _, err := strconv.ParseUint(input, 10, 64); err != nil {
+			return nil, invalid(err)
+		}
+		return input, nil
+	case "float", "double":
+		v, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		// JSON has no NaN or infinities; protojson spells them as strings
+		switch {
+		case math.IsNaN(v):
+			return "NaN", nil
+		case math.IsInf(v, 1):
+			return "Infinity", nil
+		case math.IsInf(v, -1):
+			return "-Infinity", nil
+		}
+		return v, nil
+	case FormKindJSON:
+		if !json.Valid([]byte(input)) {
+			return nil, invalid(fmt.Errorf("not valid JSON"))
+		}
+		return json.RawMessage(input), nil
+	default:
+		// string, bytes (base64), and enum value names
+		return input, nil
+	}
+}
@@ -0,0 +1,137 @@
+package docs
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+)
+
+func loadFormTestMessage(t *testing.T) *FormSpec {
+	t.Helper()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	msg, ok := reg.FindMessage("notifications.v1.NotificationPreferences")
+	if !ok {
+		t.Fatal("Message notifications.v1.NotificationPreferences not found")
+	}
+	return BuildInputForm(msg)
+}
+
+func TestBuildInputForm(t *testing.T) {
+	spec := loadFormTestMessage(t)
+
+	type fieldSummary struct {
+		Input       string
+		Kind        string
+		Repeated    bool
+		EnumOptions []string
+	}
+	var flatten func(fields []FormField) []fieldSummary
+	flatten = func(fields []FormField) []fieldSummary {
+		var out []fieldSummary
+		for _, f := range fields {
+			out = append(out, fieldSummary{f.Input, f.Kind, f.Repeated, f.EnumOptions})
+			out = append(out, flatten(f.Fields)...)
+		}
+		return out
+	}
+
+	channels := []string{
+		"NOTIFICATION_CHANNEL_UNSPECIFIED", "NOTIFICATION_CHANNEL_IN_APP", "NOTIFICATION_CHANNEL_EMAIL",
+		"NOTIFICATION_CHANNEL_PUSH", "NOTIFICATION_CHANNEL_SMS", "NOTIFICATION_CHANNEL_WEBHOOK",
+	}
+	types := []string{
+		"NOTIFICATION_TYPE_UNSPECIFIED", "NOTIFICATION_TYPE_SYSTEM", "NOTIFICATION_TYPE_ACCOUNT",
+		"NOTIFICATION_TYPE_ORDER", "NOTIFICATION_TYPE_PAYMENT", "NOTIFICATION_TYPE_SOCIAL",
+		"NOTIFICATION_TYPE_PROMOTIONAL", "NOTIFICATION_TYPE_REMINDER", "NOTIFICATION_TYPE_ALERT",
+	}
+	priorities := []string{
+		"NOTIFICATION_PRIORITY_UNSPECIFIED", "NOTIFICATION_PRIORITY_LOW", "NOTIFICATION_PRIORITY_NORMAL",
+		"NOTIFICATION_PRIORITY_HIGH", "NOTIFICATION_PRIORITY_URGENT",
+	}
+	frequencies := []string{
+		"DIGEST_FREQUENCY_UNSPECIFIED", "DIGEST_FREQUENCY_DAILY", "DIGEST_FREQUENCY_WEEKLY",
+		"DIGEST_FREQUENCY_BIWEEKLY", "DIGEST_FREQUENCY_MONTHLY",
+	}
+	want := []fieldSummary{
+		{"userId", "string", false, nil},
+		{"notificationsEnabled", "bool", false, nil},
+		{"channelPreferences", "message", true, nil},
+		{"channelPreferences.channel", "enum", false, channels},
+		{"channelPreferences.enabled", "bool", false, nil},
+		{"channelPreferences.config", FormKindJSON, false, nil}, // map
+		{"typePreferences", "message", true, nil},
+		{"typePreferences.type", "enum", false, types},
+		{"typePreferences.enabled", "bool", false, nil},
+		{"typePreferences.channels", FormKindJSON, false, nil}, // repeated inside a repeated message
+		{"typePreferences.minPriority", "enum", false, priorities},
+		{"quietHours", "message", false, nil},
+		{"quietHours.enabled", "bool", false, nil},
+		{"quietHours.startTime", "string", false, nil},
+		{"quietHours.endTime", "string", false, nil},
+		{"quietHours.days", "int32", true, nil},
+		{"quietHours.timezone", "string", false, nil},
+		{"digestSettings", "message", false, nil},
+		{"digestSettings.enabled", "bool", false, nil},
+		{"digestSettings.frequency", "enum", false, frequencies},
+		{"digestSettings.deliveryTime", "string", false, nil},
+		{"digestSettings.types", "enum", true, types},
+	}
+
+	got := flatten(spec.Fields)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected form fields:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestFormSpecEncodeJSON(t *testing.T) {
+	spec := loadFormTestMessage(t)
+
+	values := url.Values{
+		"userId":                     {"user-1"},
+		"notificationsEnabled":       {"true"},
+		"channelPreferences.channel": {"NOTIFICATION_CHANNEL_EMAIL", ""},
+		"channelPreferences.enabled": {"false", ""},
+		"channelPreferences.config":  {`{"digest":"daily"}`, ""},
+		"quietHours.days":            {"0", "", "6"},
+		"quietHours.startTime":       {""},
+		"digestSettings.enabled":     {""},
+	}
+
+	body, err := spec.EncodeJSON(values)
+	if err != nil {
+		t.Fatalf("EncodeJSON() error = %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("EncodeJSON() returned invalid JSON %q: %v", body, err)
+	}
+	want := map[string]any{
+		"userId":               "user-1",
+		"notificationsEnabled": true,
+		"channelPreferences": []any{
+			// The second row is empty and omitted
+			map[string]any{"channel": "NOTIFICATION_CHANNEL_EMAIL", "enabled": false, "config": map[string]any{"digest": "daily"}},
+		},
+		"quietHours": map[string]any{"days": []any{float64(0), float64(6)}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EncodeJSON() = %s, want %v", body, want)
+	}
+
+	if _, err := spec.EncodeJSON(url.Values{"quietHours.days": {"monday"}}); err == nil {
+		t.Error("Expected an error for a non-numeric int32 value")
+	}
+	if _, err := spec.EncodeJSON(url.Values{"channelPreferences.config": {"{"}}); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
@@ -0,0 +1,260 @@
+package docs
+
+import (
+	"html"
+	"html/template"
+	"net/url"
+	"strings"
+)
+
+// RenderComment converts a Markdown comment to HTML for the doc pages. It
+// supports the subset common in proto comments: paragraphs, bullet and
+// numbered lists, fenced and indented code blocks, code spans, emphasis,
+// [text](url) links, and bare http(s) URLs. All text is escaped, so HTML in a
+// comment is shown as written, and links are limited to http, https, mailto,
+// and relative URLs.
+func RenderComment(comment string) template.HTML {
+	var b strings.Builder
+	lines := strings.Split(comment, "\n")
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+
+		case strings.HasPrefix(strings.TrimSpace(line), "```"):
+			// Fenced code block, up to the closing fence or the end
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			i++
+			writeCodeBlock(&b, code)
+
+		case isIndentedCode(line):
+			var code []string
+			for ; i < len(lines) && (isIndentedCode(lines[i]) || strings.TrimSpace(lines[i]) == ""); i++ {
+				code = append(code, strings.TrimPrefix(strings.TrimPrefix(lines[i], "\t"), "    "))
+			}
+			for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+				code = code[:len(code)-1]
+			}
+			writeCodeBlock(&b, code)
+
+		case listMarker(line) != "":
+			i = writeList(&b, lines, i)
+
+		default:
+			// Paragraph, up to a blank line or the start of another block
+			var text []string
+			for ; i < len(lines); i++ {
+				l := lines[i]
+				if strings.TrimSpace(l) == "" || (len(text) > 0 && startsBlock(l)) {
+					break
+				}
+				text = append(text, strings.TrimSpace(l))
+			}
+			b.WriteString("<p>")
+			b.WriteString(renderInline(strings.Join(text, " ")))
+			b.WriteString("</p>\n")
+		}
+	}
+
+	return template.HTML(strings.TrimSuffix(b.String(), "\n"))
+}
+
+// PlainComment escapes a comment for display as plain text.
+func PlainComment(comment string) template.HTML {
+	return template.HTML(html.EscapeString(comment))
+}
+
+// startsBlock reports whether a line starts a block other than a paragraph.
+func startsBlock(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "```") || listMarker(line) != ""
+}
+
+// isIndentedCode reports whether a line is indented enough to be code.
+func isIndentedCode(line string) bool {
+	return strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+}
+
+// listMarker returns the list item marker a line starts with ("-", "*", "+",
+// or digits followed by "." or ")"), or "" if it is not a list item.
+func listMarker(line string) string {
+	if len(line) >= 2 && strings.ContainsRune("-*+", rune(line[0])) && line[1] == ' ' {
+		return line[:1]
+	}
+	digits := len(line) - len(strings.TrimLeft(line, "0123456789"))
+	if digits > 0 && digits < 10 && len(line) > digits+1 && strings.ContainsRune(".)", rune(line[digits])) && line[digits+1] == ' ' {
+		return line[:digits+1]
+	}
+	return ""
+}
+
+// writeList writes the list starting at lines[start] and returns the index of
+// the first line after it. Indented lines continue the previous item.
+func writeList(b *strings.Builder, lines []string, start int) int {
+	ordered := !strings.ContainsRune("-*+", rune(lines[start][0]))
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+
+	var items []string
+	i := start
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if marker := listMarker(line); marker != "" && (!strings.ContainsRune("-*+", rune(marker[0])) == ordered) {
+			items = append(items, strings.TrimSpace(line[len(marker):]))
+			continue
+		}
+		if strings.TrimSpace(line) != "" && (line[0] == ' ' || line[0] == '\t') {
+			items[len(items)-1] += " " + strings.TrimSpace(line)
+			continue
+		}
+		break
+	}
+
+	b.WriteString("<" + tag + ">\n")
+	for _, item := range items {
+		b.WriteString("<li>")
+		b.WriteString(renderInline(item))
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// writeCodeBlock writes escaped lines as a preformatted code block.
+func writeCodeBlock(b *strings.Builder, code []string) {
+	b.WriteString("<pre><code>")
+	b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+	b.WriteString("</code></pre>\n")
+}
+
+// renderInline renders code spans, links, bare URLs, and emphasis within a
+// block, escaping everything else.
+func renderInline(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				b.WriteString("<code>" + html.EscapeString(rest[1:1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+
+		case rest[0] == '[':
+			if label, href, n, ok := parseLink(rest); ok {
+				if safe, ok := safeURL(href); ok {
+					b.WriteString(`<a href="` + html.EscapeString(safe) + `">` + renderInline(label) + "</a>")
+				} else {
+					b.WriteString(renderInline(label))
+				}
+				i += n
+				continue
+			}
+
+		case strings.HasPrefix(rest, "http://") || strings.HasPrefix(rest, "https://"):
+			if i == 0 || !isWordByte(text[i-1]) {
+				end := strings.IndexAny(rest, " \t")
+				if end < 0 {
+					end = len(rest)
+				}
+				link := strings.TrimRight(rest[:end], ".,;:!?)")
+				if safe, ok := safeURL(link); ok {
+					b.WriteString(`<a href="` + html.EscapeString(safe) + `">` + html.EscapeString(link) + "</a>")
+					i += len(link)
+					continue
+				}
+			}
+
+		case strings.HasPrefix(rest, "**"):
+			if end := strings.Index(rest[2:], "**"); end > 0 {
+				b.WriteString("<strong>" + renderInline(rest[2:2+end]) + "</strong>")
+				i += end + 4
+				continue
+			}
+
+		case rest[0] == '*' || rest[0] == '_':
+			if end, ok := emphasisEnd(text, i); ok {
+				b.WriteString("<em>" + renderInline(text[i+1:end]) + "</em>")
+				i = end + 1
+				continue
+			}
+		}
+
+		b.WriteString(html.EscapeString(text[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// parseLink parses a [label](href) link at the start of s, returning the
+// number of bytes it spans. Parentheses in href must be balanced.
+func parseLink(s string) (label, href string, n int, ok bool) {
+	closeLabel := strings.Index(s, "](")
+	if closeLabel < 0 {
+		return "", "", 0, false
+	}
+	depth := 0
+	for i := closeLabel + 2; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return s[1:closeLabel], s[closeLabel+2 : i], i + 1, true
+			}
+			depth--
+		}
+	}
+	return "", "", 0, false
+}
+
+// emphasisEnd finds the delimiter closing the emphasis opened at text[start].
+// Delimiters inside words, as in snake_case names, do not count.
+func emphasisEnd(text string, start int) (int, bool) {
+	delim := text[start]
+	if start > 0 && isWordByte(text[start-1]) {
+		return 0, false
+	}
+	if start+1 >= len(text) || text[start+1] == ' ' || text[start+1] == delim {
+		return 0, false
+	}
+	for end := start + 2; end < len(text); end++ {
+		if text[end] != delim || text[end-1] == ' ' {
+			continue
+		}
+		if end+1 < len(text) && isWordByte(text[end+1]) {
+			continue
+		}
+		return end, true
+	}
+	return 0, false
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// safeURL returns href if it is an http, https, or mailto URL, or a relative
+// URL; anything else, such as javascript: URLs, is rejected.
+func safeURL(href string) (string, bool) {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.ContainsAny(href, " \t\n\"'<>") {
+		return "", false
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return href, true
+	}
+	return "", false
+}
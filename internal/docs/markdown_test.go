@@ -0,0 +1,104 @@
+package docs
+
+import (
+	"html/template"
+	"testing"
+)
+
+func TestRenderComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment string
+		want    template.HTML
+	}{
+		{
+			name:    "plain text",
+			comment: "Returns a user.",
+			want:    "<p>Returns a user.</p>",
+		},
+		{
+			name:    "link",
+			comment: "See [the guide](https://example.com/guide?a=1&b=2) for details.",
+			want:    `<p>See <a href="https://example.com/guide?a=1&amp;b=2">the guide</a> for details.</p>`,
+		},
+		{
+			name:    "relative link",
+			comment: "Uses [User](/types/users.v1.User).",
+			want:    `<p>Uses <a href="/types/users.v1.User">User</a>.</p>`,
+		},
+		{
+			name:    "bullet list",
+			comment: "Modes:\n- FAST skips checks\n- SAFE runs\n  every check",
+			want:    "<p>Modes:</p>\n<ul>\n<li>FAST skips checks</li>\n<li>SAFE runs every check</li>\n</ul>",
+		},
+		{
+			name:    "numbered list",
+			comment: "1. Create\n2. Delete",
+			want:    "<ol>\n<li>Create</li>\n<li>Delete</li>\n</ol>",
+		},
+		{
+			name:    "paragraphs",
+			comment: "First.\n\nSecond.",
+			want:    "<p>First.</p>\n<p>Second.</p>",
+		},
+		{
+			name:    "code span and emphasis",
+			comment: "Set `page_size` to **at most** 100, *not* more.",
+			want:    "<p>Set <code>page_size</code> to <strong>at most</strong> 100, <em>not</em> more.</p>",
+		},
+		{
+			name:    "snake_case is not emphasis",
+			comment: "Use user_id and created_at_time.",
+			want:    "<p>Use user_id and created_at_time.</p>",
+		},
+		{
+			name:    "fenced code",
+			comment: "Example:\n```\nif a < b {\n}\n```",
+			want:    "<p>Example:</p>\n<pre><code>if a &lt; b {\n}</code></pre>",
+		},
+		{
+			name:    "indented code",
+			comment: "Example:\n\n    rpc Get(Req) returns (Resp);",
+			want:    "<p>Example:</p>\n<pre><code>rpc Get(Req) returns (Resp);</code></pre>",
+		},
+		{
+			name:    "bare URL",
+			comment: "Docs at https://example.com/docs.",
+			want:    `<p>Docs at <a href="https://example.com/docs">https://example.com/docs</a>.</p>`,
+		},
+		{
+			name:    "HTML is escaped",
+			comment: `<script>alert("x")</script> & <b>bold</b>`,
+			want:    "<p>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; &lt;b&gt;bold&lt;/b&gt;</p>",
+		},
+		{
+			name:    "unsafe link scheme",
+			comment: "[click](javascript:alert(1))",
+			want:    "<p>click</p>",
+		},
+		{
+			name:    "attribute injection",
+			comment: `[x](https://example.com/"onmouseover="alert(1))`,
+			want:    `<p>x</p>`,
+		},
+		{
+			name:    "empty",
+			comment: "",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderComment(tt.comment); got != tt.want {
+				t.Errorf("RenderComment(%q) =\n%s\nwant\n%s", tt.comment, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainComment(t *testing.T) {
+	if got, want := PlainComment("a < b\n- [x](y)"), template.HTML("a &lt; b\n- [x](y)"); got != want {
+		t.Errorf("PlainComment() = %q, want %q", got, want)
+	}
+}
@@ -1,28 +1,108 @@
 package docs
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"math"
+	"net/url"
+	"path"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/bnprtr/reflect/internal/descriptor"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 // Index represents the main overview page with all services.
 type Index struct {
 	Services []ServiceSummary
+
+	// Groups sections Services by the configured service groups. It is nil
+	// when no groups are configured.
+	Groups []ServiceGroup
+
+	// Packages is the sidebar's package tree of services, messages, and enums.
+	Packages []PackageNode
+}
+
+// ServiceGroup is a named section of services on the index.
+type ServiceGroup struct {
+	Name     string
+	Services []ServiceSummary
+}
+
+// OtherServiceGroup names the section for services that match no group.
+const OtherServiceGroup = "Other"
+
+// IndexOptions configures BuildIndexWithOptions.
+type IndexOptions struct {
+	// HideInternal excludes internal-only services.
+	HideInternal bool
+
+	// ServiceGroups maps section names (e.g. "Billing") to globs (path.Match
+	// syntax) matched against full service names. Groups are listed by name,
+	// a service matching several goes in the first, and services matching
+	// none go in OtherServiceGroup.
+	ServiceGroups map[string][]string
 }
 
 // ServiceSummary represents a service in the index.
 type ServiceSummary struct {
 	Name, FullName, Package, Comment string
+	Internal                         bool
 }
 
 // ServiceView represents a detailed service view.
 type ServiceView struct {
 	Name, FullName, Package, Comment string
+	Internal                         bool
 	Methods                          []MethodSummary
+	// TagGroups lists the methods by tag; empty when no method is tagged.
+	TagGroups []MethodTagGroup
+	// SourceFile is the import path of the file declaring the service;
+	// empty if its source is not available.
+	SourceFile string
+}
+
+// untaggedGroup names the tag group holding methods without tags.
+const untaggedGroup = "Other"
+
+// MethodTagGroup is the set of methods sharing a tag.
+type MethodTagGroup struct {
+	Tag     string
+	Methods []MethodSummary
+}
+
+// HideInternalMethods removes internal methods from the view, dropping tag
+// groups left empty.
+func (v *ServiceView) HideInternalMethods() {
+	v.Methods = withoutInternalMethods(v.Methods)
+	var groups []MethodTagGroup
+	for _, group := range v.TagGroups {
+		group.Methods = withoutInternalMethods(group.Methods)
+		if len(group.Methods) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	v.TagGroups = groups
+}
+
+// withoutInternalMethods returns methods minus the internal ones.
+func withoutInternalMethods(methods []MethodSummary) []MethodSummary {
+	var visible []MethodSummary
+	for _, method := range methods {
+		if !method.Internal {
+			visible = append(visible, method)
+		}
+	}
+	return visible
 }
 
 // HTTPRule represents a single HTTP mapping rule.
@@ -37,22 +117,85 @@ type HTTPRule struct {
 type MethodSummary struct {
 	Name, FullName, Comment          string
 	InputType, OutputType            string
+	InputComment, OutputComment      string // comments on the input and output messages
 	ClientStreaming, ServerStreaming bool
 	Deprecated                       bool
+	Internal                         bool
+	Tags                             []string
 	HTTPRules                        []HTTPRule
 	Examples                         struct {
-		Curl    string
-		Grpcurl string
+		Curl        string
+		Grpcurl     string
+		ConnectCurl string // empty for streaming methods
 	}
 	ExampleRequest  string
 	ExampleResponse string
+	// ExampleRequestStream and ExampleResponseStream hold a few varied
+	// sample messages for the streamed side of a streaming method.
+	ExampleRequestStream  []string
+	ExampleResponseStream []string
+	// ExampleHTTPBody is the example body for the first HTTP rule, scoped to
+	// the rule's body field. Empty when the rule has no body.
+	ExampleHTTPBody string
+	// ServiceConfig is the timeout and retry policy from the configured gRPC
+	// service config, if any.
+	ServiceConfig *MethodServiceConfig
 }
 
 // MessageView represents a detailed message view.
 type MessageView struct {
 	Name, FullName, Package, Comment string
+	Internal                         bool
 	Fields                           []FieldView
 	ExampleJSON                      string
+	// UsedAsInput and UsedAsOutput list the RPCs that accept or return this
+	// message, sorted by full name.
+	UsedAsInput, UsedAsOutput []TypeRef
+	// Oneofs groups the fields of each oneof, in declaration order. Synthetic
+	// oneofs from proto3 optional fields are excluded.
+	Oneofs []OneofView
+	// Options holds the custom options set on the message, keyed by option
+	// name (e.g. "acme.v1.resource"), formatted as proto values.
+	Options map[string]string
+	// SourceFile is the import path of the file declaring the message;
+	// empty if its source is not available.
+	SourceFile string
+}
+
+// HasOptions reports whether the message or any of its fields sets a custom
+// option.
+func (v *MessageView) HasOptions() bool {
+	if len(v.Options) > 0 {
+		return true
+	}
+	for _, field := range v.Fields {
+		if len(field.Options) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// OneofView represents a oneof and its member fields.
+type OneofView struct {
+	Name    string
+	Comment string
+	Fields  []FieldView
+}
+
+// TypeRef is a link to another documented element. For RPCs, FullName is the
+// method key used in /methods/ URLs (e.g. pkg.Service/Method).
+type TypeRef struct {
+	Name, FullName string
+}
+
+// Reference is a link to a message field that refers to a type.
+type Reference struct {
+	// Message is the full name of the message declaring the field.
+	Message string
+	// Field is the field's name, and Anchor its element ID on the message's
+	// type page.
+	Field, Anchor string
 }
 
 // FieldView represents a field in a message.
@@ -63,34 +206,98 @@ type FieldView struct {
 	Label   string // repeated / optional / required (proto2)
 	Oneof   string // if part of a oneof
 	Comment string
+	// HasPresence reports whether the field tracks if it was set, so an unset
+	// field is distinguishable from its default value. True for message
+	// fields, oneof members, and optional scalars; false for repeated fields
+	// and implicit-presence proto3 scalars.
+	HasPresence bool
+	// WireType is the wire encoding of each value (e.g. "varint",
+	// "length-delimited"); Packed reports that a repeated scalar's values are
+	// sent together in one length-delimited record.
+	WireType string
+	Packed   bool
+	// JSONName is the lowerCamelCase name used in JSON (e.g. fullName for
+	// full_name), which Try It bodies and Connect requests use.
+	JSONName string
+	// Options holds the custom options set on the field (e.g.
+	// "google.api.field_behavior" = "[REQUIRED]"), keyed by option name.
+	Options map[string]string
+	// Anchor is the field's element ID on the type page (see FieldAnchor).
+	Anchor string
+	// Default is the field's declared default in proto syntax (e.g. 5,
+	// "anonymous", TIER_BASIC); empty if it has none.
+	Default string
 }
 
 // EnumView represents a detailed enum view.
 type EnumView struct {
 	Name, FullName, Package, Comment string
+	Internal                         bool
 	Values                           []EnumValueView
+	// SortBy is the order of Values: EnumSortByNumber or EnumSortByName.
+	SortBy string
+	// SourceFile is the import path of the file declaring the enum; empty
+	// if its source is not available.
+	SourceFile string
+	// UsedByFields lists the message fields of this enum type, including
+	// map fields with enum values, sorted by message then field name.
+	UsedByFields []Reference
 }
 
 // EnumValueView represents a value in an enum.
 type EnumValueView struct {
-	Name    string
-	Number  int32
-	Comment string
+	Name       string
+	Number     int32
+	Comment    string
+	Deprecated bool
+	// Alias reports that an earlier value has the same number, which
+	// requires the enum's allow_alias option.
+	Alias bool
+	// Anchor is the value's element ID on the type page (see EnumValueAnchor).
+	Anchor string
 }
 
+// FieldAnchor returns the element ID of a field on its message's type page,
+// such as "field-full_name". Proto names are URL-safe, and the prefix keeps
+// the ID apart from the page's other elements.
+func FieldAnchor(name string) string {
+	return "field-" + name
+}
+
+// EnumValueAnchor returns the element ID of a value on its enum's type page,
+// such as "value-STATUS_ACTIVE".
+func EnumValueAnchor(name string) string {
+	return "value-" + name
+}
+
+// Orders for EnumView.SortValues.
+const (
+	EnumSortByNumber = "number"
+	EnumSortByName   = "name"
+)
+
 // BuildIndex creates an index view from the registry.
 func BuildIndex(reg *descriptor.Registry) (*Index, error) {
+	return BuildIndexWithOptions(reg, IndexOptions{})
+}
+
+// BuildIndexWithOptions creates an index view from the registry using the given options.
+func BuildIndexWithOptions(reg *descriptor.Registry, opts IndexOptions) (*Index, error) {
 	if reg == nil {
 		return &Index{Services: []ServiceSummary{}}, nil
 	}
 
 	var services []ServiceSummary
 	for _, service := range reg.ServicesByName {
+		if opts.HideInternal && reg.IsInternal(string(service.FullName())) {
+			continue
+		}
 		summary := ServiceSummary{
 			Name:     string(service.Name()),
 			FullName: string(service.FullName()),
 			Package:  string(service.ParentFile().Package()),
 			Comment:  reg.CommentIndex[string(service.FullName())],
+			Internal: reg.IsInternal(string(service.FullName())),
 		}
 		services = append(services, summary)
 	}
@@ -100,7 +307,124 @@ func BuildIndex(reg *descriptor.Registry) (*Index, error) {
 		return services[i].FullName < services[j].FullName
 	})
 
-	return &Index{Services: services}, nil
+	index := &Index{Services: services, Packages: BuildPackageTreeWithOptions(reg, opts)}
+	if len(opts.ServiceGroups) > 0 {
+		index.Groups = groupServices(services, opts.ServiceGroups)
+	}
+	return index, nil
+}
+
+// PackageNode is a proto package in the sidebar's package tree. Symbol names
+// are relative to the package, so a nested message reads "Outer.Inner".
+type PackageNode struct {
+	// Name is the package name, or "" for the default package.
+	Name     string
+	Services []TypeRef
+	Messages []TypeRef
+	Enums    []TypeRef
+}
+
+// BuildPackageTree groups the registry's services, messages, and enums by
+// package. Packages are sorted by name, with the default package first, and
+// symbols by name within them.
+func BuildPackageTree(reg *descriptor.Registry) []PackageNode {
+	return BuildPackageTreeWithOptions(reg, IndexOptions{})
+}
+
+// BuildPackageTreeWithOptions builds the package tree, leaving out internal
+// symbols when opts.HideInternal is set.
+func BuildPackageTreeWithOptions(reg *descriptor.Registry, opts IndexOptions) []PackageNode {
+	if reg == nil {
+		return nil
+	}
+
+	nodes := make(map[string]*PackageNode)
+	add := func(fullName protoreflect.FullName, file protoreflect.FileDescriptor, list func(*PackageNode) *[]TypeRef) {
+		if opts.HideInternal && reg.IsInternal(string(fullName)) {
+			return
+		}
+		pkg := string(file.Package())
+		node, ok := nodes[pkg]
+		if !ok {
+			node = &PackageNode{Name: pkg}
+			nodes[pkg] = node
+		}
+		name := strings.TrimPrefix(string(fullName), pkg+".")
+		if pkg == "" {
+			name = string(fullName)
+		}
+		refs := list(node)
+		*refs = append(*refs, TypeRef{Name: name, FullName: string(fullName)})
+	}
+
+	for _, service := range reg.ServicesByName {
+		add(service.FullName(), service.ParentFile(), func(n *PackageNode) *[]TypeRef { return &n.Services })
+	}
+	for _, message := range reg.MessagesByName {
+		// Map entries are synthesized and have no page of their own
+		if message.IsMapEntry() {
+			continue
+		}
+		add(message.FullName(), message.ParentFile(), func(n *PackageNode) *[]TypeRef { return &n.Messages })
+	}
+	for _, enum := range reg.EnumsByName {
+		add(enum.FullName(), enum.ParentFile(), func(n *PackageNode) *[]TypeRef { return &n.Enums })
+	}
+
+	tree := make([]PackageNode, 0, len(nodes))
+	for _, node := range nodes {
+		for _, refs := range [][]TypeRef{node.Services, node.Messages, node.Enums} {
+			sort.Slice(refs, func(i, j int) bool { return refs[i].FullName < refs[j].FullName })
+		}
+		tree = append(tree, *node)
+	}
+	sort.Slice(tree, func(i, j int) bool { return tree[i].Name < tree[j].Name })
+	return tree
+}
+
+// groupServices sections sorted services into the configured groups, in group
+// name order, followed by OtherServiceGroup. Empty groups are omitted.
+func groupServices(services []ServiceSummary, serviceGroups map[string][]string) []ServiceGroup {
+	names := make([]string, 0, len(serviceGroups))
+	for name := range serviceGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	members := make(map[string][]ServiceSummary, len(names))
+	var other []ServiceSummary
+	for _, service := range services {
+		group, ok := matchServiceGroup(service.FullName, names, serviceGroups)
+		if !ok {
+			other = append(other, service)
+			continue
+		}
+		members[group] = append(members[group], service)
+	}
+
+	var groups []ServiceGroup
+	for _, name := range names {
+		if len(members[name]) > 0 {
+			groups = append(groups, ServiceGroup{Name: name, Services: members[name]})
+		}
+	}
+	if len(other) > 0 {
+		groups = append(groups, ServiceGroup{Name: OtherServiceGroup, Services: other})
+	}
+	return groups
+}
+
+// matchServiceGroup returns the first group, in the given order, with a glob
+// matching the service's full name.
+func matchServiceGroup(fullName string, names []string, serviceGroups map[string][]string) (string, bool) {
+	for _, name := range names {
+		for _, pattern := range serviceGroups[name] {
+			if ok, _ := path.Match(pattern, fullName); ok {
+				return name, true
+			}
+		}
+	}
+	return "", false
 }
 
 // BuildServiceView creates a service view from the registry.
@@ -128,16 +452,18 @@ func BuildServiceView(reg *descriptor.Registry, fullName string) (*ServiceView,
 			ClientStreaming: method.IsStreamingClient(),
 			ServerStreaming: method.IsStreamingServer(),
 			Deprecated:      false, // TODO: implement deprecated detection
+			Internal:        reg.IsInternal(methodName),
+			Tags:            reg.MethodTags(method),
 		}
 
 		// Generate example request and response JSON
 		if inputMsg, exists := reg.FindMessage(string(method.Input().FullName())); exists {
-			if example, err := descriptor.GenerateExampleJSON(inputMsg, descriptor.DefaultExampleOptions()); err == nil {
+			if example, err := descriptor.GenerateExampleJSON(inputMsg, reg.ExampleOptions()); err == nil {
 				summary.ExampleRequest = example
 			}
 		}
 		if outputMsg, exists := reg.FindMessage(string(method.Output().FullName())); exists {
-			if example, err := descriptor.GenerateExampleJSON(outputMsg, descriptor.DefaultExampleOptions()); err == nil {
+			if example, err := descriptor.GenerateExampleJSON(outputMsg, reg.ExampleOptions()); err == nil {
 				summary.ExampleResponse = example
 			}
 		}
@@ -151,14 +477,62 @@ func BuildServiceView(reg *descriptor.Registry, fullName string) (*ServiceView,
 	})
 
 	return &ServiceView{
-		Name:     string(service.Name()),
-		FullName: fullName,
-		Package:  string(service.ParentFile().Package()),
-		Comment:  reg.CommentIndex[fullName],
-		Methods:  methods,
+		Name:       string(service.Name()),
+		FullName:   fullName,
+		Package:    string(service.ParentFile().Package()),
+		Comment:    reg.CommentIndex[fullName],
+		Internal:   reg.IsInternal(fullName),
+		Methods:    methods,
+		TagGroups:  groupMethodsByTag(methods),
+		SourceFile: sourceFile(reg, service),
 	}, nil
 }
 
+// sourceFile returns the import path of the file declaring d, or "" if the
+// registry holds no source for it.
+func sourceFile(reg *descriptor.Registry, d protoreflect.Descriptor) string {
+	path := d.ParentFile().Path()
+	if _, ok := reg.Source(path); !ok {
+		return ""
+	}
+	return path
+}
+
+// groupMethodsByTag groups methods under each of their tags, sorted by tag,
+// with untagged methods in a final group. It returns nil when no method has
+// a tag.
+func groupMethodsByTag(methods []MethodSummary) []MethodTagGroup {
+	byTag := make(map[string][]MethodSummary)
+	var untagged []MethodSummary
+	for _, method := range methods {
+		if len(method.Tags) == 0 {
+			untagged = append(untagged, method)
+			continue
+		}
+		for _, tag := range method.Tags {
+			byTag[tag] = append(byTag[tag], method)
+		}
+	}
+	if len(byTag) == 0 {
+		return nil
+	}
+
+	groups := make([]MethodTagGroup, 0, len(byTag)+1)
+	for tag, tagged := range byTag {
+		groups = append(groups, MethodTagGroup{Tag: tag, Methods: tagged})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Tag < groups[j].Tag
+	})
+	if len(untagged) > 0 {
+		groups = append(groups, MethodTagGroup{Tag: untaggedGroup, Methods: untagged})
+	}
+	return groups
+}
+
+// streamExampleCount is the number of sample messages shown for a stream.
+const streamExampleCount = 3
+
 // BuildMethodView creates a method view from the registry.
 func BuildMethodView(reg *descriptor.Registry, fullName string) (*MethodSummary, error) {
 	if reg == nil {
@@ -176,38 +550,62 @@ func BuildMethodView(reg *descriptor.Registry, fullName string) (*MethodSummary,
 		Comment:         reg.CommentIndex[fullName],
 		InputType:       string(method.Input().FullName()),
 		OutputType:      string(method.Output().FullName()),
+		InputComment:    reg.CommentIndex[string(method.Input().FullName())],
+		OutputComment:   reg.CommentIndex[string(method.Output().FullName())],
 		ClientStreaming: method.IsStreamingClient(),
 		ServerStreaming: method.IsStreamingServer(),
 		Deprecated:      false, // TODO: implement deprecated detection
+		Internal:        reg.IsInternal(fullName),
+		Tags:            reg.MethodTags(method),
 	}
 
 	// Extract HTTP rules
-	httpRules, err := extractHTTPRules(method)
+	httpRules, err := extractHTTPRules(reg, method)
 	if err != nil {
 		// Log error but don't fail - HTTP rules are optional
-		fmt.Printf("Warning: failed to extract HTTP rules for %s: %v\n", fullName, err)
+		slog.Warn("Failed to extract HTTP rules", "method", fullName, "error", err)
 	} else {
 		summary.HTTPRules = httpRules
 	}
 
-	// Generate examples
-	summary.Examples.Curl = generateCurlExample(summary)
-	summary.Examples.Grpcurl = generateGrpcurlExample(summary)
+	if len(summary.HTTPRules) > 0 {
+		// An unresolvable body field leaves the body example empty
+		if body, err := generateHTTPBodyExample(method.Input(), summary.HTTPRules[0].Body, reg.ExampleOptions()); err == nil {
+			summary.ExampleHTTPBody = body
+		}
+	}
 
 	// Generate example request and response JSON
 	if reg != nil {
 		if inputMsg, exists := reg.FindMessage(string(method.Input().FullName())); exists {
-			if example, err := descriptor.GenerateExampleJSON(inputMsg, descriptor.DefaultExampleOptions()); err == nil {
+			if example, err := descriptor.GenerateExampleJSON(inputMsg, reg.ExampleOptions()); err == nil {
 				summary.ExampleRequest = example
 			}
 		}
 		if outputMsg, exists := reg.FindMessage(string(method.Output().FullName())); exists {
-			if example, err := descriptor.GenerateExampleJSON(outputMsg, descriptor.DefaultExampleOptions()); err == nil {
+			if example, err := descriptor.GenerateExampleJSON(outputMsg, reg.ExampleOptions()); err == nil {
 				summary.ExampleResponse = example
 			}
 		}
+
+		// Streamed sides show a sequence of messages
+		if method.IsStreamingClient() {
+			if examples, err := descriptor.GenerateExampleJSONSequence(method.Input(), reg.ExampleOptions(), streamExampleCount); err == nil {
+				summary.ExampleRequestStream = examples
+			}
+		}
+		if method.IsStreamingServer() {
+			if examples, err := descriptor.GenerateExampleJSONSequence(method.Output(), reg.ExampleOptions(), streamExampleCount); err == nil {
+				summary.ExampleResponseStream = examples
+			}
+		}
 	}
 
+	// Generate examples
+	summary.Examples.Curl = generateCurlExample(summary)
+	summary.Examples.Grpcurl = GrpcurlExample(summary, GrpcurlTarget{})
+	summary.Examples.ConnectCurl = ConnectCurlExample(summary, "")
+
 	return summary, nil
 }
 
@@ -222,18 +620,32 @@ func BuildMessageView(reg *descriptor.Registry, fullName string) (*MessageView,
 		return nil, fmt.Errorf("message %q not found", fullName)
 	}
 
+	commentOption := findCommentOption(reg)
+
 	var fields []FieldView
 	for i := 0; i < message.Fields().Len(); i++ {
 		field := message.Fields().Get(i)
 		fieldName := fmt.Sprintf("%s.%s", fullName, field.Name())
 
+		comment := reg.CommentIndex[fieldName]
+		if comment == "" && commentOption != nil {
+			comment = optionComment(reg, field, commentOption)
+		}
+
 		fieldView := FieldView{
-			Name:    string(field.Name()),
-			Number:  int(field.Number()),
-			Type:    formatFieldType(field),
-			Label:   formatFieldLabel(field),
-			Oneof:   formatOneofName(field),
-			Comment: reg.CommentIndex[fieldName],
+			Name:        string(field.Name()),
+			JSONName:    field.JSONName(),
+			Number:      int(field.Number()),
+			Type:        formatFieldType(field),
+			Label:       formatFieldLabel(field),
+			Oneof:       formatOneofName(field),
+			Comment:     comment,
+			HasPresence: field.HasPresence(),
+			WireType:    formatWireType(field),
+			Packed:      field.IsPacked(),
+			Options:     customOptions(reg, field.Options()),
+			Anchor:      FieldAnchor(string(field.Name())),
+			Default:     formatFieldDefault(field),
 		}
 		fields = append(fields, fieldView)
 	}
@@ -246,21 +658,69 @@ func BuildMessageView(reg *descriptor.Registry, fullName string) (*MessageView,
 	// Generate example JSON
 	exampleJSON := ""
 	if reg != nil {
-		if example, err := descriptor.GenerateExampleJSON(message, descriptor.DefaultExampleOptions()); err == nil {
+		if example, err := descriptor.GenerateExampleJSON(message, reg.ExampleOptions()); err == nil {
 			exampleJSON = example
 		}
 	}
 
+	usedAsInput, usedAsOutput := findMethodUsages(reg, fullName)
+
+	var oneofs []OneofView
+	for i := 0; i < message.Oneofs().Len(); i++ {
+		oneof := message.Oneofs().Get(i)
+		if oneof.IsSynthetic() {
+			continue
+		}
+		oneofView := OneofView{
+			Name:    string(oneof.Name()),
+			Comment: reg.CommentIndex[fmt.Sprintf("%s.%s", fullName, oneof.Name())],
+		}
+		for _, field := range fields {
+			if field.Oneof == oneofView.Name {
+				oneofView.Fields = append(oneofView.Fields, field)
+			}
+		}
+		oneofs = append(oneofs, oneofView)
+	}
+
 	return &MessageView{
-		Name:        string(message.Name()),
-		FullName:    fullName,
-		Package:     string(message.ParentFile().Package()),
-		Comment:     reg.CommentIndex[fullName],
-		Fields:      fields,
-		ExampleJSON: exampleJSON,
+		Name:         string(message.Name()),
+		FullName:     fullName,
+		Package:      string(message.ParentFile().Package()),
+		Comment:      reg.CommentIndex[fullName],
+		Internal:     reg.IsInternal(fullName),
+		Fields:       fields,
+		ExampleJSON:  exampleJSON,
+		UsedAsInput:  usedAsInput,
+		UsedAsOutput: usedAsOutput,
+		Oneofs:       oneofs,
+		Options:      customOptions(reg, message.Options()),
+		SourceFile:   sourceFile(reg, message),
 	}, nil
 }
 
+// findMethodUsages returns the RPCs whose input or output type is the given
+// message.
+func findMethodUsages(reg *descriptor.Registry, fullName string) (inputs, outputs []TypeRef) {
+	for methodName, method := range reg.MethodsByName {
+		ref := TypeRef{Name: string(method.Name()), FullName: methodName}
+		if string(method.Input().FullName()) == fullName {
+			inputs = append(inputs, ref)
+		}
+		if string(method.Output().FullName()) == fullName {
+			outputs = append(outputs, ref)
+		}
+	}
+
+	sort.Slice(inputs, func(i, j int) bool {
+		return inputs[i].FullName < inputs[j].FullName
+	})
+	sort.Slice(outputs, func(i, j int) bool {
+		return outputs[i].FullName < outputs[j].FullName
+	})
+	return inputs, outputs
+}
+
 // BuildEnumView creates an enum view from the registry.
 func BuildEnumView(reg *descriptor.Registry, fullName string) (*EnumView, error) {
 	if reg == nil {
@@ -277,32 +737,81 @@ func BuildEnumView(reg *descriptor.Registry, fullName string) (*EnumView, error)
 		value := enum.Values().Get(i)
 		valueName := fmt.Sprintf("%s.%s", fullName, value.Name())
 
+		options, _ := value.Options().(*descriptorpb.EnumValueOptions)
 		valueView := EnumValueView{
-			Name:    string(value.Name()),
-			Number:  int32(value.Number()),
-			Comment: reg.CommentIndex[valueName],
+			Name:       string(value.Name()),
+			Number:     int32(value.Number()),
+			Comment:    reg.CommentIndex[valueName],
+			Deprecated: options.GetDeprecated(),
+			Alias:      enum.Values().ByNumber(value.Number()) != value,
+			Anchor:     EnumValueAnchor(string(value.Name())),
 		}
 		values = append(values, valueView)
 	}
 
-	// Sort values by number
-	sort.Slice(values, func(i, j int) bool {
-		return values[i].Number < values[j].Number
+	view := &EnumView{
+		Name:         string(enum.Name()),
+		FullName:     fullName,
+		Package:      string(enum.ParentFile().Package()),
+		Comment:      reg.CommentIndex[fullName],
+		Internal:     reg.IsInternal(fullName),
+		Values:       values,
+		SourceFile:   sourceFile(reg, enum),
+		UsedByFields: findEnumUsages(reg, fullName),
+	}
+	view.SortValues(EnumSortByNumber)
+	return view, nil
+}
+
+// findEnumUsages returns the message fields whose type, or map value type,
+// is the given enum. Map entry messages are skipped in favor of the map field.
+func findEnumUsages(reg *descriptor.Registry, fullName string) []Reference {
+	var refs []Reference
+	for messageName, message := range reg.MessagesByName {
+		if message.IsMapEntry() {
+			continue
+		}
+		fields := message.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			field := fields.Get(i)
+			if field.IsMap() {
+				field = field.MapValue()
+			}
+			if field.Kind() != protoreflect.EnumKind || string(field.Enum().FullName()) != fullName {
+				continue
+			}
+			name := string(fields.Get(i).Name())
+			refs = append(refs, Reference{Message: messageName, Field: name, Anchor: FieldAnchor(name)})
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Message != refs[j].Message {
+			return refs[i].Message < refs[j].Message
+		}
+		return refs[i].Field < refs[j].Field
 	})
+	return refs
+}
 
-	return &EnumView{
-		Name:     string(enum.Name()),
-		FullName: fullName,
-		Package:  string(enum.ParentFile().Package()),
-		Comment:  reg.CommentIndex[fullName],
-		Values:   values,
-	}, nil
+// SortValues orders the values by number (the default, with aliases in
+// declaration order) or by name. Unknown orders sort by number.
+func (v *EnumView) SortValues(by string) {
+	if by != EnumSortByName {
+		by = EnumSortByNumber
+	}
+	v.SortBy = by
+	sort.SliceStable(v.Values, func(i, j int) bool {
+		if by == EnumSortByName {
+			return v.Values[i].Name < v.Values[j].Name
+		}
+		return v.Values[i].Number < v.Values[j].Number
+	})
 }
 
 // formatFieldType formats a field type for display.
 func formatFieldType(field protoreflect.FieldDescriptor) string {
 	switch field.Kind() {
-	case protoreflect.MessageKind:
+	case protoreflect.MessageKind, protoreflect.GroupKind:
 		return string(field.Message().FullName())
 	case protoreflect.EnumKind:
 		return string(field.Enum().FullName())
@@ -311,6 +820,39 @@ func formatFieldType(field protoreflect.FieldDescriptor) string {
 	}
 }
 
+// formatFieldDefault formats a field's declared default as it would be
+// written in a .proto file, or returns "" if it has none.
+func formatFieldDefault(field protoreflect.FieldDescriptor) string {
+	if !field.HasDefault() {
+		return ""
+	}
+	value := field.Default()
+	switch field.Kind() {
+	case protoreflect.EnumKind:
+		return string(field.DefaultEnumValue().Name())
+	case protoreflect.StringKind:
+		return strconv.Quote(value.String())
+	case protoreflect.BytesKind:
+		return strconv.Quote(string(value.Bytes()))
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		f := value.Float()
+		switch {
+		case math.IsNaN(f):
+			return "nan"
+		case math.IsInf(f, 1):
+			return "inf"
+		case math.IsInf(f, -1):
+			return "-inf"
+		}
+		if field.Kind() == protoreflect.FloatKind {
+			return strconv.FormatFloat(f, 'g', -1, 32)
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
 // formatFieldLabel formats a field label for display.
 func formatFieldLabel(field protoreflect.FieldDescriptor) string {
 	if field.Cardinality() == protoreflect.Repeated {
@@ -325,6 +867,23 @@ func formatFieldLabel(field protoreflect.FieldDescriptor) string {
 	return ""
 }
 
+// formatWireType names the wire type a field's values are encoded with.
+func formatWireType(field protoreflect.FieldDescriptor) string {
+	switch field.Kind() {
+	case protoreflect.DoubleKind, protoreflect.Fixed64Kind, protoreflect.Sfixed64Kind:
+		return "64-bit"
+	case protoreflect.FloatKind, protoreflect.Fixed32Kind, protoreflect.Sfixed32Kind:
+		return "32-bit"
+	case protoreflect.StringKind, protoreflect.BytesKind, protoreflect.MessageKind:
+		return "length-delimited"
+	case protoreflect.GroupKind:
+		return "group"
+	default:
+		// bool, enum, and the int, uint, and sint kinds
+		return "varint"
+	}
+}
+
 // formatOneofName formats a oneof name for display.
 func formatOneofName(field protoreflect.FieldDescriptor) string {
 	if field.ContainingOneof() != nil {
@@ -333,36 +892,261 @@ func formatOneofName(field protoreflect.FieldDescriptor) string {
 	return ""
 }
 
-// extractHTTPRules extracts HTTP rules from a method descriptor.
-func extractHTTPRules(method protoreflect.MethodDescriptor) ([]HTTPRule, error) {
-	// TODO: Implement proper HTTP rule extraction using proto.GetExtension
-	// This requires importing the google.api.annotations package and
-	// properly resolving the extension descriptor.
-	// For now, we'll return empty rules as the extension handling
-	// requires more complex protobuf extension resolution.
+// extractHTTPRules extracts HTTP rules from a method's google.api.http option.
+// The option is resolved dynamically, so rules are only found when
+// google/api/annotations.proto is part of the registry.
+func extractHTTPRules(reg *descriptor.Registry, method protoreflect.MethodDescriptor) ([]HTTPRule, error) {
+	d, err := reg.Files.FindDescriptorByName("google.api.http")
+	if err != nil {
+		return nil, nil
+	}
+	ext, ok := d.(protoreflect.ExtensionDescriptor)
+	if !ok {
+		return nil, nil
+	}
+
+	// Re-parse the options with the registry's types so the extension is
+	// decoded instead of being left in unknown fields.
+	raw, err := proto.Marshal(method.Options())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal method options: %w", err)
+	}
+	options := dynamicpb.NewMessage(ext.ContainingMessage())
+	if err := (proto.UnmarshalOptions{Resolver: reg.Resolver()}).Unmarshal(raw, options); err != nil {
+		return nil, fmt.Errorf("failed to parse method options: %w", err)
+	}
+
+	var rules []HTTPRule
+	options.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.FullName() == ext.FullName() {
+			rules = appendHTTPRule(rules, v.Message())
+			return false
+		}
+		return true
+	})
+
+	return rules, nil
+}
+
+// findCommentOption returns the registry's configured comment option, or nil
+// if none is configured or it does not name a string field option in the
+// registry.
+func findCommentOption(reg *descriptor.Registry) protoreflect.ExtensionDescriptor {
+	if reg.CommentOption == "" {
+		return nil
+	}
+	d, err := reg.Files.FindDescriptorByName(protoreflect.FullName(reg.CommentOption))
+	if err != nil {
+		return nil
+	}
+	ext, ok := d.(protoreflect.ExtensionDescriptor)
+	if !ok || ext.Kind() != protoreflect.StringKind || ext.IsList() || ext.ContainingMessage().FullName() != "google.protobuf.FieldOptions" {
+		return nil
+	}
+	return ext
+}
+
+// optionComment returns the value of the comment option set on a field, or
+// "" if it is not set. As in extractHTTPRules, the options are re-parsed with
+// the registry's types so the extension is decoded.
+func optionComment(reg *descriptor.Registry, field protoreflect.FieldDescriptor, ext protoreflect.ExtensionDescriptor) string {
+	raw, err := proto.Marshal(field.Options())
+	if err != nil || len(raw) == 0 {
+		return ""
+	}
+	options := dynamicpb.NewMessage(ext.ContainingMessage())
+	if err := (proto.UnmarshalOptions{Resolver: reg.Resolver()}).Unmarshal(raw, options); err != nil {
+		return ""
+	}
+
+	var comment string
+	options.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.FullName() == ext.FullName() {
+			comment = strings.TrimSpace(v.String())
+			return false
+		}
+		return true
+	})
+	return comment
+}
+
+// customOptions returns the custom options set in a descriptor's options,
+// keyed by full name and formatted as they would be written in a .proto file.
+// Extensions unknown when the file was linked are left as unknown fields, so
+// the options are re-parsed with the registry's types first. Returns nil if
+// no custom options are set.
+func customOptions(reg *descriptor.Registry, opts proto.Message) map[string]string {
+	if opts == nil || !opts.ProtoReflect().IsValid() {
+		return nil
+	}
+	raw, err := proto.Marshal(opts)
+	if err != nil || len(raw) == 0 {
+		return nil
+	}
+	parsed := dynamicpb.NewMessage(opts.ProtoReflect().Descriptor())
+	if err := (proto.UnmarshalOptions{Resolver: reg.Resolver()}).Unmarshal(raw, parsed); err != nil {
+		return nil
+	}
+
+	var options map[string]string
+	proto.RangeExtensions(parsed, func(xt protoreflect.ExtensionType, v any) bool {
+		if options == nil {
+			options = make(map[string]string)
+		}
+		xd := xt.TypeDescriptor()
+		options[string(xd.FullName())] = formatOptionValue(xd, xt.ValueOf(v))
+		return true
+	})
+	return options
+}
+
+// formatOptionValue formats an option value in .proto syntax: repeated values
+// as a list, messages in text format, and enums by value name.
+func formatOptionValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	if fd.IsList() {
+		list := v.List()
+		items := make([]string, list.Len())
+		for i := range items {
+			items[i] = formatOptionScalar(fd, list.Get(i))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return formatOptionScalar(fd, v)
+}
+
+// formatOptionScalar formats a single option value of fd's kind.
+func formatOptionScalar(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		text, err := prototext.MarshalOptions{}.Marshal(v.Message().Interface())
+		if err != nil {
+			return "{}"
+		}
+		return "{" + strings.TrimSpace(string(text)) + "}"
+	case protoreflect.EnumKind:
+		if value := fd.Enum().Values().ByNumber(v.Enum()); value != nil {
+			return string(value.Name())
+		}
+		return strconv.Itoa(int(v.Enum()))
+	case protoreflect.StringKind:
+		return strconv.Quote(v.String())
+	case protoreflect.BytesKind:
+		return strconv.Quote(string(v.Bytes()))
+	default:
+		return v.String()
+	}
+}
+
+// appendHTTPRule appends a google.api.HttpRule and its additional bindings.
+func appendHTTPRule(rules []HTTPRule, msg protoreflect.Message) []HTTPRule {
+	fields := msg.Descriptor().Fields()
+	get := func(name string) protoreflect.Value {
+		if fd := fields.ByName(protoreflect.Name(name)); fd != nil && msg.Has(fd) {
+			return msg.Get(fd)
+		}
+		return protoreflect.Value{}
+	}
 
-	return []HTTPRule{}, nil
+	var rule HTTPRule
+	for _, verb := range []string{"get", "put", "post", "delete", "patch"} {
+		if v := get(verb); v.IsValid() {
+			rule.Method = strings.ToUpper(verb)
+			rule.Path = v.String()
+		}
+	}
+	if v := get("custom"); v.IsValid() {
+		custom := v.Message()
+		customFields := custom.Descriptor().Fields()
+		rule.Method = strings.ToUpper(custom.Get(customFields.ByName("kind")).String())
+		rule.Path = custom.Get(customFields.ByName("path")).String()
+	}
+	if v := get("body"); v.IsValid() {
+		rule.Body = v.String()
+	}
+
+	if rule.Method != "" {
+		rules = append(rules, rule)
+	}
+
+	if v := get("additional_bindings"); v.IsValid() {
+		list := v.List()
+		for i := 0; i < list.Len(); i++ {
+			rules = appendHTTPRule(rules, list.Get(i).Message())
+		}
+	}
+
+	return rules
+}
+
+// generateHTTPBodyExample generates the example JSON sent as the HTTP body for
+// a rule. A body of "*" maps the whole request message, while a field name
+// maps only that top-level field of the request.
+func generateHTTPBodyExample(input protoreflect.MessageDescriptor, body string, options descriptor.ExampleOptions) (string, error) {
+	switch body {
+	case "":
+		return "", nil
+	case "*":
+		return descriptor.GenerateExampleJSON(input, options)
+	}
+
+	field := input.Fields().ByName(protoreflect.Name(body))
+	if field == nil {
+		return "", fmt.Errorf("body field %q not found in %s", body, input.FullName())
+	}
+
+	if field.Message() != nil && !field.IsList() && !field.IsMap() {
+		return descriptor.GenerateExampleJSON(field.Message(), options)
+	}
+
+	// Scalar, repeated, and map fields: take the field's value from the full
+	// request example.
+	example, err := descriptor.GenerateExampleJSON(input, options)
+	if err != nil {
+		return "", err
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(example), &values); err != nil {
+		return "", fmt.Errorf("failed to parse request example: %w", err)
+	}
+	value, ok := values[field.JSONName()]
+	if !ok {
+		return "", nil
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, value, "", "  "); err != nil {
+		return "", fmt.Errorf("failed to format body example: %w", err)
+	}
+	return out.String(), nil
 }
 
+// placeholderBaseURL addresses curl examples when no environment hosts the
+// method.
+const placeholderBaseURL = "https://api.example.com"
+
 // generateCurlExample generates a curl example for the method.
 func generateCurlExample(method *MethodSummary) string {
 	if len(method.HTTPRules) == 0 {
 		return ""
 	}
 
-	rule := method.HTTPRules[0]       // Use first rule
-	host := "https://api.example.com" // Placeholder host
+	rule := method.HTTPRules[0] // Use first rule
+	host := placeholderBaseURL
+
+	data := "{}"
+	if method.ExampleHTTPBody != "" {
+		data = strings.ReplaceAll(method.ExampleHTTPBody, "'", `'\''`)
+	}
 
 	var curlCmd string
 	switch strings.ToUpper(rule.Method) {
 	case "GET":
 		curlCmd = fmt.Sprintf("curl -X GET %s%s", host, rule.Path)
 	case "POST":
-		curlCmd = fmt.Sprintf("curl -X POST %s%s \\\n  -H \"Content-Type: application/json\" \\\n  -d '{}'", host, rule.Path)
+		curlCmd = fmt.Sprintf("curl -X POST %s%s \\\n  -H \"Content-Type: application/json\" \\\n  -d '%s'", host, rule.Path, data)
 	case "PUT":
-		curlCmd = fmt.Sprintf("curl -X PUT %s%s \\\n  -H \"Content-Type: application/json\" \\\n  -d '{}'", host, rule.Path)
+		curlCmd = fmt.Sprintf("curl -X PUT %s%s \\\n  -H \"Content-Type: application/json\" \\\n  -d '%s'", host, rule.Path, data)
 	case "PATCH":
-		curlCmd = fmt.Sprintf("curl -X PATCH %s%s \\\n  -H \"Content-Type: application/json\" \\\n  -d '{}'", host, rule.Path)
+		curlCmd = fmt.Sprintf("curl -X PATCH %s%s \\\n  -H \"Content-Type: application/json\" \\\n  -d '%s'", host, rule.Path, data)
 	case "DELETE":
 		curlCmd = fmt.Sprintf("curl -X DELETE %s%s", host, rule.Path)
 	default:
@@ -372,8 +1156,103 @@ func generateCurlExample(method *MethodSummary) string {
 	return curlCmd
 }
 
-// generateGrpcurlExample generates a grpcurl example for the method.
-func generateGrpcurlExample(method *MethodSummary) string {
-	host := "localhost:8080" // Placeholder host
-	return fmt.Sprintf("grpcurl -plaintext -d '{}' %s %s", host, method.FullName)
+// ConnectCurlExample generates a curl command that calls a unary method with
+// the Connect protocol: a JSON POST to {baseURL}/{package.Service/Method}, as
+// sent by the Try It Connect invoker. An empty baseURL uses a placeholder
+// host, and a unix:// base URL is reached with --unix-socket. Streaming
+// methods have no example.
+func ConnectCurlExample(method *MethodSummary, baseURL string) string {
+	if method.ClientStreaming || method.ServerStreaming {
+		return ""
+	}
+
+	var flags string
+	if socketPath, ok := strings.CutPrefix(baseURL, "unix://"); ok && socketPath != "" {
+		// The socket, not the URL, picks the server
+		flags = "--unix-socket " + shellQuote(socketPath) + " "
+		baseURL = "http://localhost"
+	} else if baseURL == "" {
+		baseURL = placeholderBaseURL
+	}
+
+	data := compactExampleRequest(method)
+	endpoint := strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(method.FullName, "/")
+	return fmt.Sprintf("curl %s-X POST %s \\\n  -H 'Content-Type: application/json' \\\n  -d %s", flags, shellQuote(endpoint), shellQuote(data))
+}
+
+// GrpcurlTarget is the server a grpcurl example is addressed to.
+type GrpcurlTarget struct {
+	// BaseURL is the server URL, e.g. "https://api.example.com" or
+	// "unix:///tmp/api.sock". Default: plaintext to localhost:8080.
+	BaseURL string
+
+	// Headers are sent with -H flags, in sorted order. Callers should leave
+	// out sensitive headers such as Authorization.
+	Headers map[string]string
+}
+
+// GrpcurlExample generates a copy-ready grpcurl command for the method. The
+// example request is sent as single-line JSON, and all arguments are quoted
+// for POSIX shells.
+func GrpcurlExample(method *MethodSummary, target GrpcurlTarget) string {
+	flags, address := grpcurlAddress(target.BaseURL)
+
+	keys := make([]string, 0, len(target.Headers))
+	for key := range target.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		flags = append(flags, "-H "+shellQuote(key+": "+target.Headers[key]))
+	}
+
+	flags = append(flags, "-d "+shellQuote(compactExampleRequest(method)))
+
+	return "grpcurl " + strings.Join(flags, " \\\n  ") + " \\\n  " + address + " " + shellQuote(method.FullName)
+}
+
+// grpcurlAddress returns the transport flags and address for a base URL.
+// grpcurl needs an explicit port, so the scheme's default port is added when
+// the URL has none.
+func grpcurlAddress(baseURL string) ([]string, string) {
+	if socketPath, ok := strings.CutPrefix(baseURL, "unix://"); ok && socketPath != "" {
+		return []string{"-plaintext", "-unix"}, shellQuote(socketPath)
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return []string{"-plaintext"}, "localhost:8080" // Placeholder host
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "https" {
+			host += ":443"
+		} else {
+			host += ":80"
+		}
+	}
+	if u.Scheme == "https" {
+		return nil, shellQuote(host)
+	}
+	return []string{"-plaintext"}, shellQuote(host)
+}
+
+// compactExampleRequest returns the method's example request as single-line
+// JSON, or "{}" if there is none.
+func compactExampleRequest(method *MethodSummary) string {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(method.ExampleRequest)); err != nil {
+		return "{}"
+	}
+	return compact.String()
+}
+
+// shellQuote quotes s for a POSIX shell. Strings made only of safe characters
+// are returned as is.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:@") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
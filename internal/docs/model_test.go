@@ -0,0 +1,965 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+)
+
+func TestBuildMethodViewHTTPBody(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "http")
+	includePath := filepath.Join("..", "third_party", "googleapis")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, []string{includePath})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		wantRule   HTTPRule
+		wantFields []string // top-level keys of the HTTP body example; nil for no body
+	}{
+		{
+			name:       "body maps to nested field",
+			method:     "echo.v1.EchoService/UpdateEcho",
+			wantRule:   HTTPRule{Method: "PATCH", Path: "/v1/echo/{id}", Body: "echo"},
+			wantFields: []string{"id", "message", "timestamp"},
+		},
+		{
+			name:       "body maps whole request",
+			method:     "echo.v1.EchoService/Echo",
+			wantRule:   HTTPRule{Method: "POST", Path: "/v1/echo", Body: "*"},
+			wantFields: []string{"message", "repeatCount"},
+		},
+		{
+			name:     "no body",
+			method:   "echo.v1.EchoService/GetEcho",
+			wantRule: HTTPRule{Method: "GET", Path: "/v1/echo/{id}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view, err := BuildMethodView(reg, tt.method)
+			if err != nil {
+				t.Fatalf("BuildMethodView() error = %v", err)
+			}
+
+			if len(view.HTTPRules) != 1 || view.HTTPRules[0] != tt.wantRule {
+				t.Fatalf("Expected HTTP rules [%+v], got %+v", tt.wantRule, view.HTTPRules)
+			}
+
+			if tt.wantFields == nil {
+				if view.ExampleHTTPBody != "" {
+					t.Errorf("Expected no HTTP body example, got %s", view.ExampleHTTPBody)
+				}
+				return
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal([]byte(view.ExampleHTTPBody), &body); err != nil {
+				t.Fatalf("Failed to parse HTTP body example %q: %v", view.ExampleHTTPBody, err)
+			}
+			if len(body) != len(tt.wantFields) {
+				t.Errorf("Expected body fields %v, got %v", tt.wantFields, body)
+			}
+			for _, field := range tt.wantFields {
+				if _, ok := body[field]; !ok {
+					t.Errorf("Expected body field %q, got %v", field, body)
+				}
+			}
+
+			if !strings.Contains(view.Examples.Curl, view.ExampleHTTPBody) {
+				t.Errorf("Expected curl example to send the scoped body, got:\n%s", view.Examples.Curl)
+			}
+		})
+	}
+}
+
+func TestBuildMethodViewTypeComments(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "basic")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	view, err := BuildMethodView(reg, "echo.v1.EchoService/Echo")
+	if err != nil {
+		t.Fatalf("BuildMethodView() error = %v", err)
+	}
+
+	if want := "EchoRequest contains the message to echo."; view.InputComment != want {
+		t.Errorf("Expected input comment %q, got %q", want, view.InputComment)
+	}
+	if view.OutputComment != reg.CommentIndex["echo.v1.EchoResponse"] {
+		t.Errorf("Expected output comment %q, got %q", reg.CommentIndex["echo.v1.EchoResponse"], view.OutputComment)
+	}
+}
+
+func TestBuildMessageViewFieldPresence(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		message      string
+		field        string
+		wantLabel    string
+		wantPresence bool
+	}{
+		{"scalar", "notifications.v1.ListNotificationsRequest", "user_id", "", false},
+		{"optional scalar", "notifications.v1.ListNotificationsRequest", "is_read", "optional", true},
+		{"message field", "notifications.v1.ListNotificationsRequest", "pagination", "", true},
+		{"repeated field", "notifications.v1.ListNotificationsResponse", "notifications", "repeated", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view, err := BuildMessageView(reg, tt.message)
+			if err != nil {
+				t.Fatalf("BuildMessageView() error = %v", err)
+			}
+
+			var field *FieldView
+			for i := range view.Fields {
+				if view.Fields[i].Name == tt.field {
+					field = &view.Fields[i]
+				}
+			}
+			if field == nil {
+				t.Fatalf("Field %q not found in %s", tt.field, tt.message)
+			}
+
+			if field.Label != tt.wantLabel {
+				t.Errorf("Expected label %q, got %q", tt.wantLabel, field.Label)
+			}
+			if field.HasPresence != tt.wantPresence {
+				t.Errorf("Expected HasPresence %v, got %v", tt.wantPresence, field.HasPresence)
+			}
+		})
+	}
+}
+
+func TestBuildMessageViewMethodUsages(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "basic")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	request, err := BuildMessageView(reg, "echo.v1.EchoRequest")
+	if err != nil {
+		t.Fatalf("BuildMessageView() error = %v", err)
+	}
+	wantInputs := []TypeRef{
+		{Name: "Echo", FullName: "echo.v1.EchoService/Echo"},
+		{Name: "EchoStream", FullName: "echo.v1.EchoService/EchoStream"},
+	}
+	if !reflect.DeepEqual(request.UsedAsInput, wantInputs) {
+		t.Errorf("Expected UsedAsInput %+v, got %+v", wantInputs, request.UsedAsInput)
+	}
+	if len(request.UsedAsOutput) != 0 {
+		t.Errorf("Expected no UsedAsOutput, got %+v", request.UsedAsOutput)
+	}
+
+	response, err := BuildMessageView(reg, "echo.v1.EchoResponse")
+	if err != nil {
+		t.Fatalf("BuildMessageView() error = %v", err)
+	}
+	if len(response.UsedAsInput) != 0 {
+		t.Errorf("Expected no UsedAsInput, got %+v", response.UsedAsInput)
+	}
+	if !reflect.DeepEqual(response.UsedAsOutput, wantInputs) {
+		t.Errorf("Expected UsedAsOutput %+v, got %+v", wantInputs, response.UsedAsOutput)
+	}
+}
+
+func TestBuildMessageViewOneofs(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	view, err := BuildMessageView(reg, "orders.v1.GetOrderRequest")
+	if err != nil {
+		t.Fatalf("BuildMessageView() error = %v", err)
+	}
+	if len(view.Oneofs) != 1 {
+		t.Fatalf("Expected 1 oneof, got %+v", view.Oneofs)
+	}
+	oneof := view.Oneofs[0]
+	if oneof.Name != "identifier" {
+		t.Errorf("Expected oneof identifier, got %q", oneof.Name)
+	}
+	if oneof.Comment != "The order identifier." {
+		t.Errorf("Expected oneof comment, got %q", oneof.Comment)
+	}
+	var members []string
+	for _, field := range oneof.Fields {
+		members = append(members, field.Name)
+	}
+	if want := []string{"order_id", "order_number"}; !reflect.DeepEqual(members, want) {
+		t.Errorf("Expected members %v, got %v", want, members)
+	}
+	// Members are still listed with the other fields
+	if len(view.Fields) != 2 {
+		t.Errorf("Expected 2 fields, got %d", len(view.Fields))
+	}
+
+	// proto3 optional fields use synthetic oneofs, which are not groups
+	view, err = BuildMessageView(reg, "notifications.v1.ListNotificationsRequest")
+	if err != nil {
+		t.Fatalf("BuildMessageView() error = %v", err)
+	}
+	if len(view.Oneofs) != 0 {
+		t.Errorf("Expected no oneofs, got %+v", view.Oneofs)
+	}
+}
+
+func TestBuildEnumViewTrailingComments(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comments")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	view, err := BuildEnumView(reg, "comments.v1.Color")
+	if err != nil {
+		t.Fatalf("BuildEnumView() error = %v", err)
+	}
+	comments := make(map[string]string)
+	for _, value := range view.Values {
+		comments[value.Name] = value.Comment
+	}
+	if comments["COLOR_RED"] != "The color of fire." {
+		t.Errorf("Expected trailing comment on COLOR_RED, got %q", comments["COLOR_RED"])
+	}
+}
+
+func TestBuildMessageViewCommentOption(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comments")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		option string
+		want   map[string]string
+	}{
+		{
+			name:   "no option configured",
+			option: "",
+			want: map[string]string{
+				"hex":  "",
+				"name": "Source comments take precedence over the description option.",
+				"size": "",
+			},
+		},
+		{
+			name:   "option fallback",
+			option: "comments.v1.description",
+			want: map[string]string{
+				"hex":  "Hex color code, such as #ff0000.",
+				"name": "Source comments take precedence over the description option.",
+				"size": "",
+			},
+		},
+		{
+			name:   "unknown option",
+			option: "comments.v1.summary",
+			want: map[string]string{
+				"hex":  "",
+				"name": "Source comments take precedence over the description option.",
+				"size": "",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view, err := BuildMessageView(reg.WithCommentOption(tt.option), "comments.v1.Swatch")
+			if err != nil {
+				t.Fatalf("BuildMessageView() error = %v", err)
+			}
+			for _, field := range view.Fields {
+				if field.Comment != tt.want[field.Name] {
+					t.Errorf("Field %s: expected comment %q, got %q", field.Name, tt.want[field.Name], field.Comment)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildServiceViewTagGroups(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "tags")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	view, err := BuildServiceView(reg, "tags.v1.AccountService")
+	if err != nil {
+		t.Fatalf("BuildServiceView() error = %v", err)
+	}
+
+	groupNames := func(groups []MethodTagGroup) map[string][]string {
+		names := make(map[string][]string)
+		for _, group := range groups {
+			for _, method := range group.Methods {
+				names[group.Tag] = append(names[group.Tag], method.Name)
+			}
+		}
+		return names
+	}
+
+	want := map[string][]string{
+		"accounts": {"ChargeAccount", "GetAccount"},
+		"billing":  {"ChargeAccount"},
+		"Other":    {"Reindex"},
+	}
+	if got := groupNames(view.TagGroups); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected tag groups %v, got %v", want, got)
+	}
+	if last := view.TagGroups[len(view.TagGroups)-1].Tag; last != "Other" {
+		t.Errorf("Expected untagged methods last, got %q", last)
+	}
+
+	view.HideInternalMethods()
+	if len(view.Methods) != 2 {
+		t.Errorf("Expected 2 visible methods, got %d", len(view.Methods))
+	}
+	delete(want, "Other")
+	if got := groupNames(view.TagGroups); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected tag groups %v after hiding internal methods, got %v", want, got)
+	}
+
+	// Services without tagged methods are not grouped
+	comprehensive, err := descriptor.LoadDirectory(context.Background(), filepath.Join("..", "descriptor", "testdata", "comprehensive"), nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	untagged, err := BuildServiceView(comprehensive, "users.v1.UserService")
+	if err != nil {
+		t.Fatalf("BuildServiceView() error = %v", err)
+	}
+	if untagged.TagGroups != nil {
+		t.Errorf("Expected no tag groups, got %v", untagged.TagGroups)
+	}
+}
+
+func TestGrpcurlExample(t *testing.T) {
+	method := &MethodSummary{
+		FullName:       "users.v1.UserService/GetUser",
+		ExampleRequest: "{\n  \"name\": \"O'Brien\",\n  \"tags\": [\n    \"a b\"\n  ]\n}",
+	}
+
+	tests := []struct {
+		name   string
+		method *MethodSummary
+		target GrpcurlTarget
+		want   string
+	}{
+		{
+			name:   "placeholder host",
+			method: method,
+			want: "grpcurl -plaintext \\\n" +
+				"  -d '{\"name\":\"O'\\''Brien\",\"tags\":[\"a b\"]}' \\\n" +
+				"  localhost:8080 users.v1.UserService/GetUser",
+		},
+		{
+			name:   "https environment with headers",
+			method: method,
+			target: GrpcurlTarget{
+				BaseURL: "https://api.example.com",
+				Headers: map[string]string{"X-Tenant": "acme", "X-Env": "it's staging"},
+			},
+			want: "grpcurl -H 'X-Env: it'\\''s staging' \\\n" +
+				"  -H 'X-Tenant: acme' \\\n" +
+				"  -d '{\"name\":\"O'\\''Brien\",\"tags\":[\"a b\"]}' \\\n" +
+				"  api.example.com:443 users.v1.UserService/GetUser",
+		},
+		{
+			name:   "http environment with port",
+			method: &MethodSummary{FullName: "echo.v1.EchoService/Echo"},
+			target: GrpcurlTarget{BaseURL: "http://localhost:50051/"},
+			want: "grpcurl -plaintext \\\n" +
+				"  -d '{}' \\\n" +
+				"  localhost:50051 echo.v1.EchoService/Echo",
+		},
+		{
+			name:   "unix socket",
+			method: &MethodSummary{FullName: "echo.v1.EchoService/Echo"},
+			target: GrpcurlTarget{BaseURL: "unix:///tmp/echo.sock"},
+			want: "grpcurl -plaintext \\\n" +
+				"  -unix \\\n" +
+				"  -d '{}' \\\n" +
+				"  /tmp/echo.sock echo.v1.EchoService/Echo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GrpcurlExample(tt.method, tt.target); got != tt.want {
+				t.Errorf("GrpcurlExample() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildMethodViewGrpcurlExample(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	view, err := BuildMethodView(reg, "users.v1.UserService/GetUser")
+	if err != nil {
+		t.Fatalf("BuildMethodView() error = %v", err)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(view.ExampleRequest)); err != nil {
+		t.Fatalf("Expected JSON example request, got %q: %v", view.ExampleRequest, err)
+	}
+	if want := "-d '" + compact.String() + "'"; !strings.Contains(view.Examples.Grpcurl, want) {
+		t.Errorf("Expected grpcurl example to contain %s, got:\n%s", want, view.Examples.Grpcurl)
+	}
+	if !strings.HasSuffix(view.Examples.Grpcurl, " users.v1.UserService/GetUser") {
+		t.Errorf("Expected grpcurl example to end with the method path, got:\n%s", view.Examples.Grpcurl)
+	}
+}
+
+func TestBuildMethodViewStreamingExamples(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	tests := []struct {
+		method                      string
+		wantRequests, wantResponses int
+	}{
+		{method: "users.v1.UserService/GetUser"},
+		{method: "users.v1.UserService/StreamUsers", wantResponses: 3},
+		{method: "users.v1.UserService/BulkUpdateUsers", wantRequests: 3},
+		{method: "users.v1.UserService/SyncUsers", wantRequests: 3, wantResponses: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			view, err := BuildMethodView(reg, tt.method)
+			if err != nil {
+				t.Fatalf("BuildMethodView() error = %v", err)
+			}
+			if len(view.ExampleRequestStream) != tt.wantRequests {
+				t.Errorf("Expected %d request messages, got %d", tt.wantRequests, len(view.ExampleRequestStream))
+			}
+			if len(view.ExampleResponseStream) != tt.wantResponses {
+				t.Errorf("Expected %d response messages, got %d", tt.wantResponses, len(view.ExampleResponseStream))
+			}
+
+			for _, stream := range [][]string{view.ExampleRequestStream, view.ExampleResponseStream} {
+				seen := make(map[string]bool)
+				for _, message := range stream {
+					if !json.Valid([]byte(message)) {
+						t.Errorf("Expected JSON message, got %s", message)
+					}
+					if seen[message] {
+						t.Errorf("Expected distinct sample messages, got a repeat:\n%s", message)
+					}
+					seen[message] = true
+				}
+			}
+		})
+	}
+}
+
+func TestConnectCurlExample(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "basic")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	view, err := BuildMethodView(reg, "echo.v1.EchoService/Echo")
+	if err != nil {
+		t.Fatalf("BuildMethodView() error = %v", err)
+	}
+
+	want := "curl -X POST https://api.example.com/echo.v1.EchoService/Echo \\\n" +
+		"  -H 'Content-Type: application/json' \\\n" +
+		"  -d '{\"count\":42,\"message\":\"example_message\"}'"
+	if got := ConnectCurlExample(view, "https://api.example.com/"); got != want {
+		t.Errorf("ConnectCurlExample() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := ConnectCurlExample(view, "unix:///tmp/echo.sock"); !strings.HasPrefix(got, "curl --unix-socket /tmp/echo.sock -X POST http://localhost/echo.v1.EchoService/Echo ") {
+		t.Errorf("Expected Unix socket Connect curl example, got:\n%s", got)
+	}
+
+	if view.Examples.ConnectCurl != want {
+		t.Errorf("Expected placeholder Connect curl example, got:\n%s", view.Examples.ConnectCurl)
+	}
+
+	stream, err := BuildMethodView(reg, "echo.v1.EchoService/EchoStream")
+	if err != nil {
+		t.Fatalf("BuildMethodView() error = %v", err)
+	}
+	if stream.Examples.ConnectCurl != "" {
+		t.Errorf("Expected no Connect curl example for a streaming method, got:\n%s", stream.Examples.ConnectCurl)
+	}
+}
+
+func TestBuildIndexServiceGroups(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	index, err := BuildIndexWithOptions(reg, IndexOptions{
+		ServiceGroups: map[string][]string{
+			"Identity": {"users.v1.UserService"},
+			"Commerce": {"orders.v1.*", "products.v1.*"},
+			"Empty":    {"billing.v1.*"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BuildIndexWithOptions() error = %v", err)
+	}
+
+	got := make(map[string][]string)
+	var order []string
+	for _, group := range index.Groups {
+		order = append(order, group.Name)
+		for _, service := range group.Services {
+			got[group.Name] = append(got[group.Name], service.FullName)
+		}
+	}
+
+	// Groups are sorted by name with "Other" last; empty groups are omitted
+	wantOrder := []string{"Commerce", "Identity", OtherServiceGroup}
+	if strings.Join(order, ",") != strings.Join(wantOrder, ",") {
+		t.Errorf("Expected groups %v, got %v", wantOrder, order)
+	}
+
+	want := map[string][]string{
+		"Commerce":        {"orders.v1.OrderService", "products.v1.ProductService"},
+		"Identity":        {"users.v1.UserService"},
+		OtherServiceGroup: {"notifications.v1.NotificationService"},
+	}
+	for name, services := range want {
+		if strings.Join(got[name], ",") != strings.Join(services, ",") {
+			t.Errorf("Group %q: expected %v, got %v", name, services, got[name])
+		}
+	}
+
+	if len(index.Services) != 4 {
+		t.Errorf("Expected all 4 services in the flat list, got %d", len(index.Services))
+	}
+
+	ungrouped, err := BuildIndex(reg)
+	if err != nil {
+		t.Fatalf("BuildIndex() error = %v", err)
+	}
+	if ungrouped.Groups != nil {
+		t.Errorf("Expected no groups without configuration, got %v", ungrouped.Groups)
+	}
+}
+
+func TestBuildMessageViewWireTypes(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	view, err := BuildMessageView(reg, "notifications.v1.QuietHours")
+	if err != nil {
+		t.Fatalf("BuildMessageView() error = %v", err)
+	}
+
+	tests := []struct {
+		field      string
+		wantWire   string
+		wantPacked bool
+	}{
+		{"enabled", "varint", false},
+		{"start_time", "length-delimited", false},
+		// proto3 packs repeated scalars by default
+		{"days", "varint", true},
+	}
+	for _, tt := range tests {
+		var found *FieldView
+		for i := range view.Fields {
+			if view.Fields[i].Name == tt.field {
+				found = &view.Fields[i]
+			}
+		}
+		if found == nil {
+			t.Errorf("Field %q not found", tt.field)
+			continue
+		}
+		if found.WireType != tt.wantWire {
+			t.Errorf("Field %q: expected wire type %q, got %q", tt.field, tt.wantWire, found.WireType)
+		}
+		if found.Packed != tt.wantPacked {
+			t.Errorf("Field %q: expected packed %v, got %v", tt.field, tt.wantPacked, found.Packed)
+		}
+	}
+}
+
+func TestBuildMessageViewJSONNames(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	view, err := BuildMessageView(reg, "users.v1.User")
+	if err != nil {
+		t.Fatalf("BuildMessageView() error = %v", err)
+	}
+
+	jsonNames := make(map[string]string)
+	for _, field := range view.Fields {
+		jsonNames[field.Name] = field.JSONName
+	}
+	if got := jsonNames["full_name"]; got != "fullName" {
+		t.Errorf("Expected JSON name fullName for full_name, got %q", got)
+	}
+	if got := jsonNames["email"]; got != "email" {
+		t.Errorf("Expected JSON name email for email, got %q", got)
+	}
+}
+
+func TestBuildEnumViewFlagsAndSort(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comments")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	view, err := BuildEnumView(reg, "comments.v1.Shade")
+	if err != nil {
+		t.Fatalf("BuildEnumView() error = %v", err)
+	}
+	if view.SortBy != EnumSortByNumber {
+		t.Errorf("Expected values sorted by number, got %q", view.SortBy)
+	}
+
+	var names []string
+	for _, value := range view.Values {
+		names = append(names, value.Name)
+		if want := value.Name == "SHADE_PALE"; value.Alias != want {
+			t.Errorf("%s: expected alias %v", value.Name, want)
+		}
+		if want := value.Name == "SHADE_DARK"; value.Deprecated != want {
+			t.Errorf("%s: expected deprecated %v", value.Name, want)
+		}
+	}
+	// Aliases follow the value they alias
+	if want := []string{"SHADE_UNSPECIFIED", "SHADE_LIGHT", "SHADE_PALE", "SHADE_DARK"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected values %v, got %v", want, names)
+	}
+
+	view.SortValues(EnumSortByName)
+	names = nil
+	for _, value := range view.Values {
+		names = append(names, value.Name)
+	}
+	if want := []string{"SHADE_DARK", "SHADE_LIGHT", "SHADE_PALE", "SHADE_UNSPECIFIED"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected values by name %v, got %v", want, names)
+	}
+}
+
+func TestBuildMessageViewCustomOptions(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "options")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	view, err := BuildMessageView(reg, "options.v1.Document")
+	if err != nil {
+		t.Fatalf("BuildMessageView() error = %v", err)
+	}
+
+	if want := map[string]string{"options.v1.resource": `"documents/{id}"`}; !reflect.DeepEqual(view.Options, want) {
+		t.Errorf("Expected message options %v, got %v", want, view.Options)
+	}
+	if !view.HasOptions() {
+		t.Error("Expected HasOptions() to be true")
+	}
+
+	fields := make(map[string]FieldView)
+	for _, field := range view.Fields {
+		fields[field.Name] = field
+	}
+
+	if want := map[string]string{"google.api.field_behavior": "[OUTPUT_ONLY, IMMUTABLE]"}; !reflect.DeepEqual(fields["id"].Options, want) {
+		t.Errorf("Expected id options %v, got %v", want, fields["id"].Options)
+	}
+
+	owner := fields["owner_email"].Options
+	if got := owner["google.api.field_behavior"]; got != "[REQUIRED]" {
+		t.Errorf("Expected field_behavior [REQUIRED], got %q", got)
+	}
+	if got := owner["options.v1.sensitivity"]; got != `"pii"` {
+		t.Errorf("Expected sensitivity \"pii\", got %q", got)
+	}
+	rules := owner["options.v1.rules"]
+	for _, want := range []string{"min_len:", "3", "pattern:", `".+@.+"`} {
+		if !strings.Contains(rules, want) {
+			t.Errorf("Expected rules %q to contain %q", rules, want)
+		}
+	}
+
+	// Built-in options such as deprecated are not custom options
+	if opts := fields["body"].Options; opts != nil {
+		t.Errorf("Expected no custom options on body, got %v", opts)
+	}
+}
+
+func TestBuildViewAnchors(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	message, err := BuildMessageView(reg, "users.v1.User")
+	if err != nil {
+		t.Fatalf("BuildMessageView() error = %v", err)
+	}
+	anchors := make(map[string]bool)
+	for _, field := range message.Fields {
+		if anchors[field.Anchor] {
+			t.Errorf("Duplicate field anchor %q", field.Anchor)
+		}
+		anchors[field.Anchor] = true
+	}
+	if !anchors["field-full_name"] {
+		t.Errorf("Expected anchor field-full_name, got %v", anchors)
+	}
+
+	enum, err := BuildEnumView(reg, "common.v1.Status")
+	if err != nil {
+		t.Fatalf("BuildEnumView() error = %v", err)
+	}
+	if enum.Values[1].Anchor != "value-"+enum.Values[1].Name {
+		t.Errorf("Expected value anchor value-%s, got %q", enum.Values[1].Name, enum.Values[1].Anchor)
+	}
+}
+
+func TestBuildPackageTree(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	tree := BuildPackageTree(reg)
+	packages := make(map[string]PackageNode)
+	var names []string
+	for _, node := range tree {
+		names = append(names, node.Name)
+		packages[node.Name] = node
+	}
+	// The well-known types the files import are listed too
+	wantNames := []string{"common.v1", "google.protobuf", "notifications.v1", "orders.v1", "products.v1", "users.v1"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("Expected packages %v, got %v", wantNames, names)
+	}
+
+	common := packages["common.v1"]
+	if len(common.Services) != 0 {
+		t.Errorf("Expected no services in common.v1, got %v", common.Services)
+	}
+	if got := typeRefNames(common.Enums); !reflect.DeepEqual(got, []string{"ErrorType", "Status"}) {
+		t.Errorf("Expected common.v1 enums [ErrorType Status], got %v", got)
+	}
+	for _, message := range common.Messages {
+		if strings.HasSuffix(message.Name, "Entry") {
+			t.Errorf("Expected map entries to be left out, got %s", message.FullName)
+		}
+	}
+
+	users := packages["users.v1"]
+	if len(users.Services) != 1 || users.Services[0] != (TypeRef{Name: "UserService", FullName: "users.v1.UserService"}) {
+		t.Errorf("Expected users.v1 to hold UserService, got %v", users.Services)
+	}
+	for _, refs := range [][]TypeRef{users.Messages, users.Enums} {
+		for i := range refs {
+			if i > 0 && refs[i-1].FullName >= refs[i].FullName {
+				t.Errorf("Expected symbols sorted by name, got %s before %s", refs[i-1].FullName, refs[i].FullName)
+			}
+			if !strings.HasPrefix(refs[i].FullName, "users.v1.") {
+				t.Errorf("Expected only users.v1 symbols, got %s", refs[i].FullName)
+			}
+		}
+	}
+
+	// Both packages declare a DigestFrequency enum
+	for _, pkg := range []string{"notifications.v1", "users.v1"} {
+		found := false
+		for _, enum := range packages[pkg].Enums {
+			found = found || enum.FullName == pkg+".DigestFrequency"
+		}
+		if !found {
+			t.Errorf("Expected %s.DigestFrequency in package %s", pkg, pkg)
+		}
+	}
+
+	if BuildPackageTree(nil) != nil {
+		t.Error("Expected no tree for a nil registry")
+	}
+}
+
+func TestBuildPackageTreeDefaultPackage(t *testing.T) {
+	dir := t.TempDir()
+	source := `syntax = "proto3";
+
+service Pinger {
+  rpc Ping(Ping.Request) returns (Pong);
+}
+
+message Ping {
+  message Request {}
+}
+
+message Pong {}
+
+enum Level {
+  LEVEL_UNSPECIFIED = 0;
+}
+`
+	path := filepath.Join(dir, "ping.proto")
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatalf("Failed to write proto file: %v", err)
+	}
+	reg, err := descriptor.LoadFile(context.Background(), path, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	tree := BuildPackageTree(reg)
+	if len(tree) != 1 {
+		t.Fatalf("Expected one package, got %d", len(tree))
+	}
+	node := tree[0]
+	if node.Name != "" {
+		t.Errorf("Expected the default package, got %q", node.Name)
+	}
+	if got := typeRefNames(node.Services); !reflect.DeepEqual(got, []string{"Pinger"}) {
+		t.Errorf("Expected services [Pinger], got %v", got)
+	}
+	if got := typeRefNames(node.Messages); !reflect.DeepEqual(got, []string{"Ping", "Ping.Request", "Pong"}) {
+		t.Errorf("Expected messages [Ping Ping.Request Pong], got %v", got)
+	}
+	if got := typeRefNames(node.Enums); !reflect.DeepEqual(got, []string{"Level"}) {
+		t.Errorf("Expected enums [Level], got %v", got)
+	}
+}
+
+func typeRefNames(refs []TypeRef) []string {
+	var names []string
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
+func TestBuildEnumViewUsedByFields(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	view, err := BuildEnumView(reg, "users.v1.UserRole")
+	if err != nil {
+		t.Fatalf("BuildEnumView() error = %v", err)
+	}
+
+	want := Reference{Message: "users.v1.User", Field: "role", Anchor: "field-role"}
+	found := false
+	for _, ref := range view.UsedByFields {
+		if ref == want {
+			found = true
+		}
+		if ref.Field != "role" {
+			t.Errorf("Unexpected usage %s.%s", ref.Message, ref.Field)
+		}
+	}
+	if !found {
+		t.Errorf("Expected %+v in UsedByFields, got %+v", want, view.UsedByFields)
+	}
+	if !sort.SliceIsSorted(view.UsedByFields, func(i, j int) bool {
+		return view.UsedByFields[i].Message < view.UsedByFields[j].Message
+	}) {
+		t.Errorf("Expected usages sorted by message, got %+v", view.UsedByFields)
+	}
+}
+
+func TestBuildMessageViewProto2(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "proto2")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	view, err := BuildMessageView(reg, "legacy.v1.Account")
+	if err != nil {
+		t.Fatalf("BuildMessageView() error = %v", err)
+	}
+
+	tests := []struct {
+		field, label, typ, def string
+	}{
+		{"id", "required", "string", ""},
+		{"name", "optional", "string", `"anonymous"`},
+		{"max_sessions", "optional", "int32", "5"},
+		{"active", "optional", "bool", "true"},
+		{"tier", "optional", "legacy.v1.Tier", "TIER_BASIC"},
+		{"ratio", "optional", "double", "inf"},
+		{"balance", "optional", "int64", ""},
+		{"tags", "repeated", "string", ""},
+		{"address", "optional", "legacy.v1.Account.Address", ""},
+	}
+	fields := make(map[string]FieldView)
+	for _, field := range view.Fields {
+		fields[field.Name] = field
+	}
+	for _, tt := range tests {
+		field, ok := fields[tt.field]
+		if !ok {
+			t.Errorf("Field %s not found", tt.field)
+			continue
+		}
+		if field.Label != tt.label || field.Type != tt.typ || field.Default != tt.def {
+			t.Errorf("Field %s: label=%q type=%q default=%q, want %q, %q, %q", tt.field, field.Label, field.Type, field.Default, tt.label, tt.typ, tt.def)
+		}
+	}
+
+	if !strings.Contains(view.ExampleJSON, `"anonymous"`) {
+		t.Errorf("Expected the example to use declared defaults, got:\n%s", view.ExampleJSON)
+	}
+}
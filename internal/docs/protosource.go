@@ -0,0 +1,306 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+)
+
+// SourceTokenKind classifies a token of highlighted proto source.
+type SourceTokenKind string
+
+const (
+	// SourcePlain is whitespace, punctuation, and names that are not
+	// highlighted.
+	SourcePlain SourceTokenKind = ""
+	// SourceKeyword is a proto keyword such as message or rpc.
+	SourceKeyword SourceTokenKind = "keyword"
+	// SourceScalar is a scalar type such as int32 or string.
+	SourceScalar SourceTokenKind = "scalar"
+	// SourceComment is a line or block comment.
+	SourceComment SourceTokenKind = "comment"
+	// SourceString is a string literal.
+	SourceString SourceTokenKind = "string"
+	// SourceNumber is a numeric literal.
+	SourceNumber SourceTokenKind = "number"
+)
+
+// protoKeywords are the words highlighted as keywords.
+var protoKeywords = map[string]bool{
+	"syntax": true, "edition": true, "package": true, "import": true, "public": true, "weak": true,
+	"option": true, "message": true, "enum": true, "service": true, "rpc": true, "returns": true,
+	"stream": true, "repeated": true, "optional": true, "required": true, "oneof": true, "map": true,
+	"reserved": true, "extend": true, "extensions": true, "to": true, "max": true, "group": true,
+	"true": true, "false": true,
+}
+
+// protoScalars are the scalar type names highlighted as types.
+var protoScalars = map[string]bool{
+	"double": true, "float": true, "int32": true, "int64": true, "uint32": true, "uint64": true,
+	"sint32": true, "sint64": true, "fixed32": true, "fixed64": true, "sfixed32": true,
+	"sfixed64": true, "bool": true, "string": true, "bytes": true,
+}
+
+// SourceView represents a .proto file for the highlighted source page.
+type SourceView struct {
+	// Path is the file's import path (e.g. "echo/v1/echo.proto").
+	Path    string
+	Package string
+	Lines   []SourceLine
+}
+
+// SourceLine is one line of a SourceView.
+type SourceLine struct {
+	Number int
+	Tokens []SourceToken
+}
+
+// SourceToken is a run of source text. Href is set on names of services,
+// methods, messages, and enums that have a doc page.
+type SourceToken struct {
+	Text string
+	Kind SourceTokenKind
+	Href string
+}
+
+// BuildSourceView splits the source of the file with the given import path
+// into highlighted lines, linking symbol names to their doc pages. With
+// hideInternal, internal symbols are not linked.
+func BuildSourceView(reg *descriptor.Registry, path string, hideInternal bool) (*SourceView, error) {
+	if reg == nil {
+		return nil, fmt.Errorf("registry is nil")
+	}
+	source, ok := reg.Source(path)
+	if !ok {
+		return nil, fmt.Errorf("source not found: %s", path)
+	}
+	file, err := reg.Files.FindFileByPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("file not found: %s", path)
+	}
+
+	lines := tokenizeProto(source)
+	linker := &sourceLinker{reg: reg, pkg: string(file.Package()), hideInternal: hideInternal}
+	linker.link(lines)
+
+	view := &SourceView{Path: path, Package: string(file.Package())}
+	for i, tokens := range lines {
+		view.Lines = append(view.Lines, SourceLine{Number: i + 1, Tokens: tokens})
+	}
+	return view, nil
+}
+
+// tokenizeProto splits proto source into lines of tokens. Tokens never span
+// lines: a block comment over several lines is one token per line.
+func tokenizeProto(source string) [][]SourceToken {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	lines := [][]SourceToken{nil}
+	add := func(text string, kind SourceTokenKind) {
+		for i, part := range strings.Split(text, "\n") {
+			if i > 0 {
+				lines = append(lines, nil)
+			}
+			if part != "" {
+				last := len(lines) - 1
+				lines[last] = append(lines[last], SourceToken{Text: part, Kind: kind})
+			}
+		}
+	}
+
+	for i := 0; i < len(source); {
+		rest := source[i:]
+		c := rest[0]
+		var n int
+		kind := SourcePlain
+		switch {
+		case strings.HasPrefix(rest, "//"):
+			n = strings.IndexByte(rest, '\n')
+			if n < 0 {
+				n = len(rest)
+			}
+			kind = SourceComment
+		case strings.HasPrefix(rest, "/*"):
+			n = strings.Index(rest[2:], "*/")
+			if n < 0 {
+				n = len(rest)
+			} else {
+				n += 4
+			}
+			kind = SourceComment
+		case c == '"' || c == '\'':
+			n = 1
+			for n < len(rest) && rest[n] != c && rest[n] != '\n' {
+				if rest[n] == '\\' && n+1 < len(rest) {
+					n++
+				}
+				n++
+			}
+			if n < len(rest) && rest[n] == c {
+				n++
+			}
+			kind = SourceString
+		case isDigit(c) || (c == '-' && len(rest) > 1 && isDigit(rest[1])):
+			n = 1
+			for n < len(rest) && (isIdentByte(rest[n]) || rest[n] == '.') {
+				n++
+			}
+			kind = SourceNumber
+		case isIdentByte(c) || c == '.':
+			n = 1
+			for n < len(rest) && (isIdentByte(rest[n]) || rest[n] == '.') {
+				n++
+			}
+			switch word := rest[:n]; {
+			case protoKeywords[word]:
+				kind = SourceKeyword
+			case protoScalars[word]:
+				kind = SourceScalar
+			}
+		default:
+			// Punctuation and whitespace, up to the next interesting byte
+			n = 1
+			for n < len(rest) && !startsToken(rest[n:]) {
+				n++
+			}
+		}
+		add(rest[:n], kind)
+		i += n
+	}
+	return lines
+}
+
+// startsToken reports whether s begins a token other than plain text.
+func startsToken(s string) bool {
+	c := s[0]
+	return strings.HasPrefix(s, "//") || strings.HasPrefix(s, "/*") ||
+		c == '"' || c == '\'' || c == '-' || c == '.' || isIdentByte(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || isDigit(c)
+}
+
+// sourceLinker sets Href on the names in tokenized source that resolve to
+// doc pages, following proto scoping: a name is looked up in the enclosing
+// message, then its parent, and so on out to the package.
+type sourceLinker struct {
+	reg          *descriptor.Registry
+	pkg          string
+	hideInternal bool
+
+	// scopes holds, per open brace, the name of the service, message, or
+	// enum it opened, or "" for other blocks
+	scopes []string
+	// declared is the name of the service, message, or enum whose opening
+	// brace comes next
+	declared string
+	// previous is the last keyword or name seen
+	previous string
+}
+
+// link walks the tokens in order, tracking scopes and linking names.
+func (l *sourceLinker) link(lines [][]SourceToken) {
+	for _, tokens := range lines {
+		for i := range tokens {
+			token := &tokens[i]
+			switch token.Kind {
+			case SourceKeyword, SourceScalar:
+				l.previous = token.Text
+			case SourcePlain:
+				if isIdentByte(token.Text[0]) || token.Text[0] == '.' {
+					l.linkName(token)
+					continue
+				}
+				for _, c := range token.Text {
+					switch c {
+					case '{':
+						l.scopes = append(l.scopes, l.declared)
+						l.declared = ""
+					case '}':
+						if len(l.scopes) > 0 {
+							l.scopes = l.scopes[:len(l.scopes)-1]
+						}
+					case ';', '=':
+						l.previous = ""
+					}
+				}
+			}
+		}
+	}
+}
+
+// linkName links a name token and records declarations.
+func (l *sourceLinker) linkName(token *SourceToken) {
+	previous := l.previous
+	l.previous = token.Text
+
+	switch previous {
+	case "package", "option", "import":
+		return
+	case "message", "enum", "service":
+		l.declared = token.Text
+		token.Href = l.href(l.scopeName() + "." + token.Text)
+		return
+	case "rpc":
+		service := l.scopeName()
+		if _, ok := l.reg.FindService(service); ok {
+			token.Href = l.href(service + "/" + token.Text)
+		}
+		return
+	}
+
+	if strings.HasPrefix(token.Text, ".") {
+		token.Href = l.href(token.Text[1:])
+		return
+	}
+	scope := strings.Split(l.scopeName(), ".")
+	for i := len(scope); i >= 0; i-- {
+		name := strings.Join(append(scope[:i:i], token.Text), ".")
+		if href := l.href(name); href != "" {
+			token.Href = href
+			return
+		}
+	}
+}
+
+// scopeName returns the full name of the innermost enclosing declaration, or
+// the package at the top level.
+func (l *sourceLinker) scopeName() string {
+	parts := strings.Split(l.pkg, ".")
+	if l.pkg == "" {
+		parts = nil
+	}
+	for _, scope := range l.scopes {
+		if scope != "" {
+			parts = append(parts, scope)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// href returns the doc URL of a service, method, message, or enum, or "" if
+// there is none.
+func (l *sourceLinker) href(fullName string) string {
+	fullName = strings.TrimPrefix(fullName, ".")
+	if l.hideInternal && l.reg.IsInternal(fullName) {
+		return ""
+	}
+	if _, ok := l.reg.FindService(fullName); ok {
+		return "/services/" + fullName
+	}
+	if _, ok := l.reg.FindMethod(fullName); ok {
+		return "/methods/" + fullName
+	}
+	if _, ok := l.reg.FindMessage(fullName); ok {
+		return "/types/" + fullName
+	}
+	if _, ok := l.reg.FindEnum(fullName); ok {
+		return "/types/" + fullName
+	}
+	return ""
+}
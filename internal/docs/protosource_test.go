@@ -0,0 +1,95 @@
+package docs
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+)
+
+func TestTokenizeProto(t *testing.T) {
+	source := "message Foo { // note\n  repeated int64 ids = 1 [default = -2];\n  string s = 2 [(x) = \"a \\\" b\"];\n}\n/* one\n   two */"
+	lines := tokenizeProto(source)
+
+	type token struct {
+		text string
+		kind SourceTokenKind
+	}
+	var got [][]token
+	for _, line := range lines {
+		var tokens []token
+		for _, tok := range line {
+			if tok.Kind != SourcePlain {
+				tokens = append(tokens, token{tok.Text, tok.Kind})
+			}
+		}
+		got = append(got, tokens)
+	}
+
+	want := [][]token{
+		{{"message", SourceKeyword}, {"// note", SourceComment}},
+		{{"repeated", SourceKeyword}, {"int64", SourceScalar}, {"1", SourceNumber}, {"-2", SourceNumber}},
+		{{"string", SourceScalar}, {"2", SourceNumber}, {`"a \" b"`, SourceString}},
+		nil,
+		{{"/* one", SourceComment}},
+		{{"   two */", SourceComment}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokenizeProto() highlighted tokens = %v, want %v", got, want)
+	}
+
+	// Joining the tokens gives back the source
+	var joined []string
+	for _, line := range lines {
+		var b strings.Builder
+		for _, tok := range line {
+			b.WriteString(tok.Text)
+		}
+		joined = append(joined, b.String())
+	}
+	if strings.Join(joined, "\n") != source {
+		t.Errorf("Tokens do not reproduce the source:\n%s", strings.Join(joined, "\n"))
+	}
+}
+
+func TestBuildSourceView(t *testing.T) {
+	reg, err := descriptor.LoadDirectory(context.Background(), filepath.Join("..", "descriptor", "testdata", "basic"), nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	view, err := BuildSourceView(reg, "echo.proto", false)
+	if err != nil {
+		t.Fatalf("BuildSourceView() error = %v", err)
+	}
+	if view.Package != "echo.v1" {
+		t.Errorf("Expected package echo.v1, got %q", view.Package)
+	}
+
+	links := make(map[string]string)
+	for _, line := range view.Lines {
+		for _, tok := range line.Tokens {
+			if tok.Href != "" {
+				links[tok.Text] = tok.Href
+			}
+		}
+	}
+	want := map[string]string{
+		"EchoService":  "/services/echo.v1.EchoService",
+		"Echo":         "/methods/echo.v1.EchoService/Echo",
+		"EchoStream":   "/methods/echo.v1.EchoService/EchoStream",
+		"EchoRequest":  "/types/echo.v1.EchoRequest",
+		"EchoResponse": "/types/echo.v1.EchoResponse",
+		"Status":       "/types/echo.v1.Status",
+	}
+	if !reflect.DeepEqual(links, want) {
+		t.Errorf("Expected links %v, got %v", want, links)
+	}
+
+	if _, err := BuildSourceView(reg, "missing.proto", false); err == nil {
+		t.Error("Expected an error for an unknown file")
+	}
+}
@@ -1,20 +1,42 @@
 package docs
 
 import (
+	"html"
+	"html/template"
+	"slices"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/bnprtr/reflect/internal/descriptor"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // SearchIndex holds all searchable items for fast lookup.
 type SearchIndex struct {
 	Items []SearchItem
+
+	// registry is used to resolve dotted field paths at query time.
+	registry *descriptor.Registry
+
+	// hidden reports whether a symbol is excluded from the index.
+	hidden func(fullName string) bool
+
+	// comment returns the comment indexed for a symbol.
+	comment func(fullName string) string
 }
 
+// maxMemberItems bounds the number of field and enum value items added to the
+// index so very large schemas don't produce an unbounded index.
+const maxMemberItems = 20000
+
+// maxCommentSummaryLength caps the comment stored for each item when only
+// comment summaries are indexed.
+const maxCommentSummaryLength = 160
+
 // SearchItem represents a single searchable item.
 type SearchItem struct {
-	Type     string // "service", "method", "message", "enum"
+	Type     string // "service", "method", "message", "enum", "field", "enum_value"
 	Name     string
 	FullName string
 	Package  string
@@ -26,24 +48,72 @@ type SearchItem struct {
 type SearchResult struct {
 	SearchItem
 	Score int // Higher score = better match
+
+	// Snippet is the part of the comment around the first match of the
+	// query, with each match wrapped in HighlightStart and HighlightEnd. It
+	// is empty when the comment does not match.
+	Snippet string
+}
+
+// HighlightStart and HighlightEnd mark query matches in a snippet. They are
+// control characters, which never appear in the snippet text.
+const (
+	HighlightStart = "\x02"
+	HighlightEnd   = "\x03"
+)
+
+// maxSnippetLength caps the text of a snippet, excluding highlight markers,
+// in bytes.
+const maxSnippetLength = 160
+
+// snippetEllipsis marks where a snippet was cut from a longer comment.
+const snippetEllipsis = "..."
+
+// SearchOptions configures which symbols are included in the search index.
+type SearchOptions struct {
+	// HideInternal excludes internal-only symbols and their members.
+	HideInternal bool
+
+	// CommentSummaries indexes only the first line of each comment, capped at
+	// maxCommentSummaryLength bytes, instead of the full comment. Name and
+	// full-name search are unaffected.
+	CommentSummaries bool
 }
 
 // BuildSearchIndex creates a search index from the registry.
 func BuildSearchIndex(reg *descriptor.Registry) *SearchIndex {
+	return BuildSearchIndexWithOptions(reg, SearchOptions{})
+}
+
+// BuildSearchIndexWithOptions creates a search index from the registry using the given options.
+func BuildSearchIndexWithOptions(reg *descriptor.Registry, opts SearchOptions) *SearchIndex {
 	if reg == nil {
 		return &SearchIndex{Items: []SearchItem{}}
 	}
 
+	hidden := func(fullName string) bool {
+		return opts.HideInternal && reg.IsInternal(fullName)
+	}
+	comment := func(fullName string) string {
+		if opts.CommentSummaries {
+			return commentSummary(reg.CommentIndex[fullName])
+		}
+		return reg.CommentIndex[fullName]
+	}
+
 	var items []SearchItem
 
 	// Index services
 	for _, service := range reg.ServicesByName {
+		if hidden(string(service.FullName())) {
+			continue
+		}
 		item := SearchItem{
 			Type:     "service",
 			Name:     string(service.Name()),
 			FullName: string(service.FullName()),
 			Package:  string(service.ParentFile().Package()),
-			Comment:  reg.CommentIndex[string(service.FullName())],
+			Comment:  comment(string(service.FullName())),
 			URL:      "/services/" + string(service.FullName()),
 		}
 		items = append(items, item)
@@ -52,12 +122,15 @@ func BuildSearchIndex(reg *descriptor.Registry) *SearchIndex {
 		for i := 0; i < service.Methods().Len(); i++ {
 			method := service.Methods().Get(i)
 			methodName := string(service.FullName()) + "/" + string(method.Name())
+			if hidden(methodName) {
+				continue
+			}
 			methodItem := SearchItem{
 				Type:     "method",
 				Name:     string(method.Name()),
 				FullName: methodName,
 				Package:  string(service.ParentFile().Package()),
-				Comment:  reg.CommentIndex[methodName],
+				Comment:  comment(methodName),
 				URL:      "/methods/" + methodName,
 			}
 			items = append(items, methodItem)
@@ -66,12 +139,15 @@ func BuildSearchIndex(reg *descriptor.Registry) *SearchIndex {
 
 	// Index messages
 	for _, message := range reg.MessagesByName {
+		if hidden(string(message.FullName())) {
+			continue
+		}
 		item := SearchItem{
 			Type:     "message",
 			Name:     string(message.Name()),
 			FullName: string(message.FullName()),
 			Package:  string(message.ParentFile().Package()),
-			Comment:  reg.CommentIndex[string(message.FullName())],
+			Comment:  comment(string(message.FullName())),
 			URL:      "/types/" + string(message.FullName()),
 		}
 		items = append(items, item)
@@ -79,41 +155,190 @@ func BuildSearchIndex(reg *descriptor.Registry) *SearchIndex {
 
 	// Index enums
 	for _, enum := range reg.EnumsByName {
+		if hidden(string(enum.FullName())) {
+			continue
+		}
 		item := SearchItem{
 			Type:     "enum",
 			Name:     string(enum.Name()),
 			FullName: string(enum.FullName()),
 			Package:  string(enum.ParentFile().Package()),
-			Comment:  reg.CommentIndex[string(enum.FullName())],
+			Comment:  comment(string(enum.FullName())),
 			URL:      "/types/" + string(enum.FullName()),
 		}
 		items = append(items, item)
 	}
 
-	return &SearchIndex{Items: items}
+	// Index message fields and enum values
+	items = append(items, buildMemberItems(reg, maxMemberItems, hidden, comment)...)
+
+	return &SearchIndex{Items: items, registry: reg, hidden: hidden, comment: comment}
+}
+
+// commentSummary returns the first line of a comment, truncated to
+// maxCommentSummaryLength bytes on a rune boundary.
+func commentSummary(comment string) string {
+	line, _, _ := strings.Cut(comment, "\n")
+	if len(line) <= maxCommentSummaryLength {
+		return line
+	}
+	line = line[:maxCommentSummaryLength]
+	for !utf8.ValidString(line) {
+		line = line[:len(line)-1]
+	}
+	return line
+}
+
+// buildMemberItems creates search items for message fields and enum values.
+// Types are visited in name order so that the same members are kept when the
+// limit is reached.
+func buildMemberItems(reg *descriptor.Registry, limit int, hidden func(string) bool, comment func(string) string) []SearchItem {
+	var items []SearchItem
+
+	messageNames := make([]string, 0, len(reg.MessagesByName))
+	for name := range reg.MessagesByName {
+		messageNames = append(messageNames, name)
+	}
+	sort.Strings(messageNames)
+
+	for _, msgName := range messageNames {
+		if hidden(msgName) {
+			continue
+		}
+		message := reg.MessagesByName[msgName]
+		for i := 0; i < message.Fields().Len(); i++ {
+			if len(items) >= limit {
+				return items
+			}
+			field := message.Fields().Get(i)
+			fieldName := msgName + "." + string(field.Name())
+			items = append(items, SearchItem{
+				Type:     "field",
+				Name:     string(field.Name()),
+				FullName: fieldName,
+				Package:  string(message.ParentFile().Package()),
+				Comment:  comment(fieldName),
+				URL:      "/types/" + msgName + "#" + FieldAnchor(string(field.Name())),
+			})
+		}
+	}
+
+	enumNames := make([]string, 0, len(reg.EnumsByName))
+	for name := range reg.EnumsByName {
+		enumNames = append(enumNames, name)
+	}
+	sort.Strings(enumNames)
+
+	for _, enumName := range enumNames {
+		if hidden(enumName) {
+			continue
+		}
+		enum := reg.EnumsByName[enumName]
+		for i := 0; i < enum.Values().Len(); i++ {
+			if len(items) >= limit {
+				return items
+			}
+			value := enum.Values().Get(i)
+			valueName := enumName + "." + string(value.Name())
+			items = append(items, SearchItem{
+				Type:     "enum_value",
+				Name:     string(value.Name()),
+				FullName: valueName,
+				Package:  string(enum.ParentFile().Package()),
+				Comment:  comment(valueName),
+				URL:      "/types/" + enumName + "#" + EnumValueAnchor(string(value.Name())),
+			})
+		}
+	}
+
+	return items
+}
+
+// searchKinds are the symbol kinds search results can be filtered to. They
+// are the SearchItem types.
+var searchKinds = []string{"service", "method", "message", "enum", "field", "enum_value"}
+
+// kindPrefix introduces an inline kind filter in a query (e.g. "kind:service echo").
+const kindPrefix = "kind:"
+
+// ValidSearchKind reports whether kind is a symbol kind search results can
+// be filtered to.
+func ValidSearchKind(kind string) bool {
+	return slices.Contains(searchKinds, kind)
+}
+
+// parseSearchQuery splits inline kind filters such as "kind:service" out of a
+// query, returning the remaining query text and the kinds named. Terms naming
+// an unknown kind are kept as query text.
+func parseSearchQuery(query string) (string, []string) {
+	var terms, kinds []string
+	for _, term := range strings.Fields(query) {
+		if kind, ok := strings.CutPrefix(strings.ToLower(term), kindPrefix); ok && ValidSearchKind(kind) {
+			kinds = append(kinds, kind)
+			continue
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " "), kinds
 }
 
 // Search performs a case-insensitive search across the index.
 // Returns up to 20 results, ranked by relevance.
 func (idx *SearchIndex) Search(query string) []SearchResult {
+	return idx.SearchKind(query, "")
+}
+
+// SearchKind is like Search, but only returns symbols of the given kind (see
+// ValidSearchKind), or of every kind if kind is empty. The query may also
+// filter by kind inline, as in "kind:service echo"; a result must match
+// every kind filter. Results are ranked within the filtered set.
+func (idx *SearchIndex) SearchKind(query, kind string) []SearchResult {
+	query, kinds := parseSearchQuery(query)
+	if kind != "" {
+		kinds = append(kinds, kind)
+	}
 	if len(query) < 2 {
 		return []SearchResult{}
 	}
+	matchesKind := func(itemType string) bool {
+		for _, k := range kinds {
+			if itemType != k {
+				return false
+			}
+		}
+		return true
+	}
 
 	query = strings.ToLower(query)
 	var results []SearchResult
 
+	// Dotted queries may be a field path like "User.profile.email"
+	resolved := make(map[string]bool)
+	if strings.Contains(query, ".") && matchesKind("field") {
+		for _, item := range idx.resolveFieldPath(query) {
+			resolved[item.FullName] = true
+			results = append(results, SearchResult{
+				SearchItem: item,
+				Score:      fieldPathScore,
+			})
+		}
+	}
+
 	for _, item := range idx.Items {
+		if !matchesKind(item.Type) || item.Type == "field" && resolved[item.FullName] {
+			continue
+		}
 		score := calculateScore(item, query)
 		if score > 0 {
 			results = append(results, SearchResult{
 				SearchItem: item,
 				Score:      score,
+				Snippet:    commentSnippet(item.Comment, query, maxSnippetLength),
 			})
 		}
 	}
 
-	// Sort by score (descending), then by type, then by name
+	// Sort by score (descending), then by type, then by name, then by full name
 	sort.Slice(results, func(i, j int) bool {
 		if results[i].Score != results[j].Score {
 			return results[i].Score > results[j].Score
@@ -121,7 +346,10 @@ func (idx *SearchIndex) Search(query string) []SearchResult {
 		if results[i].Type != results[j].Type {
 			return results[i].Type < results[j].Type
 		}
-		return results[i].Name < results[j].Name
+		if results[i].Name != results[j].Name {
+			return results[i].Name < results[j].Name
+		}
+		return results[i].FullName < results[j].FullName
 	})
 
 	// Limit to 20 results
@@ -132,6 +360,171 @@ func (idx *SearchIndex) Search(query string) []SearchResult {
 	return results
 }
 
+// commentSnippet returns the part of comment around the first match of the
+// lowercase query, on one line and at most maxLength bytes long, with each
+// match wrapped in highlight markers. Returns "" if the comment does not
+// match.
+func commentSnippet(comment, query string, maxLength int) string {
+	// Collapse the comment onto one line, dropping any marker characters
+	text := strings.Join(strings.Fields(strings.Map(func(r rune) rune {
+		if r < ' ' && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, comment)), " ")
+
+	match := indexFold(text, query)
+	if match < 0 {
+		return ""
+	}
+
+	// Center the first match in the window, leaving room for ellipses
+	start, end := 0, len(text)
+	if len(text) > maxLength {
+		window := maxLength - 2*len(snippetEllipsis)
+		start = min(max(match-(window-len(query))/2, 0), match)
+		end = min(start+window, len(text))
+		start = max(end-window, 0)
+		for start < match && !utf8.RuneStart(text[start]) {
+			start++
+		}
+		for end > match+len(query) && end < len(text) && !utf8.RuneStart(text[end]) {
+			end--
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(snippetEllipsis)
+	}
+	rest := text[start:end]
+	for {
+		i := indexFold(rest, query)
+		if i < 0 {
+			break
+		}
+		b.WriteString(rest[:i])
+		b.WriteString(HighlightStart)
+		b.WriteString(rest[i : i+len(query)])
+		b.WriteString(HighlightEnd)
+		rest = rest[i+len(query):]
+	}
+	b.WriteString(rest)
+	if end < len(text) {
+		b.WriteString(snippetEllipsis)
+	}
+	return b.String()
+}
+
+// indexFold returns the byte index of the first case-insensitive match of
+// the lowercase query in text, or -1. Matches have the query's byte length.
+func indexFold(text, query string) int {
+	for i := 0; i+len(query) <= len(text); i++ {
+		if utf8.RuneStart(text[i]) && strings.EqualFold(text[i:i+len(query)], query) {
+			return i
+		}
+	}
+	return -1
+}
+
+// HighlightSnippet renders a search snippet as HTML, escaping its text and
+// wrapping highlighted matches in <mark> elements.
+func HighlightSnippet(snippet string) template.HTML {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, HighlightStart, "<mark>")
+	escaped = strings.ReplaceAll(escaped, HighlightEnd, "</mark>")
+	return template.HTML(escaped)
+}
+
+// fieldPathScore is the score given to a field resolved from a dotted path.
+// It ranks above every substring match since the path identifies one field.
+const fieldPathScore = 300
+
+// resolveFieldPath resolves a lowercase dotted path such as "user.profile.email"
+// by matching a message name (short or fully-qualified) followed by a chain of
+// field names, walking into nested messages. Returns one item per message the
+// path resolves from.
+func (idx *SearchIndex) resolveFieldPath(query string) []SearchItem {
+	if idx.registry == nil {
+		return nil
+	}
+
+	var items []SearchItem
+	for _, message := range idx.registry.MessagesByName {
+		var rest string
+		shortPrefix := strings.ToLower(string(message.Name())) + "."
+		fullPrefix := strings.ToLower(string(message.FullName())) + "."
+		switch {
+		case strings.HasPrefix(query, fullPrefix):
+			rest = query[len(fullPrefix):]
+		case strings.HasPrefix(query, shortPrefix):
+			rest = query[len(shortPrefix):]
+		default:
+			continue
+		}
+
+		if item, ok := idx.walkFieldPath(message, strings.Split(rest, ".")); ok {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// walkFieldPath follows field names through nested messages and returns the
+// search item for the final field. Paths through hidden messages don't resolve.
+func (idx *SearchIndex) walkFieldPath(message protoreflect.MessageDescriptor, path []string) (SearchItem, bool) {
+	for i, segment := range path {
+		if idx.hidden != nil && idx.hidden(string(message.FullName())) {
+			return SearchItem{}, false
+		}
+
+		field := findFieldFold(message, segment)
+		if field == nil {
+			return SearchItem{}, false
+		}
+
+		if i < len(path)-1 {
+			if field.Message() == nil || field.IsMap() {
+				return SearchItem{}, false
+			}
+			message = field.Message()
+			continue
+		}
+
+		msgName := string(message.FullName())
+		fieldName := msgName + "." + string(field.Name())
+		return SearchItem{
+			Type:     "field",
+			Name:     string(field.Name()),
+			FullName: fieldName,
+			Package:  string(message.ParentFile().Package()),
+			Comment:  idx.comment(fieldName),
+			URL:      "/types/" + msgName + "#" + FieldAnchor(string(field.Name())),
+		}, true
+	}
+
+	return SearchItem{}, false
+}
+
+// findFieldFold finds a field by proto or JSON name, ignoring case.
+func findFieldFold(message protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	for i := 0; i < message.Fields().Len(); i++ {
+		field := message.Fields().Get(i)
+		if strings.EqualFold(string(field.Name()), name) || strings.EqualFold(field.JSONName(), name) {
+			return field
+		}
+	}
+	return nil
+}
+
+// Fuzzy matching parameters. Fuzzy matches only apply to queries of at least
+// minFuzzyQueryLength characters and always score below substring matches.
+const (
+	minFuzzyQueryLength = 4
+	maxFuzzyScore       = 9
+)
+
 // calculateScore calculates a relevance score for a search item.
 // Higher scores indicate better matches.
 func calculateScore(item SearchItem, query string) int {
@@ -170,6 +563,12 @@ func calculateScore(item SearchItem, query string) int {
 		score += 10
 	}
 
+	// Fall back to fuzzy matching on the name for near-misses. Fuzzy scores
+	// get no length bonus, so they stay below every substring match.
+	if score == 0 {
+		return fuzzyScore(lowerName, query)
+	}
+
 	// Bonus for shorter names (more specific matches)
 	if len(item.Name) < 20 {
 		score += 5
@@ -177,3 +576,45 @@ func calculateScore(item SearchItem, query string) int {
 
 	return score
 }
+
+// fuzzyScore scores a name against a query by edit distance.
+// Returns 0 if the query is too short or the distance exceeds the threshold.
+func fuzzyScore(name, query string) int {
+	if len(query) < minFuzzyQueryLength {
+		return 0
+	}
+
+	// Allow roughly one edit per four characters of query
+	maxDistance := len(query) / 4
+	distance := levenshtein(name, query)
+	if distance > maxDistance {
+		return 0
+	}
+
+	return maxFuzzyScore - distance
+}
+
+// levenshtein computes the edit distance between two strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,441 @@
+package docs
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+)
+
+func loadSearchIndex(t *testing.T) *SearchIndex {
+	t.Helper()
+
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "basic")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	return BuildSearchIndex(reg)
+}
+
+func TestSearch(t *testing.T) {
+	idx := loadSearchIndex(t)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantFirst string
+		wantNone  bool
+	}{
+		{
+			name:      "exact name",
+			query:     "EchoService",
+			wantFirst: "echo.v1.EchoService",
+		},
+		{
+			name:      "prefix",
+			query:     "EchoReq",
+			wantFirst: "echo.v1.EchoRequest",
+		},
+		{
+			name:      "fuzzy typo",
+			query:     "EchoServce",
+			wantFirst: "echo.v1.EchoService",
+		},
+		{
+			name:     "garbage query",
+			query:    "zzzz",
+			wantNone: true,
+		},
+		{
+			name:     "short query does not fuzzy match",
+			query:    "zz",
+			wantNone: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := idx.Search(tt.query)
+
+			if tt.wantNone {
+				if len(results) != 0 {
+					t.Errorf("Expected no results for %q, got %d (first: %s)", tt.query, len(results), results[0].FullName)
+				}
+				return
+			}
+
+			if len(results) == 0 {
+				t.Fatalf("Expected results for %q, got none", tt.query)
+			}
+			if results[0].FullName != tt.wantFirst {
+				t.Errorf("Expected first result %q, got %q", tt.wantFirst, results[0].FullName)
+			}
+		})
+	}
+}
+
+func TestSearchFuzzyRanksBelowSubstring(t *testing.T) {
+	item := SearchItem{Name: "EchoService", FullName: "echo.v1.EchoService"}
+	commentItems := []SearchItem{
+		{Name: "Other", FullName: "echo.v1.Other", Comment: "mentions echoservce"},
+		// Long names get no length bonus, so this is the lowest substring score
+		{Name: "SomeVeryLongMessageName", FullName: "echo.v1.SomeVeryLongMessageName", Comment: "mentions echoservce"},
+	}
+
+	fuzzy := calculateScore(item, "echoservce")
+	if fuzzy == 0 {
+		t.Fatal("Expected fuzzy match to score")
+	}
+	for _, commentItem := range commentItems {
+		if substring := calculateScore(commentItem, "echoservce"); fuzzy >= substring {
+			t.Errorf("Expected fuzzy score %d to be below substring score %d for %s", fuzzy, substring, commentItem.Name)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"echoservice", "echoservce", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSearchIndexesMembers(t *testing.T) {
+	idx := loadSearchIndex(t)
+
+	tests := []struct {
+		query    string
+		wantType string
+		wantName string
+		wantURL  string
+	}{
+		{"count", "field", "echo.v1.EchoRequest.count", "/types/echo.v1.EchoRequest#field-count"},
+		{"STATUS_SUCCESS", "enum_value", "echo.v1.Status.STATUS_SUCCESS", "/types/echo.v1.Status#value-STATUS_SUCCESS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results := idx.Search(tt.query)
+			for _, r := range results {
+				if r.FullName == tt.wantName {
+					if r.Type != tt.wantType {
+						t.Errorf("Expected type %q, got %q", tt.wantType, r.Type)
+					}
+					if r.URL != tt.wantURL {
+						t.Errorf("Expected URL %q, got %q", tt.wantURL, r.URL)
+					}
+					return
+				}
+			}
+			t.Errorf("Expected %q in results for %q", tt.wantName, tt.query)
+		})
+	}
+}
+
+func TestSearchMemberComments(t *testing.T) {
+	idx := loadSearchIndex(t)
+
+	// "echo back" only appears in the comment of EchoRequest.message
+	results := idx.Search("echo back")
+	for _, r := range results {
+		if r.FullName == "echo.v1.EchoRequest.message" {
+			return
+		}
+	}
+	t.Error("Expected field comment to be searchable")
+}
+
+func TestBuildMemberItemsLimit(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	items := buildMemberItems(reg, 5, func(string) bool { return false }, func(string) string { return "" })
+	if len(items) != 5 {
+		t.Errorf("Expected 5 member items, got %d", len(items))
+	}
+}
+
+func TestSearchFieldPath(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	idx := BuildSearchIndex(reg)
+
+	tests := []struct {
+		name     string
+		query    string
+		wantName string
+		wantURL  string
+	}{
+		{
+			name:     "two-level nested path",
+			query:    "User.profile.timezone",
+			wantName: "users.v1.UserProfile.timezone",
+			wantURL:  "/types/users.v1.UserProfile#field-timezone",
+		},
+		{
+			name:     "fully-qualified root and JSON names",
+			query:    "users.v1.User.profile.socialLinks",
+			wantName: "users.v1.UserProfile.social_links",
+			wantURL:  "/types/users.v1.UserProfile#field-social_links",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := idx.Search(tt.query)
+			if len(results) == 0 {
+				t.Fatalf("Expected results for %q, got none", tt.query)
+			}
+			if results[0].FullName != tt.wantName {
+				t.Errorf("Expected first result %q, got %q", tt.wantName, results[0].FullName)
+			}
+			if results[0].URL != tt.wantURL {
+				t.Errorf("Expected URL %q, got %q", tt.wantURL, results[0].URL)
+			}
+		})
+	}
+
+	if results := idx.resolveFieldPath("user.profile.nonexistent"); len(results) != 0 {
+		t.Errorf("Expected unresolvable path to return nothing, got %d", len(results))
+	}
+}
+
+func TestSearchCommentSummaries(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	idx := BuildSearchIndexWithOptions(reg, SearchOptions{CommentSummaries: true})
+
+	found := func(query, fullName string) bool {
+		for _, r := range idx.Search(query) {
+			if r.FullName == fullName {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The first line of OrderService's comment is still searchable
+	if !found("customer orders and fulfillment", "orders.v1.OrderService") {
+		t.Error("Expected comment summary to be searchable")
+	}
+	// Later lines are dropped
+	if found("payment processing", "orders.v1.OrderService") {
+		t.Error("Expected only the first comment line to be indexed")
+	}
+	// Name search is unaffected
+	if !found("OrderService", "orders.v1.OrderService") {
+		t.Error("Expected name search to still work")
+	}
+
+	for _, item := range idx.Items {
+		if len(item.Comment) > maxCommentSummaryLength || strings.Contains(item.Comment, "\n") {
+			t.Errorf("Expected capped single-line comment for %s, got %q", item.FullName, item.Comment)
+		}
+	}
+}
+
+func TestCommentSummary(t *testing.T) {
+	if got := commentSummary("First line.\nSecond line."); got != "First line." {
+		t.Errorf("Expected first line, got %q", got)
+	}
+
+	long := strings.Repeat("é", maxCommentSummaryLength)
+	got := commentSummary(long)
+	if len(got) > maxCommentSummaryLength || !utf8.ValidString(got) {
+		t.Errorf("Expected valid UTF-8 capped at %d bytes, got %d bytes", maxCommentSummaryLength, len(got))
+	}
+}
+
+func TestSearchSnippets(t *testing.T) {
+	idx := loadSearchIndex(t)
+
+	var snippet string
+	for _, r := range idx.Search("Echo Back") {
+		if r.FullName == "echo.v1.EchoRequest.message" {
+			snippet = r.Snippet
+		}
+	}
+	if !strings.Contains(strings.ToLower(snippet), HighlightStart+"echo back"+HighlightEnd) {
+		t.Errorf("Expected snippet to highlight the query, got %q", snippet)
+	}
+
+	for _, r := range idx.Search("EchoRequest") {
+		if r.Snippet != "" && !strings.Contains(strings.ToLower(r.Snippet), strings.ToLower(HighlightStart+"EchoRequest"+HighlightEnd)) {
+			t.Errorf("Expected snippet of %s to highlight the query, got %q", r.FullName, r.Snippet)
+		}
+	}
+}
+
+func TestCommentSnippet(t *testing.T) {
+	strip := func(s string) string {
+		return strings.NewReplacer(HighlightStart, "", HighlightEnd, "").Replace(s)
+	}
+
+	tests := []struct {
+		name    string
+		comment string
+		query   string
+		want    string
+	}{
+		{
+			name:    "short comment",
+			comment: "Returns the user.\nFails if the User is missing.",
+			query:   "user",
+			want:    "Returns the \x02user\x03. Fails if the \x02User\x03 is missing.",
+		},
+		{
+			name:    "no match",
+			comment: "Returns the user.",
+			query:   "order",
+			want:    "",
+		},
+		{
+			name:    "markers in the comment are dropped",
+			comment: "a \x02user\x03 id",
+			query:   "user",
+			want:    "a \x02user\x03 id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commentSnippet(tt.comment, tt.query, maxSnippetLength); got != tt.want {
+				t.Errorf("commentSnippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("long comment is truncated around the match", func(t *testing.T) {
+		for _, position := range []string{"start", "middle", "end"} {
+			filler := strings.Repeat("lorem ipsum dolor ", 30)
+			var comment string
+			switch position {
+			case "start":
+				comment = "needle " + filler
+			case "middle":
+				comment = filler + "needle " + filler
+			case "end":
+				comment = filler + "needle"
+			}
+
+			got := commentSnippet(comment, "needle", 60)
+			if !strings.Contains(got, HighlightStart+"needle"+HighlightEnd) {
+				t.Errorf("%s: expected highlighted match, got %q", position, got)
+			}
+			if text := strip(got); len(text) > 60 {
+				t.Errorf("%s: expected at most 60 bytes, got %d: %q", position, len(text), text)
+			}
+			if position != "start" && !strings.HasPrefix(got, snippetEllipsis) {
+				t.Errorf("%s: expected leading ellipsis, got %q", position, got)
+			}
+			if position != "end" && !strings.HasSuffix(got, snippetEllipsis) {
+				t.Errorf("%s: expected trailing ellipsis, got %q", position, got)
+			}
+		}
+	})
+
+	t.Run("multi-byte text is cut on rune boundaries", func(t *testing.T) {
+		comment := strings.Repeat("é", 100) + "needle" + strings.Repeat("é", 100)
+		got := commentSnippet(comment, "needle", 41)
+		if !utf8.ValidString(got) || len(strip(got)) > 41 {
+			t.Errorf("Expected valid UTF-8 of at most 41 bytes, got %q", got)
+		}
+	})
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	got := string(HighlightSnippet("a <b> " + HighlightStart + "user" + HighlightEnd))
+	if want := "a &lt;b&gt; <mark>user</mark>"; got != want {
+		t.Errorf("HighlightSnippet() = %q, want %q", got, want)
+	}
+}
+
+func TestSearchKind(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	idx := BuildSearchIndex(reg)
+
+	kindsOf := func(results []SearchResult) map[string]bool {
+		kinds := make(map[string]bool)
+		for _, r := range results {
+			kinds[r.Type] = true
+		}
+		return kinds
+	}
+	if kinds := kindsOf(idx.Search("user")); len(kinds) < 3 {
+		t.Fatalf("Expected unfiltered results of several kinds, got %v", kinds)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		kind  string
+		want  string
+	}{
+		{"param", "user", "service", "service"},
+		{"inline", "kind:method user", "", "method"},
+		{"inline after the query", "user KIND:enum", "", "enum"},
+		{"param and inline agree", "kind:field user", "field", "field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := idx.SearchKind(tt.query, tt.kind)
+			if len(results) == 0 {
+				t.Fatalf("Expected %s results for %q", tt.want, tt.query)
+			}
+			if kinds := kindsOf(results); len(kinds) != 1 || !kinds[tt.want] {
+				t.Errorf("Expected only %s results, got %v", tt.want, kinds)
+			}
+			for i := 1; i < len(results); i++ {
+				if results[i].Score > results[i-1].Score {
+					t.Errorf("Expected results ranked by score, got %d after %d", results[i].Score, results[i-1].Score)
+				}
+			}
+		})
+	}
+
+	// Filtering happens before the result limit, so a kind crowded out of
+	// the unfiltered results is still found
+	if services := idx.SearchKind("user", "service"); services[0].FullName != "users.v1.UserService" {
+		t.Errorf("Expected users.v1.UserService first, got %q", services[0].FullName)
+	}
+	if results := idx.SearchKind("kind:service user", "method"); len(results) != 0 {
+		t.Errorf("Expected conflicting kinds to match nothing, got %d results", len(results))
+	}
+	if results := idx.Search("kind:service"); len(results) != 0 {
+		t.Errorf("Expected a kind filter alone to match nothing, got %d results", len(results))
+	}
+
+	// Unknown kinds are searched as text
+	if query, kinds := parseSearchQuery("kind:widget user"); query != "kind:widget user" || kinds != nil {
+		t.Errorf("Expected unknown kind kept as text, got %q %v", query, kinds)
+	}
+}
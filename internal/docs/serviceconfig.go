@@ -0,0 +1,156 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MethodServiceConfig is the per-method policy from a gRPC service config.
+type MethodServiceConfig struct {
+	// Timeout is the default deadline as a protobuf Duration string (e.g. "1.5s").
+	Timeout      string
+	WaitForReady *bool
+	RetryPolicy  *RetryPolicy
+}
+
+// RetryPolicy describes how failed calls to a method are retried.
+type RetryPolicy struct {
+	MaxAttempts          int
+	InitialBackoff       string
+	MaxBackoff           string
+	BackoffMultiplier    float64
+	RetryableStatusCodes []string
+}
+
+// ServiceConfig holds method configs parsed from a grpc_service_config JSON
+// document, indexed by the names they apply to.
+type ServiceConfig struct {
+	methods  map[string]*MethodServiceConfig // "pkg.Service/Method"
+	services map[string]*MethodServiceConfig // "pkg.Service"
+	fallback *MethodServiceConfig            // entry with an empty name
+}
+
+// serviceConfigJSON mirrors the subset of the grpc.service_config.ServiceConfig
+// JSON format that is shown in the docs.
+type serviceConfigJSON struct {
+	MethodConfig []struct {
+		Name []struct {
+			Service string `json:"service"`
+			Method  string `json:"method"`
+		} `json:"name"`
+		Timeout      string `json:"timeout"`
+		WaitForReady *bool  `json:"waitForReady"`
+		RetryPolicy  *struct {
+			MaxAttempts          int     `json:"maxAttempts"`
+			InitialBackoff       string  `json:"initialBackoff"`
+			MaxBackoff           string  `json:"maxBackoff"`
+			BackoffMultiplier    float64 `json:"backoffMultiplier"`
+			RetryableStatusCodes []any   `json:"retryableStatusCodes"`
+		} `json:"retryPolicy"`
+	} `json:"methodConfig"`
+}
+
+// LoadServiceConfig reads and parses a gRPC service config JSON file.
+func LoadServiceConfig(path string) (*ServiceConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read service config: %w", err)
+	}
+	return ParseServiceConfig(data)
+}
+
+// ParseServiceConfig parses a gRPC service config JSON document.
+func ParseServiceConfig(data []byte) (*ServiceConfig, error) {
+	var raw serviceConfigJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse service config JSON: %w", err)
+	}
+
+	sc := &ServiceConfig{
+		methods:  make(map[string]*MethodServiceConfig),
+		services: make(map[string]*MethodServiceConfig),
+	}
+
+	for i, mc := range raw.MethodConfig {
+		cfg := &MethodServiceConfig{
+			Timeout:      mc.Timeout,
+			WaitForReady: mc.WaitForReady,
+		}
+		if rp := mc.RetryPolicy; rp != nil {
+			cfg.RetryPolicy = &RetryPolicy{
+				MaxAttempts:       rp.MaxAttempts,
+				InitialBackoff:    rp.InitialBackoff,
+				MaxBackoff:        rp.MaxBackoff,
+				BackoffMultiplier: rp.BackoffMultiplier,
+			}
+			for _, code := range rp.RetryableStatusCodes {
+				name, err := statusCodeName(code)
+				if err != nil {
+					return nil, fmt.Errorf("methodConfig[%d]: %w", i, err)
+				}
+				cfg.RetryPolicy.RetryableStatusCodes = append(cfg.RetryPolicy.RetryableStatusCodes, name)
+			}
+		}
+
+		for _, name := range mc.Name {
+			switch {
+			case name.Service == "" && name.Method == "":
+				sc.fallback = cfg
+			case name.Service == "":
+				return nil, fmt.Errorf("methodConfig[%d]: method %q has no service", i, name.Method)
+			case name.Method == "":
+				sc.services[name.Service] = cfg
+			default:
+				sc.methods[name.Service+"/"+name.Method] = cfg
+			}
+		}
+	}
+
+	return sc, nil
+}
+
+// MethodConfig returns the config that applies to a method ("pkg.Service/Method"),
+// preferring an exact method match, then the service, then the default entry.
+// Returns nil if none applies or sc is nil.
+func (sc *ServiceConfig) MethodConfig(fullName string) *MethodServiceConfig {
+	if sc == nil {
+		return nil
+	}
+	if cfg, ok := sc.methods[fullName]; ok {
+		return cfg
+	}
+	service, _, _ := strings.Cut(fullName, "/")
+	if cfg, ok := sc.services[service]; ok {
+		return cfg
+	}
+	return sc.fallback
+}
+
+// statusCodeNames are the canonical gRPC status code names, indexed by code.
+var statusCodeNames = []string{
+	"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED",
+	"NOT_FOUND", "ALREADY_EXISTS", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED",
+	"FAILED_PRECONDITION", "ABORTED", "OUT_OF_RANGE", "UNIMPLEMENTED",
+	"INTERNAL", "UNAVAILABLE", "DATA_LOSS", "UNAUTHENTICATED",
+}
+
+// statusCodeName normalizes a retryable status code, given either as a name
+// ("UNAVAILABLE") or a number (14), to its canonical name.
+func statusCodeName(code any) (string, error) {
+	switch v := code.(type) {
+	case string:
+		name := strings.ToUpper(v)
+		for _, known := range statusCodeNames {
+			if name == known {
+				return name, nil
+			}
+		}
+	case float64:
+		if n := int(v); float64(n) == v && n >= 0 && n < len(statusCodeNames) {
+			return statusCodeNames[n], nil
+		}
+	}
+	return "", fmt.Errorf("invalid status code %v", code)
+}
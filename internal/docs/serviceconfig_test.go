@@ -0,0 +1,71 @@
+package docs
+
+import "testing"
+
+func TestParseServiceConfig(t *testing.T) {
+	sc, err := ParseServiceConfig([]byte(`{
+  "methodConfig": [
+    {"name": [{}], "timeout": "30s"},
+    {"name": [{"service": "users.v1.UserService"}], "timeout": "5s"},
+    {
+      "name": [{"service": "users.v1.UserService", "method": "GetUser"}],
+      "timeout": "1.5s",
+      "waitForReady": true,
+      "retryPolicy": {
+        "maxAttempts": 3,
+        "initialBackoff": "0.1s",
+        "maxBackoff": "1s",
+        "backoffMultiplier": 2,
+        "retryableStatusCodes": ["unavailable", 4]
+      }
+    }
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("ParseServiceConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		method      string
+		wantTimeout string
+	}{
+		{"users.v1.UserService/GetUser", "1.5s"},
+		{"users.v1.UserService/CreateUser", "5s"},
+		{"orders.v1.OrderService/GetOrder", "30s"},
+	}
+	for _, tt := range tests {
+		cfg := sc.MethodConfig(tt.method)
+		if cfg == nil || cfg.Timeout != tt.wantTimeout {
+			t.Errorf("MethodConfig(%q) = %+v, want timeout %q", tt.method, cfg, tt.wantTimeout)
+		}
+	}
+
+	rp := sc.MethodConfig("users.v1.UserService/GetUser").RetryPolicy
+	if rp == nil {
+		t.Fatal("Expected retry policy")
+	}
+	if rp.MaxAttempts != 3 || rp.InitialBackoff != "0.1s" || rp.MaxBackoff != "1s" || rp.BackoffMultiplier != 2 {
+		t.Errorf("Unexpected retry policy: %+v", rp)
+	}
+	if len(rp.RetryableStatusCodes) != 2 || rp.RetryableStatusCodes[0] != "UNAVAILABLE" || rp.RetryableStatusCodes[1] != "DEADLINE_EXCEEDED" {
+		t.Errorf("Expected normalized status codes, got %v", rp.RetryableStatusCodes)
+	}
+
+	var nilConfig *ServiceConfig
+	if cfg := nilConfig.MethodConfig("users.v1.UserService/GetUser"); cfg != nil {
+		t.Errorf("Expected nil config from nil ServiceConfig, got %+v", cfg)
+	}
+}
+
+func TestParseServiceConfigErrors(t *testing.T) {
+	tests := map[string]string{
+		"malformed JSON":         `{"methodConfig": [`,
+		"unknown status code":    `{"methodConfig": [{"name": [{}], "retryPolicy": {"retryableStatusCodes": ["NOPE"]}}]}`,
+		"method without service": `{"methodConfig": [{"name": [{"method": "GetUser"}]}]}`,
+	}
+	for name, data := range tests {
+		if _, err := ParseServiceConfig([]byte(data)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
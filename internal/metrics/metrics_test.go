@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.NewCounterVec("test_requests_total", "Requests served.", "path")
+	latency := registry.NewHistogramVec("test_latency_seconds", "Latency.", []float64{0.1, 1}, "path")
+
+	requests.Inc("/b")
+	requests.Inc("/a")
+	requests.Add(2, "/a")
+	requests.Inc(`say "hi"` + "\n")
+	latency.Observe(0.05, "/a")
+	latency.Observe(0.5, "/a")
+	latency.Observe(5, "/a")
+
+	var buf bytes.Buffer
+	if err := registry.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+
+	want := `# HELP test_requests_total Requests served.
+# TYPE test_requests_total counter
+test_requests_total{path="/a"} 3
+test_requests_total{path="/b"} 1
+test_requests_total{path="say \"hi\"\n"} 1
+# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{path="/a",le="0.1"} 1
+test_latency_seconds_bucket{path="/a",le="1"} 2
+test_latency_seconds_bucket{path="/a",le="+Inf"} 3
+test_latency_seconds_sum{path="/a"} 5.55
+test_latency_seconds_count{path="/a"} 3
+`
+	if buf.String() != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
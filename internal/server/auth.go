@@ -0,0 +1,86 @@
+package server
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// requireAuth is middleware that rejects requests without the credentials
+// configured in ui.auth. Liveness probes at /healthz, CORS preflights (which
+// never carry credentials), and POST /api/reload (which checks its own token)
+// are let through.
+func (s *Server) requireAuth(next http.Handler) http.Handler {
+	auth := s.config.UI.Auth
+	checker := &basicAuthChecker{username: auth.Username, passwordHash: []byte(auth.PasswordHash)}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/healthz",
+			r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "",
+			r.URL.Path == "/api/reload" && s.config.ReloadToken != "":
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var ok bool
+		switch auth.Type {
+		case config.UIAuthTypeBasic:
+			username, password, hasBasic := r.BasicAuth()
+			ok = hasBasic && checker.check(username, password)
+		case config.UIAuthTypeBearer:
+			token, hasBearer := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			ok = hasBearer && subtle.ConstantTimeCompare([]byte(token), []byte(auth.Token)) == 1
+		}
+		if ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Browsers prompt for basic auth credentials on this challenge
+		if auth.Type == config.UIAuthTypeBasic {
+			w.Header().Set("WWW-Authenticate", `Basic realm="Reflect", charset="UTF-8"`)
+		} else {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="Reflect"`)
+		}
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	})
+}
+
+// basicAuthChecker verifies basic auth credentials against a bcrypt hash.
+// bcrypt is slow by design and browsers resend credentials with every
+// request, so the digest of the last credentials that matched is remembered.
+type basicAuthChecker struct {
+	username     string
+	passwordHash []byte
+
+	mu       sync.Mutex
+	verified [sha256.Size]byte
+}
+
+func (c *basicAuthChecker) check(username, password string) bool {
+	if subtle.ConstantTimeCompare([]byte(username), []byte(c.username)) != 1 {
+		return false
+	}
+	digest := sha256.Sum256([]byte(password))
+
+	c.mu.Lock()
+	verified := c.verified
+	c.mu.Unlock()
+	if subtle.ConstantTimeCompare(digest[:], verified[:]) == 1 {
+		return true
+	}
+
+	if bcrypt.CompareHashAndPassword(c.passwordHash, []byte(password)) != nil {
+		return false
+	}
+	c.mu.Lock()
+	c.verified = digest
+	c.mu.Unlock()
+	return true
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/server/theme"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestRequireAuth(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("hunter2"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		auth          config.UIAuthConfig
+		setAuth       func(r *http.Request)
+		wantStatus    int
+		wantChallenge string
+	}{
+		{
+			name:       "basic with valid credentials",
+			auth:       config.UIAuthConfig{Type: config.UIAuthTypeBasic, Username: "admin", PasswordHash: string(hash)},
+			setAuth:    func(r *http.Request) { r.SetBasicAuth("admin", "hunter2") },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:          "basic with wrong password",
+			auth:          config.UIAuthConfig{Type: config.UIAuthTypeBasic, Username: "admin", PasswordHash: string(hash)},
+			setAuth:       func(r *http.Request) { r.SetBasicAuth("admin", "hunter3") },
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: `Basic realm="Reflect", charset="UTF-8"`,
+		},
+		{
+			name:          "basic with wrong username",
+			auth:          config.UIAuthConfig{Type: config.UIAuthTypeBasic, Username: "admin", PasswordHash: string(hash)},
+			setAuth:       func(r *http.Request) { r.SetBasicAuth("root", "hunter2") },
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: `Basic realm="Reflect", charset="UTF-8"`,
+		},
+		{
+			name:          "basic without credentials",
+			auth:          config.UIAuthConfig{Type: config.UIAuthTypeBasic, Username: "admin", PasswordHash: string(hash)},
+			setAuth:       func(r *http.Request) {},
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: `Basic realm="Reflect", charset="UTF-8"`,
+		},
+		{
+			name:       "bearer with valid token",
+			auth:       config.UIAuthConfig{Type: config.UIAuthTypeBearer, Token: "s3cret"},
+			setAuth:    func(r *http.Request) { r.Header.Set("Authorization", "Bearer s3cret") },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:          "bearer with wrong token",
+			auth:          config.UIAuthConfig{Type: config.UIAuthTypeBearer, Token: "s3cret"},
+			setAuth:       func(r *http.Request) { r.Header.Set("Authorization", "Bearer guess") },
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: `Bearer realm="Reflect"`,
+		},
+		{
+			name:          "bearer sent as basic",
+			auth:          config.UIAuthConfig{Type: config.UIAuthTypeBearer, Token: "s3cret"},
+			setAuth:       func(r *http.Request) { r.SetBasicAuth("admin", "s3cret") },
+			wantStatus:    http.StatusUnauthorized,
+			wantChallenge: `Bearer realm="Reflect"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, err := NewWithTheme(nil, theme.GetDefaultTheme(), &config.Config{UI: config.UIConfig{Auth: tt.auth}})
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+
+			// Checked twice, as the second basic auth check is remembered
+			for i := 0; i < 2; i++ {
+				req := httptest.NewRequest("GET", "/api/status", nil)
+				tt.setAuth(req)
+				w := httptest.NewRecorder()
+				srv.ServeHTTP(w, req)
+
+				if w.Code != tt.wantStatus {
+					t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+				}
+				if got := w.Header().Get("WWW-Authenticate"); got != tt.wantChallenge {
+					t.Errorf("Expected WWW-Authenticate %q, got %q", tt.wantChallenge, got)
+				}
+			}
+
+			// Liveness probes never need credentials
+			req := httptest.NewRequest("GET", "/healthz", nil)
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected /healthz without credentials to be allowed, got %d", w.Code)
+			}
+		})
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		srv, err := NewWithTheme(nil, theme.GetDefaultTheme(), &config.Config{})
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+		req := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200 without auth configured, got %d", w.Code)
+		}
+	})
+
+	t.Run("reload endpoint uses its own token", func(t *testing.T) {
+		cfg := &config.Config{
+			ReloadToken: "reload-token",
+			UI:          config.UIConfig{Auth: config.UIAuthConfig{Type: config.UIAuthTypeBearer, Token: "s3cret"}},
+		}
+		srv, err := NewWithTheme(nil, theme.GetDefaultTheme(), cfg)
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+		req := httptest.NewRequest("POST", "/api/reload", nil)
+		req.Header.Set("Authorization", "Bearer reload-token")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		// Past authentication, reload is unavailable without a proto root
+		if w.Code != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d", w.Code)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+)
+
+func TestCompression(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	srv, err := New(reg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		path             string
+		acceptEncoding   string
+		expectedEncoding string
+		expectedType     string
+		expectedContains string
+	}{
+		{
+			name:             "gzip html",
+			path:             "/",
+			acceptEncoding:   "gzip, deflate, br",
+			expectedEncoding: "gzip",
+			expectedType:     "text/html",
+			expectedContains: "users.v1.UserService",
+		},
+		{
+			name:             "deflate html",
+			path:             "/",
+			acceptEncoding:   "deflate",
+			expectedEncoding: "deflate",
+			expectedType:     "text/html",
+			expectedContains: "users.v1.UserService",
+		},
+		{
+			name:             "gzip refused",
+			path:             "/",
+			acceptEncoding:   "gzip;q=0",
+			expectedType:     "text/html",
+			expectedContains: "users.v1.UserService",
+		},
+		{
+			name:             "no accept encoding",
+			path:             "/",
+			expectedType:     "text/html",
+			expectedContains: "users.v1.UserService",
+		},
+		{
+			name:             "small json body is not compressed",
+			path:             "/api/status",
+			acceptEncoding:   "gzip",
+			expectedType:     "application/json",
+			expectedContains: `"status":"ok"`,
+		},
+		{
+			name:             "static asset",
+			path:             "/static/app.css",
+			acceptEncoding:   "gzip",
+			expectedEncoding: "gzip",
+			expectedType:     "text/css",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+			}
+			if got := w.Header().Get("Content-Encoding"); got != tt.expectedEncoding {
+				t.Fatalf("Expected Content-Encoding %q, got %q", tt.expectedEncoding, got)
+			}
+			if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.expectedType) {
+				t.Errorf("Expected Content-Type %q, got %q", tt.expectedType, got)
+			}
+
+			var body io.Reader = w.Body
+			switch tt.expectedEncoding {
+			case "gzip":
+				if w.Header().Get("Content-Length") != "" {
+					t.Error("Expected no Content-Length on a compressed response")
+				}
+				gz, err := gzip.NewReader(w.Body)
+				if err != nil {
+					t.Fatalf("Failed to read gzip body: %v", err)
+				}
+				body = gz
+			case "deflate":
+				body = flate.NewReader(w.Body)
+			}
+
+			data, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatalf("Failed to read body: %v", err)
+			}
+			if !strings.Contains(string(data), tt.expectedContains) {
+				t.Errorf("Expected body to contain %q", tt.expectedContains)
+			}
+		})
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                  "",
+		"identity":          "",
+		"gzip":              "gzip",
+		"deflate, gzip":     "gzip",
+		"deflate":           "deflate",
+		"GZIP;q=0.5":        "gzip",
+		"gzip;q=0, deflate": "deflate",
+		"*":                 "gzip",
+		"br, gzip;q=0":      "",
+	}
+	for header, want := range tests {
+		if got := negotiateEncoding(header); got != want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
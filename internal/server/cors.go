@@ -0,0 +1,85 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bnprtr/reflect/internal/config"
+)
+
+// cors is middleware that adds CORS headers for origins allowed by the config
+// and answers preflight requests. Without configured origins it does nothing,
+// leaving the API same-origin only.
+func (s *Server) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.config == nil || len(s.config.CORS.AllowedOrigins) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		cfg := &s.config.CORS
+
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		if !cfg.AllowsOrigin(origin) {
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		methods := cfg.AllowedMethods
+		if len(methods) == 0 {
+			methods = config.DefaultCORSMethods
+		}
+		if preflight && !containsFold(methods, r.Header.Get("Access-Control-Request-Method")) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		if allowsAnyOrigin(cfg) && !cfg.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+		if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// allowsAnyOrigin reports whether the "*" origin is configured.
+func allowsAnyOrigin(cfg *config.CORSConfig) bool {
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/server/theme"
+)
+
+func TestCORS(t *testing.T) {
+	cfg := &config.Config{
+		CORS: config.CORSConfig{
+			AllowedOrigins:   []string{"https://dashboard.example.com"},
+			AllowedMethods:   []string{"GET", "POST"},
+			AllowCredentials: true,
+		},
+	}
+
+	srv, err := NewWithTheme(nil, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	tests := []struct {
+		name              string
+		method            string
+		path              string
+		origin            string
+		requestMethod     string // Access-Control-Request-Method for preflights
+		expectedStatus    int
+		expectedOrigin    string
+		expectedMethods   string
+		expectCredentials bool
+	}{
+		{
+			name:              "preflight from allowed origin",
+			method:            "OPTIONS",
+			path:              "/api/search",
+			origin:            "https://dashboard.example.com",
+			requestMethod:     "GET",
+			expectedStatus:    http.StatusNoContent,
+			expectedOrigin:    "https://dashboard.example.com",
+			expectedMethods:   "GET, POST",
+			expectCredentials: true,
+		},
+		{
+			name:              "simple request from allowed origin",
+			method:            "GET",
+			path:              "/api/status",
+			origin:            "https://dashboard.example.com",
+			expectedStatus:    http.StatusOK,
+			expectedOrigin:    "https://dashboard.example.com",
+			expectCredentials: true,
+		},
+		{
+			name:           "preflight from disallowed origin",
+			method:         "OPTIONS",
+			path:           "/api/search",
+			origin:         "https://evil.example.com",
+			requestMethod:  "GET",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "simple request from disallowed origin",
+			method:         "GET",
+			path:           "/api/status",
+			origin:         "https://evil.example.com",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "preflight for disallowed method",
+			method:         "OPTIONS",
+			path:           "/api/search",
+			origin:         "https://dashboard.example.com",
+			requestMethod:  "DELETE",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "non-API route",
+			method:         "GET",
+			path:           "/api-docs-are-not-here",
+			origin:         "https://dashboard.example.com",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+				req.Header.Set("Access-Control-Request-Headers", "content-type")
+			}
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.expectedOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tt.expectedOrigin, got)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Methods"); got != tt.expectedMethods {
+				t.Errorf("Expected Access-Control-Allow-Methods %q, got %q", tt.expectedMethods, got)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Credentials") == "true"; got != tt.expectCredentials {
+				t.Errorf("Expected Access-Control-Allow-Credentials=%v", tt.expectCredentials)
+			}
+			if tt.expectedMethods != "" && w.Header().Get("Access-Control-Allow-Headers") != "content-type" {
+				t.Errorf("Expected requested headers to be allowed, got %q", w.Header().Get("Access-Control-Allow-Headers"))
+			}
+		})
+	}
+}
+
+func TestCORSUnconfigured(t *testing.T) {
+	srv, err := NewWithTheme(nil, theme.GetDefaultTheme(), &config.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/status", nil)
+	req.Header.Set("Origin", "https://dashboard.example.com")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	for _, header := range []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Credentials"} {
+		if got := w.Header().Get(header); got != "" {
+			t.Errorf("Expected no %s header, got %q", header, got)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// etagSeed distinguishes ETags from those of an earlier process, which may
+// have served different templates for the same registry and theme.
+var etagSeed = fmt.Sprint(time.Now().UnixNano())
+
+// pageETag returns the ETag for documentation responses. Pages are built
+// from the registry, the theme, the reload error banner, and the visitor's
+// color mode alone, so the tag changes exactly when one of them does. It is
+// weak because pages carry a per-request ID and may be compressed.
+func (s *Server) pageETag(r *http.Request) string {
+	colorMode := s.colorMode(r)
+	s.mu.RLock()
+	key := fmt.Sprintf("%s/%d/%d/%d/%s/%s", etagSeed, s.regVersion, s.themeVersion, s.errVersion, s.theme.Name, colorMode)
+	s.mu.RUnlock()
+
+	sum := sha256.Sum256([]byte(key))
+	return `W/"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// etag tags successful GET and HEAD responses with pageETag and answers
+// requests whose If-None-Match holds the current tag with 304 Not Modified,
+// without running the handler.
+func (s *Server) etag(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tag := s.pageETag(r)
+		if etagMatches(r.Header.Get("If-None-Match"), tag) {
+			w.Header().Set("ETag", tag)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		next.ServeHTTP(&etagWriter{ResponseWriter: w, etag: tag}, r)
+	})
+}
+
+// etagWriter sets the ETag header on 200 responses only, so errors are never
+// revalidated as if they were the page.
+type etagWriter struct {
+	http.ResponseWriter
+	etag   string
+	tagged bool
+}
+
+func (ew *etagWriter) WriteHeader(status int) {
+	ew.tag(status)
+	ew.ResponseWriter.WriteHeader(status)
+}
+
+func (ew *etagWriter) Write(p []byte) (int, error) {
+	// Leave the implicit 200 to the underlying writer, which sniffs the
+	// content type on the first write
+	ew.tag(http.StatusOK)
+	return ew.ResponseWriter.Write(p)
+}
+
+// tag sets the caching headers once, before the status is written.
+func (ew *etagWriter) tag(status int) {
+	if ew.tagged {
+		return
+	}
+	ew.tagged = true
+	if status == http.StatusOK {
+		ew.Header().Set("ETag", ew.etag)
+		// Revalidate on every use so reloads show up immediately
+		ew.Header().Set("Cache-Control", "no-cache")
+	}
+}
+
+// etagMatches reports whether an If-None-Match header matches tag, using the
+// weak comparison RFC 9110 requires for If-None-Match.
+func etagMatches(header, tag string) bool {
+	if header == "" {
+		return false
+	}
+	want := strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// staticETags returns a content-hash ETag for each file in fsys, keyed by
+// path. Embedded files have no modification time, so without these every
+// request would re-download them.
+func staticETags(fsys fs.FS) (map[string]string, error) {
+	tags := make(map[string]string)
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		tags[path] = `W/"` + hex.EncodeToString(sum[:8]) + `"`
+		return nil
+	})
+	return tags, err
+}
+
+// withStaticETags sets the ETag of static files before serving them;
+// http.FileServer then answers matching If-None-Match requests with 304.
+func withStaticETags(tags map[string]string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tag, ok := tags[strings.TrimPrefix(r.URL.Path, "/")]; ok {
+			w.Header().Set("ETag", tag)
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
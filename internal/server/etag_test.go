@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+	"github.com/bnprtr/reflect/internal/server/theme"
+)
+
+func TestETag(t *testing.T) {
+	reg, err := descriptor.LoadDirectory(context.Background(), filepath.Join("..", "descriptor", "testdata", "comprehensive"), []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	srv, err := New(reg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	get := func(path, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, path := range []string{"/", "/api/services", "/static/app.css"} {
+		t.Run(path, func(t *testing.T) {
+			first := get(path, "")
+			etag := first.Header().Get("ETag")
+			if first.Code != http.StatusOK || etag == "" {
+				t.Fatalf("Expected 200 with an ETag, got %d and %q", first.Code, etag)
+			}
+
+			second := get(path, etag)
+			if second.Code != http.StatusNotModified {
+				t.Errorf("Expected status 304, got %d", second.Code)
+			}
+			if second.Body.Len() != 0 {
+				t.Errorf("Expected an empty 304 body, got %d bytes", second.Body.Len())
+			}
+		})
+	}
+
+	t.Run("errors are not tagged", func(t *testing.T) {
+		w := get("/services/missing.v1.Service", "")
+		if w.Code != http.StatusNotFound || w.Header().Get("ETag") != "" {
+			t.Errorf("Expected an untagged 404, got %d with ETag %q", w.Code, w.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("changes on reload and theme change", func(t *testing.T) {
+		etag := get("/", "").Header().Get("ETag")
+
+		srv.SetRegistry(reg)
+		if w := get("/", etag); w.Code != http.StatusOK {
+			t.Errorf("Expected status 200 after a registry reload, got %d", w.Code)
+		}
+
+		etag = get("/", "").Header().Get("ETag")
+		srv.SetTheme(theme.GetDefaultTheme())
+		w := get("/", etag)
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200 after a theme change, got %d", w.Code)
+		}
+		if w.Header().Get("ETag") == etag {
+			t.Error("Expected a new ETag after a theme change")
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/bnprtr/reflect/internal/docs"
+)
+
+// ExportHTML renders the complete documentation (index, all services, and all types)
+// into a single self-contained HTML page with inlined CSS and in-page anchors.
+func (s *Server) ExportHTML(w io.Writer) error {
+	registry, _ := s.getRegistry()
+
+	index, err := s.buildIndex(registry)
+	if err != nil {
+		return fmt.Errorf("build index: %w", err)
+	}
+
+	var services []*docs.ServiceView
+	var messages []*docs.MessageView
+	var enums []*docs.EnumView
+
+	if registry != nil {
+		for _, summary := range index.Services {
+			serviceView, err := docs.BuildServiceView(registry, summary.FullName)
+			if err != nil {
+				return fmt.Errorf("build service %q: %w", summary.FullName, err)
+			}
+			if s.hideInternal() {
+				serviceView.HideInternalMethods()
+			}
+			services = append(services, serviceView)
+		}
+
+		for _, name := range sortedKeys(registry.MessagesByName) {
+			if s.isHidden(registry, name) {
+				continue
+			}
+			messageView, err := docs.BuildMessageView(registry, name)
+			if err != nil {
+				return fmt.Errorf("build message %q: %w", name, err)
+			}
+			messages = append(messages, messageView)
+		}
+
+		for _, name := range sortedKeys(registry.EnumsByName) {
+			if s.isHidden(registry, name) {
+				continue
+			}
+			enumView, err := docs.BuildEnumView(registry, name)
+			if err != nil {
+				return fmt.Errorf("build enum %q: %w", name, err)
+			}
+			enums = append(enums, enumView)
+		}
+	}
+
+	css, err := staticFS.ReadFile("static/app.css")
+	if err != nil {
+		return fmt.Errorf("read stylesheet: %w", err)
+	}
+
+	data := map[string]any{
+		"Title":     "Reflect",
+		"CSS":       string(css),
+		"ThemeVars": s.getTheme().ToCSSVariables(),
+		"Services":  services,
+		"Messages":  messages,
+		"Enums":     enums,
+	}
+
+	if err := s.templates.ExecuteTemplate(w, "export.html", data); err != nil {
+		return fmt.Errorf("render export: %w", err)
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
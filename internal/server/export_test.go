@@ -0,0 +1,100 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+	"golang.org/x/net/html"
+)
+
+func TestExportHTML(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	srv, err := New(reg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := srv.ExportHTML(&buf); err != nil {
+		t.Fatalf("ExportHTML() error = %v", err)
+	}
+	out := buf.String()
+
+	for name := range reg.ServicesByName {
+		if !strings.Contains(out, name) {
+			t.Errorf("Expected export to contain service %q", name)
+		}
+	}
+	for name := range reg.MessagesByName {
+		if !strings.Contains(out, `id="type-`+name+`"`) {
+			t.Errorf("Expected export to contain anchor for message %q", name)
+		}
+	}
+
+	// Many messages share field names, such as metadata, so field anchors
+	// are scoped by type
+	if !strings.Contains(out, `id="type-users.v1.User-field-full_name"`) {
+		t.Error("Expected export to contain field anchors")
+	}
+	if dup := duplicateIDs(out); len(dup) > 0 {
+		t.Errorf("Expected unique element IDs, got duplicates %v", dup)
+	}
+
+	if strings.Contains(out, `href="/static/`) || strings.Contains(out, "<script") {
+		t.Error("Expected export to be self-contained with no server dependencies")
+	}
+
+	if err := checkBalancedHTML(out); err != nil {
+		t.Errorf("Export is not valid HTML: %v", err)
+	}
+}
+
+// checkBalancedHTML tokenizes the document and verifies that every non-void
+// element is closed in order.
+func checkBalancedHTML(doc string) error {
+	voidElements := map[string]bool{
+		"meta": true, "link": true, "br": true, "hr": true, "img": true, "input": true,
+	}
+
+	if !strings.HasPrefix(doc, "<!doctype html>") {
+		return errors.New("missing doctype")
+	}
+
+	var stack []string
+	z := html.NewTokenizer(strings.NewReader(doc))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if z.Err() != io.EOF {
+				return z.Err()
+			}
+			if len(stack) != 0 {
+				return errors.New("unclosed elements: " + strings.Join(stack, ", "))
+			}
+			return nil
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			if !voidElements[string(name)] {
+				stack = append(stack, string(name))
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if len(stack) == 0 || stack[len(stack)-1] != string(name) {
+				return errors.New("unexpected closing tag: " + string(name))
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+}
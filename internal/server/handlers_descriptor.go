@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bnprtr/reflect/internal/docs"
+	"github.com/go-chi/chi/v5"
+)
+
+// Descriptor kinds reported by the /api/descriptor endpoint.
+const (
+	DescriptorKindService = "service"
+	DescriptorKindMethod  = "method"
+	DescriptorKindMessage = "message"
+	DescriptorKindEnum    = "enum"
+)
+
+// DescriptorResponse represents the JSON response for the /api/descriptor endpoint.
+type DescriptorResponse struct {
+	// Kind is one of "service", "method", "message", or "enum".
+	Kind string `json:"kind"`
+
+	// Descriptor is the docs view for the symbol: a docs.ServiceView,
+	// docs.MethodSummary, docs.MessageView, or docs.EnumView depending on Kind.
+	Descriptor any `json:"descriptor"`
+}
+
+// handleDescriptor handles GET /api/descriptor/{fullName} requests.
+// Method names use the "pkg.Service/Method" form. With ?rawComments=true,
+// comments are returned exactly as written in the proto source.
+func (s *Server) handleDescriptor(w http.ResponseWriter, r *http.Request) {
+	fullName := chi.URLParam(r, "*")
+	if fullName == "" {
+		s.writeJSONError(w, http.StatusBadRequest, "Descriptor name required")
+		return
+	}
+
+	registry, _ := s.getRegistry()
+	if registry == nil || s.isHidden(registry, fullName) {
+		s.writeJSONError(w, http.StatusNotFound, fmt.Sprintf("Descriptor not found: %s", fullName))
+		return
+	}
+	if r.URL.Query().Get("rawComments") == "true" {
+		registry = registry.WithRawComments()
+	}
+
+	var resp DescriptorResponse
+	var err error
+	switch {
+	case registry.ServicesByName[fullName] != nil:
+		var view *docs.ServiceView
+		view, err = s.serviceView(registry, fullName)
+		if err == nil && s.hideInternal() {
+			visible := *view
+			visible.HideInternalMethods()
+			view = &visible
+		}
+		resp = DescriptorResponse{Kind: DescriptorKindService, Descriptor: view}
+	case registry.MethodsByName[fullName] != nil:
+		var view *docs.MethodSummary
+		view, err = docs.BuildMethodView(registry, fullName)
+		if err == nil {
+			view.ServiceConfig = s.svcConfig.MethodConfig(fullName)
+		}
+		resp = DescriptorResponse{Kind: DescriptorKindMethod, Descriptor: view}
+	case registry.MessagesByName[fullName] != nil:
+		resp.Kind = DescriptorKindMessage
+		resp.Descriptor, err = s.messageView(registry, fullName)
+	case registry.EnumsByName[fullName] != nil:
+		resp.Kind = DescriptorKindEnum
+		resp.Descriptor, err = docs.BuildEnumView(registry, fullName)
+	default:
+		s.writeJSONError(w, http.StatusNotFound, fmt.Sprintf("Descriptor not found: %s", fullName))
+		return
+	}
+	if err != nil {
+		s.writeJSONError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to build descriptor view: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
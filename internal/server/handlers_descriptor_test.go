@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+)
+
+func TestHandleDescriptor(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	srv, err := New(reg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		fullName     string
+		expectedKind string
+		expectedKeys []string // keys expected in the descriptor object
+	}{
+		{
+			name:         "service",
+			fullName:     "users.v1.UserService",
+			expectedKind: DescriptorKindService,
+			expectedKeys: []string{"Name", "FullName", "Package", "Comment", "Methods"},
+		},
+		{
+			name:         "method",
+			fullName:     "users.v1.UserService/GetUser",
+			expectedKind: DescriptorKindMethod,
+			expectedKeys: []string{"Name", "FullName", "InputType", "OutputType", "ClientStreaming", "ServerStreaming", "ExampleRequest"},
+		},
+		{
+			name:         "message",
+			fullName:     "users.v1.User",
+			expectedKind: DescriptorKindMessage,
+			expectedKeys: []string{"Name", "FullName", "Package", "Fields", "ExampleJSON"},
+		},
+		{
+			name:         "enum",
+			fullName:     "users.v1.UserRole",
+			expectedKind: DescriptorKindEnum,
+			expectedKeys: []string{"Name", "FullName", "Package", "Values"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/descriptor/"+tt.fullName, nil)
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Expected Content-Type application/json, got %q", ct)
+			}
+
+			var resp struct {
+				Kind       string         `json:"kind"`
+				Descriptor map[string]any `json:"descriptor"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if resp.Kind != tt.expectedKind {
+				t.Errorf("Expected kind %q, got %q", tt.expectedKind, resp.Kind)
+			}
+			if resp.Descriptor["FullName"] != tt.fullName {
+				t.Errorf("Expected FullName %q, got %v", tt.fullName, resp.Descriptor["FullName"])
+			}
+			for _, key := range tt.expectedKeys {
+				if _, ok := resp.Descriptor[key]; !ok {
+					t.Errorf("Expected descriptor key %q, got %v", key, resp.Descriptor)
+				}
+			}
+		})
+	}
+
+	t.Run("raw comments", func(t *testing.T) {
+		for query, want := range map[string]string{
+			"":                  reg.CommentIndex["users.v1.User"],
+			"?rawComments=true": reg.RawCommentIndex["users.v1.User"],
+		} {
+			req := httptest.NewRequest("GET", "/api/descriptor/users.v1.User"+query, nil)
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+
+			var resp struct {
+				Descriptor struct {
+					Comment string
+				} `json:"descriptor"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.Descriptor.Comment != want {
+				t.Errorf("query %q: expected comment %q, got %q", query, want, resp.Descriptor.Comment)
+			}
+		}
+		if reg.CommentIndex["users.v1.User"] == reg.RawCommentIndex["users.v1.User"] {
+			t.Error("Expected raw and cleaned comments to differ")
+		}
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/descriptor/non.existent.Type", nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+		}
+
+		var resp struct {
+			Success bool `json:"success"`
+			Error   struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Expected JSON error body, got %q: %v", w.Body.String(), err)
+		}
+		if resp.Success || resp.Error.Code != http.StatusNotFound || resp.Error.Message == "" {
+			t.Errorf("Unexpected error response: %+v", resp)
+		}
+	})
+}
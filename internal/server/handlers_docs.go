@@ -1,6 +1,8 @@
 package server
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,18 +16,18 @@ import (
 
 // baseData returns common template data with theme configuration
 func (s *Server) baseData(r *http.Request) map[string]any {
-	// Check for theme parameter in URL
-	themeName := r.URL.Query().Get("theme")
-	if themeName == "" {
-		themeName = s.theme.Name
+	// Use the theme parameter in the URL if it names another built-in theme
+	themeConfig := s.getTheme()
+	if themeName := r.URL.Query().Get("theme"); themeName != "" && themeName != themeConfig.Name {
+		themeConfig = theme.GetThemeByName(themeName)
 	}
 
-	// Get theme by name (fallback to current theme if not found)
-	themeConfig := theme.GetThemeByName(themeName)
-
 	return map[string]any{
-		"ThemeVars": themeConfig.ToCSSVariables(),
-		"ThemeName": themeConfig.Name,
+		"ThemeVars":    themeConfig.ToCSSVariables(),
+		"ThemeName":    themeConfig.Name,
+		"ColorMode":    s.colorMode(r),
+		"RequestID":    requestID(r.Context()),
+		"ReloadErrors": s.getReloadErrors(),
 	}
 }
 
@@ -39,41 +41,112 @@ func (s *Server) mergeData(r *http.Request, data map[string]any) map[string]any
 }
 
 func (s *Server) routes() {
-	// Documentation routes
-	s.router.Get("/", s.handleHome())
-	s.router.Get("/services/{fullName}", s.handleServiceDetail())
-	s.router.Get("/methods/*", s.handleMethodDetail())
-	s.router.Get("/types/{fullName}", s.handleTypeDetail())
-	s.router.Get("/partial/types/*", s.handleTypePartial())
+	// Documentation routes; they only change with the registry and theme, so
+	// browsers can revalidate them by ETag
+	s.router.Group(func(r chi.Router) {
+		r.Use(s.etag)
+		r.Get("/", s.handleHome())
+		r.Get("/services/{fullName}", s.handleServiceDetail())
+		r.Get("/methods/*", s.handleMethodDetail())
+		r.Get("/types/{fullName}", s.handleTypeDetail())
+		r.Get("/partial/types/*", s.handleTypePartial())
+		r.Get("/partial/tryit/form/*", s.handleTryItFormPartial())
+
+		r.Get("/files/*", s.handleSourceFile)
+		r.Get("/source/*", s.handleSource)
+		r.Get("/sitemap.xml", s.handleSitemap)
+	})
+
+	// Health probes; plain text, outside the themed pages and the JSON API
+	s.router.Get("/healthz", s.handleHealthz)
+	s.router.Get("/readyz", s.handleReadyz)
+
+	// Build metadata for support and debugging
+	s.router.Get("/version", s.handleVersion)
+
+	// Prometheus metrics, when enabled
+	if s.metrics != nil {
+		s.router.Handle("/metrics", s.metrics.registry.Handler())
+	}
+
+	// JSON API routes; CORS applies only to these
+	s.router.Route("/api", func(r chi.Router) {
+		r.Use(s.cors)
 
-	// Theme API routes
-	s.router.Get("/api/themes", s.handleThemesList())
-	s.router.Get("/api/themes/current", s.handleCurrentTheme())
+		// Theme API
+		r.With(s.etag).Get("/themes", s.handleThemesList())
+		r.With(s.etag).Get("/themes/current", s.handleCurrentTheme())
+		r.With(s.etag).Get("/themes/current/contrast", s.handleCurrentThemeContrast())
 
-	// Example generation API
-	s.router.Post("/api/examples/generate", s.handleGenerateExample())
+		// Example generation API
+		r.Post("/examples/generate", s.handleGenerateExample())
+		r.Get("/examples/binary", s.handleGenerateBinaryExample())
 
-	// Search API
-	s.router.Get("/api/search", s.handleSearch())
+		// Descriptor API
+		r.With(s.etag).Get("/descriptor/*", s.handleDescriptor)
+
+		// Status API
+		r.Get("/status", s.handleStatus)
+
+		// Reload API, only with a token to authenticate callers
+		if s.config != nil && s.config.ReloadToken != "" {
+			r.Post("/reload", s.handleReload)
+		}
 
-	// Try It API routes
-	s.router.Post("/api/tryit/invoke", s.handleTryItInvoke)
+		// Service list API
+		r.With(s.etag).Get("/services", s.handleServices)
+
+		// Search API
+		r.With(s.etag).Get("/search", s.handleSearch())
+
+		// Try It API
+		r.Post("/tryit/invoke", s.handleTryItInvoke)
+		r.Post("/tryit/ping", s.handleTryItPing)
+		r.Post("/tryit/compare", s.handleTryItCompare)
+		r.Get("/environments", s.handleEnvironments)
+
+		// Preferences API
+		r.Get("/preferences", s.handleGetPreferences)
+		r.Post("/preferences", s.handleSetPreferences)
+		r.Post("/validate", s.handleValidate)
+		if s.reqTemplates != nil {
+			r.Get("/tryit/templates", s.handleListRequestTemplates)
+			r.Post("/tryit/templates", s.handleSaveRequestTemplate)
+			r.Get("/tryit/templates/{name}", s.handleGetRequestTemplate)
+		}
+	})
 }
 
 func (s *Server) handleHome() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		registry, _ := s.getRegistry()
-		index, err := docs.BuildIndex(registry)
+		index, err := s.buildIndex(registry)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Failed to build index: %v", err), http.StatusInternalServerError)
 			return
 		}
 
 		data := s.mergeData(r, map[string]any{
-			"Title":    "Reflect",
-			"Services": index.Services,
+			"Title":         "Reflect",
+			"Services":      index.Services,
+			"ServiceGroups": index.Groups,
+			"Packages":      index.Packages,
 		})
 
+		// ?page= and ?pageSize= list one page of services; groups are not
+		// paginated, so a paged index is a flat list
+		page, pageSize, paged, err := parsePageParams(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if paged {
+			servicePage := paginateServices(index.Services, page, pageSize)
+			data["Page"] = servicePage
+			data["PageServices"] = servicePage.summaries()
+			data["ServiceGroups"] = nil
+		}
+
 		err = s.templates.ExecuteTemplate(w, "home.html", data)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
@@ -91,24 +164,39 @@ func (s *Server) handleServiceDetail() http.HandlerFunc {
 		}
 
 		registry, _ := s.getRegistry()
-		serviceView, err := docs.BuildServiceView(registry, fullName)
+		if s.isHidden(registry, fullName) {
+			http.Error(w, fmt.Sprintf("Service not found: service %q not found", fullName), http.StatusNotFound)
+			return
+		}
+		cached, err := s.serviceView(registry, fullName)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Service not found: %v", err), http.StatusNotFound)
 			return
 		}
+		// ?hideInternal=true hides internal methods even when the config
+		// shows them
+		serviceView := *cached
+		hideInternal := s.hideInternal() || r.URL.Query().Get("hideInternal") == "true"
+		if hideInternal {
+			serviceView.HideInternalMethods()
+		}
 
 		// Get all services for sidebar navigation
-		index, err := docs.BuildIndex(registry)
+		index, err := s.buildIndex(registry)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Failed to build index: %v", err), http.StatusInternalServerError)
 			return
 		}
 
 		data := s.mergeData(r, map[string]any{
-			"Title":          fmt.Sprintf("Service: %s", serviceView.Name),
-			"Service":        serviceView,
-			"Services":       index.Services,
-			"CurrentService": serviceView.FullName,
+			"Title":           fmt.Sprintf("Service: %s", serviceView.Name),
+			"Service":         &serviceView,
+			"Services":        index.Services,
+			"Packages":        index.Packages,
+			"CurrentService":  serviceView.FullName,
+			"CurrentPackage":  serviceView.Package,
+			"HideInternal":    hideInternal,
+			"CanShowInternal": !s.hideInternal(),
 		})
 		err = s.templates.ExecuteTemplate(w, "service_detail.html", data)
 		if err != nil {
@@ -127,11 +215,24 @@ func (s *Server) handleMethodDetail() http.HandlerFunc {
 		}
 
 		registry, _ := s.getRegistry()
+		if s.isHidden(registry, fullName) {
+			http.Error(w, fmt.Sprintf("Method not found: method %q not found", fullName), http.StatusNotFound)
+			return
+		}
 		methodView, err := docs.BuildMethodView(registry, fullName)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Method not found: %v", err), http.StatusNotFound)
 			return
 		}
+		methodView.ServiceConfig = s.svcConfig.MethodConfig(fullName)
+
+		// Address the grpcurl and Connect curl examples to the first
+		// environment hosting the method
+		environments := s.environmentsForMethod(fullName)
+		if len(environments) > 0 {
+			methodView.Examples.Grpcurl = docs.GrpcurlExample(methodView, grpcurlTarget(environments[0], s.config.GlobalDefaultHeaders))
+			methodView.Examples.ConnectCurl = docs.ConnectCurlExample(methodView, environments[0].BaseURL)
+		}
 
 		// Extract service name from method full name
 		parts := strings.Split(fullName, "/")
@@ -139,9 +240,13 @@ func (s *Server) handleMethodDetail() http.HandlerFunc {
 		if len(parts) >= 2 {
 			serviceName = parts[0]
 		}
+		servicePackage := ""
+		if service, ok := registry.FindService(serviceName); ok {
+			servicePackage = string(service.ParentFile().Package())
+		}
 
 		// Get all services for sidebar navigation
-		index, err := docs.BuildIndex(registry)
+		index, err := s.buildIndex(registry)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Failed to build index: %v", err), http.StatusInternalServerError)
 			return
@@ -152,8 +257,11 @@ func (s *Server) handleMethodDetail() http.HandlerFunc {
 			"Method":         methodView,
 			"ServiceName":    serviceName,
 			"Services":       index.Services,
+			"Packages":       index.Packages,
 			"CurrentService": serviceName,
+			"CurrentPackage": servicePackage,
 			"Config":         s.config,
+			"Environments":   environments,
 		})
 		err = s.templates.ExecuteTemplate(w, "method_detail.html", data)
 		if err != nil {
@@ -172,21 +280,28 @@ func (s *Server) handleTypeDetail() http.HandlerFunc {
 		}
 
 		registry, _ := s.getRegistry()
+		if s.isHidden(registry, fullName) {
+			http.Error(w, fmt.Sprintf("Type not found: %s", fullName), http.StatusNotFound)
+			return
+		}
 
 		// Get all services for sidebar navigation
-		index, err := docs.BuildIndex(registry)
+		index, err := s.buildIndex(registry)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Failed to build index: %v", err), http.StatusInternalServerError)
 			return
 		}
 
 		// Try to find as message first, then as enum
-		messageView, err := docs.BuildMessageView(registry, fullName)
+		messageView, err := s.messageView(registry, fullName)
 		if err == nil {
 			data := s.mergeData(r, map[string]any{
-				"Title":    fmt.Sprintf("Message: %s", messageView.Name),
-				"Message":  messageView,
-				"Services": index.Services,
+				"Title":          fmt.Sprintf("Message: %s", messageView.Name),
+				"Message":        messageView,
+				"Services":       index.Services,
+				"Packages":       index.Packages,
+				"CurrentType":    messageView.FullName,
+				"CurrentPackage": messageView.Package,
 			})
 			_ = s.templates.ExecuteTemplate(w, "type_detail.html", data)
 			return
@@ -194,10 +309,17 @@ func (s *Server) handleTypeDetail() http.HandlerFunc {
 
 		enumView, err := docs.BuildEnumView(registry, fullName)
 		if err == nil {
+			// Values are listed by number unless ?sort=name is given
+			if by := r.URL.Query().Get("sort"); by != "" {
+				enumView.SortValues(by)
+			}
 			data := s.mergeData(r, map[string]any{
-				"Title":    fmt.Sprintf("Enum: %s", enumView.Name),
-				"Enum":     enumView,
-				"Services": index.Services,
+				"Title":          fmt.Sprintf("Enum: %s", enumView.Name),
+				"Enum":           enumView,
+				"Services":       index.Services,
+				"Packages":       index.Packages,
+				"CurrentType":    enumView.FullName,
+				"CurrentPackage": enumView.Package,
 			})
 			_ = s.templates.ExecuteTemplate(w, "type_detail.html", data)
 			return
@@ -216,9 +338,13 @@ func (s *Server) handleTypePartial() http.HandlerFunc {
 		}
 
 		registry, _ := s.getRegistry()
+		if s.isHidden(registry, fullName) {
+			http.Error(w, fmt.Sprintf("Type not found: %s", fullName), http.StatusNotFound)
+			return
+		}
 
 		// Try to find as message first, then as enum
-		messageView, err := docs.BuildMessageView(registry, fullName)
+		messageView, err := s.messageView(registry, fullName)
 		if err == nil {
 			data := map[string]any{
 				"Message": messageView,
@@ -244,6 +370,35 @@ func (s *Server) handleTypePartial() http.HandlerFunc {
 	}
 }
 
+// handleTryItFormPartial renders the form for entering a method's request
+// message, used by the Try It panel in place of the JSON editor.
+func (s *Server) handleTryItFormPartial() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fullName := chi.URLParam(r, "*")
+		if fullName == "" {
+			http.Error(w, "Method name required", http.StatusBadRequest)
+			return
+		}
+
+		registry, _ := s.getRegistry()
+		if registry == nil || s.isHidden(registry, fullName) {
+			http.Error(w, fmt.Sprintf("Method not found: %s", fullName), http.StatusNotFound)
+			return
+		}
+
+		method, exists := registry.FindMethod(fullName)
+		if !exists {
+			http.Error(w, fmt.Sprintf("Method not found: %s", fullName), http.StatusNotFound)
+			return
+		}
+
+		if err := s.templates.ExecuteTemplate(w, "tryit_body_form.html", docs.BuildInputForm(method.Input())); err != nil {
+			http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
 // GenerateExampleRequest represents the request body for example generation.
 type GenerateExampleRequest struct {
 	MessageType string                    `json:"messageType"`
@@ -251,13 +406,22 @@ type GenerateExampleRequest struct {
 }
 
 // GenerateExampleResponse represents the response for example generation.
+// ExampleJSON is set by default; with format=textproto, ExampleTextProto is
+// set instead.
 type GenerateExampleResponse struct {
-	ExampleJSON string `json:"exampleJson"`
-	Error       string `json:"error,omitempty"`
+	ExampleJSON      string `json:"exampleJson,omitempty"`
+	ExampleTextProto string `json:"exampleTextProto,omitempty"`
+	Error            string `json:"error,omitempty"`
 }
 
 func (s *Server) handleGenerateExample() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("format")
+		if format != "" && format != "json" && format != "textproto" {
+			http.Error(w, fmt.Sprintf("Unsupported format %q, must be json or textproto", format), http.StatusBadRequest)
+			return
+		}
+
 		var req GenerateExampleRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
@@ -278,16 +442,73 @@ func (s *Server) handleGenerateExample() http.HandlerFunc {
 			return
 		}
 
-		// Generate example JSON
-		exampleJSON, err := descriptor.GenerateExampleJSON(msg, req.Options)
+		// Generate the example, honoring the configured Any type hints and
+		// example option
+		defaults := registry.ExampleOptions()
+		req.Options.AnyTypes = defaults.AnyTypes
+		req.Options.ExampleOption = defaults.ExampleOption
+		var response GenerateExampleResponse
+		var err error
+		if format == "textproto" {
+			response.ExampleTextProto, err = descriptor.GenerateExampleTextProto(msg, req.Options)
+		} else {
+			response.ExampleJSON, err = descriptor.GenerateExampleJSON(msg, req.Options)
+		}
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Failed to generate example: %v", err), http.StatusInternalServerError)
 			return
 		}
 
-		// Return response
-		response := GenerateExampleResponse{
-			ExampleJSON: exampleJSON,
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// GenerateBinaryExampleResponse represents the response for binary example generation.
+type GenerateBinaryExampleResponse struct {
+	MessageType string `json:"messageType"`
+	Size        int    `json:"size"`
+	Hex         string `json:"hex"`
+	Base64      string `json:"base64"`
+}
+
+func (s *Server) handleGenerateBinaryExample() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		messageType := r.URL.Query().Get("messageType")
+		if messageType == "" {
+			http.Error(w, "messageType is required", http.StatusBadRequest)
+			return
+		}
+
+		registry, _ := s.getRegistry()
+		if registry == nil {
+			http.Error(w, "No protobuf descriptors loaded", http.StatusServiceUnavailable)
+			return
+		}
+
+		// Find the message in the registry
+		msg, exists := registry.FindMessage(messageType)
+		if !exists {
+			http.Error(w, fmt.Sprintf("Message type %s not found", messageType), http.StatusNotFound)
+			return
+		}
+
+		// Generate example wire bytes with the same options as the JSON
+		// examples on the docs pages
+		data, err := descriptor.GenerateExampleBinary(msg, registry.ExampleOptions())
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to generate example: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		response := GenerateBinaryExampleResponse{
+			MessageType: messageType,
+			Size:        len(data),
+			Hex:         hex.EncodeToString(data),
+			Base64:      base64.StdEncoding.EncodeToString(data),
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -308,8 +529,15 @@ func (s *Server) handleSearch() http.HandlerFunc {
 			return
 		}
 
+		// Results can be filtered by kind here or inline, as in "kind:service echo"
+		kind := r.URL.Query().Get("kind")
+		if kind != "" && !docs.ValidSearchKind(kind) {
+			http.Error(w, fmt.Sprintf("invalid kind %q, must be one of: service, method, message, enum, field, enum_value", kind), http.StatusBadRequest)
+			return
+		}
+
 		_, searchIndex := s.getRegistry()
-		results := searchIndex.Search(query)
+		results := searchIndex.SearchKind(query, kind)
 
 		// Set content type for HTMX
 		w.Header().Set("Content-Type", "text/html")
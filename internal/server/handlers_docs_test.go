@@ -2,13 +2,19 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/bnprtr/reflect/internal/config"
 	"github.com/bnprtr/reflect/internal/descriptor"
+	"github.com/bnprtr/reflect/internal/server/theme"
 )
 
 func TestDocHandlers(t *testing.T) {
@@ -83,6 +89,19 @@ func TestDocHandlers(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedText:   []string{"EchoRequest", "message", "count"},
 		},
+		{
+			name:           "binary example",
+			method:         "GET",
+			path:           "/api/examples/binary?messageType=echo.v1.EchoRequest",
+			expectedStatus: http.StatusOK,
+			expectedText:   []string{`"messageType":"echo.v1.EchoRequest"`, `"hex":"0a0f6578616d706c655f6d657373616765102a"`},
+		},
+		{
+			name:           "binary example unknown type",
+			method:         "GET",
+			path:           "/api/examples/binary?messageType=non.existent.Type",
+			expectedStatus: http.StatusNotFound,
+		},
 		{
 			name:           "non-existent service",
 			method:         "GET",
@@ -171,3 +190,591 @@ func TestDocHandlersWithNilRegistry(t *testing.T) {
 		})
 	}
 }
+
+func TestDocHandlersHideInternal(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "visibility")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	tests := []struct {
+		path         string
+		hiddenStatus int
+	}{
+		{"/services/visibility.v1.AdminService", http.StatusNotFound},
+		{"/services/visibility.internal.v1.OpsService", http.StatusNotFound},
+		{"/methods/visibility.v1.PublicService/Debug", http.StatusNotFound},
+		{"/types/visibility.v1.DebugState", http.StatusNotFound},
+		{"/partial/types/visibility.v1.DebugState", http.StatusNotFound},
+		{"/services/visibility.v1.PublicService", http.StatusOK},
+		{"/methods/visibility.v1.PublicService/Ping", http.StatusOK},
+	}
+
+	for _, hide := range []bool{true, false} {
+		srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), &config.Config{HideInternal: hide})
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("hide=%v %s", hide, tt.path), func(t *testing.T) {
+				req := httptest.NewRequest("GET", tt.path, nil)
+				w := httptest.NewRecorder()
+				srv.ServeHTTP(w, req)
+
+				want := http.StatusOK
+				if hide {
+					want = tt.hiddenStatus
+				}
+				if w.Code != want {
+					t.Errorf("Expected status %d, got %d", want, w.Code)
+				}
+			})
+		}
+
+		t.Run(fmt.Sprintf("hide=%v index and search", hide), func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+			if got := strings.Contains(w.Body.String(), "AdminService"); got == hide {
+				t.Errorf("Expected AdminService on index = %v", !hide)
+			}
+
+			req = httptest.NewRequest("GET", "/api/search?q=AdminService", nil)
+			w = httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+			if got := strings.Contains(w.Body.String(), "visibility.v1.AdminService"); got == hide {
+				t.Errorf("Expected AdminService in search = %v", !hide)
+			}
+
+			req = httptest.NewRequest("GET", "/services/visibility.v1.PublicService", nil)
+			w = httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+			if got := strings.Contains(w.Body.String(), "/methods/visibility.v1.PublicService/Debug"); got == hide {
+				t.Errorf("Expected Debug method on service page = %v", !hide)
+			}
+		})
+	}
+}
+
+func TestMethodDetailServiceConfig(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	serviceConfigPath := filepath.Join(t.TempDir(), "service_config.json")
+	serviceConfig := `{
+  "methodConfig": [{
+    "name": [{"service": "users.v1.UserService", "method": "GetUser"}],
+    "timeout": "1.5s",
+    "retryPolicy": {
+      "maxAttempts": 3,
+      "initialBackoff": "0.1s",
+      "maxBackoff": "1s",
+      "backoffMultiplier": 2,
+      "retryableStatusCodes": ["UNAVAILABLE"]
+    }
+  }]
+}`
+	if err := os.WriteFile(serviceConfigPath, []byte(serviceConfig), 0o644); err != nil {
+		t.Fatalf("Failed to write service config: %v", err)
+	}
+
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), &config.Config{ServiceConfig: serviceConfigPath})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/methods/users.v1.UserService/GetUser", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{"Service Config", "1.5s", "UNAVAILABLE"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected method page to contain %q", want)
+		}
+	}
+
+	req = httptest.NewRequest("GET", "/methods/users.v1.UserService/CreateUser", nil)
+	w = httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), "Service Config") {
+		t.Error("Expected no service config for a method without a policy")
+	}
+}
+
+func TestMethodDetailGrpcurlExample(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	cfg := &config.Config{
+		Environments: []config.Environment{
+			{
+				Name:      "staging",
+				BaseURL:   "https://users.staging.example.com",
+				Transport: "grpc",
+				DefaultHeaders: map[string]string{
+					"X-Tenant":      "acme",
+					"Authorization": "Bearer secret",
+				},
+			},
+		},
+	}
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/methods/users.v1.UserService/GetUser", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{"-H 'X-Tenant: acme'", "users.staging.example.com:443 users.v1.UserService/GetUser"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected grpcurl example to contain %q", want)
+		}
+	}
+	if strings.Contains(body, "Bearer secret") {
+		t.Error("Expected sensitive default headers to be left out of the grpcurl example")
+	}
+}
+
+func TestMethodDetailConnectCurlExample(t *testing.T) {
+	reg, err := descriptor.LoadDirectory(context.Background(), filepath.Join("..", "descriptor", "testdata", "comprehensive"), []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	cfg := &config.Config{
+		Environments: []config.Environment{
+			{Name: "staging", BaseURL: "https://users.staging.example.com", Transport: "connect"},
+		},
+	}
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/methods/users.v1.UserService/GetUser", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, "https://users.staging.example.com/users.v1.UserService/GetUser") {
+		t.Error("Expected Connect curl example to use the environment's base URL")
+	}
+	if strings.Contains(body, "localhost:8080") {
+		t.Error("Expected no localhost placeholder in the method page")
+	}
+}
+
+func TestTypeDetailRendersMarkdownComments(t *testing.T) {
+	reg, err := descriptor.LoadDirectory(context.Background(), filepath.Join("..", "descriptor", "testdata", "comments"), []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     *config.Config
+		want    string
+		notWant string
+	}{
+		{
+			name:    "markdown",
+			cfg:     nil,
+			want:    `Made by <a href="https://acme.example.com">Acme</a>, &lt;b&gt;not&lt;/b&gt; others.`,
+			notWant: "<b>not</b>",
+		},
+		{
+			name:    "plain comments",
+			cfg:     &config.Config{PlainComments: true},
+			want:    "Made by [Acme](https://acme.example.com), &lt;b&gt;not&lt;/b&gt; others.",
+			notWant: "<b>not</b>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), tt.cfg)
+			if err != nil {
+				t.Fatalf("Failed to create server: %v", err)
+			}
+
+			req := httptest.NewRequest("GET", "/types/comments.v1.Paint", nil)
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.want) {
+				t.Errorf("Expected page to contain %q", tt.want)
+			}
+			if strings.Contains(body, tt.notWant) {
+				t.Errorf("Expected page not to contain %q", tt.notWant)
+			}
+		})
+	}
+}
+
+func TestHomeServiceGroups(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	cfg := &config.Config{ServiceGroups: map[string][]string{"Commerce": {"orders.v1.*"}}}
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+
+	body := w.Body.String()
+	commerce := strings.Index(body, `<h2 class="heading-2">Commerce</h2>`)
+	other := strings.Index(body, `<h2 class="heading-2">Other</h2>`)
+	if commerce < 0 || other < 0 {
+		t.Fatalf("Expected Commerce and Other sections on the home page")
+	}
+	if !strings.Contains(body[commerce:other], "orders.v1.OrderService") {
+		t.Errorf("Expected OrderService in the Commerce section")
+	}
+	if !strings.Contains(body[other:], "users.v1.UserService") {
+		t.Errorf("Expected UserService in the Other section")
+	}
+}
+
+func TestSidebarPackageTree(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), &config.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/types/users.v1.User", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	body := w.Body.String()
+
+	// The current type's package is expanded and the type marked active
+	open := strings.Index(body, "<details open>")
+	if open < 0 || !strings.Contains(body[open:open+strings.Index(body[open:], "</summary>")], ">users.v1") {
+		t.Errorf("Expected the users.v1 package to be expanded")
+	}
+	if strings.Count(body, "<details open>") != 1 {
+		t.Errorf("Expected only one expanded package")
+	}
+	if !strings.Contains(body, `<a href="/types/users.v1.User" class="active">User</a>`) {
+		t.Errorf("Expected User marked active in the sidebar")
+	}
+	if !strings.Contains(body, `<a href="/types/orders.v1.OrderStatus" class="">OrderStatus</a>`) {
+		t.Errorf("Expected OrderStatus in the sidebar")
+	}
+}
+
+func TestTypeDetailEnumTable(t *testing.T) {
+	reg, err := descriptor.LoadDirectory(context.Background(), filepath.Join("..", "descriptor", "testdata", "comprehensive"), []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	srv, err := New(reg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	get := func(path string) string {
+		t.Helper()
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d for %s, got %d", http.StatusOK, path, w.Code)
+		}
+		return w.Body.String()
+	}
+
+	values := []struct {
+		name   string
+		number int
+	}{
+		{"STATUS_UNSPECIFIED", 0},
+		{"STATUS_ACTIVE", 1},
+		{"STATUS_INACTIVE", 2},
+		{"STATUS_PENDING", 3},
+		{"STATUS_DELETED", 4},
+	}
+
+	body := get("/types/common.v1.Status")
+	for _, value := range values {
+		row := regexp.MustCompile(fmt.Sprintf(`<tr id="value-%s"[^>]*>\s*<td[^>]*>%s</td>\s*<td[^>]*>%d</td>`, value.name, value.name, value.number))
+		if !row.MatchString(body) {
+			t.Errorf("Expected a table row for %s = %d", value.name, value.number)
+		}
+	}
+	if strings.Index(body, `id="value-STATUS_UNSPECIFIED"`) > strings.Index(body, `id="value-STATUS_ACTIVE"`) {
+		t.Error("Expected values sorted by number by default")
+	}
+
+	body = get("/types/common.v1.Status?sort=name")
+	if strings.Index(body, `id="value-STATUS_ACTIVE"`) > strings.Index(body, `id="value-STATUS_DELETED"`) ||
+		strings.Index(body, `id="value-STATUS_PENDING"`) > strings.Index(body, `id="value-STATUS_UNSPECIFIED"`) {
+		t.Error("Expected values sorted by name with ?sort=name")
+	}
+}
+
+func TestServiceDetailTagsAndHideInternalQuery(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "tags")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	srv, err := New(reg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	tests := []struct {
+		path         string
+		wantInternal bool
+		wantToggle   string
+	}{
+		{"/services/tags.v1.AccountService", true, "?hideInternal=true"},
+		{"/services/tags.v1.AccountService?hideInternal=true", false, "Show internal methods"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+
+			body := w.Body.String()
+			for _, text := range []string{"accounts", "billing", "/methods/tags.v1.AccountService/ChargeAccount", tt.wantToggle} {
+				if !strings.Contains(body, text) {
+					t.Errorf("Expected body to contain %q", text)
+				}
+			}
+			if got := strings.Contains(body, "/methods/tags.v1.AccountService/Reindex"); got != tt.wantInternal {
+				t.Errorf("Expected Reindex on service page = %v", tt.wantInternal)
+			}
+		})
+	}
+}
+
+func TestTypeDetailCustomOptions(t *testing.T) {
+	reg, err := descriptor.LoadDirectory(context.Background(), filepath.Join("..", "descriptor", "testdata", "options"), nil)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	srv, err := New(reg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/types/options.v1.Document", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{
+		`id="custom-options"`,
+		`(options.v1.resource) = &#34;documents/{id}&#34;`,
+		`(google.api.field_behavior) = [OUTPUT_ONLY, IMMUTABLE]`,
+		`(options.v1.sensitivity) = &#34;pii&#34;`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected type page to contain %q", want)
+		}
+	}
+
+	// Types without custom options have no options section
+	req = httptest.NewRequest("GET", "/types/options.v1.Rules", nil)
+	w = httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), `id="custom-options"`) {
+		t.Error("Expected no custom options section for a type without options")
+	}
+}
+
+func TestSearchKindParam(t *testing.T) {
+	reg, err := descriptor.LoadDirectory(context.Background(), filepath.Join("..", "descriptor", "testdata", "comprehensive"), []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	srv, err := New(reg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	search := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/search?"+query, nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, query := range []string{"q=user&kind=service", "q=" + url.QueryEscape("kind:service user")} {
+		w := search(query)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %s, got %d", query, w.Code)
+		}
+		body := w.Body.String()
+		if !strings.Contains(body, "/services/users.v1.UserService") {
+			t.Errorf("Expected UserService in results for %s", query)
+		}
+		if strings.Contains(body, "/methods/") || strings.Contains(body, "/types/") {
+			t.Errorf("Expected only services in results for %s", query)
+		}
+	}
+
+	if w := search("q=user&kind=widget"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown kind, got %d", w.Code)
+	}
+}
+
+func TestTypeDetailAnchors(t *testing.T) {
+	reg, err := descriptor.LoadDirectory(context.Background(), filepath.Join("..", "descriptor", "testdata", "comprehensive"), []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	srv, err := New(reg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		anchor string
+	}{
+		{"/types/users.v1.User", "field-full_name"},
+		{"/types/common.v1.Status", "value-STATUS_ACTIVE"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d for %s, got %d", http.StatusOK, tt.path, w.Code)
+		}
+
+		body := w.Body.String()
+		if !strings.Contains(body, `id="`+tt.anchor+`"`) {
+			t.Errorf("Expected %s to contain anchor %q", tt.path, tt.anchor)
+		}
+		if dup := duplicateIDs(body); len(dup) > 0 {
+			t.Errorf("Expected unique element IDs on %s, got duplicates %v", tt.path, dup)
+		}
+	}
+
+	// Search links to the anchors
+	req := httptest.NewRequest("GET", "/api/search?q=full_name&kind=field", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if !strings.Contains(w.Body.String(), `href="/types/users.v1.User#field-full_name"`) {
+		t.Error("Expected search results to link to the field anchor")
+	}
+}
+
+// duplicateIDs returns the element IDs that appear more than once in an HTML
+// document.
+func duplicateIDs(doc string) []string {
+	seen := make(map[string]bool)
+	var dup []string
+	for _, match := range regexp.MustCompile(` id="([^"]+)"`).FindAllStringSubmatch(doc, -1) {
+		if seen[match[1]] {
+			dup = append(dup, match[1])
+		}
+		seen[match[1]] = true
+	}
+	return dup
+}
+
+func TestGenerateExampleFormats(t *testing.T) {
+	reg, err := descriptor.LoadDirectory(context.Background(), filepath.Join("..", "descriptor", "testdata", "basic"), []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	srv, err := New(reg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedText   []string
+	}{
+		{
+			name:           "json by default",
+			query:          "",
+			expectedStatus: http.StatusOK,
+			expectedText:   []string{`"exampleJson"`, `\"message\": \"example_message\"`},
+		},
+		{
+			name:           "textproto",
+			query:          "?format=textproto",
+			expectedStatus: http.StatusOK,
+			expectedText:   []string{`"exampleTextProto":"message: \"example_message\"\ncount: 42\n"`},
+		},
+		{
+			name:           "unsupported format",
+			query:          "?format=yaml",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := strings.NewReader(`{"messageType": "echo.v1.EchoRequest"}`)
+			req := httptest.NewRequest(http.MethodPost, "/api/examples/generate"+tt.query, body)
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			for _, text := range tt.expectedText {
+				if !strings.Contains(w.Body.String(), text) {
+					t.Errorf("Expected body to contain %q, got: %s", text, w.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bnprtr/reflect/internal/version"
+)
+
+// StatusResponse represents the JSON response for the /api/status endpoint.
+type StatusResponse struct {
+	// Status is "ok" when the server is running.
+	Status string `json:"status"`
+
+	// Services is the number of loaded services.
+	Services int `json:"services"`
+
+	// Environments is the number of configured environments.
+	Environments int `json:"environments"`
+
+	// Warnings lists non-fatal configuration problems.
+	Warnings []string `json:"warnings"`
+}
+
+// handleStatus handles GET /api/status requests.
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	resp := StatusResponse{
+		Status:   "ok",
+		Warnings: []string{},
+	}
+
+	if registry, _ := s.getRegistry(); registry != nil {
+		resp.Services = len(registry.ServicesByName)
+	}
+
+	if s.config != nil {
+		resp.Environments = len(s.config.Environments)
+		if warnings := s.config.Warnings(); len(warnings) > 0 {
+			resp.Warnings = warnings
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleVersion handles GET /version requests with the build metadata.
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(version.Get()); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleHealthz handles GET /healthz liveness probes. It succeeds whenever the
+// server is able to respond.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+// handleReadyz handles GET /readyz readiness probes. It succeeds only while a
+// registry is loaded, so it tracks reloads in dev mode.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if registry, _ := s.getRegistry(); registry == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, "registry not loaded")
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/descriptor"
+	"github.com/bnprtr/reflect/internal/server/theme"
+)
+
+func TestHandleStatus(t *testing.T) {
+	cfg := &config.Config{
+		Environments: []config.Environment{
+			{Name: "dev", BaseURL: "https://dev.example.com", TLS: config.TLSConfig{InsecureSkipVerify: true}},
+			{Name: "prod", BaseURL: "https://api.example.com", TLS: config.TLSConfig{InsecureSkipVerify: true}},
+		},
+	}
+
+	srv, err := NewWithTheme(nil, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/status", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp StatusResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if resp.Status != "ok" {
+		t.Errorf("Expected status ok, got %q", resp.Status)
+	}
+	if resp.Environments != 2 {
+		t.Errorf("Expected 2 environments, got %d", resp.Environments)
+	}
+	if len(resp.Warnings) != 1 {
+		t.Errorf("Expected 1 warning for the insecure prod environment, got %v", resp.Warnings)
+	}
+}
+
+func TestHealthAndReadiness(t *testing.T) {
+	reg, err := descriptor.LoadDirectory(context.Background(), filepath.Join("..", "descriptor", "testdata", "basic"), []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	probe := func(t *testing.T, srv *Server, path string, wantCode int) {
+		t.Helper()
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != wantCode {
+			t.Errorf("%s: expected status %d, got %d", path, wantCode, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+			t.Errorf("%s: expected text/plain content type, got %q", path, ct)
+		}
+	}
+
+	t.Run("loaded registry", func(t *testing.T) {
+		srv, err := New(reg)
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+		probe(t, srv, "/healthz", http.StatusOK)
+		probe(t, srv, "/readyz", http.StatusOK)
+	})
+
+	t.Run("nil registry", func(t *testing.T) {
+		srv, err := New(nil)
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+		probe(t, srv, "/healthz", http.StatusOK)
+		probe(t, srv, "/readyz", http.StatusServiceUnavailable)
+
+		// Readiness follows registry reloads
+		srv.SetRegistry(reg)
+		probe(t, srv, "/readyz", http.StatusOK)
+		srv.SetRegistry(nil)
+		probe(t, srv, "/readyz", http.StatusServiceUnavailable)
+	})
+}
+
+func TestHandleVersion(t *testing.T) {
+	srv, err := New(nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/version", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", ct)
+	}
+
+	var resp map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	// Test binaries are not built with -ldflags, so the version is usually "dev"
+	if resp["version"] == "" {
+		t.Errorf("Expected a version field, got %v", resp)
+	}
+	if !strings.HasPrefix(resp["goVersion"], "go") {
+		t.Errorf("Expected a Go version, got %q", resp["goVersion"])
+	}
+}
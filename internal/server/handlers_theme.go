@@ -25,10 +25,31 @@ func (s *Server) handleThemesList() http.HandlerFunc {
 // handleCurrentTheme returns the currently active theme
 func (s *Server) handleCurrentTheme() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		current := s.getTheme()
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(map[string]any{
-			"name":   s.theme.Name,
-			"colors": s.theme.Colors,
+			"name":   current.Name,
+			"colors": current.Colors,
+		}); err != nil {
+			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// handleCurrentThemeContrast reports WCAG contrast issues in the active theme
+func (s *Server) handleCurrentThemeContrast() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		current := s.getTheme()
+		issues := current.ContrastReport()
+		if issues == nil {
+			issues = []theme.ContrastIssue{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]any{
+			"name":   current.Name,
+			"issues": issues,
 		}); err != nil {
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 			return
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/server/theme"
+)
+
+func TestSetTheme(t *testing.T) {
+	srv, err := New(nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	custom := theme.GetDefaultTheme()
+	custom.Name = "custom"
+	custom.Colors.Light.Primary = "#123456"
+	srv.SetTheme(custom)
+
+	t.Run("current theme API", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/themes/current", nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		var resp struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if resp.Name != "custom" {
+			t.Errorf("Expected current theme %q, got %q", "custom", resp.Name)
+		}
+	})
+
+	t.Run("pages render the new theme", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "--color-primary-light: #123456") {
+			t.Error("Expected page to use the custom theme's colors")
+		}
+	})
+}
+
+func TestCurrentThemeContrast(t *testing.T) {
+	srv, err := New(nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	bad := theme.GetDefaultTheme()
+	bad.Name = "washed-out"
+	bad.Colors.Light.Text = "#cccccc"
+	srv.SetTheme(bad)
+
+	req := httptest.NewRequest("GET", "/api/themes/current/contrast", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp struct {
+		Name   string                `json:"name"`
+		Issues []theme.ContrastIssue `json:"issues"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Name != "washed-out" {
+		t.Errorf("Expected theme %q, got %q", "washed-out", resp.Name)
+	}
+	if len(resp.Issues) != 1 || resp.Issues[0].ForegroundColor != "#cccccc" {
+		t.Errorf("Expected one issue for the light text color, got %+v", resp.Issues)
+	}
+}
@@ -2,12 +2,20 @@ package server
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/docs"
 	"github.com/bnprtr/reflect/internal/tryit"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // TryItRequest represents the JSON request body for the /api/tryit/invoke endpoint.
@@ -46,11 +54,17 @@ type TryItResponse struct {
 	// Body is the response body as JSON.
 	Body string `json:"body,omitempty"`
 
+	// Compact indicates the body was too large to pretty-print.
+	Compact bool `json:"compact,omitempty"`
+
 	// Latency is the request duration in milliseconds.
 	LatencyMs int64 `json:"latencyMs"`
 
 	// Error contains error details if the invocation failed.
 	Error *TryItError `json:"error,omitempty"`
+
+	// RequestID identifies the request in the server logs.
+	RequestID string `json:"requestId,omitempty"`
 }
 
 // TryItError represents error details in the Try It response.
@@ -65,18 +79,92 @@ type TryItError struct {
 	Details []string `json:"details,omitempty"`
 }
 
-// handleTryItInvoke handles POST /api/tryit/invoke requests.
+// DryRunResponse represents the JSON response for /api/tryit/invoke when the
+// dryRun parameter is set. It describes the request that would have been sent.
+type DryRunResponse struct {
+	// Transport is the RPC transport the request was built for.
+	Transport string `json:"transport"`
+
+	// Method is the HTTP method.
+	Method string `json:"method"`
+
+	// URL is the full upstream URL.
+	URL string `json:"url"`
+
+	// Headers are the outgoing headers (with sensitive values redacted).
+	Headers map[string][]string `json:"headers"`
+
+	// Body is the marshaled request body. Binary bodies are base64-encoded.
+	Body string `json:"body"`
+
+	// BodyEncoding is "base64" for binary bodies and empty for JSON bodies.
+	BodyEncoding string `json:"bodyEncoding,omitempty"`
+}
+
+// handleTryItInvoke handles POST /api/tryit/invoke requests. With dryRun=true,
+// the outgoing request is built and returned as a DryRunResponse without
+// being sent. With bodyFormat=form, the body is built from the URL-encoded
+// inputs of the generated request form, passed in the form value. For
+// client-streaming and bidi methods, the body is a JSON array of messages and
+// the transport must be grpc.
 func (s *Server) handleTryItInvoke(w http.ResponseWriter, r *http.Request) {
+	tryItReq, methodDesc, ok := s.parseTryItRequest(w, r)
+	if !ok {
+		return
+	}
+
+	dryRun := r.FormValue("dryRun") == "true"
+	call, status, err := s.prepareCall(r.Context(), tryItReq, methodDesc, dryRun)
+	if err != nil {
+		s.writeJSONError(w, status, err.Error())
+		return
+	}
+
+	if dryRun {
+		s.writeDryRun(w, call.invoker, call.req, call.transport)
+		return
+	}
+
+	tryItResp, err := s.runCall(r.Context(), tryItReq.Method, call)
+	if err != nil {
+		s.writeJSONError(w, http.StatusInternalServerError, fmt.Sprintf("invocation failed: %v", err))
+		return
+	}
+
+	// Render response template
+	w.Header().Set("Content-Type", "text/html")
+	if err := s.templates.ExecuteTemplate(w, "tryit_response.html", tryItResp); err != nil {
+		http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseTryItRequest reads the form of a Try It request and looks up its
+// method. On failure it writes the error response and returns false.
+func (s *Server) parseTryItRequest(w http.ResponseWriter, r *http.Request) (TryItRequest, protoreflect.MethodDescriptor, bool) {
 	// Ensure we have a config
 	if s.config == nil {
 		s.writeJSONError(w, http.StatusServiceUnavailable, "Try It functionality is not configured (missing reflect.yaml)")
-		return
+		return TryItRequest{}, nil, false
+	}
+
+	// Cap the request body so oversized uploads are rejected while they are
+	// read, rather than after the whole form is in memory
+	maxBytes := int64(config.DefaultMaxRequestBodyBytes)
+	if s.config.MaxRequestBodyBytes > 0 {
+		maxBytes = s.config.MaxRequestBodyBytes
 	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
 
 	// Parse form data from request
-	if err := r.ParseForm(); err != nil {
+	if err := parseTryItForm(r, maxBytes); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			s.writeJSONError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds limit of %d bytes", maxBytesErr.Limit))
+			return TryItRequest{}, nil, false
+		}
 		s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("failed to parse form data: %v", err))
-		return
+		return TryItRequest{}, nil, false
 	}
 
 	// Extract form values into TryItRequest
@@ -92,35 +180,77 @@ func (s *Server) handleTryItInvoke(w http.ResponseWriter, r *http.Request) {
 	if headersJSON != "" && headersJSON != "{}" {
 		if err := json.Unmarshal([]byte(headersJSON), &tryItReq.Headers); err != nil {
 			s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("failed to parse headers JSON: %v", err))
-			return
+			return TryItRequest{}, nil, false
 		}
 	}
 
 	// Validate request size
 	if err := tryit.ValidateJSONSize(tryItReq.Body, s.config.MaxRequestBodyBytes); err != nil {
 		s.writeJSONError(w, http.StatusRequestEntityTooLarge, err.Error())
-		return
+		return TryItRequest{}, nil, false
 	}
 
 	// Get registry
 	registry, _ := s.getRegistry()
 	if registry == nil {
 		s.writeJSONError(w, http.StatusServiceUnavailable, "No protobuf descriptors loaded")
-		return
+		return TryItRequest{}, nil, false
 	}
 
 	// Look up method descriptor
 	methodDesc, exists := registry.FindMethod(tryItReq.Method)
 	if !exists {
 		s.writeJSONError(w, http.StatusNotFound, fmt.Sprintf("method %q not found", tryItReq.Method))
-		return
+		return TryItRequest{}, nil, false
+	}
+
+	// A body entered with the generated form arrives as URL-encoded inputs
+	if r.FormValue("bodyFormat") == "form" {
+		values, err := url.ParseQuery(r.FormValue("form"))
+		if err != nil {
+			s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("failed to parse form body: %v", err))
+			return TryItRequest{}, nil, false
+		}
+		body, err := docs.BuildInputForm(methodDesc.Input()).EncodeJSON(values)
+		if err != nil {
+			s.writeJSONError(w, http.StatusBadRequest, err.Error())
+			return TryItRequest{}, nil, false
+		}
+		if methodDesc.IsStreamingClient() {
+			// The form describes one message; stream it as the only element
+			body = "[" + body + "]"
+		}
+		if err := tryit.ValidateJSONSize(body, s.config.MaxRequestBodyBytes); err != nil {
+			s.writeJSONError(w, http.StatusRequestEntityTooLarge, err.Error())
+			return TryItRequest{}, nil, false
+		}
+		tryItReq.Body = body
 	}
 
+	return tryItReq, methodDesc, true
+}
+
+// tryItCall is a Try It invocation prepared for one environment.
+type tryItCall struct {
+	env       *config.Environment
+	transport tryit.Transport
+	invoker   tryit.Invoker
+	req       *tryit.Request
+}
+
+// prepareCall resolves the environment, transport, headers, and auth token of
+// a Try It request. A dry run is never sent, so it gets a placeholder token
+// instead of fetching one. On failure it returns the HTTP status to answer with.
+func (s *Server) prepareCall(ctx context.Context, tryItReq TryItRequest, methodDesc protoreflect.MethodDescriptor, dryRun bool) (*tryItCall, int, error) {
 	// Look up environment configuration
 	env, err := s.config.GetEnvironment(tryItReq.Environment)
 	if err != nil {
-		s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("environment %q not found", tryItReq.Environment))
-		return
+		return nil, http.StatusBadRequest, fmt.Errorf("environment %q not found", tryItReq.Environment)
+	}
+
+	// Ensure the environment hosts this method
+	if !env.AllowsMethod(tryItReq.Method) {
+		return nil, http.StatusForbidden, fmt.Errorf("method %q is not available in environment %q", tryItReq.Method, tryItReq.Environment)
 	}
 
 	// Determine transport
@@ -131,70 +261,99 @@ func (s *Server) handleTryItInvoke(w http.ResponseWriter, r *http.Request) {
 
 	parsedTransport, err := tryit.ParseTransport(transport)
 	if err != nil {
-		s.writeJSONError(w, http.StatusBadRequest, err.Error())
-		return
+		return nil, http.StatusBadRequest, err
+	}
+
+	// Streaming request bodies need a transport that streams from the client
+	if methodDesc.IsStreamingClient() && parsedTransport != tryit.TransportGRPC {
+		return nil, http.StatusBadRequest, fmt.Errorf("method %q is client-streaming and can only be invoked with the grpc transport", tryItReq.Method)
 	}
 
 	// Filter headers through allowlist
 	filteredHeaders := tryit.FilterHeaders(tryItReq.Headers, s.config.HeaderAllowlist)
 
-	// Merge with environment default headers
-	mergedHeaders := tryit.MergeHeaders(env.DefaultHeaders, filteredHeaders)
+	// Merge with global and environment default headers
+	defaultHeaders := tryit.MergeHeaders(s.config.GlobalDefaultHeaders, env.DefaultHeaders)
+	mergedHeaders := tryit.MergeHeaders(defaultHeaders, filteredHeaders)
+
+	// Prefer the environment's timeout over the global one
+	timeout := env.GetTimeout(s.config.RequestTimeoutSeconds)
+
+	// Add the environment's auth token unless the request sets its own
+	if dryRun {
+		addAuthPlaceholder(s.tokenSources[env.Name], mergedHeaders)
+	} else if err := s.addAuthToken(ctx, env, mergedHeaders, timeout); err != nil {
+		return nil, http.StatusBadGateway, err
+	}
 
 	// Create invoker request
 	invokerReq := &tryit.Request{
-		Environment:      tryItReq.Environment,
-		MethodDescriptor: methodDesc,
-		JSONBody:         tryItReq.Body,
-		Headers:          mergedHeaders,
-		BaseURL:          env.BaseURL,
-		Timeout:          s.config.GetTimeout(),
-		InsecureSkipVerify: env.TLS.InsecureSkipVerify,
+		Environment:          tryItReq.Environment,
+		MethodDescriptor:     methodDesc,
+		JSONBody:             tryItReq.Body,
+		Headers:              mergedHeaders,
+		BaseURL:              env.BaseURL,
+		Timeout:              timeout,
+		InsecureSkipVerify:   env.TLS.InsecureSkipVerify,
+		Proxy:                env.Proxy,
+		BlockPrivateNetworks: !env.AllowPrivateNetworks,
+		UseProtoNames:        env.UseProtoNames,
+		PrettyPrintMaxBytes:  s.config.PrettyPrintMaxBytes,
+		Logger:               s.logger,
 	}
 
 	// Select appropriate invoker
-	var invoker tryit.Invoker
-	switch parsedTransport {
-	case tryit.TransportConnect:
-		invoker = tryit.NewConnectInvoker()
-	case tryit.TransportGRPC:
-		invoker = tryit.NewGRPCInvoker()
-	case tryit.TransportGRPCWeb:
-		invoker = tryit.NewGRPCWebInvoker()
-	default:
-		s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("unsupported transport: %s", parsedTransport))
-		return
+	invoker, err := tryit.NewInvoker(parsedTransport)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
 	}
 
+	return &tryItCall{env: env, transport: parsedTransport, invoker: invoker, req: invokerReq}, 0, nil
+}
+
+// runCall invokes a prepared call, logging and recording metrics for it. The
+// returned error is set only if the invocation could not be made; upstream
+// errors are reported in the response.
+func (s *Server) runCall(ctx context.Context, method string, call *tryItCall) (*TryItResponse, error) {
 	// Log invocation start
-	slog.Info("Try It: Starting invocation",
-		"method", tryItReq.Method,
-		"transport", parsedTransport,
-		"environment", tryItReq.Environment,
-		"baseURL", env.BaseURL)
+	s.logger.Info("Try It: Starting invocation",
+		"method", method,
+		"transport", call.transport,
+		"environment", call.env.Name,
+		"baseURL", call.env.BaseURL)
 
 	// Create context with timeout
-	ctx, cancel := context.WithTimeout(r.Context(), s.config.GetTimeout())
+	ctx, cancel := context.WithTimeout(ctx, call.req.Timeout)
 	defer cancel()
 
 	// Execute invocation
-	resp, err := invoker.Invoke(ctx, invokerReq)
+	start := time.Now()
+	resp, err := call.invoker.Invoke(ctx, call.req)
 	if err != nil {
-		s.writeJSONError(w, http.StatusInternalServerError, fmt.Sprintf("invocation failed: %v", err))
-		return
+		s.metrics.observeInvocation(string(call.transport), "error", time.Since(start))
+		s.logger.Error("Try It: Invocation failed",
+			"method", method,
+			"transport", call.transport,
+			"environment", call.env.Name,
+			"latencyMs", time.Since(start).Milliseconds(),
+			"error", err)
+		return nil, err
 	}
+	s.metrics.observeInvocation(string(call.transport), strconv.Itoa(resp.Status), resp.Latency)
 
 	// Redact sensitive headers
 	redactedHeaders := tryit.RedactSensitiveHeaders(resp.Headers)
 
 	// Build response
-	tryItResp := TryItResponse{
+	tryItResp := &TryItResponse{
 		Success:    resp.Error == nil,
 		Status:     resp.Status,
 		StatusText: resp.StatusText,
 		Headers:    redactedHeaders,
 		Body:       resp.JSONBody,
+		Compact:    resp.Compact,
 		LatencyMs:  resp.Latency.Milliseconds(),
+		RequestID:  requestID(ctx),
 	}
 
 	if resp.Error != nil {
@@ -204,27 +363,301 @@ func (s *Server) handleTryItInvoke(w http.ResponseWriter, r *http.Request) {
 			Details: resp.Error.Details,
 		}
 		// Log error response
-		slog.Error("Try It: Invocation failed",
-			"method", tryItReq.Method,
-			"transport", parsedTransport,
-			"environment", tryItReq.Environment,
+		s.logger.Error("Try It: Invocation failed",
+			"method", method,
+			"transport", call.transport,
+			"environment", call.env.Name,
 			"status", resp.Status,
 			"latencyMs", resp.Latency.Milliseconds(),
 			"error", resp.Error.Message)
 	} else {
 		// Log successful response
-		slog.Info("Try It: Invocation succeeded",
-			"method", tryItReq.Method,
-			"transport", parsedTransport,
-			"environment", tryItReq.Environment,
+		s.logger.Info("Try It: Invocation succeeded",
+			"method", method,
+			"transport", call.transport,
+			"environment", call.env.Name,
 			"status", resp.Status,
 			"latencyMs", resp.Latency.Milliseconds())
 	}
 
-	// Render response template
-	w.Header().Set("Content-Type", "text/html")
-	if err := s.templates.ExecuteTemplate(w, "tryit_response.html", tryItResp); err != nil {
-		http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
+	return tryItResp, nil
+}
+
+// addAuthToken sets the Authorization header from the environment's token
+// source, unless headers already carry one.
+func (s *Server) addAuthToken(ctx context.Context, env *config.Environment, headers map[string]string, timeout time.Duration) error {
+	source := s.tokenSources[env.Name]
+	if source == nil || hasHeader(headers, "Authorization") {
+		return nil
+	}
+
+	tokenCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	token, err := source.Token(tokenCtx)
+	if err != nil {
+		return fmt.Errorf("failed to obtain auth token for environment %q: %w", env.Name, err)
+	}
+	headers["Authorization"] = "Bearer " + token
+	return nil
+}
+
+// addAuthPlaceholder sets a redacted Authorization header where addAuthToken
+// would set a real one, without calling the token source.
+func addAuthPlaceholder(source tryit.TokenSource, headers map[string]string) {
+	if source != nil && !hasHeader(headers, "Authorization") {
+		headers["Authorization"] = "Bearer [REDACTED]"
+	}
+}
+
+// parseTryItForm parses URL-encoded or multipart form data. Multipart bodies
+// are parsed explicitly, since FormValue would otherwise parse them lazily and
+// drop the error.
+func parseTryItForm(r *http.Request, maxBytes int64) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		return r.ParseMultipartForm(maxBytes)
+	}
+	return nil
+}
+
+// newTokenSources creates a token source for each environment with auth configured.
+func newTokenSources(cfg *config.Config) (map[string]tryit.TokenSource, error) {
+	sources := make(map[string]tryit.TokenSource)
+	if cfg == nil {
+		return sources, nil
+	}
+
+	for i := range cfg.Environments {
+		env := &cfg.Environments[i]
+		switch env.Auth.Type {
+		case config.AuthTypeBearer:
+			sources[env.Name] = tryit.StaticToken(env.Auth.Token)
+		case config.AuthTypeOAuth2ClientCredentials:
+			source, err := tryit.NewClientCredentialsSource(env.Auth.TokenURL, env.Auth.ClientID, env.Auth.ClientSecret,
+				env.Auth.Scopes, env.TLS.InsecureSkipVerify, env.Proxy)
+			if err != nil {
+				return nil, fmt.Errorf("environment %q auth: %w", env.Name, err)
+			}
+			sources[env.Name] = source
+		}
+	}
+	return sources, nil
+}
+
+// hasHeader reports whether headers contains name, ignoring case.
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeDryRun builds the outgoing request without sending it and writes it as
+// a DryRunResponse.
+func (s *Server) writeDryRun(w http.ResponseWriter, invoker tryit.Invoker, req *tryit.Request, transport tryit.Transport) {
+	out, err := invoker.BuildRequest(req)
+	if err != nil {
+		var buildErr *tryit.BuildError
+		if errors.As(err, &buildErr) {
+			s.writeJSONError(w, http.StatusBadRequest, buildErr.Error())
+			return
+		}
+		s.writeJSONError(w, http.StatusInternalServerError, fmt.Sprintf("failed to build request: %v", err))
+		return
+	}
+
+	resp := DryRunResponse{
+		Transport: transport.String(),
+		Method:    out.Method,
+		URL:       out.URL,
+		Headers:   tryit.RedactSensitiveHeaders(out.Header),
+	}
+	if transport == tryit.TransportConnect {
+		resp.Body = string(out.Body)
+	} else {
+		resp.Body = base64.StdEncoding.EncodeToString(out.Body)
+		resp.BodyEncoding = "base64"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// EnvironmentInfo describes a configured environment for the /api/environments endpoint.
+type EnvironmentInfo struct {
+	// Name is the environment name.
+	Name string `json:"name"`
+
+	// BaseURL is the upstream service URL.
+	BaseURL string `json:"baseURL"`
+
+	// Transport is the environment's default transport.
+	Transport string `json:"transport"`
+
+	// Services are the glob patterns restricting which methods can be invoked.
+	// Empty means all methods are allowed.
+	Services []string `json:"services,omitempty"`
+
+	// Available reports whether the requested method can be invoked against
+	// this environment. Only set when the request specifies a method.
+	Available *bool `json:"available,omitempty"`
+}
+
+// handleEnvironments handles GET /api/environments requests. If the "method"
+// query parameter is set, each environment reports whether it hosts that method.
+func (s *Server) handleEnvironments(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Query().Get("method")
+
+	environments := []EnvironmentInfo{}
+	if s.config != nil {
+		for i := range s.config.Environments {
+			env := &s.config.Environments[i]
+			info := EnvironmentInfo{
+				Name:      env.Name,
+				BaseURL:   env.BaseURL,
+				Transport: env.Transport,
+				Services:  env.Services,
+			}
+			if method != "" {
+				available := env.AllowsMethod(method)
+				info.Available = &available
+			}
+			environments = append(environments, info)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{"environments": environments}); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// environmentsForMethod returns the configured environments that can invoke the method.
+func (s *Server) environmentsForMethod(methodFullName string) []*config.Environment {
+	if s.config == nil {
+		return nil
+	}
+
+	var environments []*config.Environment
+	for i := range s.config.Environments {
+		if s.config.Environments[i].AllowsMethod(methodFullName) {
+			environments = append(environments, &s.config.Environments[i])
+		}
+	}
+	return environments
+}
+
+// grpcurlTarget addresses a grpcurl example to an environment, including the
+// global default headers. Sensitive default headers, such as credentials, are
+// left out of the example.
+func grpcurlTarget(env *config.Environment, globalHeaders map[string]string) docs.GrpcurlTarget {
+	headers := make(map[string]string, len(env.DefaultHeaders))
+	for key, value := range tryit.MergeHeaders(globalHeaders, env.DefaultHeaders) {
+		if !tryit.IsSensitiveHeader(key) {
+			headers[key] = value
+		}
+	}
+	return docs.GrpcurlTarget{BaseURL: env.BaseURL, Headers: headers}
+}
+
+// ValidateRequest represents the JSON request body for the /api/validate endpoint.
+type ValidateRequest struct {
+	// MessageType is the fully-qualified message name (e.g., "echo.v1.EchoRequest").
+	MessageType string `json:"messageType"`
+
+	// Body is the JSON body to validate, either as a JSON object or as a string
+	// containing JSON.
+	Body json.RawMessage `json:"body"`
+}
+
+// ValidateResponse represents the JSON response for the /api/validate endpoint.
+type ValidateResponse struct {
+	// Valid indicates whether the body parses into the message type.
+	Valid bool `json:"valid"`
+
+	// MessageType is the message type the body was validated against.
+	MessageType string `json:"messageType"`
+
+	// Error contains details if the body is invalid.
+	Error *ValidateError `json:"error,omitempty"`
+}
+
+// ValidateError describes why a body failed validation.
+type ValidateError struct {
+	// Path is the location of the offending field (e.g., "profile.tags[1]").
+	Path string `json:"path,omitempty"`
+
+	// Message is the parse error.
+	Message string `json:"message"`
+}
+
+// handleValidate handles POST /api/validate requests. It checks that a JSON
+// body parses into the given message type without invoking any RPC.
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	maxBytes := int64(config.DefaultMaxRequestBodyBytes)
+	if s.config != nil && s.config.MaxRequestBodyBytes > 0 {
+		maxBytes = s.config.MaxRequestBodyBytes
+	}
+
+	var req ValidateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBytes)).Decode(&req); err != nil {
+		s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	if req.MessageType == "" {
+		s.writeJSONError(w, http.StatusBadRequest, "messageType is required")
+		return
+	}
+
+	// Accept the body either inline or as a JSON-encoded string
+	body := string(req.Body)
+	var bodyString string
+	if err := json.Unmarshal(req.Body, &bodyString); err == nil {
+		body = bodyString
+	} else if body == "null" {
+		body = ""
+	}
+
+	registry, _ := s.getRegistry()
+	if registry == nil {
+		s.writeJSONError(w, http.StatusServiceUnavailable, "No protobuf descriptors loaded")
+		return
+	}
+
+	msg, exists := registry.FindMessage(req.MessageType)
+	if !exists {
+		s.writeJSONError(w, http.StatusNotFound, fmt.Sprintf("message type %q not found", req.MessageType))
+		return
+	}
+
+	resp := ValidateResponse{
+		Valid:       true,
+		MessageType: req.MessageType,
+	}
+
+	if _, err := tryit.ParseJSONBody(msg, body, registry.Resolver()); err != nil {
+		resp.Valid = false
+		resp.Error = &ValidateError{Message: err.Error()}
+
+		var validationErr *tryit.ValidationError
+		if errors.As(err, &validationErr) {
+			resp.Error.Path = validationErr.Path
+			resp.Error.Message = validationErr.Message
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
 		return
 	}
 }
@@ -234,12 +667,17 @@ func (s *Server) writeJSONError(w http.ResponseWriter, statusCode int, message s
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 
+	errorDetails := map[string]interface{}{
+		"code":    statusCode,
+		"message": message,
+	}
+	// Set by the access log middleware, so users can quote it in reports
+	if id := w.Header().Get(requestIDHeader); id != "" {
+		errorDetails["requestId"] = id
+	}
 	resp := map[string]interface{}{
 		"success": false,
-		"error": map[string]interface{}{
-			"code":    statusCode,
-			"message": message,
-		},
+		"error":   errorDetails,
 	}
 
 	json.NewEncoder(w).Encode(resp)
@@ -0,0 +1,915 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/descriptor"
+	"github.com/bnprtr/reflect/internal/server/theme"
+)
+
+func TestHandleValidate(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	srv, err := New(reg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		expectedStatus int
+		expectedValid  bool
+		expectedPath   string
+	}{
+		{
+			name:           "valid body",
+			requestBody:    `{"messageType": "users.v1.User", "body": {"email": "a@example.com", "profile": {"timezone": "UTC"}}}`,
+			expectedStatus: http.StatusOK,
+			expectedValid:  true,
+		},
+		{
+			name:           "valid body as string",
+			requestBody:    `{"messageType": "users.v1.User", "body": "{\"email\": \"a@example.com\"}"}`,
+			expectedStatus: http.StatusOK,
+			expectedValid:  true,
+		},
+		{
+			name:           "empty body",
+			requestBody:    `{"messageType": "users.v1.User"}`,
+			expectedStatus: http.StatusOK,
+			expectedValid:  true,
+		},
+		{
+			name:           "unknown field",
+			requestBody:    `{"messageType": "users.v1.User", "body": {"email": "a@example.com", "nickname": "al"}}`,
+			expectedStatus: http.StatusOK,
+			expectedValid:  false,
+			expectedPath:   "nickname",
+		},
+		{
+			name:           "nested type mismatch",
+			requestBody:    `{"messageType": "users.v1.User", "body": {"profile": {"timezone": 5}}}`,
+			expectedStatus: http.StatusOK,
+			expectedValid:  false,
+			expectedPath:   "profile.timezone",
+		},
+		{
+			name:           "missing message type",
+			requestBody:    `{"body": {}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown message type",
+			requestBody:    `{"messageType": "non.existent.Type", "body": {}}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "malformed request",
+			requestBody:    `{"messageType":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/validate", strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			srv.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp ValidateResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if resp.Valid != tt.expectedValid {
+				t.Errorf("Expected valid=%v, got %v (error: %+v)", tt.expectedValid, resp.Valid, resp.Error)
+			}
+			if tt.expectedValid {
+				if resp.Error != nil {
+					t.Errorf("Expected no error, got %+v", resp.Error)
+				}
+				return
+			}
+			if resp.Error == nil {
+				t.Fatal("Expected error details")
+			}
+			if resp.Error.Path != tt.expectedPath {
+				t.Errorf("Expected path %q, got %q", tt.expectedPath, resp.Error.Path)
+			}
+			if resp.Error.Message == "" {
+				t.Error("Expected non-empty error message")
+			}
+		})
+	}
+}
+
+func TestHandleTryItInvokeServiceRestriction(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		Environments: []config.Environment{
+			{
+				Name:                 "users-only",
+				BaseURL:              upstream.URL,
+				AllowPrivateNetworks: true,
+				Transport:            "connect",
+				Services:             []string{"users.v1.*"},
+			},
+		},
+		MaxRequestBodyBytes:   config.DefaultMaxRequestBodyBytes,
+		RequestTimeoutSeconds: config.DefaultRequestTimeoutSeconds,
+	}
+
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		expectedStatus int
+	}{
+		{
+			name:           "allowed method",
+			method:         "users.v1.UserService/GetUser",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "disallowed method",
+			method:         "orders.v1.OrderService/GetOrder",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{
+				"environment": {"users-only"},
+				"method":      {tt.method},
+				"body":        {"{}"},
+			}
+			req := httptest.NewRequest("POST", "/api/tryit/invoke", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			srv.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusForbidden && !strings.Contains(w.Body.String(), "not available in environment") {
+				t.Errorf("Expected clear restriction error, got %s", w.Body.String())
+			}
+		})
+	}
+
+	t.Run("environments API reports availability", func(t *testing.T) {
+		for method, want := range map[string]bool{
+			"users.v1.UserService/GetUser":    true,
+			"orders.v1.OrderService/GetOrder": false,
+		} {
+			req := httptest.NewRequest("GET", "/api/environments?method="+url.QueryEscape(method), nil)
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+
+			var resp struct {
+				Environments []EnvironmentInfo `json:"environments"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(resp.Environments) != 1 {
+				t.Fatalf("Expected 1 environment, got %d", len(resp.Environments))
+			}
+			env := resp.Environments[0]
+			if env.Available == nil || *env.Available != want {
+				t.Errorf("Expected available=%v for %s, got %v", want, method, env.Available)
+			}
+			if len(env.Services) != 1 || env.Services[0] != "users.v1.*" {
+				t.Errorf("Expected services restriction to be surfaced, got %v", env.Services)
+			}
+		}
+	})
+}
+
+func TestHandleTryItInvokeDryRun(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Dry run should not reach upstream, got %s %s", r.Method, r.URL.Path)
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		Environments: []config.Environment{
+			{
+				Name:                 "local",
+				BaseURL:              upstream.URL + "/",
+				AllowPrivateNetworks: true,
+				Transport:            "connect",
+			},
+		},
+		MaxRequestBodyBytes:   config.DefaultMaxRequestBodyBytes,
+		RequestTimeoutSeconds: config.DefaultRequestTimeoutSeconds,
+	}
+
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	invoke := func(body string) *httptest.ResponseRecorder {
+		form := url.Values{
+			"environment": {"local"},
+			"method":      {"users.v1.UserService/GetUser"},
+			"body":        {body},
+			"headers":     {`{"Authorization": "Bearer secret", "X-Request-ID": "abc123"}`},
+			"dryRun":      {"true"},
+		}
+		req := httptest.NewRequest("POST", "/api/tryit/invoke", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("connect", func(t *testing.T) {
+		w := invoke(`{"user_id": "u-123"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		var resp DryRunResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		if resp.Transport != "connect" || resp.Method != http.MethodPost {
+			t.Errorf("Expected connect POST, got %s %s", resp.Transport, resp.Method)
+		}
+		if want := upstream.URL + "/users.v1.UserService/GetUser"; resp.URL != want {
+			t.Errorf("Expected URL %q, got %q", want, resp.URL)
+		}
+		if resp.BodyEncoding != "" {
+			t.Errorf("Expected plain JSON body, got encoding %q", resp.BodyEncoding)
+		}
+
+		var body map[string]any
+		if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+			t.Fatalf("Expected JSON body, got %q: %v", resp.Body, err)
+		}
+		if len(body) != 1 || body["userId"] != "u-123" {
+			t.Errorf("Expected body {\"userId\":\"u-123\"}, got %s", resp.Body)
+		}
+
+		if got := resp.Headers["Content-Type"]; len(got) != 1 || got[0] != "application/json" {
+			t.Errorf("Expected Content-Type application/json, got %v", got)
+		}
+		if got := resp.Headers["Authorization"]; len(got) != 1 || got[0] != "[REDACTED]" {
+			t.Errorf("Expected Authorization to be redacted, got %v", got)
+		}
+		if got := resp.Headers["X-Request-Id"]; len(got) != 1 || got[0] != "abc123" {
+			t.Errorf("Expected X-Request-Id=abc123, got %v", got)
+		}
+	})
+
+	t.Run("form body", func(t *testing.T) {
+		form := url.Values{
+			"environment": {"local"},
+			"method":      {"users.v1.UserService/GetUser"},
+			"bodyFormat":  {"form"},
+			"form":        {url.Values{"userId": {"u-456"}, "fieldMask": {"email,fullName"}}.Encode()},
+			"dryRun":      {"true"},
+		}
+		req := httptest.NewRequest("POST", "/api/tryit/invoke", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+
+		var resp DryRunResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		var body map[string]any
+		if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+			t.Fatalf("Expected JSON body, got %q: %v", resp.Body, err)
+		}
+		if body["userId"] != "u-456" || body["fieldMask"] != "email,fullName" {
+			t.Errorf("Expected body built from the form inputs, got %s", resp.Body)
+		}
+	})
+
+	t.Run("form partial", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/partial/tryit/form/users.v1.UserService/GetUser", nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		for _, want := range []string{`name="userId"`, `name="fieldMask"`} {
+			if !strings.Contains(w.Body.String(), want) {
+				t.Errorf("Expected form to contain %s, got:\n%s", want, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		w := invoke(`{"user_id": 42}`)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+		}
+		if !strings.Contains(w.Body.String(), "failed to parse JSON request") {
+			t.Errorf("Expected parse error, got %s", w.Body.String())
+		}
+	})
+}
+
+func TestHandleTryItInvokeClientStreaming(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	cfg := &config.Config{
+		Environments: []config.Environment{
+			{
+				Name:                 "local",
+				BaseURL:              "http://localhost:50051",
+				AllowPrivateNetworks: true,
+				Transport:            "connect",
+			},
+		},
+		MaxRequestBodyBytes:   config.DefaultMaxRequestBodyBytes,
+		RequestTimeoutSeconds: config.DefaultRequestTimeoutSeconds,
+	}
+
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	invoke := func(values url.Values) *httptest.ResponseRecorder {
+		values.Set("environment", "local")
+		values.Set("dryRun", "true")
+		req := httptest.NewRequest("POST", "/api/tryit/invoke", strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name       string
+		values     url.Values
+		wantStatus int
+		wantFrames int
+	}{
+		{
+			name: "client streaming over grpc",
+			values: url.Values{
+				"method":    {"users.v1.UserService/BulkUpdateUsers"},
+				"transport": {"grpc"},
+				"body":      {`[{"batch_update": {"user_ids": ["u-1"]}}, {"batch_update": {"user_ids": ["u-2", "u-3"]}}]`},
+			},
+			wantStatus: http.StatusOK,
+			wantFrames: 2,
+		},
+		{
+			name: "bidi from the form",
+			values: url.Values{
+				"method":     {"users.v1.UserService/SyncUsers"},
+				"transport":  {"grpc"},
+				"bodyFormat": {"form"},
+			},
+			wantStatus: http.StatusOK,
+			wantFrames: 1,
+		},
+		{
+			name: "environment default transport",
+			values: url.Values{
+				"method": {"users.v1.UserService/BulkUpdateUsers"},
+				"body":   {`[]`},
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "body is not an array",
+			values: url.Values{
+				"method":    {"users.v1.UserService/BulkUpdateUsers"},
+				"transport": {"grpc"},
+				"body":      {`{"batch_update": {}}`},
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := invoke(tt.values)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp DryRunResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			body, err := base64.StdEncoding.DecodeString(resp.Body)
+			if err != nil {
+				t.Fatalf("Expected base64 body, got %q: %v", resp.Body, err)
+			}
+			// Count the length-prefixed gRPC frames
+			frames := 0
+			for len(body) >= 5 {
+				body = body[5+binary.BigEndian.Uint32(body[1:5]):]
+				frames++
+			}
+			if frames != tt.wantFrames || len(body) != 0 {
+				t.Errorf("Expected %d framed messages, got %d (%d trailing bytes)", tt.wantFrames, frames, len(body))
+			}
+		})
+	}
+}
+
+func TestHandleTryItInvokeAuth(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	var tokenRequests atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests.Add(1)
+		if id, secret, ok := r.BasicAuth(); !ok || id != "reflect" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "oauth-token", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer tokenServer.Close()
+
+	received := make(chan string, 10)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		Environments: []config.Environment{
+			{
+				Name:                 "static",
+				BaseURL:              upstream.URL,
+				AllowPrivateNetworks: true,
+				Transport:            "connect",
+				Auth:                 config.AuthConfig{Type: config.AuthTypeBearer, Token: "static-token"},
+			},
+			{
+				Name:                 "oauth",
+				BaseURL:              upstream.URL,
+				AllowPrivateNetworks: true,
+				Transport:            "connect",
+				Auth: config.AuthConfig{
+					Type:         config.AuthTypeOAuth2ClientCredentials,
+					TokenURL:     tokenServer.URL,
+					ClientID:     "reflect",
+					ClientSecret: "s3cret",
+				},
+			},
+		},
+		MaxRequestBodyBytes:   config.DefaultMaxRequestBodyBytes,
+		RequestTimeoutSeconds: config.DefaultRequestTimeoutSeconds,
+	}
+
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	invoke := func(env string, extra url.Values) *httptest.ResponseRecorder {
+		form := url.Values{
+			"environment": {env},
+			"method":      {"users.v1.UserService/GetUser"},
+			"body":        {"{}"},
+		}
+		for key, values := range extra {
+			form[key] = values
+		}
+		req := httptest.NewRequest("POST", "/api/tryit/invoke", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name  string
+		env   string
+		extra url.Values
+		want  string
+	}{
+		{"bearer token", "static", nil, "Bearer static-token"},
+		{"oauth2 token", "oauth", nil, "Bearer oauth-token"},
+		{"cached oauth2 token", "oauth", nil, "Bearer oauth-token"},
+		{"request header wins", "oauth", url.Values{"headers": {`{"authorization": "Bearer mine"}`}}, "Bearer mine"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := invoke(tt.env, tt.extra)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			if got := <-received; got != tt.want {
+				t.Errorf("Expected upstream Authorization %q, got %q", tt.want, got)
+			}
+			if strings.Contains(w.Body.String(), "oauth-token") || strings.Contains(w.Body.String(), "static-token") {
+				t.Errorf("Expected token to be absent from the response, got %s", w.Body.String())
+			}
+		})
+	}
+
+	if got := tokenRequests.Load(); got != 1 {
+		t.Errorf("Expected the OAuth2 token to be fetched once, got %d requests", got)
+	}
+
+	t.Run("dry run redacts the token", func(t *testing.T) {
+		w := invoke("oauth", url.Values{"dryRun": {"true"}})
+		var resp DryRunResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if got := resp.Headers["Authorization"]; len(got) != 1 || got[0] != "[REDACTED]" {
+			t.Errorf("Expected redacted Authorization header, got %v", got)
+		}
+	})
+
+	t.Run("dry run does not fetch a token", func(t *testing.T) {
+		freshSrv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+		before := tokenRequests.Load()
+
+		form := url.Values{"environment": {"oauth"}, "method": {"users.v1.UserService/GetUser"}, "body": {"{}"}, "dryRun": {"true"}}
+		req := httptest.NewRequest("POST", "/api/tryit/invoke", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		freshSrv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if got := tokenRequests.Load(); got != before {
+			t.Errorf("Expected no token requests during a dry run, got %d", got-before)
+		}
+		var resp DryRunResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if got := resp.Headers["Authorization"]; len(got) != 1 || got[0] != "[REDACTED]" {
+			t.Errorf("Expected redacted Authorization placeholder, got %v", got)
+		}
+	})
+
+	t.Run("token endpoint failure", func(t *testing.T) {
+		badCfg := *cfg
+		badCfg.Environments = []config.Environment{cfg.Environments[1]}
+		badCfg.Environments[0].Auth.ClientSecret = "wrong"
+		badSrv, err := NewWithTheme(reg, theme.GetDefaultTheme(), &badCfg)
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+
+		form := url.Values{"environment": {"oauth"}, "method": {"users.v1.UserService/GetUser"}, "body": {"{}"}}
+		req := httptest.NewRequest("POST", "/api/tryit/invoke", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		badSrv.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadGateway {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusBadGateway, w.Code, w.Body.String())
+		}
+		if !strings.Contains(w.Body.String(), "failed to obtain auth token") {
+			t.Errorf("Expected token error, got %s", w.Body.String())
+		}
+	})
+}
+
+// countingReader serves n bytes of filler and records how many were read.
+type countingReader struct {
+	remaining, read int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	if c.remaining <= 0 {
+		return 0, io.EOF
+	}
+	n := int64(len(p))
+	if n > c.remaining {
+		n = c.remaining
+	}
+	for i := range p[:n] {
+		p[i] = 'a'
+	}
+	c.remaining -= n
+	c.read += n
+	return int(n), nil
+}
+
+func TestHandleTryItInvokeGlobalDefaultHeaders(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	received := make(chan http.Header, 1)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		GlobalDefaultHeaders: map[string]string{
+			"User-Agent":   "reflect-global",
+			"X-Team":       "global-team",
+			"X-Request-Id": "global-id",
+		},
+		Environments: []config.Environment{
+			{
+				Name:                 "local",
+				BaseURL:              upstream.URL,
+				AllowPrivateNetworks: true,
+				Transport:            "connect",
+				DefaultHeaders: map[string]string{
+					"x-team": "env-team",
+				},
+			},
+		},
+		MaxRequestBodyBytes:   config.DefaultMaxRequestBodyBytes,
+		RequestTimeoutSeconds: config.DefaultRequestTimeoutSeconds,
+	}
+
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	form := url.Values{
+		"environment": {"local"},
+		"method":      {"users.v1.UserService/GetUser"},
+		"body":        {"{}"},
+		"headers":     {`{"x-request-id": "request-id"}`},
+	}
+	req := httptest.NewRequest("POST", "/api/tryit/invoke", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	headers := <-received
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"User-Agent", "reflect-global"}, // global only
+		{"X-Team", "env-team"},           // environment beats global
+		{"X-Request-Id", "request-id"},   // request beats both
+	}
+	for _, tt := range tests {
+		if got := headers.Values(tt.header); len(got) != 1 || got[0] != tt.want {
+			t.Errorf("Expected upstream %s [%s], got %v", tt.header, tt.want, got)
+		}
+	}
+}
+
+// recordingHandler is a slog.Handler that keeps every record it handles.
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r.Clone())
+	return nil
+}
+
+// snapshot returns the records handled so far.
+func (h *recordingHandler) snapshot() []slog.Record {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]slog.Record(nil), h.records...)
+}
+
+// reset discards the records handled so far.
+func (h *recordingHandler) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+func TestHandleTryItInvokeLogging(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		Environments: []config.Environment{
+			{Name: "local", BaseURL: upstream.URL, Transport: "connect", AllowPrivateNetworks: true},
+		},
+		MaxRequestBodyBytes:   config.DefaultMaxRequestBodyBytes,
+		RequestTimeoutSeconds: config.DefaultRequestTimeoutSeconds,
+	}
+
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	handler := &recordingHandler{}
+	srv.SetLogger(slog.New(handler))
+
+	form := url.Values{
+		"environment": {"local"},
+		"method":      {"users.v1.UserService/GetUser"},
+		"body":        {"{}"},
+	}
+	req := httptest.NewRequest("POST", "/api/tryit/invoke", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	records := handler.snapshot()
+	var record *slog.Record
+	for i := range records {
+		if records[i].Message == "Try It: Invocation succeeded" {
+			record = &records[i]
+		}
+	}
+	if record == nil {
+		t.Fatalf("Expected an invocation record, got %d records", len(records))
+	}
+	if record.Level != slog.LevelInfo {
+		t.Errorf("Expected level INFO, got %v", record.Level)
+	}
+
+	attrs := make(map[string]slog.Value)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs[attr.Key] = attr.Value
+		return true
+	})
+	want := map[string]string{
+		"method":      "users.v1.UserService/GetUser",
+		"transport":   "connect",
+		"environment": "local",
+		"status":      "200",
+	}
+	for key, value := range want {
+		if got, ok := attrs[key]; !ok || got.String() != value {
+			t.Errorf("Expected attribute %s=%s, got %v", key, value, got)
+		}
+	}
+	if latency, ok := attrs["latencyMs"]; !ok || latency.Kind() != slog.KindInt64 {
+		t.Errorf("Expected integer latencyMs attribute, got %v", latency)
+	}
+}
+
+func TestHandleTryItInvokeBodyLimit(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	cfg := &config.Config{
+		Environments: []config.Environment{
+			{Name: "local", BaseURL: "http://localhost:1/", Transport: "connect", AllowPrivateNetworks: true},
+		},
+		MaxRequestBodyBytes:   1024,
+		RequestTimeoutSeconds: config.DefaultRequestTimeoutSeconds,
+	}
+
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	const size = 64 << 20
+
+	tests := []struct {
+		name        string
+		contentType string
+		prefix      string
+	}{
+		{
+			name:        "url-encoded",
+			contentType: "application/x-www-form-urlencoded",
+			prefix:      "environment=local&body=",
+		},
+		{
+			name:        "multipart",
+			contentType: "multipart/form-data; boundary=limit",
+			prefix:      "--limit\r\nContent-Disposition: form-data; name=\"upload\"; filename=\"big.json\"\r\n\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filler := &countingReader{remaining: size}
+			req := httptest.NewRequest("POST", "/api/tryit/invoke", io.MultiReader(strings.NewReader(tt.prefix), filler))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+
+			if w.Code != http.StatusRequestEntityTooLarge {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+			}
+			if filler.read >= size {
+				t.Errorf("Expected the body to be rejected while streaming, but all %d bytes were read", filler.read)
+			}
+		})
+	}
+}
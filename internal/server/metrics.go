@@ -0,0 +1,97 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bnprtr/reflect/internal/metrics"
+	"github.com/go-chi/chi/v5"
+)
+
+// serverMetrics holds the Prometheus metrics served at /metrics. A nil
+// *serverMetrics records nothing, so call sites need no enabled check.
+type serverMetrics struct {
+	registry         *metrics.Registry
+	tryItInvocations *metrics.CounterVec
+	tryItLatency     *metrics.HistogramVec
+	httpRequests     *metrics.CounterVec
+}
+
+func newServerMetrics() *serverMetrics {
+	registry := metrics.NewRegistry()
+	return &serverMetrics{
+		registry: registry,
+		tryItInvocations: registry.NewCounterVec("reflect_tryit_invocations_total",
+			"Try It invocations by transport and upstream status.", "transport", "status"),
+		tryItLatency: registry.NewHistogramVec("reflect_tryit_latency_seconds",
+			"Try It upstream call latency in seconds.", metrics.DefaultBuckets, "transport"),
+		httpRequests: registry.NewCounterVec("reflect_http_requests_total",
+			"HTTP requests by route pattern and response code.", "path", "code"),
+	}
+}
+
+// observeInvocation records a Try It invocation. status is the upstream
+// HTTP or gRPC status code, or "error" if the call could not be made.
+func (m *serverMetrics) observeInvocation(transport, status string, latency time.Duration) {
+	if m == nil {
+		return
+	}
+	m.tryItInvocations.Inc(transport, status)
+	m.tryItLatency.Observe(latency.Seconds(), transport)
+}
+
+// instrument is middleware that counts requests by chi route pattern (not the
+// raw path, which would make one series per method or type page) and status.
+func (m *serverMetrics) instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r)
+
+		path := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			path = rctx.RoutePattern()
+		}
+		m.httpRequests.Inc(path, strconv.Itoa(sw.Status()))
+	})
+}
+
+// statusWriter records the status code written to a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	if sw.status == 0 {
+		sw.status = status
+	}
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+func (sw *statusWriter) Write(p []byte) (int, error) {
+	if sw.status == 0 {
+		sw.status = http.StatusOK
+	}
+	return sw.ResponseWriter.Write(p)
+}
+
+// Status returns the response status, defaulting to 200 when none was written.
+func (sw *statusWriter) Status() int {
+	if sw.status == 0 {
+		return http.StatusOK
+	}
+	return sw.status
+}
+
+// Flush passes flushes through for streaming responses.
+func (sw *statusWriter) Flush() {
+	if flusher, ok := sw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/descriptor"
+	"github.com/bnprtr/reflect/internal/server/theme"
+)
+
+func TestMetrics(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		Environments: []config.Environment{
+			{Name: "dev", BaseURL: upstream.URL, Transport: "connect", AllowPrivateNetworks: true},
+		},
+		MaxRequestBodyBytes:   config.DefaultMaxRequestBodyBytes,
+		RequestTimeoutSeconds: config.DefaultRequestTimeoutSeconds,
+		Metrics:               true,
+	}
+
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	form := url.Values{
+		"environment": {"dev"},
+		"method":      {"users.v1.UserService/GetUser"},
+		"body":        {"{}"},
+	}
+	req := httptest.NewRequest("POST", "/api/tryit/invoke", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected invoke status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	// A page view with a path parameter is counted under its route pattern
+	req = httptest.NewRequest("GET", "/types/users.v1.User", nil)
+	srv.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest("GET", "/metrics", nil)
+	w = httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected metrics status %d, got %d", http.StatusOK, w.Code)
+	}
+	body, _ := io.ReadAll(w.Body)
+
+	for _, want := range []string{
+		`reflect_tryit_invocations_total{transport="connect",status="200"} 1`,
+		`reflect_tryit_latency_seconds_count{transport="connect"} 1`,
+		`reflect_tryit_latency_seconds_bucket{transport="connect",le="+Inf"} 1`,
+		`reflect_http_requests_total{path="/api/tryit/invoke",code="200"} 1`,
+		`reflect_http_requests_total{path="/types/{fullName}",code="200"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	srv, err := NewWithTheme(nil, theme.GetDefaultTheme(), &config.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	srv.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d when metrics are disabled, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bnprtr/reflect/internal/docs"
+)
+
+// Page size bounds for paginated service lists.
+const (
+	defaultServicePageSize = 50
+	maxServicePageSize     = 500
+)
+
+// ServicePage is one page of the sorted service list.
+type ServicePage struct {
+	// Services holds the services on this page; empty past the last page.
+	Services []ServiceEntry `json:"services"`
+
+	// Page is the 1-based page number.
+	Page int `json:"page"`
+
+	// PageSize is the maximum number of services per page.
+	PageSize int `json:"pageSize"`
+
+	// TotalPages is the number of non-empty pages.
+	TotalPages int `json:"totalPages"`
+
+	// Total is the number of services across all pages.
+	Total int `json:"total"`
+}
+
+// ServiceEntry is a service as listed by GET /api/services.
+type ServiceEntry struct {
+	Name     string `json:"name"`
+	FullName string `json:"fullName"`
+	Package  string `json:"package"`
+	Comment  string `json:"comment,omitempty"`
+	Internal bool   `json:"internal,omitempty"`
+}
+
+// PrevPage returns the number of the preceding page.
+func (p *ServicePage) PrevPage() int {
+	return p.Page - 1
+}
+
+// NextPage returns the number of the following page.
+func (p *ServicePage) NextPage() int {
+	return p.Page + 1
+}
+
+// summaries returns the page's services as index summaries for templates.
+func (p *ServicePage) summaries() []docs.ServiceSummary {
+	summaries := make([]docs.ServiceSummary, 0, len(p.Services))
+	for _, service := range p.Services {
+		summaries = append(summaries, docs.ServiceSummary{
+			Name:     service.Name,
+			FullName: service.FullName,
+			Package:  service.Package,
+			Comment:  service.Comment,
+			Internal: service.Internal,
+		})
+	}
+	return summaries
+}
+
+// parsePageParams reads the "page" and "pageSize" query parameters. paged is
+// false when neither is set. A missing page means the first page and a
+// missing pageSize means defaultServicePageSize.
+func parsePageParams(query url.Values) (page, pageSize int, paged bool, err error) {
+	pageParam, sizeParam := query.Get("page"), query.Get("pageSize")
+	if pageParam == "" && sizeParam == "" {
+		return 0, 0, false, nil
+	}
+
+	page, pageSize = 1, defaultServicePageSize
+	if pageParam != "" {
+		page, err = strconv.Atoi(pageParam)
+		if err != nil || page < 1 {
+			return 0, 0, false, fmt.Errorf("invalid page %q, must be a positive integer", pageParam)
+		}
+	}
+	if sizeParam != "" {
+		pageSize, err = strconv.Atoi(sizeParam)
+		if err != nil || pageSize < 1 || pageSize > maxServicePageSize {
+			return 0, 0, false, fmt.Errorf("invalid pageSize %q, must be between 1 and %d", sizeParam, maxServicePageSize)
+		}
+	}
+	return page, pageSize, true, nil
+}
+
+// paginateServices slices services, already sorted, to the given page. A
+// page past the end is empty.
+func paginateServices(services []docs.ServiceSummary, page, pageSize int) *ServicePage {
+	result := &ServicePage{
+		Services:   []ServiceEntry{},
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (len(services) + pageSize - 1) / pageSize,
+		Total:      len(services),
+	}
+
+	start := (page - 1) * pageSize
+	if start >= len(services) {
+		return result
+	}
+	end := min(start+pageSize, len(services))
+	for _, service := range services[start:end] {
+		result.Services = append(result.Services, ServiceEntry{
+			Name:     service.Name,
+			FullName: service.FullName,
+			Package:  service.Package,
+			Comment:  service.Comment,
+			Internal: service.Internal,
+		})
+	}
+	return result
+}
+
+// handleServices handles GET /api/services requests, returning one page of
+// the services shown on the index. Without page parameters every service is
+// returned as a single page.
+func (s *Server) handleServices(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, paged, err := parsePageParams(r.URL.Query())
+	if err != nil {
+		s.writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	registry, _ := s.getRegistry()
+	index, err := s.buildIndex(registry)
+	if err != nil {
+		s.writeJSONError(w, http.StatusInternalServerError, fmt.Sprintf("failed to build index: %v", err))
+		return
+	}
+	if !paged {
+		page, pageSize = 1, max(len(index.Services), 1)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(paginateServices(index.Services, page, pageSize)); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+)
+
+func TestServicesPagination(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	srv, err := New(reg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	get := func(t *testing.T, target string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) ServicePage {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var page ServicePage
+		if err := json.NewDecoder(w.Body).Decode(&page); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return page
+	}
+
+	all := decode(t, get(t, "/api/services"))
+	total := len(reg.ServicesByName)
+	if total < 3 {
+		t.Fatalf("Test data needs at least 3 services, has %d", total)
+	}
+	if len(all.Services) != total || all.Total != total || all.TotalPages != 1 {
+		t.Fatalf("Expected all %d services on one page, got %+v", total, all)
+	}
+
+	t.Run("page boundaries", func(t *testing.T) {
+		pageSize := total - 1
+		first := decode(t, get(t, "/api/services?page=1&pageSize="+strconv.Itoa(pageSize)))
+		last := decode(t, get(t, "/api/services?page=2&pageSize="+strconv.Itoa(pageSize)))
+
+		if first.TotalPages != 2 || last.TotalPages != 2 {
+			t.Errorf("Expected 2 pages, got %d and %d", first.TotalPages, last.TotalPages)
+		}
+		if len(first.Services) != pageSize || len(last.Services) != 1 {
+			t.Fatalf("Expected pages of %d and 1 services, got %d and %d", pageSize, len(first.Services), len(last.Services))
+		}
+		if first.Services[0].FullName != all.Services[0].FullName || last.Services[0].FullName != all.Services[total-1].FullName {
+			t.Errorf("Expected pages to slice the sorted services in order")
+		}
+	})
+
+	t.Run("page size defaults", func(t *testing.T) {
+		page := decode(t, get(t, "/api/services?page=1"))
+		if page.PageSize != defaultServicePageSize {
+			t.Errorf("Expected default page size %d, got %d", defaultServicePageSize, page.PageSize)
+		}
+	})
+
+	t.Run("out of range page", func(t *testing.T) {
+		page := decode(t, get(t, "/api/services?page=99&pageSize=2"))
+		if len(page.Services) != 0 || page.Total != total {
+			t.Errorf("Expected an empty page with total %d, got %+v", total, page)
+		}
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		for _, query := range []string{"page=0", "page=abc", "pageSize=0", "pageSize=100000"} {
+			if w := get(t, "/api/services?"+query); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for %s, got %d", query, w.Code)
+			}
+			if w := get(t, "/?"+query); w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400 for home page %s, got %d", query, w.Code)
+			}
+		}
+	})
+
+	t.Run("home page", func(t *testing.T) {
+		body := get(t, "/").Body.String()
+		if strings.Contains(body, "Next →") {
+			t.Error("Expected no pager without page params")
+		}
+
+		body = get(t, "/?page=1&pageSize=1").Body.String()
+		if !strings.Contains(body, "Page 1 of "+strconv.Itoa(total)) || !strings.Contains(body, "/?page=2&pageSize=1") {
+			t.Errorf("Expected a pager on the first page, got %s", body)
+		}
+		if strings.Contains(body, "← Previous") {
+			t.Error("Expected no previous link on the first page")
+		}
+
+		body = get(t, "/?page="+strconv.Itoa(total)+"&pageSize=1").Body.String()
+		if !strings.Contains(body, "← Previous") || strings.Contains(body, "Next →") {
+			t.Error("Expected only a previous link on the last page")
+		}
+	})
+}
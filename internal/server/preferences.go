@@ -0,0 +1,73 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bnprtr/reflect/internal/config"
+)
+
+// colorModeCookie remembers the visitor's chosen color mode.
+const colorModeCookie = "reflect-color-mode"
+
+// colorModeCookieMaxAge keeps the chosen color mode for a year.
+const colorModeCookieMaxAge = 365 * 24 * time.Hour
+
+// Preferences represents the JSON body of GET and POST /api/preferences.
+type Preferences struct {
+	// ColorMode is "light", "dark", or "system".
+	ColorMode string `json:"colorMode"`
+}
+
+// colorMode returns the visitor's color mode: the cookie if it holds a valid
+// mode, otherwise the configured default.
+func (s *Server) colorMode(r *http.Request) string {
+	if cookie, err := r.Cookie(colorModeCookie); err == nil && config.ValidColorMode(cookie.Value) {
+		return cookie.Value
+	}
+	if s.config != nil && config.ValidColorMode(s.config.DefaultColorMode) {
+		return s.config.DefaultColorMode
+	}
+	return config.DefaultColorMode
+}
+
+// handleGetPreferences handles GET /api/preferences requests.
+func (s *Server) handleGetPreferences(w http.ResponseWriter, r *http.Request) {
+	s.writePreferences(w, Preferences{ColorMode: s.colorMode(r)})
+}
+
+// handleSetPreferences handles POST /api/preferences requests, storing the
+// chosen color mode in a cookie.
+func (s *Server) handleSetPreferences(w http.ResponseWriter, r *http.Request) {
+	var prefs Preferences
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&prefs); err != nil {
+		s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if !config.ValidColorMode(prefs.ColorMode) {
+		s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid colorMode %q, must be one of: light, dark, system", prefs.ColorMode))
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     colorModeCookie,
+		Value:    prefs.ColorMode,
+		Path:     "/",
+		MaxAge:   int(colorModeCookieMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	s.writePreferences(w, prefs)
+}
+
+// writePreferences writes prefs as a JSON response.
+func (s *Server) writePreferences(w http.ResponseWriter, prefs Preferences) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(prefs); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/server/theme"
+)
+
+func TestPreferences(t *testing.T) {
+	srv, err := NewWithTheme(nil, theme.GetDefaultTheme(), &config.Config{DefaultColorMode: "dark"})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	do := func(method, target, body string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+	colorMode := func(t *testing.T, w *httptest.ResponseRecorder) string {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var prefs Preferences
+		if err := json.NewDecoder(w.Body).Decode(&prefs); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return prefs.ColorMode
+	}
+
+	t.Run("config default", func(t *testing.T) {
+		if got := colorMode(t, do(http.MethodGet, "/api/preferences", "")); got != "dark" {
+			t.Errorf("Expected color mode dark, got %q", got)
+		}
+		if body := do(http.MethodGet, "/", "").Body.String(); !strings.Contains(body, `class="scroll-smooth dark" data-color-mode="dark"`) {
+			t.Error("Expected the page to render in dark mode")
+		}
+	})
+
+	t.Run("cookie round-trip", func(t *testing.T) {
+		w := do(http.MethodPost, "/api/preferences", `{"colorMode": "light"}`)
+		if got := colorMode(t, w); got != "light" {
+			t.Errorf("Expected color mode light, got %q", got)
+		}
+		cookies := w.Result().Cookies()
+		if len(cookies) != 1 || cookies[0].Name != colorModeCookie || cookies[0].Value != "light" {
+			t.Fatalf("Expected a %s=light cookie, got %v", colorModeCookie, cookies)
+		}
+
+		if got := colorMode(t, do(http.MethodGet, "/api/preferences", "", cookies[0])); got != "light" {
+			t.Errorf("Expected the cookie's color mode light, got %q", got)
+		}
+		if body := do(http.MethodGet, "/", "", cookies[0]).Body.String(); !strings.Contains(body, `data-color-mode="light"`) || strings.Contains(body, "scroll-smooth dark") {
+			t.Error("Expected the page to render in light mode")
+		}
+
+		// Pages differ by color mode, so their ETags must too
+		if do(http.MethodGet, "/", "").Header().Get("ETag") == do(http.MethodGet, "/", "", cookies[0]).Header().Get("ETag") {
+			t.Error("Expected different ETags for different color modes")
+		}
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		if w := do(http.MethodPost, "/api/preferences", `{"colorMode": "sepia"}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d", w.Code)
+		}
+		bad := &http.Cookie{Name: colorModeCookie, Value: "sepia"}
+		if got := colorMode(t, do(http.MethodGet, "/api/preferences", "", bad)); got != "dark" {
+			t.Errorf("Expected an invalid cookie to fall back to dark, got %q", got)
+		}
+	})
+}
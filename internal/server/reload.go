@@ -0,0 +1,81 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+)
+
+// ReloadResponse represents the JSON response for the /api/reload endpoint.
+type ReloadResponse struct {
+	// Files is the number of loaded .proto files, including imports.
+	Files int `json:"files"`
+
+	// Services, Methods, Messages, and Enums count the loaded symbols.
+	Services int `json:"services"`
+	Methods  int `json:"methods"`
+	Messages int `json:"messages"`
+	Enums    int `json:"enums"`
+
+	// Skipped lists the files a best-effort load could not parse.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// SetLoadOptions records the proto root and options the registry was loaded
+// with, so POST /api/reload can load it again. It must be called before the
+// server handles requests.
+func (s *Server) SetLoadOptions(root string, opts descriptor.LoadOptions) {
+	s.loadRoot = root
+	s.loadOpts = opts
+}
+
+// handleReload handles POST /api/reload requests, reloading the proto files
+// from disk. A load that fails is reported and not applied, so the previous
+// registry keeps being served.
+func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.config.ReloadToken)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		s.writeJSONError(w, http.StatusUnauthorized, "A valid reload token is required")
+		return
+	}
+	if s.loadRoot == "" {
+		s.writeJSONError(w, http.StatusConflict, "Reload is not available: the descriptors were not loaded from a proto root")
+		return
+	}
+
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	registry, err := descriptor.LoadDirectoryWithOptions(r.Context(), s.loadRoot, s.loadOpts)
+	if err != nil {
+		err = fmt.Errorf("failed to reload proto files in %s: %w", s.loadRoot, err)
+		s.SetReloadError("proto", err)
+		s.writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.SetRegistry(registry)
+	s.SetReloadError("proto", nil)
+	s.logger.Info("Proto files reloaded via API", "path", s.loadRoot)
+
+	resp := ReloadResponse{
+		Files:    registry.Files.NumFiles(),
+		Services: len(registry.ServicesByName),
+		Methods:  len(registry.MethodsByName),
+		Messages: len(registry.MessagesByName),
+		Enums:    len(registry.EnumsByName),
+	}
+	for _, loadErr := range registry.LoadErrors {
+		resp.Skipped = append(resp.Skipped, loadErr.File)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
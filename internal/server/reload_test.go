@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/descriptor"
+	"github.com/bnprtr/reflect/internal/server/theme"
+)
+
+func TestHandleReload(t *testing.T) {
+	root := t.TempDir()
+	writeProto := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(root, "greeter.proto"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write proto file: %v", err)
+		}
+	}
+	writeProto("syntax = \"proto3\";\npackage greeter.v1;\nservice Greeter { rpc Greet(Hello) returns (Hello); }\nmessage Hello { string name = 1; }\n")
+
+	opts := descriptor.LoadOptions{}
+	reg, err := descriptor.LoadDirectoryWithOptions(context.Background(), root, opts)
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), &config.Config{ReloadToken: "s3cret"})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	srv.SetLoadOptions(root, opts)
+
+	reload := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/reload", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("requires the token", func(t *testing.T) {
+		for _, token := range []string{"", "wrong"} {
+			if w := reload(token); w.Code != http.StatusUnauthorized {
+				t.Errorf("Token %q: expected status 401, got %d", token, w.Code)
+			}
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		writeProto("syntax = \"proto3\";\npackage greeter.v1;\nservice Greeter {\n  rpc Greet(Hello) returns (Hello);\n  rpc Wave(Hello) returns (Hello);\n}\nmessage Hello { string name = 1; Mood mood = 2; }\nenum Mood { MOOD_UNSPECIFIED = 0; }\n")
+
+		w := reload("s3cret")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp ReloadResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		want := ReloadResponse{Services: 1, Methods: 2, Messages: 1, Enums: 1}
+		if resp.Services != want.Services || resp.Methods != want.Methods || resp.Messages != want.Messages || resp.Enums != want.Enums {
+			t.Errorf("Expected counts %+v, got %+v", want, resp)
+		}
+
+		registry, _ := srv.getRegistry()
+		if _, ok := registry.FindMethod("greeter.v1.Greeter/Wave"); !ok {
+			t.Error("Expected the reloaded registry to be served")
+		}
+	})
+
+	t.Run("failure keeps the previous registry", func(t *testing.T) {
+		writeProto("syntax = \"proto3\";\npackage greeter.v1;\nmessage Hello { string name = 1 }\n")
+
+		w := reload("s3cret")
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("Expected status 500, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "greeter.proto:3") {
+			t.Errorf("Expected the parse error in the response, got %s", w.Body.String())
+		}
+
+		registry, _ := srv.getRegistry()
+		if _, ok := registry.FindMethod("greeter.v1.Greeter/Wave"); !ok {
+			t.Error("Expected the previous registry to still be served")
+		}
+		if errs := srv.getReloadErrors(); len(errs) != 1 {
+			t.Errorf("Expected the failure in the reload banner, got %v", errs)
+		}
+	})
+
+	t.Run("needs a proto root", func(t *testing.T) {
+		srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), &config.Config{ReloadToken: "s3cret"})
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+		req := httptest.NewRequest("POST", "/api/reload", nil)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		if w.Code != http.StatusConflict {
+			t.Errorf("Expected status 409, got %d", w.Code)
+		}
+	})
+
+	t.Run("disabled without a token", func(t *testing.T) {
+		srv, err := New(reg)
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+		srv.SetLoadOptions(root, opts)
+		req := httptest.NewRequest("POST", "/api/reload", nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound && w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("Expected the endpoint to be disabled, got status %d", w.Code)
+		}
+	})
+}
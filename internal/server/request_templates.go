@@ -0,0 +1,329 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/tryit"
+	"github.com/go-chi/chi/v5"
+)
+
+// requestTemplatesFile is the file, under the configured templates directory,
+// that holds the saved request templates.
+const requestTemplatesFile = "tryit_templates.json"
+
+// maxRequestTemplateNameLength bounds template names, which appear in URLs.
+const maxRequestTemplateNameLength = 100
+
+// RequestTemplate is a named, saved "Try It" request.
+type RequestTemplate struct {
+	// Name identifies the template; names are unique.
+	Name string `json:"name"`
+
+	// Method is the full method name (e.g., "echo.v1.EchoService/Echo").
+	Method string `json:"method"`
+
+	// Body is the JSON request body.
+	Body string `json:"body"`
+
+	// HeaderKeys lists the names of headers to send. Header values are never
+	// stored, since they often hold credentials.
+	HeaderKeys []string `json:"headerKeys,omitempty"`
+
+	// CreatedAt is when the template was saved.
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// errDuplicateTemplate is returned when saving a template under a taken name.
+var errDuplicateTemplate = errors.New("a template with this name already exists")
+
+// requestTemplateStore persists request templates as a JSON file.
+type requestTemplateStore struct {
+	path string
+	mu   sync.Mutex // Serializes reads and writes of the file
+}
+
+// newRequestTemplateStore returns a store in dir, creating dir if needed.
+func newRequestTemplateStore(dir string) (*requestTemplateStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create templates directory: %w", err)
+	}
+	return &requestTemplateStore{path: filepath.Join(dir, requestTemplatesFile)}, nil
+}
+
+// List returns all templates sorted by name.
+func (s *requestTemplateStore) List() ([]RequestTemplate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+// Get returns the template with the given name.
+func (s *requestTemplateStore) Get(name string) (RequestTemplate, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	templates, err := s.read()
+	if err != nil {
+		return RequestTemplate{}, false, err
+	}
+	for _, template := range templates {
+		if template.Name == name {
+			return template, true, nil
+		}
+	}
+	return RequestTemplate{}, false, nil
+}
+
+// Save adds a template, failing with errDuplicateTemplate if the name is taken.
+func (s *requestTemplateStore) Save(template RequestTemplate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	templates, err := s.read()
+	if err != nil {
+		return err
+	}
+	for _, existing := range templates {
+		if existing.Name == template.Name {
+			return errDuplicateTemplate
+		}
+	}
+
+	templates = append(templates, template)
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return s.write(templates)
+}
+
+// read loads the templates file; a missing file holds no templates.
+func (s *requestTemplateStore) read() ([]RequestTemplate, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []RequestTemplate{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read templates: %w", err)
+	}
+
+	templates := []RequestTemplate{}
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("parse templates file %q: %w", s.path, err)
+	}
+	return templates, nil
+}
+
+// write replaces the templates file through a temporary file, so a failed
+// write never leaves it truncated.
+func (s *requestTemplateStore) write(templates []RequestTemplate) error {
+	data, err := json.MarshalIndent(templates, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), requestTemplatesFile+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("write templates: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write templates: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write templates: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("write templates: %w", err)
+	}
+	return nil
+}
+
+// SaveTemplateRequest represents the JSON request body for POST /api/tryit/templates.
+type SaveTemplateRequest struct {
+	// Name identifies the template.
+	Name string `json:"name"`
+
+	// Method is the full method name (e.g., "echo.v1.EchoService/Echo").
+	Method string `json:"method"`
+
+	// Body is the JSON request body, either as a JSON value or as a string
+	// containing JSON.
+	Body json.RawMessage `json:"body"`
+
+	// HeaderKeys are the names of headers to send with the request.
+	HeaderKeys []string `json:"headerKeys,omitempty"`
+
+	// Headers may be sent instead of HeaderKeys; only their names are kept.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// handleListRequestTemplates handles GET /api/tryit/templates requests. If the
+// "method" query parameter is set, only templates for that method are listed.
+func (s *Server) handleListRequestTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := s.reqTemplates.List()
+	if err != nil {
+		s.writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if method := r.URL.Query().Get("method"); method != "" {
+		filtered := []RequestTemplate{}
+		for _, template := range templates {
+			if template.Method == method {
+				filtered = append(filtered, template)
+			}
+		}
+		templates = filtered
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{"templates": templates}); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleGetRequestTemplate handles GET /api/tryit/templates/{name} requests.
+func (s *Server) handleGetRequestTemplate(w http.ResponseWriter, r *http.Request) {
+	name, err := url.PathUnescape(chi.URLParam(r, "name"))
+	if err != nil {
+		s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid template name: %v", err))
+		return
+	}
+
+	template, found, err := s.reqTemplates.Get(name)
+	if err != nil {
+		s.writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !found {
+		s.writeJSONError(w, http.StatusNotFound, fmt.Sprintf("template %q not found", name))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(template); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// handleSaveRequestTemplate handles POST /api/tryit/templates requests. The
+// method must exist and the body must parse as its input message; names must
+// be unique.
+func (s *Server) handleSaveRequestTemplate(w http.ResponseWriter, r *http.Request) {
+	maxBytes := int64(config.DefaultMaxRequestBodyBytes)
+	if s.config.MaxRequestBodyBytes > 0 {
+		maxBytes = s.config.MaxRequestBodyBytes
+	}
+
+	var req SaveTemplateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBytes)).Decode(&req); err != nil {
+		s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		s.writeJSONError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if len(name) > maxRequestTemplateNameLength {
+		s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("name must be at most %d characters", maxRequestTemplateNameLength))
+		return
+	}
+	if req.Method == "" {
+		s.writeJSONError(w, http.StatusBadRequest, "method is required")
+		return
+	}
+
+	registry, _ := s.getRegistry()
+	if registry == nil {
+		s.writeJSONError(w, http.StatusServiceUnavailable, "No protobuf descriptors loaded")
+		return
+	}
+	method, exists := registry.FindMethod(req.Method)
+	if !exists || s.isHidden(registry, req.Method) {
+		s.writeJSONError(w, http.StatusNotFound, fmt.Sprintf("method %q not found", req.Method))
+		return
+	}
+
+	// Accept the body either inline or as a JSON-encoded string
+	body := string(req.Body)
+	var bodyString string
+	if err := json.Unmarshal(req.Body, &bodyString); err == nil {
+		body = bodyString
+	} else if body == "null" {
+		body = ""
+	}
+
+	if method.IsStreamingClient() {
+		_, err := tryit.ParseJSONMessages(method.Input(), body, registry.Resolver())
+		if err != nil {
+			s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+			return
+		}
+	} else if _, err := tryit.ParseJSONBody(method.Input(), body, registry.Resolver()); err != nil {
+		s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return
+	}
+
+	template := RequestTemplate{
+		Name:       name,
+		Method:     req.Method,
+		Body:       body,
+		HeaderKeys: templateHeaderKeys(req.HeaderKeys, req.Headers),
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := s.reqTemplates.Save(template); err != nil {
+		if errors.Is(err, errDuplicateTemplate) {
+			s.writeJSONError(w, http.StatusConflict, fmt.Sprintf("template %q already exists", name))
+			return
+		}
+		s.writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(template); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// templateHeaderKeys merges header names from keys and headers, dropping
+// values, blanks, and case-insensitive duplicates, sorted for stable output.
+func templateHeaderKeys(keys []string, headers map[string]string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	add := func(key string) {
+		key = strings.TrimSpace(key)
+		if key == "" || seen[strings.ToLower(key)] {
+			return
+		}
+		seen[strings.ToLower(key)] = true
+		result = append(result, key)
+	}
+	for _, key := range keys {
+		add(key)
+	}
+	for key := range headers {
+		add(key)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/descriptor"
+	"github.com/bnprtr/reflect/internal/server/theme"
+)
+
+func TestRequestTemplates(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "templates")
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), &config.Config{TemplatesDir: dir})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	// Save
+	saves := []struct {
+		name           string
+		requestBody    string
+		expectedStatus int
+	}{
+		{
+			name:           "valid template",
+			requestBody:    `{"name": "get alice", "method": "users.v1.UserService/GetUser", "body": {"userId": "alice"}, "headers": {"Authorization": "Bearer secret"}}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "body as string",
+			requestBody:    `{"name": "create", "method": "users.v1.UserService/CreateUser", "body": "{}", "headerKeys": ["X-Request-Id"]}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "duplicate name",
+			requestBody:    `{"name": "get alice", "method": "users.v1.UserService/GetUser", "body": {}}`,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "missing name",
+			requestBody:    `{"method": "users.v1.UserService/GetUser"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown method",
+			requestBody:    `{"name": "nope", "method": "users.v1.UserService/Nope"}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid body",
+			requestBody:    `{"name": "bad", "method": "users.v1.UserService/GetUser", "body": {"nickname": "al"}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range saves {
+		t.Run("save "+tc.name, func(t *testing.T) {
+			w := do(http.MethodPost, "/api/tryit/templates", tc.requestBody)
+			if w.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	// Header values must never reach disk
+	data, err := os.ReadFile(filepath.Join(dir, requestTemplatesFile))
+	if err != nil {
+		t.Fatalf("Failed to read templates file: %v", err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("Templates file contains a header value: %s", data)
+	}
+
+	t.Run("list", func(t *testing.T) {
+		w := do(http.MethodGet, "/api/tryit/templates", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Templates []RequestTemplate `json:"templates"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if len(resp.Templates) != 2 || resp.Templates[0].Name != "create" || resp.Templates[1].Name != "get alice" {
+			t.Errorf("Expected templates [create, get alice], got %+v", resp.Templates)
+		}
+
+		w = do(http.MethodGet, "/api/tryit/templates?method=users.v1.UserService/GetUser", "")
+		resp.Templates = nil
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if len(resp.Templates) != 1 || resp.Templates[0].Name != "get alice" {
+			t.Errorf("Expected only the GetUser template, got %+v", resp.Templates)
+		}
+	})
+
+	t.Run("load", func(t *testing.T) {
+		w := do(http.MethodGet, "/api/tryit/templates/get%20alice", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var template RequestTemplate
+		if err := json.NewDecoder(w.Body).Decode(&template); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if template.Method != "users.v1.UserService/GetUser" {
+			t.Errorf("Expected method users.v1.UserService/GetUser, got %q", template.Method)
+		}
+		if template.Body != `{"userId": "alice"}` {
+			t.Errorf("Expected body to round-trip, got %q", template.Body)
+		}
+		if len(template.HeaderKeys) != 1 || template.HeaderKeys[0] != "Authorization" {
+			t.Errorf("Expected header keys [Authorization], got %v", template.HeaderKeys)
+		}
+
+		w = do(http.MethodGet, "/api/tryit/templates/missing", "")
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for a missing template, got %d", w.Code)
+		}
+	})
+
+	t.Run("disabled without a directory", func(t *testing.T) {
+		srv, err := New(reg)
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/api/tryit/templates", nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		if w.Code == http.StatusOK {
+			t.Errorf("Expected templates API to be disabled, got status %d", w.Code)
+		}
+	})
+}
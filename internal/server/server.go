@@ -1,18 +1,28 @@
 package server
 
 import (
+	"context"
+	"crypto/rand"
 	"embed"
+	"encoding/hex"
+	"fmt"
 	"io/fs"
+	"log/slog"
 	"net/http"
+	"runtime/debug"
+	"sort"
 	"strings"
 	"sync"
 	"text/template"
+	"time"
 
 	"github.com/bnprtr/reflect/internal/config"
 	"github.com/bnprtr/reflect/internal/descriptor"
 	"github.com/bnprtr/reflect/internal/docs"
 	"github.com/bnprtr/reflect/internal/server/theme"
+	"github.com/bnprtr/reflect/internal/tryit"
 	"github.com/go-chi/chi/v5"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 //go:embed templates/*.html templates/partials/*.html static/*.css static/*.js
@@ -22,13 +32,26 @@ var templatesFS embed.FS
 var staticFS embed.FS
 
 type Server struct {
-	router      *chi.Mux
-	templates   *template.Template
-	registry    *descriptor.Registry
-	searchIndex *docs.SearchIndex
-	theme       *theme.Theme
-	config      *config.Config
-	mu          sync.RWMutex // Protects registry and searchIndex during hot reload
+	router       *chi.Mux
+	templates    *template.Template
+	registry     *descriptor.Registry
+	searchIndex  *docs.SearchIndex
+	theme        *theme.Theme
+	config       *config.Config
+	svcConfig    *docs.ServiceConfig
+	metrics      *serverMetrics               // nil unless metrics are enabled
+	tokenSources map[string]tryit.TokenSource // Per-environment auth, shared so tokens stay cached
+	reqTemplates *requestTemplateStore        // nil unless a templates directory is configured
+	logger       *slog.Logger                 // Request logging, slog.Default() unless replaced by SetLogger
+	views        *viewCache                   // Built service and message views, cleared on hot reload
+	regVersion   uint64                       // Incremented each time the registry is replaced
+	themeVersion uint64                       // Incremented each time the theme is replaced
+	reloadErrors map[string]error             // Failed hot reloads by source, until one succeeds
+	errVersion   uint64                       // Incremented each time reloadErrors changes
+	loadRoot     string                       // Proto root POST /api/reload loads from; empty disables it
+	loadOpts     descriptor.LoadOptions       // Options POST /api/reload loads with
+	reloadMu     sync.Mutex                   // Serializes POST /api/reload
+	mu           sync.RWMutex                 // Protects registry, searchIndex, theme, reloadErrors, and their versions during hot reload
 }
 
 func New(registry *descriptor.Registry) (*Server, error) {
@@ -40,34 +63,154 @@ func NewWithTheme(registry *descriptor.Registry, themeConfig *theme.Theme, cfg *
 		"contains": func(s, substr string) bool {
 			return strings.Contains(s, substr)
 		},
+		"firstLine": func(s string) string {
+			line, _, _ := strings.Cut(s, "\n")
+			return line
+		},
+		// comment renders a proto comment as HTML: Markdown unless plain
+		// comments are configured, escaped either way
+		"comment": func(s string) string {
+			if cfg != nil && cfg.PlainComments {
+				return string(docs.PlainComment(s))
+			}
+			return string(docs.RenderComment(s))
+		},
+		// highlight renders a search snippet with its matches marked
+		"highlight": func(s string) string {
+			return string(docs.HighlightSnippet(s))
+		},
 	}).ParseFS(templatesFS, "templates/*.html", "templates/partials/*.html")
 	if err != nil {
 		return nil, err
 	}
 
 	r := chi.NewRouter()
+	s := &Server{router: r, templates: t, theme: themeConfig, config: cfg, logger: slog.Default(), views: newViewCache(maxCachedViews)}
+
+	// Access logging is outermost so it times and sees the whole response,
+	// including the 500 written when a handler panics
+	r.Use(s.accessLog)
+	r.Use(s.recoverPanics)
+
+	// Credentials are checked before any page or API is served
+	if cfg != nil && cfg.UI.Auth.Type != "" {
+		r.Use(s.requireAuth)
+	}
+
+	// Metrics wrap compression so they see the status the handler wrote
+	if cfg != nil && cfg.Metrics {
+		s.metrics = newServerMetrics()
+		r.Use(s.metrics.instrument)
+	}
+	r.Use(compress)
+
 	// Static assets
 	staticSub, _ := fs.Sub(staticFS, "static")
-	r.Handle("/static/*", http.StripPrefix("/static/", http.FileServer(http.FS(staticSub))))
+	staticTags, err := staticETags(staticSub)
+	if err != nil {
+		return nil, fmt.Errorf("hash static assets: %w", err)
+	}
+	r.Handle("/static/*", http.StripPrefix("/static/", withStaticETags(staticTags, http.FileServer(http.FS(staticSub)))))
+
+	s.registry = s.configureRegistry(registry)
+
+	if cfg != nil && cfg.ServiceConfig != "" {
+		s.svcConfig, err = docs.LoadServiceConfig(cfg.ServiceConfig)
+		if err != nil {
+			return nil, fmt.Errorf("load service config: %w", err)
+		}
+	}
+
+	s.tokenSources, err = newTokenSources(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg != nil && cfg.TemplatesDir != "" {
+		s.reqTemplates, err = newRequestTemplateStore(cfg.TemplatesDir)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Build search index
-	searchIndex := docs.BuildSearchIndex(registry)
+	s.searchIndex = s.buildSearchIndex(s.registry)
 
-	s := &Server{router: r, templates: t, registry: registry, searchIndex: searchIndex, theme: themeConfig, config: cfg}
 	s.routes()
 	return s, nil
 }
 
 // SetRegistry atomically updates the registry and rebuilds the search index
 func (s *Server) SetRegistry(registry *descriptor.Registry) {
-	searchIndex := docs.BuildSearchIndex(registry)
+	registry = s.configureRegistry(registry)
+	searchIndex := s.buildSearchIndex(registry)
 
 	s.mu.Lock()
 	s.registry = registry
 	s.searchIndex = searchIndex
+	s.regVersion++
+	s.views.clear()
 	s.mu.Unlock()
 }
 
+// SetTheme atomically replaces the active theme
+func (s *Server) SetTheme(t *theme.Theme) {
+	s.mu.Lock()
+	s.theme = t
+	s.themeVersion++
+	s.mu.Unlock()
+}
+
+// SetReloadError records the outcome of a hot reload of source (e.g.
+// "proto" or "theme"). A non-nil error is shown in a banner on every page
+// while the server keeps serving what it last loaded; nil clears it.
+func (s *Server) SetReloadError(source string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		if _, ok := s.reloadErrors[source]; !ok {
+			return
+		}
+		delete(s.reloadErrors, source)
+	} else {
+		if s.reloadErrors == nil {
+			s.reloadErrors = make(map[string]error)
+		}
+		s.reloadErrors[source] = err
+	}
+	s.errVersion++
+}
+
+// getReloadErrors returns the messages of the failed hot reloads, sorted by
+// source.
+func (s *Server) getReloadErrors() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sources := make([]string, 0, len(s.reloadErrors))
+	for source := range s.reloadErrors {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	messages := make([]string, 0, len(sources))
+	for _, source := range sources {
+		messages = append(messages, s.reloadErrors[source].Error())
+	}
+	return messages
+}
+
+// SetLogger replaces the logger used for request logging, which defaults to
+// slog.Default(). It must be called before the server handles requests.
+func (s *Server) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// getTheme safely retrieves the active theme
+func (s *Server) getTheme() *theme.Theme {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.theme
+}
+
 // getRegistry safely retrieves the current registry
 func (s *Server) getRegistry() (*descriptor.Registry, *docs.SearchIndex) {
 	s.mu.RLock()
@@ -75,7 +218,202 @@ func (s *Server) getRegistry() (*descriptor.Registry, *docs.SearchIndex) {
 	return s.registry, s.searchIndex
 }
 
+// configureRegistry attaches the configured Any type hints, comment option,
+// and method tag option to a registry.
+func (s *Server) configureRegistry(registry *descriptor.Registry) *descriptor.Registry {
+	registry = s.withAnyTypeHints(registry)
+	if registry == nil || s.config == nil {
+		return registry
+	}
+	if s.config.CommentOption != "" {
+		if _, err := registry.Files.FindDescriptorByName(protoreflect.FullName(s.config.CommentOption)); err != nil {
+			s.logger.Warn("Comment option names an unknown extension", "option", s.config.CommentOption)
+		}
+		registry = registry.WithCommentOption(s.config.CommentOption)
+	}
+	if s.config.MethodTagOption != "" {
+		if _, err := registry.Files.FindDescriptorByName(protoreflect.FullName(s.config.MethodTagOption)); err != nil {
+			s.logger.Warn("Method tag option names an unknown extension", "option", s.config.MethodTagOption)
+		}
+		registry = registry.WithTagOption(s.config.MethodTagOption)
+	}
+	if s.config.ExampleOption != "" {
+		if _, err := registry.Files.FindDescriptorByName(protoreflect.FullName(s.config.ExampleOption)); err != nil {
+			s.logger.Warn("Example option names an unknown extension", "option", s.config.ExampleOption)
+		}
+		registry = registry.WithExampleOption(s.config.ExampleOption)
+	}
+	return registry
+}
+
+// withAnyTypeHints attaches the configured google.protobuf.Any type hints to a
+// registry, warning about hints that name unknown messages.
+func (s *Server) withAnyTypeHints(registry *descriptor.Registry) *descriptor.Registry {
+	if registry == nil || s.config == nil || len(s.config.AnyTypeHints) == 0 {
+		return registry
+	}
+	for field, message := range s.config.AnyTypeHints {
+		if _, ok := registry.FindMessage(message); !ok {
+			s.logger.Warn("Any type hint names an unknown message", "field", field, "message", message)
+		}
+	}
+	return registry.WithAnyTypeHints(s.config.AnyTypeHints)
+}
+
+// buildSearchIndex builds a search index honoring the configured visibility.
+func (s *Server) buildSearchIndex(registry *descriptor.Registry) *docs.SearchIndex {
+	return docs.BuildSearchIndexWithOptions(registry, docs.SearchOptions{
+		HideInternal:     s.hideInternal(),
+		CommentSummaries: s.config != nil && s.config.SearchCommentSummaries,
+	})
+}
+
+// hideInternal reports whether internal-only symbols should be hidden.
+func (s *Server) hideInternal() bool {
+	return s.config != nil && s.config.HideInternal
+}
+
+// isHidden reports whether the named symbol should be hidden from the UI.
+func (s *Server) isHidden(registry *descriptor.Registry, fullName string) bool {
+	return s.hideInternal() && registry != nil && registry.IsInternal(fullName)
+}
+
+// buildIndex builds the index view, omitting hidden services and sectioning
+// the rest by the configured service groups.
+func (s *Server) buildIndex(registry *descriptor.Registry) (*docs.Index, error) {
+	opts := docs.IndexOptions{HideInternal: s.hideInternal()}
+	if s.config != nil {
+		opts.ServiceGroups = s.config.ServiceGroups
+	}
+	return docs.BuildIndexWithOptions(registry, opts)
+}
+
 // ServeHTTP implements http.Handler
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.router.ServeHTTP(w, r)
 }
+
+// requestIDHeader carries the request ID on requests and responses.
+const requestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength bounds request IDs accepted from clients.
+const maxRequestIDLength = 128
+
+// requestIDKey is the context key for the request ID.
+type requestIDKey struct{}
+
+// accessLog assigns each request an ID, reusing a well-formed incoming
+// X-Request-Id, and logs one line per request once it completes. The ID is
+// stored in the request context and echoed in the response header.
+func (s *Server) accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		r = r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id))
+
+		sw := &statusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r)
+
+		s.logger.Info("HTTP request",
+			"requestId", id,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", sw.Status(),
+			"durationMs", time.Since(start).Milliseconds())
+	})
+}
+
+// recoverPanics turns a panicking handler into a 500 response instead of a
+// dropped connection: an error page for documentation routes or a JSON error
+// for /api routes. The panic and its stack are logged at error level with the
+// request ID. If the handler had already started its response, only the log
+// is written.
+func (s *Server) recoverPanics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			// The server uses this panic to abort a response deliberately
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			id := requestID(r.Context())
+			s.logger.Error("Handler panic",
+				"requestId", id,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"panic", fmt.Sprint(v),
+				"stack", string(debug.Stack()))
+
+			if sw.status != 0 {
+				return
+			}
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				s.writeJSONError(sw, http.StatusInternalServerError, "internal server error")
+				return
+			}
+			sw.Header().Set("Content-Type", "text/html; charset=utf-8")
+			sw.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(sw, panicPage, id)
+		}()
+		next.ServeHTTP(sw, r)
+	})
+}
+
+// panicPage is the error page for a documentation request whose handler
+// panicked. It does not use the page templates, which may be what failed.
+const panicPage = `<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8">
+    <title>Internal Server Error</title>
+    <link rel="stylesheet" href="/static/app.css" />
+  </head>
+  <body class="bg-gray-50 text-gray-900 p-5">
+    <h1 class="text-2xl font-semibold mb-2">Something went wrong</h1>
+    <p class="mb-2">The server hit an unexpected error rendering this page.</p>
+    <p class="text-sm text-gray-600">Request ID: <code class="font-mono">%s</code></p>
+  </body>
+</html>
+`
+
+// requestID returns the ID assigned to the request by accessLog, or "" if
+// there is none.
+func requestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// newRequestID returns a random 16-character hex request ID.
+func newRequestID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// validRequestID reports whether a client-supplied request ID is safe to
+// reuse: non-empty, bounded, and limited to letters, digits, and "-_.:", so it
+// needs no escaping in logs, headers, or pages.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-' || c == '_' || c == '.' || c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+)
+
+func TestAccessLogRequestID(t *testing.T) {
+	srv, err := New(nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	handler := &recordingHandler{}
+	srv.SetLogger(slog.New(handler))
+
+	t.Run("incoming ID round-trips", func(t *testing.T) {
+		handler.reset()
+		req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+		req.Header.Set("X-Request-Id", "abc-123")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if got := w.Header().Get("X-Request-Id"); got != "abc-123" {
+			t.Errorf("Expected X-Request-Id abc-123, got %q", got)
+		}
+		records := handler.snapshot()
+		if len(records) != 1 {
+			t.Fatalf("Expected 1 log record, got %d", len(records))
+		}
+
+		attrs := make(map[string]string)
+		records[0].Attrs(func(attr slog.Attr) bool {
+			attrs[attr.Key] = attr.Value.String()
+			return true
+		})
+		want := map[string]string{
+			"requestId": "abc-123",
+			"method":    "GET",
+			"path":      "/healthz",
+			"status":    "200",
+		}
+		for key, value := range want {
+			if attrs[key] != value {
+				t.Errorf("Expected attribute %s=%s, got %q", key, value, attrs[key])
+			}
+		}
+		if _, ok := attrs["durationMs"]; !ok {
+			t.Error("Expected a durationMs attribute")
+		}
+	})
+
+	t.Run("generated when missing or malformed", func(t *testing.T) {
+		for _, incoming := range []string{"", "bad id\nforged", strings.Repeat("a", maxRequestIDLength+1)} {
+			req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+			if incoming != "" {
+				req.Header.Set("X-Request-Id", incoming)
+			}
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+
+			got := w.Header().Get("X-Request-Id")
+			if got == incoming || !validRequestID(got) {
+				t.Errorf("Expected a generated request ID for %q, got %q", incoming, got)
+			}
+		}
+	})
+
+	t.Run("JSON errors carry the ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/validate", strings.NewReader(`{}`))
+		req.Header.Set("X-Request-Id", "err-42")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		var resp struct {
+			Error struct {
+				RequestID string `json:"requestId"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if resp.Error.RequestID != "err-42" {
+			t.Errorf("Expected error requestId err-42, got %q", resp.Error.RequestID)
+		}
+	})
+}
+
+func TestRecoverPanics(t *testing.T) {
+	srv, err := New(nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	handler := &recordingHandler{}
+	srv.SetLogger(slog.New(handler))
+
+	boom := func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}
+	srv.router.Get("/panic", boom)
+	srv.router.Get("/api/panic", boom)
+
+	// A real server, so a panic that escaped would reset the connection
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	tests := []struct {
+		path        string
+		contentType string
+		body        string
+	}{
+		{"/panic", "text/html", "Something went wrong"},
+		{"/api/panic", "application/json", `"requestId":"panic-1"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			handler.reset()
+			req, err := http.NewRequest(http.MethodGet, ts.URL+tt.path, nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+			req.Header.Set("X-Request-Id", "panic-1")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Failed to read response: %v", err)
+			}
+
+			if resp.StatusCode != http.StatusInternalServerError {
+				t.Errorf("Expected status 500, got %d", resp.StatusCode)
+			}
+			if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, tt.contentType) {
+				t.Errorf("Expected content type %s, got %q", tt.contentType, got)
+			}
+			if !strings.Contains(string(body), tt.body) {
+				t.Errorf("Expected body to contain %q, got %s", tt.body, body)
+			}
+
+			records := handler.snapshot()
+			var panicRecord *slog.Record
+			for i := range records {
+				if records[i].Message == "Handler panic" {
+					panicRecord = &records[i]
+				}
+			}
+			if panicRecord == nil {
+				t.Fatal("Expected the panic to be logged")
+			}
+			if panicRecord.Level != slog.LevelError {
+				t.Errorf("Expected level ERROR, got %v", panicRecord.Level)
+			}
+			attrs := make(map[string]string)
+			panicRecord.Attrs(func(attr slog.Attr) bool {
+				attrs[attr.Key] = attr.Value.String()
+				return true
+			})
+			if attrs["requestId"] != "panic-1" || attrs["panic"] != "boom" || !strings.Contains(attrs["stack"], "TestRecoverPanics") {
+				t.Errorf("Expected request ID, panic value, and stack in the log, got %v", attrs)
+			}
+		})
+	}
+}
+
+func TestSetReloadError(t *testing.T) {
+	reg, err := descriptor.LoadDirectory(context.Background(), filepath.Join("..", "descriptor", "testdata", "basic"), []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	srv, err := New(reg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		return w
+	}
+	before := get()
+	if strings.Contains(before.Body.String(), `role="alert"`) {
+		t.Fatal("Expected no reload banner before a failed reload")
+	}
+
+	// A failed reload keeps the previous registry and shows the error
+	srv.SetReloadError("proto", errors.New("failed to reload proto files: echo.proto:3:1: syntax error"))
+	w := get()
+	body := w.Body.String()
+	if !strings.Contains(body, `role="alert"`) || !strings.Contains(body, "echo.proto:3:1: syntax error") {
+		t.Error("Expected the reload error in a banner")
+	}
+	if !strings.Contains(body, "echo.v1.EchoService") {
+		t.Error("Expected the previous registry to still be served")
+	}
+	if w.Header().Get("ETag") == before.Header().Get("ETag") {
+		t.Error("Expected a new ETag when the banner changes")
+	}
+
+	// The error is HTML-escaped
+	srv.SetReloadError("theme", errors.New("<script>bad</script>"))
+	if body := get().Body.String(); strings.Contains(body, "<script>bad</script>") || !strings.Contains(body, "&lt;script&gt;bad&lt;/script&gt;") {
+		t.Error("Expected the theme reload error escaped in the banner")
+	}
+
+	// A successful reload clears its own error only
+	srv.SetReloadError("proto", nil)
+	body = get().Body.String()
+	if strings.Contains(body, "syntax error") {
+		t.Error("Expected the proto reload error cleared")
+	}
+	if !strings.Contains(body, "bad") {
+		t.Error("Expected the theme reload error to remain")
+	}
+	srv.SetReloadError("theme", nil)
+	if strings.Contains(get().Body.String(), `role="alert"`) {
+		t.Error("Expected no reload banner once all reloads succeed")
+	}
+}
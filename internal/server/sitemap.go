@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+)
+
+// sitemapNamespace is the XML namespace of the sitemaps.org protocol.
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// sitemapURLSet is the root element of sitemap.xml.
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// sitemapURL is a single page entry in sitemap.xml.
+type sitemapURL struct {
+	Loc string `xml:"loc"`
+}
+
+// handleSitemap handles GET /sitemap.xml requests. It lists the home page and
+// every visible service, method, and type page.
+func (s *Server) handleSitemap(w http.ResponseWriter, r *http.Request) {
+	base := s.publicBaseURL(r)
+	registry, _ := s.getRegistry()
+
+	urlSet := sitemapURLSet{Xmlns: sitemapNamespace}
+	for _, path := range s.sitemapPaths(registry) {
+		urlSet.URLs = append(urlSet.URLs, sitemapURL{Loc: base + path})
+	}
+
+	data, err := xml.MarshalIndent(urlSet, "", "  ")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode sitemap: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+	w.Write(data)
+}
+
+// sitemapPaths returns the paths of the doc pages, honoring the configured
+// visibility.
+func (s *Server) sitemapPaths(registry *descriptor.Registry) []string {
+	paths := []string{"/"}
+	if registry == nil {
+		return paths
+	}
+
+	visible := func(names []string) []string {
+		var result []string
+		for _, name := range names {
+			if !s.isHidden(registry, name) {
+				result = append(result, name)
+			}
+		}
+		return result
+	}
+
+	for _, name := range visible(sortedKeys(registry.ServicesByName)) {
+		paths = append(paths, "/services/"+name)
+	}
+	for _, name := range visible(sortedKeys(registry.MethodsByName)) {
+		paths = append(paths, "/methods/"+name)
+	}
+
+	types := append(sortedKeys(registry.MessagesByName), sortedKeys(registry.EnumsByName)...)
+	sort.Strings(types)
+	for _, name := range visible(types) {
+		paths = append(paths, "/types/"+name)
+	}
+
+	return paths
+}
+
+// publicBaseURL returns the external URL of the docs without a trailing
+// slash, from the configured publicURL or else the request.
+func (s *Server) publicBaseURL(r *http.Request) string {
+	if s.config != nil && s.config.PublicURL != "" {
+		return strings.TrimSuffix(s.config.PublicURL, "/")
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/descriptor"
+	"github.com/bnprtr/reflect/internal/server/theme"
+)
+
+func TestSitemap(t *testing.T) {
+	reg, err := descriptor.LoadDirectory(context.Background(), filepath.Join("..", "descriptor", "testdata", "comprehensive"), []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	fetch := func(t *testing.T, cfg *config.Config) map[string]bool {
+		t.Helper()
+		srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+
+		req := httptest.NewRequest("GET", "http://docs.local/sitemap.xml", nil)
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/xml; charset=utf-8" {
+			t.Errorf("Expected XML content type, got %q", ct)
+		}
+
+		var urlSet sitemapURLSet
+		if err := xml.Unmarshal(w.Body.Bytes(), &urlSet); err != nil {
+			t.Fatalf("Sitemap is not well-formed XML: %v\n%s", err, w.Body.String())
+		}
+		if urlSet.XMLName.Space != sitemapNamespace {
+			t.Errorf("Expected namespace %q, got %q", sitemapNamespace, urlSet.XMLName.Space)
+		}
+
+		locs := make(map[string]bool)
+		for _, u := range urlSet.URLs {
+			locs[u.Loc] = true
+		}
+		return locs
+	}
+
+	t.Run("request host", func(t *testing.T) {
+		locs := fetch(t, nil)
+		if !locs["http://docs.local/"] {
+			t.Error("Expected the home page in the sitemap")
+		}
+		for name := range reg.ServicesByName {
+			if want := "http://docs.local/services/" + name; !locs[want] {
+				t.Errorf("Expected %s in the sitemap", want)
+			}
+		}
+		for _, want := range []string{
+			"http://docs.local/methods/users.v1.UserService/GetUser",
+			"http://docs.local/types/users.v1.User",
+			"http://docs.local/types/notifications.v1.NotificationChannel",
+		} {
+			if !locs[want] {
+				t.Errorf("Expected %s in the sitemap", want)
+			}
+		}
+		if want := 1 + len(reg.ServicesByName) + len(reg.MethodsByName) + len(reg.MessagesByName) + len(reg.EnumsByName); len(locs) != want {
+			t.Errorf("Expected %d URLs, got %d", want, len(locs))
+		}
+	})
+
+	t.Run("public URL", func(t *testing.T) {
+		locs := fetch(t, &config.Config{PublicURL: "https://example.com/docs/"})
+		if !locs["https://example.com/docs/"] {
+			t.Errorf("Expected URLs under the public URL, got %v", locs)
+		}
+		for name := range reg.ServicesByName {
+			if want := "https://example.com/docs/services/" + name; !locs[want] {
+				t.Errorf("Expected %s in the sitemap", want)
+			}
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+	"github.com/bnprtr/reflect/internal/docs"
+	"github.com/go-chi/chi/v5"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// handleSource handles GET /source/{path} requests, serving the raw source of
+// a loaded .proto file by its import path (e.g. /source/echo/v1/echo.proto).
+// There is no source when the descriptors came from server reflection or a
+// descriptor set, and none is served for files declaring hidden symbols.
+func (s *Server) handleSource(w http.ResponseWriter, r *http.Request) {
+	path := chi.URLParam(r, "*")
+	registry, _ := s.getRegistry()
+	if registry == nil {
+		http.Error(w, "No protobuf descriptors loaded", http.StatusNotFound)
+		return
+	}
+	if registry.Sources == nil {
+		http.Error(w, "Source not available: the descriptors were not loaded from .proto files", http.StatusNotFound)
+		return
+	}
+
+	source, ok := registry.Source(path)
+	if !ok || s.declaresHiddenSymbol(registry, path) {
+		http.Error(w, "Source not found: "+path, http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(source))
+}
+
+// handleSourceFile handles GET /files/{path} requests, rendering the source of
+// a loaded .proto file by its import path with syntax highlighting and links
+// from symbol names to their doc pages. It is unavailable in the same cases
+// as handleSource.
+func (s *Server) handleSourceFile(w http.ResponseWriter, r *http.Request) {
+	path := chi.URLParam(r, "*")
+	registry, _ := s.getRegistry()
+	if registry == nil || registry.Sources == nil {
+		http.Error(w, "Source not available: the descriptors were not loaded from .proto files", http.StatusNotFound)
+		return
+	}
+	if s.declaresHiddenSymbol(registry, path) {
+		http.Error(w, "Source not found: "+path, http.StatusNotFound)
+		return
+	}
+
+	view, err := docs.BuildSourceView(registry, path, s.hideInternal())
+	if err != nil {
+		http.Error(w, "Source not found: "+path, http.StatusNotFound)
+		return
+	}
+
+	index, err := s.buildIndex(registry)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to build index: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	data := s.mergeData(r, map[string]any{
+		"Title":          fmt.Sprintf("Source: %s", view.Path),
+		"Source":         view,
+		"Services":       index.Services,
+		"Packages":       index.Packages,
+		"CurrentPackage": view.Package,
+	})
+	if err := s.templates.ExecuteTemplate(w, "source_file.html", data); err != nil {
+		http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// declaresHiddenSymbol reports whether the file with the given import path
+// declares a symbol hidden from the docs.
+func (s *Server) declaresHiddenSymbol(registry *descriptor.Registry, path string) bool {
+	if !s.hideInternal() {
+		return false
+	}
+	for name := range registry.InternalSymbols {
+		// Methods are named "pkg.Service/Method"; their service's file
+		// declares them
+		name, _, _ = strings.Cut(name, "/")
+		d, err := registry.Files.FindDescriptorByName(protoreflect.FullName(name))
+		if err == nil && d.ParentFile().Path() == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/descriptor"
+	"github.com/bnprtr/reflect/internal/server/theme"
+)
+
+func TestSourceHandler(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "basic")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join(testDataPath, "echo.proto"))
+	if err != nil {
+		t.Fatalf("Failed to read echo.proto: %v", err)
+	}
+
+	srv, err := New(reg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	t.Run("serves the file", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest("GET", "/source/echo.proto", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+			t.Errorf("Expected text/plain, got %q", ct)
+		}
+		if w.Body.String() != string(want) {
+			t.Errorf("Expected the contents of echo.proto, got:\n%s", w.Body.String())
+		}
+	})
+
+	t.Run("unknown file", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest("GET", "/source/missing.proto", nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("pages link to the source", func(t *testing.T) {
+		for _, path := range []string{"/services/echo.v1.EchoService", "/types/echo.v1.EchoRequest", "/types/echo.v1.Status"} {
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+			if !strings.Contains(w.Body.String(), `href="/files/echo.proto"`) {
+				t.Errorf("Expected %s to link to /files/echo.proto", path)
+			}
+		}
+	})
+
+	t.Run("highlighted page", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest("GET", "/files/echo.proto", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		body := w.Body.String()
+		for _, want := range []string{
+			`<span class="proto-keyword">message</span>`,
+			`<span class="proto-scalar">string</span>`,
+			`<span class="proto-comment">// EchoRequest contains the message to echo.</span>`,
+			`<a href="/types/echo.v1.EchoRequest" class="proto-link">EchoRequest</a>`,
+			`<a href="/methods/echo.v1.EchoService/Echo" class="proto-link">Echo</a>`,
+			`href="/source/echo.proto"`,
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("Expected page to contain %q", want)
+			}
+		}
+
+		w = httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest("GET", "/files/missing.proto", nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for an unknown file, got %d", w.Code)
+		}
+	})
+
+	t.Run("no source loaded", func(t *testing.T) {
+		// As with descriptors from server reflection
+		withoutSource := *reg
+		withoutSource.Sources = nil
+		srv, err := New(&withoutSource)
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest("GET", "/source/echo.proto", nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "not loaded from .proto files") {
+			t.Errorf("Expected an explanation, got %q", w.Body.String())
+		}
+
+		w = httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest("GET", "/files/echo.proto", nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for the highlighted page, got %d", w.Code)
+		}
+
+		w = httptest.NewRecorder()
+		srv.ServeHTTP(w, httptest.NewRequest("GET", "/types/echo.v1.EchoRequest", nil))
+		if strings.Contains(w.Body.String(), "/files/") {
+			t.Error("Expected no source link without source")
+		}
+	})
+}
+
+func TestSourceHandlerHideInternal(t *testing.T) {
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "visibility")
+	reg, err := descriptor.LoadDirectory(context.Background(), testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	for _, hide := range []bool{true, false} {
+		srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), &config.Config{HideInternal: hide})
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+
+		// Both files declare internal symbols
+		for _, path := range []string{"/source/visibility.proto", "/source/ops/ops.proto", "/files/visibility.proto"} {
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+			want := http.StatusOK
+			if hide {
+				want = http.StatusNotFound
+			}
+			if w.Code != want {
+				t.Errorf("hide=%v %s: expected status %d, got %d", hide, path, want, w.Code)
+			}
+		}
+	}
+}
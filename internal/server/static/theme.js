@@ -7,17 +7,41 @@
   const THEME_LIGHT = 'light';
   const THEME_DARK = 'dark';
 
-  // Get current dark/light theme from localStorage or system preference
+  // Get the color mode the server rendered: the saved preference or the
+  // configured default
+  function getServerColorMode() {
+    const mode = document.documentElement.getAttribute('data-color-mode');
+    return mode === THEME_LIGHT || mode === THEME_DARK ? mode : null;
+  }
+
+  // Get current dark/light theme from localStorage, the server, or system
+  // preference
   function getCurrentTheme() {
     const stored = localStorage.getItem(THEME_KEY);
     if (stored === THEME_LIGHT || stored === THEME_DARK) {
       return stored;
     }
 
+    const serverMode = getServerColorMode();
+    if (serverMode) {
+      return serverMode;
+    }
+
     // Fall back to system preference
     return window.matchMedia('(prefers-color-scheme: dark)').matches ? THEME_DARK : THEME_LIGHT;
   }
 
+  // Remember the color mode server-side so pages render in it from the start
+  function saveColorMode(mode) {
+    fetch('/api/preferences', {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ colorMode: mode })
+    }).catch(function() {
+      // localStorage still holds the choice
+    });
+  }
+
   // Get current color theme from localStorage
   function getCurrentColorTheme() {
     return localStorage.getItem(COLOR_THEME_KEY) || null;
@@ -61,6 +85,7 @@
     const newTheme = current === THEME_LIGHT ? THEME_DARK : THEME_LIGHT;
 
     localStorage.setItem(THEME_KEY, newTheme);
+    saveColorMode(newTheme);
     applyTheme(newTheme);
     updateToggleButton(newTheme);
   }
@@ -148,8 +173,8 @@
 
     // Listen for system theme changes
     window.matchMedia('(prefers-color-scheme: dark)').addEventListener('change', function(e) {
-      // Only update if no explicit preference is stored
-      if (!localStorage.getItem(THEME_KEY)) {
+      // Only update if no explicit preference is stored or configured
+      if (!localStorage.getItem(THEME_KEY) && !getServerColorMode()) {
         const newTheme = e.matches ? THEME_DARK : THEME_LIGHT;
         applyTheme(newTheme);
         updateToggleButton(newTheme);
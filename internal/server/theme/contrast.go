@@ -0,0 +1,159 @@
+package theme
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// WCAG 2.1 minimum contrast ratios for AA conformance
+const (
+	MinContrastNormalText = 4.5
+	MinContrastLargeText  = 3.0
+)
+
+// ContrastIssue describes a foreground/background color pair whose contrast
+// ratio is below the WCAG 2.1 AA minimum for normal text
+type ContrastIssue struct {
+	// Mode is "light" or "dark"
+	Mode string `json:"mode"`
+
+	// Foreground and Background name the theme colors, e.g. "text" and "background"
+	Foreground string `json:"foreground"`
+	Background string `json:"background"`
+
+	// ForegroundColor and BackgroundColor are the color values as configured
+	ForegroundColor string `json:"foregroundColor"`
+	BackgroundColor string `json:"backgroundColor"`
+
+	// Ratio is the contrast ratio, from 1 to 21
+	Ratio float64 `json:"ratio"`
+
+	// LargeTextOnly is set when the pair meets the 3:1 minimum for large
+	// text but not the 4.5:1 minimum for normal text
+	LargeTextOnly bool `json:"largeTextOnly"`
+}
+
+func (i ContrastIssue) String() string {
+	minimum := "4.5:1 (normal text) and 3:1 (large text)"
+	if i.LargeTextOnly {
+		minimum = "4.5:1 (normal text)"
+	}
+	return fmt.Sprintf("%s mode %s (%s) on %s (%s) has contrast %.2f:1, below %s",
+		i.Mode, i.Foreground, i.ForegroundColor, i.Background, i.BackgroundColor, i.Ratio, minimum)
+}
+
+// ContrastReport checks text on background and secondary text on surface in
+// both light and dark modes against the WCAG 2.1 AA contrast minimums.
+// Colors that cannot be parsed as hex or rgb()/rgba() are skipped.
+func (t *Theme) ContrastReport() []ContrastIssue {
+	type pair struct {
+		mode                   string
+		foreground, background string
+		fgColor, bgColor       string
+	}
+	pairs := []pair{
+		{"light", "text", "background", t.Colors.Light.Text, t.Colors.Light.Background},
+		{"light", "textSecondary", "surface", t.Colors.Light.TextSecondary, t.Colors.Light.Surface},
+		{"dark", "text", "background", t.Colors.Dark.Text, t.Colors.Dark.Background},
+		{"dark", "textSecondary", "surface", t.Colors.Dark.TextSecondary, t.Colors.Dark.Surface},
+	}
+
+	var issues []ContrastIssue
+	for _, p := range pairs {
+		ratio, ok := ContrastRatio(p.fgColor, p.bgColor)
+		if !ok || ratio >= MinContrastNormalText {
+			continue
+		}
+		issues = append(issues, ContrastIssue{
+			Mode:            p.mode,
+			Foreground:      p.foreground,
+			Background:      p.background,
+			ForegroundColor: p.fgColor,
+			BackgroundColor: p.bgColor,
+			Ratio:           math.Round(ratio*100) / 100,
+			LargeTextOnly:   ratio >= MinContrastLargeText,
+		})
+	}
+	return issues
+}
+
+// ContrastRatio returns the WCAG 2.1 contrast ratio between two colors.
+// It reports false if either color cannot be parsed.
+func ContrastRatio(a, b string) (float64, bool) {
+	la, ok := relativeLuminance(a)
+	if !ok {
+		return 0, false
+	}
+	lb, ok := relativeLuminance(b)
+	if !ok {
+		return 0, false
+	}
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05), true
+}
+
+// relativeLuminance computes the WCAG relative luminance of a color
+func relativeLuminance(color string) (float64, bool) {
+	r, g, b, ok := parseColor(color)
+	if !ok {
+		return 0, false
+	}
+
+	linear := func(c uint8) float64 {
+		v := float64(c) / 255
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(r) + 0.7152*linear(g) + 0.0722*linear(b), true
+}
+
+// parseColor parses #rgb, #rrggbb, #rrggbbaa, rgb(), and rgba() colors.
+// Alpha is ignored.
+func parseColor(color string) (r, g, b uint8, ok bool) {
+	color = strings.ToLower(strings.TrimSpace(color))
+
+	if hex, found := strings.CutPrefix(color, "#"); found {
+		switch len(hex) {
+		case 3:
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		case 6:
+		case 8:
+			hex = hex[:6]
+		default:
+			return 0, 0, 0, false
+		}
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return 0, 0, 0, false
+		}
+		return uint8(v >> 16), uint8(v >> 8), uint8(v), true
+	}
+
+	args, found := strings.CutPrefix(color, "rgba(")
+	if !found {
+		args, found = strings.CutPrefix(color, "rgb(")
+	}
+	args, closed := strings.CutSuffix(args, ")")
+	if !found || !closed {
+		return 0, 0, 0, false
+	}
+	parts := strings.Split(args, ",")
+	if len(parts) < 3 {
+		return 0, 0, 0, false
+	}
+	var channels [3]uint8
+	for i := range channels {
+		v, err := strconv.ParseUint(strings.TrimSpace(parts[i]), 10, 8)
+		if err != nil {
+			return 0, 0, 0, false
+		}
+		channels[i] = uint8(v)
+	}
+	return channels[0], channels[1], channels[2], true
+}
@@ -0,0 +1,68 @@
+package theme
+
+import (
+	"math"
+	"testing"
+)
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"#000000", "#ffffff", 21},
+		{"#fff", "#fff", 1},
+		{"rgb(0, 0, 0)", "#FFFFFF", 21},
+		{"#767676", "#ffffff", 4.54},
+		{"rgba(255, 255, 255, 0.5)", "#000", 21},
+	}
+	for _, tt := range tests {
+		got, ok := ContrastRatio(tt.a, tt.b)
+		if !ok {
+			t.Errorf("ContrastRatio(%q, %q) failed to parse", tt.a, tt.b)
+			continue
+		}
+		if math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("ContrastRatio(%q, %q) = %.2f, want %.2f", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if _, ok := ContrastRatio("var(--text)", "#fff"); ok {
+		t.Error("Expected unparseable color to be reported")
+	}
+}
+
+func TestContrastReport(t *testing.T) {
+	t.Run("high-contrast theme passes", func(t *testing.T) {
+		if issues := GetHighContrastTheme().ContrastReport(); len(issues) != 0 {
+			t.Errorf("Expected no issues, got %v", issues)
+		}
+	})
+
+	t.Run("low-contrast theme fails", func(t *testing.T) {
+		bad := GetDefaultTheme()
+		bad.Colors.Light.Text = "#aaaaaa"         // ~2.3:1 on white
+		bad.Colors.Dark.TextSecondary = "#76808f" // ~3.7:1 on dark surface
+
+		issues := bad.ContrastReport()
+		if len(issues) != 2 {
+			t.Fatalf("Expected 2 issues, got %d: %v", len(issues), issues)
+		}
+
+		light := issues[0]
+		if light.Mode != "light" || light.Foreground != "text" || light.Background != "background" {
+			t.Errorf("Unexpected first issue: %+v", light)
+		}
+		if light.Ratio >= MinContrastLargeText || light.LargeTextOnly {
+			t.Errorf("Expected light text to fail for large text too, got %+v", light)
+		}
+
+		dark := issues[1]
+		if dark.Mode != "dark" || dark.Foreground != "textSecondary" || dark.Background != "surface" {
+			t.Errorf("Unexpected second issue: %+v", dark)
+		}
+		if !dark.LargeTextOnly {
+			t.Errorf("Expected dark secondary text to pass for large text, got %+v", dark)
+		}
+	})
+}
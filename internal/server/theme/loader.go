@@ -3,6 +3,7 @@ package theme
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,6 +13,53 @@ import (
 
 // LoadThemeFromFile loads a theme from a JSON or YAML file
 func LoadThemeFromFile(path string) (*Theme, error) {
+	theme, err := parseThemeFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate and fill in missing values with defaults
+	if err := validateAndFillDefaults(theme); err != nil {
+		return nil, fmt.Errorf("theme validation failed: %w", err)
+	}
+
+	warnLowContrast(theme)
+	return theme, nil
+}
+
+// LoadThemeFromFiles loads theme files and merges them in order on top of the
+// default theme, so later files override fields set by earlier ones. At least
+// one of the files must set the theme name.
+func LoadThemeFromFiles(paths []string) (*Theme, error) {
+	theme := GetDefaultTheme()
+	theme.Name = ""
+	for _, path := range paths {
+		override, err := parseThemeFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		theme = MergeThemes(theme, override)
+	}
+
+	// Validate and fill in missing values with defaults
+	if err := validateAndFillDefaults(theme); err != nil {
+		return nil, fmt.Errorf("theme validation failed: %w", err)
+	}
+
+	warnLowContrast(theme)
+	return theme, nil
+}
+
+// warnLowContrast logs a warning for each color pair in the theme that fails
+// the WCAG contrast minimums
+func warnLowContrast(t *Theme) {
+	for _, issue := range t.ContrastReport() {
+		slog.Warn("Low contrast in theme", "theme", t.Name, "issue", issue.String())
+	}
+}
+
+// parseThemeFile parses a JSON or YAML theme file without validating it
+func parseThemeFile(path string) (*Theme, error) {
 	// Read file
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -36,14 +84,68 @@ func LoadThemeFromFile(path string) (*Theme, error) {
 		return nil, fmt.Errorf("unsupported file extension %q (supported: .json, .yaml, .yml)", ext)
 	}
 
-	// Validate and fill in missing values with defaults
-	if err := validateAndFillDefaults(&theme); err != nil {
-		return nil, fmt.Errorf("theme validation failed: %w", err)
-	}
-
 	return &theme, nil
 }
 
+// MergeThemes returns a copy of base with every non-empty field of override
+// applied on top. Neither argument is modified.
+func MergeThemes(base, override *Theme) *Theme {
+	merged := *base
+
+	mergeField(&merged.Name, override.Name)
+	mergeField(&merged.CustomCSS, override.CustomCSS)
+
+	// Light colors
+	mergeField(&merged.Colors.Light.Background, override.Colors.Light.Background)
+	mergeField(&merged.Colors.Light.Surface, override.Colors.Light.Surface)
+	mergeField(&merged.Colors.Light.Primary, override.Colors.Light.Primary)
+	mergeField(&merged.Colors.Light.Secondary, override.Colors.Light.Secondary)
+	mergeField(&merged.Colors.Light.Text, override.Colors.Light.Text)
+	mergeField(&merged.Colors.Light.TextSecondary, override.Colors.Light.TextSecondary)
+	mergeField(&merged.Colors.Light.Border, override.Colors.Light.Border)
+	mergeField(&merged.Colors.Light.Accent, override.Colors.Light.Accent)
+	mergeField(&merged.Colors.Light.AccentHover, override.Colors.Light.AccentHover)
+	mergeField(&merged.Colors.Light.Shadow, override.Colors.Light.Shadow)
+
+	// Dark colors
+	mergeField(&merged.Colors.Dark.Background, override.Colors.Dark.Background)
+	mergeField(&merged.Colors.Dark.Surface, override.Colors.Dark.Surface)
+	mergeField(&merged.Colors.Dark.Primary, override.Colors.Dark.Primary)
+	mergeField(&merged.Colors.Dark.Secondary, override.Colors.Dark.Secondary)
+	mergeField(&merged.Colors.Dark.Text, override.Colors.Dark.Text)
+	mergeField(&merged.Colors.Dark.TextSecondary, override.Colors.Dark.TextSecondary)
+	mergeField(&merged.Colors.Dark.Border, override.Colors.Dark.Border)
+	mergeField(&merged.Colors.Dark.Accent, override.Colors.Dark.Accent)
+	mergeField(&merged.Colors.Dark.AccentHover, override.Colors.Dark.AccentHover)
+	mergeField(&merged.Colors.Dark.Shadow, override.Colors.Dark.Shadow)
+
+	// Typography
+	mergeField(&merged.Typography.FontFamily, override.Typography.FontFamily)
+	mergeField(&merged.Typography.FontFamilyMono, override.Typography.FontFamilyMono)
+	mergeField(&merged.Typography.FontSizeBase, override.Typography.FontSizeBase)
+	mergeField(&merged.Typography.LineHeight, override.Typography.LineHeight)
+
+	// Spacing
+	mergeField(&merged.Spacing.HeaderHeight, override.Spacing.HeaderHeight)
+	mergeField(&merged.Spacing.ContentPadding, override.Spacing.ContentPadding)
+	mergeField(&merged.Spacing.CardPadding, override.Spacing.CardPadding)
+
+	// Components
+	mergeField(&merged.Components.HeaderShadow, override.Components.HeaderShadow)
+	mergeField(&merged.Components.CardShadow, override.Components.CardShadow)
+	mergeField(&merged.Components.CardRadius, override.Components.CardRadius)
+	mergeField(&merged.Components.BorderWidth, override.Components.BorderWidth)
+
+	return &merged
+}
+
+// mergeField sets dst to value if value is non-empty
+func mergeField(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
 // validateAndFillDefaults validates a theme and fills in missing values with defaults
 func validateAndFillDefaults(t *Theme) error {
 	if t.Name == "" {
@@ -0,0 +1,96 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMergeThemes(t *testing.T) {
+	base := GetDefaultTheme()
+	override := &Theme{
+		Name: "override",
+		Colors: ColorScheme{
+			Light: LightColors{Primary: "#ff0000"},
+		},
+		Typography: Typography{FontSizeBase: "18px"},
+	}
+
+	merged := MergeThemes(base, override)
+
+	if merged.Name != "override" {
+		t.Errorf("Expected name %q, got %q", "override", merged.Name)
+	}
+	if merged.Colors.Light.Primary != "#ff0000" {
+		t.Errorf("Expected overridden light primary, got %q", merged.Colors.Light.Primary)
+	}
+	if merged.Typography.FontSizeBase != "18px" {
+		t.Errorf("Expected overridden font size, got %q", merged.Typography.FontSizeBase)
+	}
+	if merged.Colors.Light.Accent != base.Colors.Light.Accent {
+		t.Errorf("Expected empty override to keep base accent %q, got %q", base.Colors.Light.Accent, merged.Colors.Light.Accent)
+	}
+	if merged.Colors.Dark.Primary != base.Colors.Dark.Primary {
+		t.Errorf("Expected base dark primary %q, got %q", base.Colors.Dark.Primary, merged.Colors.Dark.Primary)
+	}
+	if base.Colors.Light.Primary == "#ff0000" || base.Name != "default" {
+		t.Error("Expected base theme to be left unmodified")
+	}
+}
+
+func TestLoadThemeFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "base.yaml")
+	overridePath := filepath.Join(dir, "override.json")
+
+	writeFile(t, basePath, `
+name: brand
+colors:
+  light:
+    primary: "#111111"
+    accent: "#222222"
+typography:
+  fontsizebase: 15px
+`)
+	writeFile(t, overridePath, `{
+  "Colors": {"Light": {"Accent": "#333333"}},
+  "Spacing": {"CardPadding": "2rem"}
+}`)
+
+	got, err := LoadThemeFromFiles([]string{basePath, overridePath})
+	if err != nil {
+		t.Fatalf("LoadThemeFromFiles() error = %v", err)
+	}
+
+	defaultTheme := GetDefaultTheme()
+	tests := []struct {
+		field string
+		got   string
+		want  string
+	}{
+		{"Name", got.Name, "brand"},
+		{"Colors.Light.Primary", got.Colors.Light.Primary, "#111111"},
+		{"Colors.Light.Accent", got.Colors.Light.Accent, "#333333"},
+		{"Typography.FontSizeBase", got.Typography.FontSizeBase, "15px"},
+		{"Spacing.CardPadding", got.Spacing.CardPadding, "2rem"},
+		{"Colors.Dark.Background", got.Colors.Dark.Background, defaultTheme.Colors.Dark.Background},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.field, tt.got, tt.want)
+		}
+	}
+
+	t.Run("name required", func(t *testing.T) {
+		if _, err := LoadThemeFromFiles([]string{overridePath}); err == nil {
+			t.Error("Expected error when no file names the theme")
+		}
+	})
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/server/theme"
+)
+
+// writeSelfSignedCert writes a self-signed certificate for 127.0.0.1 and its
+// key to dir, returning the file paths and the parsed certificate.
+func writeSelfSignedCert(t *testing.T, dir, name string) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	return certFile, keyFile, cert
+}
+
+// serveTLS serves a new server over TLS on a local port, the way the reflect
+// command does, and returns its base URL.
+func serveTLS(t *testing.T, tlsConfig *tls.Config) string {
+	t.Helper()
+
+	srv, err := NewWithTheme(nil, theme.GetDefaultTheme(), &config.Config{})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	// Failed handshakes are expected in the mutual TLS tests
+	httpServer := &http.Server{Handler: srv, TLSConfig: tlsConfig, ErrorLog: log.New(io.Discard, "", 0)}
+	go httpServer.ServeTLS(ln, "", "")
+	t.Cleanup(func() { httpServer.Close() })
+
+	return "https://" + ln.Addr().String()
+}
+
+func TestServeTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, serverCert := writeSelfSignedCert(t, dir, "server")
+
+	tlsConfig, err := NewTLSConfig(certFile, keyFile, "")
+	if err != nil {
+		t.Fatalf("NewTLSConfig() error = %v", err)
+	}
+	baseURL := serveTLS(t, tlsConfig)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(serverCert)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+
+	resp, err := client.Get(baseURL + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz over TLS error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if resp.TLS == nil {
+		t.Error("Expected the response to arrive over TLS")
+	}
+}
+
+func TestServeMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, serverCert := writeSelfSignedCert(t, dir, "server")
+	clientCertFile, clientKeyFile, _ := writeSelfSignedCert(t, dir, "client")
+
+	tlsConfig, err := NewTLSConfig(certFile, keyFile, clientCertFile)
+	if err != nil {
+		t.Fatalf("NewTLSConfig() error = %v", err)
+	}
+	baseURL := serveTLS(t, tlsConfig)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(serverCert)
+
+	t.Run("without client certificate", func(t *testing.T) {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+		if resp, err := client.Get(baseURL + "/healthz"); err == nil {
+			resp.Body.Close()
+			t.Errorf("Expected the handshake to fail without a client certificate, got status %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("with client certificate", func(t *testing.T) {
+		clientCert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+		if err != nil {
+			t.Fatalf("Failed to load client certificate: %v", err)
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      roots,
+			Certificates: []tls.Certificate{clientCert},
+		}}}
+		resp, err := client.Get(baseURL + "/healthz")
+		if err != nil {
+			t.Fatalf("GET /healthz with client certificate error = %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestNewTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, _ := writeSelfSignedCert(t, dir, "server")
+	notPEM := filepath.Join(dir, "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name                    string
+		cert, key, clientCAFile string
+	}{
+		{"missing key", certFile, "", ""},
+		{"missing cert", "", keyFile, ""},
+		{"mismatched files", keyFile, certFile, ""},
+		{"missing client CA file", certFile, keyFile, filepath.Join(dir, "missing.pem")},
+		{"client CA file without certificates", certFile, keyFile, notPEM},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTLSConfig(tt.cert, tt.key, tt.clientCAFile); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
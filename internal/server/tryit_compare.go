@@ -0,0 +1,132 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/bnprtr/reflect/internal/tryit"
+)
+
+// CompareSide is one environment's result in a Try It compare.
+type CompareSide struct {
+	// Environment is the environment the request was sent to.
+	Environment string `json:"environment"`
+
+	// Response is the environment's response; nil if it could not be invoked.
+	Response *TryItResponse `json:"response,omitempty"`
+
+	// Error is why the environment could not be invoked.
+	Error string `json:"error,omitempty"`
+}
+
+// CompareResponse represents the response for the /api/tryit/compare endpoint.
+type CompareResponse struct {
+	// Method is the method invoked on both sides.
+	Method string `json:"method"`
+
+	// Left is the result from the environment field.
+	Left CompareSide `json:"left"`
+
+	// Right is the result from the compareEnvironment field.
+	Right CompareSide `json:"right"`
+
+	// Compared reports whether both sides succeeded with a body to diff.
+	Compared bool `json:"compared"`
+
+	// Changes are the differences from the left body to the right body.
+	Changes []tryit.JSONChange `json:"changes"`
+
+	// Note explains why the bodies were not compared.
+	Note string `json:"note,omitempty"`
+}
+
+// handleTryItCompare handles POST /api/tryit/compare requests. It takes the
+// form of /api/tryit/invoke plus compareEnvironment, sends the same request to
+// both environments, and diffs the response bodies. The result is rendered as
+// HTML, or returned as a CompareResponse with format=json. A side that fails
+// is reported in the result rather than failing the request.
+func (s *Server) handleTryItCompare(w http.ResponseWriter, r *http.Request) {
+	tryItReq, methodDesc, ok := s.parseTryItRequest(w, r)
+	if !ok {
+		return
+	}
+	compareEnv := r.FormValue("compareEnvironment")
+	if compareEnv == "" {
+		s.writeJSONError(w, http.StatusBadRequest, "compareEnvironment is required")
+		return
+	}
+
+	rightReq := tryItReq
+	rightReq.Environment = compareEnv
+	calls := make([]*tryItCall, 2)
+	for i, req := range []TryItRequest{tryItReq, rightReq} {
+		call, status, err := s.prepareCall(r.Context(), req, methodDesc, false)
+		if err != nil {
+			s.writeJSONError(w, status, err.Error())
+			return
+		}
+		calls[i] = call
+	}
+
+	// Invoke both sides at once so neither waits on the other's latency
+	sides := []CompareSide{{Environment: tryItReq.Environment}, {Environment: compareEnv}}
+	var wg sync.WaitGroup
+	for i := range calls {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := s.runCall(r.Context(), tryItReq.Method, calls[i])
+			if err != nil {
+				sides[i].Error = fmt.Sprintf("invocation failed: %v", err)
+				return
+			}
+			sides[i].Response = resp
+		}(i)
+	}
+	wg.Wait()
+
+	result := CompareResponse{Method: tryItReq.Method, Left: sides[0], Right: sides[1]}
+	result.diff()
+
+	if r.FormValue("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	if err := s.templates.ExecuteTemplate(w, "tryit_compare.html", result); err != nil {
+		http.Error(w, fmt.Sprintf("Template error: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// diff compares the bodies of both sides if both succeeded, or notes why
+// they were not compared.
+func (c *CompareResponse) diff() {
+	for _, side := range []CompareSide{c.Left, c.Right} {
+		switch {
+		case side.Response == nil:
+			c.Note = fmt.Sprintf("%q could not be invoked", side.Environment)
+			return
+		case !side.Response.Success:
+			c.Note = fmt.Sprintf("%q returned an error", side.Environment)
+			return
+		case side.Response.Body == "":
+			c.Note = fmt.Sprintf("%q returned no response body", side.Environment)
+			return
+		}
+	}
+
+	changes, err := tryit.DiffJSON(c.Left.Response.Body, c.Right.Response.Body)
+	if err != nil {
+		c.Note = fmt.Sprintf("Response bodies are not comparable JSON: %v", err)
+		return
+	}
+	c.Compared = true
+	c.Changes = changes
+}
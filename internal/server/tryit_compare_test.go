@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/descriptor"
+	"github.com/bnprtr/reflect/internal/server/theme"
+	"github.com/bnprtr/reflect/internal/tryit"
+)
+
+func TestHandleTryItCompare(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	jsonUpstream := func(body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(body))
+		}))
+	}
+	staging := jsonUpstream(`{"user":{"email":"ada@staging.example.com","fullName":"Ada"}}`)
+	defer staging.Close()
+	prod := jsonUpstream(`{"user":{"fullName":"Ada"}}`)
+	defer prod.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"code":"unavailable","message":"down for maintenance"}`))
+	}))
+	defer failing.Close()
+
+	cfg := &config.Config{
+		Environments: []config.Environment{
+			{Name: "staging", BaseURL: staging.URL, Transport: "connect", AllowPrivateNetworks: true},
+			{Name: "prod", BaseURL: prod.URL, Transport: "connect", AllowPrivateNetworks: true},
+			{Name: "failing", BaseURL: failing.URL, Transport: "connect", AllowPrivateNetworks: true},
+		},
+		MaxRequestBodyBytes:   config.DefaultMaxRequestBodyBytes,
+		RequestTimeoutSeconds: config.DefaultRequestTimeoutSeconds,
+	}
+
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	compare := func(t *testing.T, form url.Values) *httptest.ResponseRecorder {
+		t.Helper()
+		form.Set("method", "users.v1.UserService/GetUser")
+		form.Set("body", `{"userId":"1"}`)
+		req := httptest.NewRequest("POST", "/api/tryit/compare", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("structured diff", func(t *testing.T) {
+		w := compare(t, url.Values{"environment": {"staging"}, "compareEnvironment": {"prod"}, "format": {"json"}})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var resp CompareResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if !resp.Compared {
+			t.Fatalf("Expected bodies to be compared, got note %q", resp.Note)
+		}
+		want := []tryit.JSONChange{{Path: "user.email", Kind: tryit.ChangeRemoved, Left: `"ada@staging.example.com"`}}
+		if len(resp.Changes) != 1 || resp.Changes[0] != want[0] {
+			t.Errorf("Expected changes %+v, got %+v", want, resp.Changes)
+		}
+		if resp.Left.Environment != "staging" || resp.Right.Environment != "prod" {
+			t.Errorf("Expected staging and prod sides, got %q and %q", resp.Left.Environment, resp.Right.Environment)
+		}
+	})
+
+	t.Run("rendered diff", func(t *testing.T) {
+		w := compare(t, url.Values{"environment": {"staging"}, "compareEnvironment": {"prod"}})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		body := w.Body.String()
+		for _, want := range []string{"Response Diff", "user.email", "removed", "&#34;ada@staging.example.com&#34;"} {
+			if !strings.Contains(body, want) {
+				t.Errorf("Expected rendered diff to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("one side errors", func(t *testing.T) {
+		w := compare(t, url.Values{"environment": {"staging"}, "compareEnvironment": {"failing"}, "format": {"json"}})
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var resp CompareResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if resp.Left.Response == nil || !resp.Left.Response.Success {
+			t.Errorf("Expected the staging side to succeed, got %+v", resp.Left)
+		}
+		if resp.Right.Response == nil || resp.Right.Response.Success {
+			t.Errorf("Expected the failing side to report an error, got %+v", resp.Right)
+		}
+		if resp.Compared || resp.Note == "" {
+			t.Errorf("Expected no comparison with a note, got compared=%v note=%q", resp.Compared, resp.Note)
+		}
+	})
+
+	t.Run("unknown compare environment", func(t *testing.T) {
+		w := compare(t, url.Values{"environment": {"staging"}, "compareEnvironment": {"missing"}})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("missing compare environment", func(t *testing.T) {
+		w := compare(t, url.Values{"environment": {"staging"}})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
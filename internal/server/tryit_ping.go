@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bnprtr/reflect/internal/tryit"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// TryItPingRequest represents the JSON request body for POST /api/tryit/ping.
+type TryItPingRequest struct {
+	// Environment is the name of the environment to check.
+	Environment string `json:"environment"`
+
+	// Transport overrides the environment's transport (optional).
+	Transport string `json:"transport,omitempty"`
+
+	// Method is a full method name the probe may address without invoking
+	// it (optional). Connect servers are only detected through a method they
+	// serve.
+	Method string `json:"method,omitempty"`
+}
+
+// TryItPingResponse represents the JSON response for POST /api/tryit/ping.
+type TryItPingResponse struct {
+	// Environment is the environment that was checked.
+	Environment string `json:"environment"`
+
+	// Transport is the transport the environment is configured with, or the
+	// override.
+	Transport string `json:"transport"`
+
+	// Reachable reports whether the environment's base URL answered.
+	Reachable bool `json:"reachable"`
+
+	// Protocol is the protocol detected in the answer; empty if unknown.
+	Protocol string `json:"protocol,omitempty"`
+
+	// Matches reports whether Protocol is Transport.
+	Matches bool `json:"matches"`
+
+	// Message describes the answer, or why there was none.
+	Message string `json:"message"`
+
+	// LatencyMs is the time the check took, in milliseconds.
+	LatencyMs int64 `json:"latencyMs"`
+}
+
+// handleTryItPing handles POST /api/tryit/ping requests: a cheap check that
+// an environment is reachable and speaks its configured transport.
+func (s *Server) handleTryItPing(w http.ResponseWriter, r *http.Request) {
+	var req TryItPingRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+		s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if s.config == nil {
+		s.writeJSONError(w, http.StatusBadRequest, "no environments configured")
+		return
+	}
+	env, err := s.config.GetEnvironment(req.Environment)
+	if err != nil {
+		s.writeJSONError(w, http.StatusBadRequest, fmt.Sprintf("environment %q not found", req.Environment))
+		return
+	}
+
+	transport := req.Transport
+	if transport == "" {
+		transport = env.Transport
+	}
+	parsedTransport, err := tryit.ParseTransport(transport)
+	if err != nil {
+		s.writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	invoker, err := tryit.NewInvoker(parsedTransport)
+	if err != nil {
+		s.writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	pinger, ok := invoker.(tryit.Pinger)
+	if !ok {
+		s.writeJSONError(w, http.StatusNotImplemented, fmt.Sprintf("the %s transport does not support connectivity checks", parsedTransport))
+		return
+	}
+
+	var methodDesc protoreflect.MethodDescriptor
+	if req.Method != "" {
+		registry, _ := s.getRegistry()
+		if registry != nil && !s.isHidden(registry, req.Method) {
+			methodDesc, _ = registry.FindMethod(req.Method)
+		}
+	}
+
+	headers := tryit.MergeHeaders(s.config.GlobalDefaultHeaders, env.DefaultHeaders)
+	timeout := env.GetTimeout(s.config.RequestTimeoutSeconds)
+	if err := s.addAuthToken(r.Context(), env, headers, timeout); err != nil {
+		s.writeJSONError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	result, err := pinger.Ping(ctx, &tryit.Request{
+		Environment:          env.Name,
+		MethodDescriptor:     methodDesc,
+		Headers:              headers,
+		BaseURL:              env.BaseURL,
+		Timeout:              timeout,
+		InsecureSkipVerify:   env.TLS.InsecureSkipVerify,
+		Proxy:                env.Proxy,
+		BlockPrivateNetworks: !env.AllowPrivateNetworks,
+		Logger:               s.logger,
+	})
+	if err != nil {
+		s.writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.logger.Info("Try It: Ping",
+		"environment", env.Name,
+		"transport", parsedTransport,
+		"reachable", result.Reachable,
+		"protocol", result.Protocol,
+		"latencyMs", result.Latency.Milliseconds())
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(TryItPingResponse{
+		Environment: env.Name,
+		Transport:   string(parsedTransport),
+		Reachable:   result.Reachable,
+		Protocol:    string(result.Protocol),
+		Matches:     result.Protocol == parsedTransport,
+		Message:     result.Message,
+		LatencyMs:   result.Latency.Milliseconds(),
+	}); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
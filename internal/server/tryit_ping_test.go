@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/config"
+	"github.com/bnprtr/reflect/internal/descriptor"
+	"github.com/bnprtr/reflect/internal/server/theme"
+)
+
+func TestHandleTryItPing(t *testing.T) {
+	ctx := context.Background()
+	testDataPath := filepath.Join("..", "descriptor", "testdata", "comprehensive")
+	reg, err := descriptor.LoadDirectory(ctx, testDataPath, []string{})
+	if err != nil {
+		t.Fatalf("Failed to load test registry: %v", err)
+	}
+
+	// A Connect upstream rejects the probe's content type with the codecs it
+	// accepts
+	var gotPath, gotAuth string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Accept-Post", "application/json, application/proto")
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		Environments: []config.Environment{
+			{
+				Name:                 "connect",
+				BaseURL:              upstream.URL,
+				AllowPrivateNetworks: true,
+				Transport:            "connect",
+				DefaultHeaders:       map[string]string{"Authorization": "Bearer test"},
+			},
+			{
+				Name:                 "misconfigured",
+				BaseURL:              upstream.URL,
+				AllowPrivateNetworks: true,
+				Transport:            "grpc-web",
+			},
+		},
+		MaxRequestBodyBytes:   config.DefaultMaxRequestBodyBytes,
+		RequestTimeoutSeconds: config.DefaultRequestTimeoutSeconds,
+	}
+
+	srv, err := NewWithTheme(reg, theme.GetDefaultTheme(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		reachable      bool
+		protocol       string
+		matches        bool
+	}{
+		{
+			name:           "matching transport",
+			body:           `{"environment":"connect","method":"users.v1.UserService/GetUser"}`,
+			expectedStatus: http.StatusOK,
+			reachable:      true,
+			protocol:       "connect",
+			matches:        true,
+		},
+		{
+			name:           "mismatched transport",
+			body:           `{"environment":"misconfigured"}`,
+			expectedStatus: http.StatusOK,
+			reachable:      true,
+			protocol:       "connect",
+			matches:        false,
+		},
+		{
+			name:           "transport override",
+			body:           `{"environment":"misconfigured","transport":"connect"}`,
+			expectedStatus: http.StatusOK,
+			reachable:      true,
+			protocol:       "connect",
+			matches:        true,
+		},
+		{
+			name:           "unknown environment",
+			body:           `{"environment":"missing"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid transport",
+			body:           `{"environment":"connect","transport":"carrier-pigeon"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/tryit/ping", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			srv.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp TryItPingResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.Reachable != tt.reachable || resp.Protocol != tt.protocol || resp.Matches != tt.matches {
+				t.Errorf("Expected reachable=%v protocol=%q matches=%v, got %+v", tt.reachable, tt.protocol, tt.matches, resp)
+			}
+		})
+	}
+
+	t.Run("probe addresses the method with environment headers", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/tryit/ping", strings.NewReader(`{"environment":"connect","method":"users.v1.UserService/GetUser"}`))
+		w := httptest.NewRecorder()
+		srv.ServeHTTP(w, req)
+
+		if gotPath != "/users.v1.UserService/GetUser" {
+			t.Errorf("Expected probe of the method path, got %q", gotPath)
+		}
+		if gotAuth != "Bearer test" {
+			t.Errorf("Expected environment default headers, got Authorization %q", gotAuth)
+		}
+	})
+}
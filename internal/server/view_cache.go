@@ -0,0 +1,150 @@
+package server
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+	"github.com/bnprtr/reflect/internal/docs"
+)
+
+// maxCachedViews bounds the number of built views kept in memory.
+const maxCachedViews = 1024
+
+// View kinds cached by viewCache.
+const (
+	viewKindService = "service"
+	viewKindMessage = "message"
+)
+
+// viewKey identifies a built view. The registry version keeps views built
+// from a replaced registry from being served after a hot reload.
+type viewKey struct {
+	kind     string
+	fullName string
+	version  uint64
+}
+
+// viewEntry is a cached view and its key, kept in the LRU list.
+type viewEntry struct {
+	key  viewKey
+	view any
+}
+
+// viewCache is a concurrency-safe LRU cache of built documentation views.
+// Cached views are shared between requests, so callers must copy a view
+// before changing it.
+type viewCache struct {
+	max     int
+	mu      sync.Mutex
+	entries map[viewKey]*list.Element
+	order   *list.List // Most recently used first
+}
+
+// newViewCache returns an empty cache holding at most max views.
+func newViewCache(max int) *viewCache {
+	return &viewCache{
+		max:     max,
+		entries: make(map[viewKey]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the cached view for key.
+func (c *viewCache) get(key viewKey) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*viewEntry).view, true
+}
+
+// put caches view under key, evicting the least recently used view when
+// the cache is full.
+func (c *viewCache) put(key viewKey, view any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*viewEntry).view = view
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&viewEntry{key: key, view: view})
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*viewEntry).key)
+	}
+}
+
+// clear removes every cached view.
+func (c *viewCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+	c.order.Init()
+}
+
+// len returns the number of cached views.
+func (c *viewCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// registryVersion returns the version of the current registry, and whether
+// registry is still the current one.
+func (s *Server) registryVersion(registry *descriptor.Registry) (uint64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.regVersion, registry != nil && registry == s.registry
+}
+
+// serviceView returns the service view for fullName, built once per registry
+// version. The view is shared; copy it before changing it.
+func (s *Server) serviceView(registry *descriptor.Registry, fullName string) (*docs.ServiceView, error) {
+	version, current := s.registryVersion(registry)
+	key := viewKey{kind: viewKindService, fullName: fullName, version: version}
+	if current {
+		if view, ok := s.views.get(key); ok {
+			return view.(*docs.ServiceView), nil
+		}
+	}
+
+	view, err := docs.BuildServiceView(registry, fullName)
+	if err != nil {
+		return nil, err
+	}
+	// If the registry was replaced while building, the view is stored under
+	// the old version and never served
+	if current {
+		s.views.put(key, view)
+	}
+	return view, nil
+}
+
+// messageView returns the message view for fullName, built once per
+// registry version. The view is shared; copy it before changing it.
+func (s *Server) messageView(registry *descriptor.Registry, fullName string) (*docs.MessageView, error) {
+	version, current := s.registryVersion(registry)
+	key := viewKey{kind: viewKindMessage, fullName: fullName, version: version}
+	if current {
+		if view, ok := s.views.get(key); ok {
+			return view.(*docs.MessageView), nil
+		}
+	}
+
+	view, err := docs.BuildMessageView(registry, fullName)
+	if err != nil {
+		return nil, err
+	}
+	if current {
+		s.views.put(key, view)
+	}
+	return view, nil
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/bnprtr/reflect/internal/descriptor"
+	"github.com/bnprtr/reflect/internal/docs"
+)
+
+func TestViewCache(t *testing.T) {
+	t.Run("evicts least recently used", func(t *testing.T) {
+		cache := newViewCache(2)
+		key := func(name string) viewKey {
+			return viewKey{kind: viewKindMessage, fullName: name}
+		}
+		cache.put(key("a"), 1)
+		cache.put(key("b"), 2)
+		cache.get(key("a"))
+		cache.put(key("c"), 3)
+
+		if _, ok := cache.get(key("b")); ok {
+			t.Error("Expected b to be evicted")
+		}
+		for _, name := range []string{"a", "c"} {
+			if _, ok := cache.get(key(name)); !ok {
+				t.Errorf("Expected %s to be cached", name)
+			}
+		}
+		if cache.len() != 2 {
+			t.Errorf("Expected 2 cached views, got %d", cache.len())
+		}
+	})
+
+	t.Run("cleared on hot reload", func(t *testing.T) {
+		ctx := context.Background()
+		reg, err := descriptor.LoadDirectory(ctx, filepath.Join("..", "descriptor", "testdata", "comprehensive"), []string{})
+		if err != nil {
+			t.Fatalf("Failed to load test registry: %v", err)
+		}
+		tagsReg, err := descriptor.LoadDirectory(ctx, filepath.Join("..", "descriptor", "testdata", "tags"), []string{})
+		if err != nil {
+			t.Fatalf("Failed to load test registry: %v", err)
+		}
+		srv, err := New(reg)
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+
+		get := func(path string) int {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			w := httptest.NewRecorder()
+			srv.ServeHTTP(w, req)
+			return w.Code
+		}
+
+		if code := get("/services/users.v1.UserService"); code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", code)
+		}
+		if code := get("/types/users.v1.User"); code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", code)
+		}
+		if srv.views.len() != 2 {
+			t.Fatalf("Expected 2 cached views, got %d", srv.views.len())
+		}
+		current, _ := srv.getRegistry()
+		first, _ := srv.serviceView(current, "users.v1.UserService")
+		second, _ := srv.serviceView(current, "users.v1.UserService")
+		if first != second {
+			t.Error("Expected the cached service view to be reused")
+		}
+
+		srv.SetRegistry(tagsReg)
+		if srv.views.len() != 0 {
+			t.Errorf("Expected the cache to be cleared on reload, got %d views", srv.views.len())
+		}
+		if code := get("/services/users.v1.UserService"); code != http.StatusNotFound {
+			t.Errorf("Expected a stale service to 404 after reload, got %d", code)
+		}
+		if code := get("/services/tags.v1.AccountService"); code != http.StatusOK {
+			t.Errorf("Expected the reloaded service, got %d", code)
+		}
+
+		// Views built from a replaced registry are not cached
+		if _, err := srv.serviceView(reg, "users.v1.UserService"); err != nil {
+			t.Fatalf("serviceView() error = %v", err)
+		}
+		if srv.views.len() != 1 {
+			t.Errorf("Expected only the current registry's view to be cached, got %d", srv.views.len())
+		}
+	})
+}
+
+func BenchmarkServiceView(b *testing.B) {
+	reg, err := descriptor.LoadDirectory(context.Background(), filepath.Join("..", "descriptor", "testdata", "comprehensive"), []string{})
+	if err != nil {
+		b.Fatalf("Failed to load test registry: %v", err)
+	}
+	srv, err := New(reg)
+	if err != nil {
+		b.Fatalf("Failed to create server: %v", err)
+	}
+	registry, _ := srv.getRegistry()
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := docs.BuildServiceView(registry, "users.v1.UserService"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := srv.serviceView(registry, "users.v1.UserService"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package tryit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpiryDelta is how long before its expiry a cached token is refreshed,
+// so a token does not expire while a request is in flight.
+const tokenExpiryDelta = 10 * time.Second
+
+// TokenSource provides the token sent as "Authorization: Bearer <token>".
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a TokenSource that always returns the same token.
+type StaticToken string
+
+// Token returns the static token.
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+// ClientCredentialsSource is a TokenSource that fetches tokens with the OAuth2
+// client credentials grant and caches them until shortly before they expire.
+// It is safe for concurrent use.
+type ClientCredentialsSource struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	client       *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time // zero if the token must not be reused
+}
+
+// NewClientCredentialsSource creates a token source for the given OAuth2 token
+// endpoint. The token endpoint is reached with the same TLS and proxy settings
+// as the environment's upstream.
+func NewClientCredentialsSource(tokenURL, clientID, clientSecret string, scopes []string, insecureSkipVerify bool, proxy string) (*ClientCredentialsSource, error) {
+	transport, err := newHTTPTransport(insecureSkipVerify, proxy, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP transport: %w", err)
+	}
+
+	return &ClientCredentialsSource{
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+		client:       &http.Client{Transport: transport},
+	}, nil
+}
+
+// Token returns the cached token, fetching a new one if it is missing or about
+// to expire.
+func (c *ClientCredentialsSource) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Add(tokenExpiryDelta).Before(c.expiry) {
+		return c.token, nil
+	}
+
+	token, expiresIn, err := c.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.token = token
+	c.expiry = time.Time{}
+	if expiresIn > 0 {
+		c.expiry = time.Now().Add(expiresIn)
+	}
+	return token, nil
+}
+
+// tokenResponse is the token endpoint's response (RFC 6749 sections 5.1 and 5.2).
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// fetch requests a new token from the token endpoint.
+func (c *ClientCredentialsSource) fetch(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.scopes) > 0 {
+		form.Set("scope", strings.Join(c.scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	var tr tokenResponse
+	jsonErr := json.Unmarshal(body, &tr)
+
+	if resp.StatusCode != http.StatusOK {
+		if jsonErr == nil && tr.Error != "" {
+			if tr.ErrorDescription != "" {
+				return "", 0, fmt.Errorf("token endpoint returned %d: %s: %s", resp.StatusCode, tr.Error, tr.ErrorDescription)
+			}
+			return "", 0, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, tr.Error)
+		}
+		return "", 0, fmt.Errorf("token endpoint returned %d", resp.StatusCode)
+	}
+	if jsonErr != nil {
+		return "", 0, fmt.Errorf("failed to parse token response: %w", jsonErr)
+	}
+	if tr.AccessToken == "" {
+		return "", 0, fmt.Errorf("token response has no access_token")
+	}
+	if tr.TokenType != "" && !strings.EqualFold(tr.TokenType, "bearer") {
+		return "", 0, fmt.Errorf("unsupported token type %q", tr.TokenType)
+	}
+
+	return tr.AccessToken, time.Duration(tr.ExpiresIn) * time.Second, nil
+}
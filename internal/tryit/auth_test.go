@@ -0,0 +1,98 @@
+package tryit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startTokenServer starts a stub OAuth2 token endpoint that issues
+// "token-1", "token-2", ... and counts requests.
+func startTokenServer(t *testing.T, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "reflect" || secret != "s3cret" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error": "invalid_client", "error_description": "bad credentials"}`))
+			return
+		}
+		if got := r.FormValue("grant_type"); got != "client_credentials" {
+			t.Errorf("Expected grant_type=client_credentials, got %q", got)
+		}
+		if got := r.FormValue("scope"); got != "read write" {
+			t.Errorf("Expected scope %q, got %q", "read write", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClientCredentialsSource(t *testing.T) {
+	var calls atomic.Int32
+	tokenServer := startTokenServer(t, &calls)
+	ctx := context.Background()
+
+	source, err := NewClientCredentialsSource(tokenServer.URL, "reflect", "s3cret", []string{"read", "write"}, false, "")
+	if err != nil {
+		t.Fatalf("NewClientCredentialsSource() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		token, err := source.Token(ctx)
+		if err != nil {
+			t.Fatalf("Token() error = %v", err)
+		}
+		if token != "token-1" {
+			t.Errorf("Expected cached token %q, got %q", "token-1", token)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected 1 token request, got %d", got)
+	}
+
+	// A token about to expire is refreshed
+	source.expiry = time.Now().Add(tokenExpiryDelta / 2)
+	token, err := source.Token(ctx)
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if token != "token-2" {
+		t.Errorf("Expected refreshed token %q, got %q", "token-2", token)
+	}
+}
+
+func TestClientCredentialsSourceError(t *testing.T) {
+	var calls atomic.Int32
+	tokenServer := startTokenServer(t, &calls)
+
+	source, err := NewClientCredentialsSource(tokenServer.URL, "reflect", "wrong", nil, false, "")
+	if err != nil {
+		t.Fatalf("NewClientCredentialsSource() error = %v", err)
+	}
+
+	_, err = source.Token(context.Background())
+	if err == nil {
+		t.Fatal("Expected error for rejected credentials")
+	}
+	if !strings.Contains(err.Error(), "invalid_client: bad credentials") {
+		t.Errorf("Expected OAuth2 error in message, got %q", err.Error())
+	}
+}
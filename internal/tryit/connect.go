@@ -3,7 +3,7 @@ package tryit
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -22,13 +22,47 @@ type ConnectInvoker struct {
 // NewConnectInvoker creates a new Connect invoker.
 func NewConnectInvoker() *ConnectInvoker {
 	return &ConnectInvoker{
-		client: &http.Client{
-			// Client timeout is controlled per-request via context
-			Timeout: 0,
-		},
+		client: newHTTPClient(nil),
 	}
 }
 
+// BuildRequest builds the Connect request: a JSON POST to
+// {baseURL}/{package.Service/Method}.
+func (c *ConnectInvoker) BuildRequest(req *Request) (*OutgoingRequest, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	// Parse JSON into dynamic protobuf message
+	inputMsg, err := ParseJSONBody(req.InputMessageDescriptor(), req.JSONBody, nil)
+	if err != nil {
+		return nil, &BuildError{Err: fmt.Errorf("failed to parse JSON request: %w", err)}
+	}
+
+	// Marshal to Connect JSON format (protojson)
+	requestBytes, err := protojson.MarshalOptions{UseProtoNames: req.UseProtoNames}.Marshal(inputMsg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Set Connect protocol headers
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	header.Set("Accept", "application/json")
+
+	// Add user-provided headers
+	for key, value := range req.Headers {
+		header.Set(key, value)
+	}
+
+	return &OutgoingRequest{
+		Method: http.MethodPost,
+		URL:    c.buildConnectURL(connectBaseURL(req), req.MethodFullName()),
+		Header: header,
+		Body:   requestBytes,
+	}, nil
+}
+
 // Invoke executes a Connect RPC.
 // Connect uses HTTP POST with JSON encoding to /{package}.{Service}/{Method}.
 func (c *ConnectInvoker) Invoke(ctx context.Context, req *Request) (*Response, error) {
@@ -39,48 +73,41 @@ func (c *ConnectInvoker) Invoke(ctx context.Context, req *Request) (*Response, e
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
 
-	// Create HTTP client with TLS configuration
-	client := c.getHTTPClient(req.InsecureSkipVerify)
+	// Create HTTP client with TLS and proxy configuration
+	client, err := c.getHTTPClient(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
 
-	// Parse JSON into dynamic protobuf message
-	inputMsg := dynamicpb.NewMessage(req.InputMessageDescriptor())
-	if req.JSONBody != "" {
-		if err := protojson.Unmarshal([]byte(req.JSONBody), inputMsg); err != nil {
+	// Build the outgoing request
+	out, err := c.BuildRequest(req)
+	if err != nil {
+		var buildErr *BuildError
+		if errors.As(err, &buildErr) {
 			return &Response{
 				Status:     http.StatusBadRequest,
 				StatusText: "Bad Request",
 				Latency:    time.Since(start),
 				Error: &InvocationError{
 					Code:    http.StatusBadRequest,
-					Message: fmt.Sprintf("failed to parse JSON request: %v", err),
+					Message: buildErr.Error(),
 				},
 			}, nil
 		}
+		return nil, err
 	}
 
-	// Marshal to Connect JSON format (protojson)
-	requestBytes, err := protojson.Marshal(inputMsg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	// The base URL is an SSRF allowlist: never send the request elsewhere
+	if err := checkTargetURL(out.URL, connectBaseURL(req)); err != nil {
+		return nil, fmt.Errorf("refusing request: %w", err)
 	}
 
-	// Build Connect URL: {baseURL}/{package.Service/Method}
-	url := c.buildConnectURL(req.BaseURL, req.MethodFullName())
-
 	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(requestBytes))
+	httpReq, err := http.NewRequestWithContext(ctx, out.Method, out.URL, bytes.NewReader(out.Body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
-
-	// Set Connect protocol headers
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json")
-
-	// Add user-provided headers
-	for key, value := range req.Headers {
-		httpReq.Header.Set(key, value)
-	}
+	httpReq.Header = out.Header
 
 	// Execute request
 	httpResp, err := client.Do(httpReq)
@@ -148,11 +175,7 @@ func (c *ConnectInvoker) Invoke(ctx context.Context, req *Request) (*Response, e
 	}
 
 	// Marshal back to formatted JSON for display
-	formattedJSON, err := protojson.MarshalOptions{
-		Multiline:       true,
-		Indent:          "  ",
-		EmitUnpopulated: false,
-	}.Marshal(outputMsg)
+	formattedJSON, compact, err := marshalDisplay(req, outputMsg)
 	if err != nil {
 		// Fall back to raw response if we can't format it
 		formattedJSON = respBody
@@ -163,10 +186,20 @@ func (c *ConnectInvoker) Invoke(ctx context.Context, req *Request) (*Response, e
 		StatusText: httpResp.Status,
 		Headers:    httpResp.Header,
 		JSONBody:   string(formattedJSON),
+		Compact:    compact,
 		Latency:    time.Since(start),
 	}, nil
 }
 
+// connectBaseURL returns the base URL for HTTP requests. Requests to a Unix
+// socket use a placeholder host since the socket, not the URL, picks the server.
+func connectBaseURL(req *Request) string {
+	if _, ok := req.UnixSocketPath(); ok {
+		return "http://localhost"
+	}
+	return req.BaseURL
+}
+
 // buildConnectURL constructs the Connect protocol URL.
 // Format: {baseURL}/{package.Service/Method}
 // The method full name is already in the format "package.Service/Method".
@@ -182,18 +215,19 @@ func (c *ConnectInvoker) buildConnectURL(baseURL, methodFullName string) string
 	return baseURL + methodFullName
 }
 
-// getHTTPClient returns an HTTP client with the appropriate TLS configuration.
-func (c *ConnectInvoker) getHTTPClient(insecureSkipVerify bool) *http.Client {
-	if !insecureSkipVerify {
-		return c.client
+// getHTTPClient returns an HTTP client with the appropriate TLS and proxy
+// configuration, or one that dials the request's Unix socket.
+func (c *ConnectInvoker) getHTTPClient(req *Request) (*http.Client, error) {
+	if socketPath, ok := req.UnixSocketPath(); ok {
+		return newHTTPClient(newUnixTransport(socketPath)), nil
+	}
+	if !req.InsecureSkipVerify && req.Proxy == "" && !req.BlockPrivateNetworks {
+		return c.client, nil
 	}
 
-	// Create a client with TLS verification disabled
-	return &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
+	transport, err := sharedHTTPTransport(req.InsecureSkipVerify, req.Proxy, req.BlockPrivateNetworks)
+	if err != nil {
+		return nil, err
 	}
+	return newHTTPClient(transport), nil
 }
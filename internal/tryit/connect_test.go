@@ -0,0 +1,157 @@
+package tryit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+)
+
+func TestConnectInvokerUnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "connect.sock")
+	lis, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/grpc.health.v1.Health/Check" {
+			t.Errorf("Unexpected path %q", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"service":"users"}` {
+			t.Errorf("Unexpected body %s", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status": "SERVING"}`))
+	}))
+	srv.Listener = lis
+	srv.Start()
+	defer srv.Close()
+
+	req := healthCheckRequest("", nil)
+	req.BaseURL = "unix://" + socketPath
+	req.JSONBody = `{"service": "users"}`
+
+	resp, err := NewConnectInvoker().Invoke(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("Invoke() returned error response: %+v", resp.Error)
+	}
+	if !strings.Contains(resp.JSONBody, "SERVING") {
+		t.Errorf("Expected SERVING status, got %s", resp.JSONBody)
+	}
+}
+
+func TestConnectInvokerUseProtoNames(t *testing.T) {
+	tests := []struct {
+		name          string
+		useProtoNames bool
+		wantRequest   string
+		wantResponse  string // response key for valid_host
+	}{
+		{
+			name:         "JSON names",
+			wantRequest:  `{"listServices":"*"}`,
+			wantResponse: "validHost",
+		},
+		{
+			name:          "proto names",
+			useProtoNames: true,
+			wantRequest:   `{"list_services":"*"}`,
+			wantResponse:  "valid_host",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != tt.wantRequest {
+					t.Errorf("Expected request body %s, got %s", tt.wantRequest, body)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"validHost": "example.com"}`))
+			}))
+			defer srv.Close()
+
+			req := &Request{
+				Environment:      "test",
+				MethodDescriptor: reflectionv1.File_grpc_reflection_v1_reflection_proto.Services().ByName("ServerReflection").Methods().ByName("ServerReflectionInfo"),
+				JSONBody:         `{"list_services": "*"}`,
+				BaseURL:          srv.URL,
+				Timeout:          5 * time.Second,
+				UseProtoNames:    tt.useProtoNames,
+			}
+
+			resp, err := NewConnectInvoker().Invoke(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if resp.Error != nil {
+				t.Fatalf("Invoke() returned error response: %+v", resp.Error)
+			}
+			// protojson randomizes whitespace, so compare decoded keys
+			var body map[string]any
+			if err := json.Unmarshal([]byte(resp.JSONBody), &body); err != nil {
+				t.Fatalf("Expected JSON response, got %q: %v", resp.JSONBody, err)
+			}
+			if body[tt.wantResponse] != "example.com" {
+				t.Errorf("Expected response key %q, got %s", tt.wantResponse, resp.JSONBody)
+			}
+		})
+	}
+}
+
+func TestConnectInvokerPrettyPrintMaxBytes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status": "SERVING"}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name        string
+		maxBytes    int64
+		wantCompact bool
+	}{
+		{name: "no limit", maxBytes: 0},
+		{name: "under limit", maxBytes: 1024},
+		{name: "over limit", maxBytes: 10, wantCompact: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := healthCheckRequest("", nil)
+			req.BaseURL = srv.URL
+			req.PrettyPrintMaxBytes = tt.maxBytes
+
+			resp, err := NewConnectInvoker().Invoke(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if resp.Error != nil {
+				t.Fatalf("Invoke() returned error response: %+v", resp.Error)
+			}
+			if resp.Compact != tt.wantCompact {
+				t.Errorf("Compact = %v, want %v", resp.Compact, tt.wantCompact)
+			}
+			if multiline := strings.Contains(resp.JSONBody, "\n"); multiline == tt.wantCompact {
+				t.Errorf("Expected multiline body %v, got %q", !tt.wantCompact, resp.JSONBody)
+			}
+			if !strings.Contains(resp.JSONBody, "SERVING") {
+				t.Errorf("Expected SERVING status, got %s", resp.JSONBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package tryit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ChangeKind is the kind of a JSONChange.
+type ChangeKind string
+
+const (
+	// ChangeAdded is a value present only on the right.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved is a value present only on the left.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeChanged is a value present on both sides with different contents.
+	ChangeChanged ChangeKind = "changed"
+)
+
+// JSONChange is one difference between two JSON documents.
+type JSONChange struct {
+	// Path is the location of the value (e.g. "profile.tags[1]"). It is empty
+	// for the document itself.
+	Path string `json:"path"`
+
+	// Kind is whether the value was added, removed, or changed.
+	Kind ChangeKind `json:"kind"`
+
+	// Left is the compact JSON of the left value, empty if it was added.
+	Left string `json:"left,omitempty"`
+
+	// Right is the compact JSON of the right value, empty if it was removed.
+	Right string `json:"right,omitempty"`
+}
+
+// DiffJSON compares two JSON documents and returns their differences, ordered
+// by path. Objects are compared key by key and arrays element by element, so
+// an element inserted into an array changes every element after it.
+func DiffJSON(left, right string) ([]JSONChange, error) {
+	leftValue, err := decodeJSONValue(left)
+	if err != nil {
+		return nil, fmt.Errorf("left: %w", err)
+	}
+	rightValue, err := decodeJSONValue(right)
+	if err != nil {
+		return nil, fmt.Errorf("right: %w", err)
+	}
+
+	var changes []JSONChange
+	diffJSONValues(leftValue, rightValue, "", &changes)
+	return changes, nil
+}
+
+// decodeJSONValue decodes a JSON document, keeping numbers exact.
+func decodeJSONValue(data string) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(data)))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// diffJSONValues appends the differences between two decoded JSON values.
+func diffJSONValues(left, right any, path string, changes *[]JSONChange) {
+	switch l := left.(type) {
+	case map[string]any:
+		if r, ok := right.(map[string]any); ok {
+			diffJSONObjects(l, r, path, changes)
+			return
+		}
+	case []any:
+		if r, ok := right.([]any); ok {
+			diffJSONArrays(l, r, path, changes)
+			return
+		}
+	}
+
+	leftJSON, rightJSON := compactJSON(left), compactJSON(right)
+	if leftJSON != rightJSON {
+		*changes = append(*changes, JSONChange{Path: path, Kind: ChangeChanged, Left: leftJSON, Right: rightJSON})
+	}
+}
+
+// diffJSONObjects compares two objects key by key, in sorted key order.
+func diffJSONObjects(left, right map[string]any, path string, changes *[]JSONChange) {
+	keys := make([]string, 0, len(left)+len(right))
+	for key := range left {
+		keys = append(keys, key)
+	}
+	for key := range right {
+		if _, ok := left[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyPath := joinFieldPath(path, key)
+		leftValue, inLeft := left[key]
+		rightValue, inRight := right[key]
+		switch {
+		case !inRight:
+			*changes = append(*changes, JSONChange{Path: keyPath, Kind: ChangeRemoved, Left: compactJSON(leftValue)})
+		case !inLeft:
+			*changes = append(*changes, JSONChange{Path: keyPath, Kind: ChangeAdded, Right: compactJSON(rightValue)})
+		default:
+			diffJSONValues(leftValue, rightValue, keyPath, changes)
+		}
+	}
+}
+
+// diffJSONArrays compares two arrays element by element.
+func diffJSONArrays(left, right []any, path string, changes *[]JSONChange) {
+	for i := 0; i < len(left) || i < len(right); i++ {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(right):
+			*changes = append(*changes, JSONChange{Path: itemPath, Kind: ChangeRemoved, Left: compactJSON(left[i])})
+		case i >= len(left):
+			*changes = append(*changes, JSONChange{Path: itemPath, Kind: ChangeAdded, Right: compactJSON(right[i])})
+		default:
+			diffJSONValues(left[i], right[i], itemPath, changes)
+		}
+	}
+}
+
+// compactJSON encodes a decoded JSON value without indentation. Object keys
+// are sorted, so equal values encode identically.
+func compactJSON(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,76 @@
+package tryit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		left  string
+		right string
+		want  []JSONChange
+	}{
+		{
+			name:  "identical with different key order and formatting",
+			left:  `{"id": "1", "name": "Ada"}`,
+			right: "{\n  \"name\": \"Ada\",\n  \"id\": \"1\"\n}",
+			want:  nil,
+		},
+		{
+			name:  "added, removed, and changed fields",
+			left:  `{"id":"1","name":"Ada","role":"admin"}`,
+			right: `{"id":"1","name":"Grace","email":"g@example.com"}`,
+			want: []JSONChange{
+				{Path: "email", Kind: ChangeAdded, Right: `"g@example.com"`},
+				{Path: "name", Kind: ChangeChanged, Left: `"Ada"`, Right: `"Grace"`},
+				{Path: "role", Kind: ChangeRemoved, Left: `"admin"`},
+			},
+		},
+		{
+			name:  "nested objects and arrays",
+			left:  `{"profile":{"tags":["a","b"],"age":36}}`,
+			right: `{"profile":{"tags":["a","c","d"],"age":36.0}}`,
+			want: []JSONChange{
+				{Path: "profile.age", Kind: ChangeChanged, Left: `36`, Right: `36.0`},
+				{Path: "profile.tags[1]", Kind: ChangeChanged, Left: `"b"`, Right: `"c"`},
+				{Path: "profile.tags[2]", Kind: ChangeAdded, Right: `"d"`},
+			},
+		},
+		{
+			name:  "type change",
+			left:  `{"value":{"a":1}}`,
+			right: `{"value":[1]}`,
+			want: []JSONChange{
+				{Path: "value", Kind: ChangeChanged, Left: `{"a":1}`, Right: `[1]`},
+			},
+		},
+		{
+			name:  "different documents",
+			left:  `[]`,
+			right: `{}`,
+			want: []JSONChange{
+				{Path: "", Kind: ChangeChanged, Left: `[]`, Right: `{}`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DiffJSON(tt.left, tt.right)
+			if err != nil {
+				t.Fatalf("DiffJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffJSON() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		if _, err := DiffJSON(`{}`, `{`); err == nil {
+			t.Error("Expected an error for invalid JSON")
+		}
+	})
+}
@@ -3,7 +3,14 @@ package tryit
 import (
 	"context"
 	"crypto/tls"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc"
@@ -12,7 +19,6 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/dynamicpb"
 )
@@ -26,7 +32,82 @@ func NewGRPCInvoker() *GRPCInvoker {
 	return &GRPCInvoker{}
 }
 
-// Invoke executes a gRPC RPC using dynamic invocation.
+// BuildRequest builds the gRPC request. The URL is the base URL joined with
+// the method path, the headers are the request metadata, and the body is the
+// binary protobuf message. For client-streaming methods, the body holds every
+// message in gRPC length-prefixed framing, as sent on the stream.
+func (g *GRPCInvoker) BuildRequest(req *Request) (*OutgoingRequest, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	inputMsgs, md, err := g.buildMessages(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var requestBytes []byte
+	if req.MethodDescriptor.IsStreamingClient() {
+		for _, msg := range inputMsgs {
+			payload, err := proto.Marshal(msg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal request: %w", err)
+			}
+			requestBytes = append(requestBytes, 0)
+			requestBytes = binary.BigEndian.AppendUint32(requestBytes, uint32(len(payload)))
+			requestBytes = append(requestBytes, payload...)
+		}
+	} else {
+		requestBytes, err = proto.Marshal(inputMsgs[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+
+	header := make(http.Header, len(md)+1)
+	header["content-type"] = []string{"application/grpc"}
+	for key, values := range md {
+		header[key] = values
+	}
+
+	return &OutgoingRequest{
+		Method: http.MethodPost,
+		URL:    strings.TrimSuffix(req.BaseURL, "/") + "/" + req.MethodFullName(),
+		Header: header,
+		Body:   requestBytes,
+	}, nil
+}
+
+// buildMessages parses the JSON body into the input messages and converts the
+// request headers to outgoing metadata. Unary methods yield a single message;
+// client-streaming methods take a JSON array with one element per message.
+func (g *GRPCInvoker) buildMessages(req *Request) ([]*dynamicpb.Message, metadata.MD, error) {
+	// Parse JSON into dynamic protobuf messages
+	var inputMsgs []*dynamicpb.Message
+	if req.MethodDescriptor.IsStreamingClient() {
+		msgs, err := ParseJSONMessages(req.InputMessageDescriptor(), req.JSONBody, nil)
+		if err != nil {
+			return nil, nil, &BuildError{Err: fmt.Errorf("failed to parse JSON request: %w", err)}
+		}
+		inputMsgs = msgs
+	} else {
+		msg, err := ParseJSONBody(req.InputMessageDescriptor(), req.JSONBody, nil)
+		if err != nil {
+			return nil, nil, &BuildError{Err: fmt.Errorf("failed to parse JSON request: %w", err)}
+		}
+		inputMsgs = []*dynamicpb.Message{msg}
+	}
+
+	// Add metadata from headers
+	md, err := metadataFromHeaders(req.Headers)
+	if err != nil {
+		return nil, nil, &BuildError{Err: fmt.Errorf("invalid request headers: %w", err)}
+	}
+	return inputMsgs, md, nil
+}
+
+// Invoke executes a gRPC RPC using dynamic invocation. Client-streaming and
+// bidirectional methods are run as streams; see invokeStream.
 func (g *GRPCInvoker) Invoke(ctx context.Context, req *Request) (*Response, error) {
 	start := time.Now()
 
@@ -35,36 +116,31 @@ func (g *GRPCInvoker) Invoke(ctx context.Context, req *Request) (*Response, erro
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
 
-	// Determine credentials
-	var creds credentials.TransportCredentials
-	if req.InsecureSkipVerify {
-		creds = credentials.NewTLS(&tls.Config{
-			InsecureSkipVerify: true,
-		})
-	} else {
-		// Use TLS with system cert pool
-		creds = credentials.NewTLS(&tls.Config{})
+	// Build the request messages and metadata
+	inputMsgs, md, err := g.buildMessages(req)
+	if err != nil {
+		var buildErr *BuildError
+		if errors.As(err, &buildErr) {
+			return &Response{
+				Status:     int(codes.InvalidArgument),
+				StatusText: "Invalid Argument",
+				Latency:    time.Since(start),
+				Error: &InvocationError{
+					Code:    int(codes.InvalidArgument),
+					Message: buildErr.Error(),
+				},
+			}, nil
+		}
+		return nil, err
 	}
 
-	// Determine if we should use TLS based on the URL scheme
-	target := req.BaseURL
-	if target[:4] == "http" {
-		// Strip http:// or https:// prefix for gRPC dial
-		if target[:8] == "https://" {
-			target = target[8:]
-		} else if target[:7] == "http://" {
-			target = target[7:]
-			// For http:// URLs, use insecure credentials
-			creds = insecure.NewCredentials()
-		}
+	target, dialOpts, err := dialTarget(req)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create gRPC connection
-	conn, err := grpc.Dial(
-		target,
-		grpc.WithTransportCredentials(creds),
-		grpc.WithDefaultCallOptions(grpc.WaitForReady(false)),
-	)
+	conn, err := grpc.Dial(target, dialOpts...)
 	if err != nil {
 		return &Response{
 			Status:     int(codes.Unavailable),
@@ -78,29 +154,15 @@ func (g *GRPCInvoker) Invoke(ctx context.Context, req *Request) (*Response, erro
 	}
 	defer conn.Close()
 
-	// Parse JSON into dynamic protobuf message
-	inputMsg := dynamicpb.NewMessage(req.InputMessageDescriptor())
-	if req.JSONBody != "" {
-		if err := protojson.Unmarshal([]byte(req.JSONBody), inputMsg); err != nil {
-			return &Response{
-				Status:     int(codes.InvalidArgument),
-				StatusText: "Invalid Argument",
-				Latency:    time.Since(start),
-				Error: &InvocationError{
-					Code:    int(codes.InvalidArgument),
-					Message: fmt.Sprintf("failed to parse JSON request: %v", err),
-				},
-			}, nil
-		}
+	ctx = metadata.NewOutgoingContext(ctx, md)
+
+	if req.MethodDescriptor.IsStreamingClient() {
+		return g.invokeStream(ctx, conn, req, inputMsgs, start), nil
 	}
 
 	// Create output message
 	outputMsg := dynamicpb.NewMessage(req.OutputMessageDescriptor())
 
-	// Add metadata from headers
-	md := metadata.New(req.Headers)
-	ctx = metadata.NewOutgoingContext(ctx, md)
-
 	// Build full method name for gRPC: /package.Service/Method
 	fullMethod := "/" + req.MethodFullName()
 
@@ -109,7 +171,7 @@ func (g *GRPCInvoker) Invoke(ctx context.Context, req *Request) (*Response, erro
 	err = conn.Invoke(
 		ctx,
 		fullMethod,
-		inputMsg,
+		inputMsgs[0],
 		outputMsg,
 		grpc.Header(&responseHeader),
 	)
@@ -124,57 +186,284 @@ func (g *GRPCInvoker) Invoke(ctx context.Context, req *Request) (*Response, erro
 
 	// Handle error
 	if err != nil {
-		st, ok := status.FromError(err)
-		if !ok {
-			return &Response{
-				Status:     int(codes.Unknown),
-				StatusText: "Unknown Error",
-				Headers:    headers,
-				Latency:    latency,
-				Error: &InvocationError{
-					Code:    int(codes.Unknown),
-					Message: fmt.Sprintf("RPC failed: %v", err),
-				},
-			}, nil
+		return errorResponse(err, headers, latency), nil
+	}
+
+	body, compact := formatMessage(req, outputMsg)
+	return &Response{
+		Status:     int(codes.OK),
+		StatusText: codes.OK.String(),
+		Headers:    headers,
+		JSONBody:   body,
+		Compact:    compact,
+		Latency:    latency,
+	}, nil
+}
+
+// dialTarget returns the gRPC dial target and options for the request's base
+// URL: TLS for https:// (optionally unverified), plaintext for http:// and
+// unix:// sockets, routed through the configured proxy.
+func dialTarget(req *Request) (string, []grpc.DialOption, error) {
+	// Determine credentials
+	var creds credentials.TransportCredentials
+	if req.InsecureSkipVerify {
+		creds = credentials.NewTLS(&tls.Config{
+			InsecureSkipVerify: true,
+		})
+	} else {
+		// Use TLS with system cert pool
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+
+	// Determine if we should use TLS based on the URL scheme
+	target := req.BaseURL
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		// Dial only the base URL's host and port, so a path or user info in
+		// it cannot change where the connection goes
+		baseURL, err := url.Parse(target)
+		if err != nil || baseURL.Host == "" {
+			return "", nil, fmt.Errorf("invalid base URL %q", req.BaseURL)
+		}
+		target = baseURL.Host
+		if baseURL.Scheme == "http" {
+			// For http:// URLs, use insecure credentials
+			creds = insecure.NewCredentials()
+		}
+	}
+	_, unixSocket := req.UnixSocketPath()
+	if unixSocket {
+		// gRPC dials unix:///path targets itself; the socket is local, so no TLS
+		creds = insecure.NewCredentials()
+	}
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.WaitForReady(false)),
+	}
+
+	// Check every address gRPC dials, directly or through the configured
+	// proxy. The custom dialer means HTTPS_PROXY is not consulted.
+	if req.BlockPrivateNetworks && !unixSocket {
+		guard := &networkGuard{}
+		dialer, err := guard.grpcDialer(req.Proxy)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create proxy dialer: %w", err)
+		}
+		return target, append(dialOpts, grpc.WithContextDialer(dialer)), nil
+	}
+
+	// Route through the configured proxy; otherwise gRPC respects HTTPS_PROXY
+	if req.Proxy != "" && !unixSocket {
+		dialer, err := proxyDialer(req.Proxy)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create proxy dialer: %w", err)
+		}
+		dialOpts = append(dialOpts, grpc.WithContextDialer(dialer))
+	}
+
+	return target, dialOpts, nil
+}
+
+// invokeStream runs a client-streaming or bidirectional RPC. The messages are
+// sent from a separate goroutine, so replies from a bidi server are read as
+// they arrive, and the send side is half-closed once every message is sent.
+// The response carries the final status of the stream; for bidi methods the
+// body is a JSON array of the messages received, including those received
+// before an error.
+func (g *GRPCInvoker) invokeStream(ctx context.Context, conn *grpc.ClientConn, req *Request, inputMsgs []*dynamicpb.Message, start time.Time) *Response {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	desc := &grpc.StreamDesc{
+		StreamName:    string(req.MethodDescriptor.Name()),
+		ClientStreams: true,
+		ServerStreams: req.MethodDescriptor.IsStreamingServer(),
+	}
+	stream, err := conn.NewStream(ctx, desc, "/"+req.MethodFullName())
+	if err != nil {
+		return errorResponse(err, nil, time.Since(start))
+	}
+
+	sendDone := make(chan error, 1)
+	go func() {
+		for _, msg := range inputMsgs {
+			if err := stream.SendMsg(msg); err != nil {
+				// io.EOF means the server ended the stream; its status is
+				// returned by RecvMsg. Any other error aborts the stream.
+				if !errors.Is(err, io.EOF) {
+					sendDone <- err
+					cancel()
+					return
+				}
+				sendDone <- nil
+				return
+			}
 		}
+		sendDone <- stream.CloseSend()
+	}()
 
-		// Extract error details
-		details := make([]string, 0, len(st.Details()))
-		for _, detail := range st.Details() {
-			details = append(details, fmt.Sprintf("%v", detail))
+	var outputMsgs []*dynamicpb.Message
+	var recvErr error
+	for {
+		outputMsg := dynamicpb.NewMessage(req.OutputMessageDescriptor())
+		if recvErr = stream.RecvMsg(outputMsg); recvErr != nil {
+			break
 		}
+		outputMsgs = append(outputMsgs, outputMsg)
+		if !desc.ServerStreams {
+			// A client-streaming RPC ends with its single response
+			break
+		}
+	}
+	if sendErr := <-sendDone; sendErr != nil {
+		recvErr = sendErr
+	}
 
+	latency := time.Since(start)
+
+	// Convert metadata to header map
+	headers := make(map[string][]string)
+	if responseHeader, err := stream.Header(); err == nil {
+		for k, v := range responseHeader {
+			headers[k] = v
+		}
+	}
+
+	var body string
+	var compact bool
+	if desc.ServerStreams {
+		body, compact = formatMessages(req, outputMsgs)
+	} else if len(outputMsgs) > 0 {
+		body, compact = formatMessage(req, outputMsgs[0])
+	}
+
+	if recvErr != nil && !errors.Is(recvErr, io.EOF) {
+		resp := errorResponse(recvErr, headers, latency)
+		if len(outputMsgs) > 0 {
+			resp.JSONBody = body
+			resp.Compact = compact
+		}
+		return resp
+	}
+
+	return &Response{
+		Status:     int(codes.OK),
+		StatusText: codes.OK.String(),
+		Headers:    headers,
+		JSONBody:   body,
+		Compact:    compact,
+		Latency:    latency,
+	}
+}
+
+// formatMessage renders a response message as JSON for display, reporting
+// whether it was left compact.
+func formatMessage(req *Request, msg proto.Message) (string, bool) {
+	formattedJSON, compact, err := marshalDisplay(req, msg)
+	if err != nil {
+		// Fall back to binary format description
+		return fmt.Sprintf("{\"error\": \"failed to format response: %v\"}", err), false
+	}
+	return string(formattedJSON), compact
+}
+
+// formatMessages renders streamed response messages as a JSON array with one
+// message per element, reporting whether any message was left compact.
+func formatMessages(req *Request, msgs []*dynamicpb.Message) (string, bool) {
+	if len(msgs) == 0 {
+		return "[]", false
+	}
+
+	var b strings.Builder
+	var anyCompact bool
+	b.WriteString("[\n")
+	for i, msg := range msgs {
+		formatted, compact := formatMessage(req, msg)
+		anyCompact = anyCompact || compact
+		b.WriteString("  " + strings.ReplaceAll(formatted, "\n", "\n  "))
+		if i < len(msgs)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("]")
+	return b.String(), anyCompact
+}
+
+// errorResponse converts an RPC error to a response, using the gRPC status
+// carried by the error when there is one.
+func errorResponse(err error, headers map[string][]string, latency time.Duration) *Response {
+	st, ok := status.FromError(err)
+	if !ok {
 		return &Response{
-			Status:     int(st.Code()),
-			StatusText: st.Code().String(),
+			Status:     int(codes.Unknown),
+			StatusText: "Unknown Error",
 			Headers:    headers,
 			Latency:    latency,
 			Error: &InvocationError{
-				Code:    int(st.Code()),
-				Message: st.Message(),
-				Details: details,
+				Code:    int(codes.Unknown),
+				Message: fmt.Sprintf("RPC failed: %v", err),
 			},
-		}, nil
+		}
 	}
 
-	// Marshal response to JSON for display
-	formattedJSON, err := protojson.MarshalOptions{
-		Multiline:       true,
-		Indent:          "  ",
-		EmitUnpopulated: false,
-	}.Marshal(outputMsg)
-	if err != nil {
-		// Fall back to binary format description
-		formattedJSON = []byte(fmt.Sprintf("{\"error\": \"failed to format response: %v\"}", err))
+	// Extract error details
+	details := make([]string, 0, len(st.Details()))
+	for _, detail := range st.Details() {
+		details = append(details, fmt.Sprintf("%v", detail))
 	}
 
 	return &Response{
-		Status:     int(codes.OK),
-		StatusText: codes.OK.String(),
+		Status:     int(st.Code()),
+		StatusText: st.Code().String(),
 		Headers:    headers,
-		JSONBody:   string(formattedJSON),
 		Latency:    latency,
-	}, nil
+		Error: &InvocationError{
+			Code:    int(st.Code()),
+			Message: st.Message(),
+			Details: details,
+		},
+	}
+}
+
+// metadataFromHeaders builds outgoing gRPC metadata from request headers.
+// gRPC metadata keys must be lowercase, so keys are normalized (e.g. "X-Api-Key"
+// becomes "x-api-key"). Keys that are not legal metadata keys, or that use the
+// reserved "grpc-" prefix, are rejected.
+func metadataFromHeaders(headers map[string]string) (metadata.MD, error) {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	md := metadata.MD{}
+	for _, key := range keys {
+		normalized := strings.ToLower(key)
+		if err := validateMetadataKey(normalized); err != nil {
+			return nil, fmt.Errorf("header %q: %w", key, err)
+		}
+		md.Append(normalized, headers[key])
+	}
+	return md, nil
+}
+
+// validateMetadataKey checks that a lowercase key is a legal gRPC metadata key:
+// non-empty, made of [0-9a-z-_.], and not using the reserved "grpc-" prefix.
+func validateMetadataKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("metadata key cannot be empty")
+	}
+	if strings.HasPrefix(key, "grpc-") {
+		return fmt.Errorf("metadata keys starting with \"grpc-\" are reserved")
+	}
+	for _, r := range key {
+		isLegal := (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' || r == '.'
+		if !isLegal {
+			return fmt.Errorf("invalid character %q in metadata key (allowed: 0-9, a-z, '-', '_', '.')", r)
+		}
+	}
+	return nil
 }
 
 // marshalProto is a helper to marshal a proto message (unused but kept for reference).
@@ -0,0 +1,392 @@
+package tryit
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestGRPCInvokerLowercasesHeaders(t *testing.T) {
+	received := make(chan metadata.MD, 1)
+	target := startHealthServer(t, grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		received <- md
+		return handler(ctx, req)
+	}))
+
+	resp, err := NewGRPCInvoker().Invoke(context.Background(), healthCheckRequest(target, map[string]string{
+		"X-Api-Key":    "secret",
+		"X-Request-ID": "abc123",
+	}))
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("Invoke() returned error response: %+v", resp.Error)
+	}
+
+	md := <-received
+	if got := md.Get("x-api-key"); len(got) != 1 || got[0] != "secret" {
+		t.Errorf("Expected x-api-key=secret, got %v", got)
+	}
+	if got := md.Get("x-request-id"); len(got) != 1 || got[0] != "abc123" {
+		t.Errorf("Expected x-request-id=abc123, got %v", got)
+	}
+	for key := range md {
+		if key == "X-Api-Key" || key == "X-Request-ID" {
+			t.Errorf("Expected metadata key %q to be lowercased", key)
+		}
+	}
+}
+
+func TestGRPCInvokerRejectsInvalidHeaders(t *testing.T) {
+	target := startHealthServer(t)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+	}{
+		{"space in key", map[string]string{"X Api Key": "secret"}},
+		{"colon in key", map[string]string{"x-api:key": "secret"}},
+		{"reserved grpc prefix", map[string]string{"Grpc-Timeout": "1S"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewGRPCInvoker().Invoke(context.Background(), healthCheckRequest(target, tt.headers))
+			if err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if resp.Error == nil {
+				t.Fatal("Expected error response for invalid header key")
+			}
+			if resp.Status != int(codes.InvalidArgument) {
+				t.Errorf("Expected status %d, got %d", codes.InvalidArgument, resp.Status)
+			}
+		})
+	}
+}
+
+// healthCheckRequest builds a Try It request for grpc.health.v1.Health/Check.
+func TestGRPCInvokerBuildRequest(t *testing.T) {
+	req := healthCheckRequest("localhost:50051", map[string]string{"X-Request-ID": "abc123"})
+	req.JSONBody = `{"service": "users"}`
+
+	out, err := NewGRPCInvoker().BuildRequest(req)
+	if err != nil {
+		t.Fatalf("BuildRequest() error = %v", err)
+	}
+
+	if want := "http://localhost:50051/grpc.health.v1.Health/Check"; out.URL != want {
+		t.Errorf("Expected URL %q, got %q", want, out.URL)
+	}
+	if got := out.Header["x-request-id"]; len(got) != 1 || got[0] != "abc123" {
+		t.Errorf("Expected x-request-id metadata, got %v", out.Header)
+	}
+
+	var msg healthpb.HealthCheckRequest
+	if err := proto.Unmarshal(out.Body, &msg); err != nil {
+		t.Fatalf("Failed to unmarshal body: %v", err)
+	}
+	if msg.GetService() != "users" {
+		t.Errorf("Expected service %q, got %q", "users", msg.GetService())
+	}
+
+	req.JSONBody = `{"service": 1}`
+	var buildErr *BuildError
+	if _, err := NewGRPCInvoker().BuildRequest(req); !errors.As(err, &buildErr) {
+		t.Errorf("Expected BuildError for invalid body, got %v", err)
+	}
+}
+
+func TestGRPCInvokerUnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "grpc.sock")
+	lis, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	req := healthCheckRequest("", nil)
+	req.BaseURL = "unix://" + socketPath
+
+	resp, err := NewGRPCInvoker().Invoke(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("Invoke() returned error response: %+v", resp.Error)
+	}
+	if !strings.Contains(resp.JSONBody, "SERVING") {
+		t.Errorf("Expected SERVING status, got %s", resp.JSONBody)
+	}
+}
+
+func TestGRPCInvokerClientStreaming(t *testing.T) {
+	target := startStreamServer(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus codes.Code
+		wantBody   string // JSON response body, compared after decoding
+	}{
+		{
+			name:       "client streaming",
+			method:     "Count",
+			body:       `["a", "b", "c"]`,
+			wantStatus: codes.OK,
+			wantBody:   "3",
+		},
+		{
+			name:       "client streaming with no messages",
+			method:     "Count",
+			body:       `[]`,
+			wantStatus: codes.OK,
+			wantBody:   "0",
+		},
+		{
+			name:       "client streaming error status",
+			method:     "Count",
+			body:       `["a", "fail"]`,
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name:       "bidi",
+			method:     "Echo",
+			body:       `["a", "b"]`,
+			wantStatus: codes.OK,
+			wantBody:   `["a","b"]`,
+		},
+		{
+			name:       "bidi error keeps received messages",
+			method:     "Echo",
+			body:       `["a", "fail", "c"]`,
+			wantStatus: codes.InvalidArgument,
+			wantBody:   `["a"]`,
+		},
+		{
+			name:       "body is not an array",
+			method:     "Echo",
+			body:       `"a"`,
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewGRPCInvoker().Invoke(context.Background(), streamRequest(target, tt.method, tt.body))
+			if err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if resp.Status != int(tt.wantStatus) {
+				t.Fatalf("Expected status %v, got %d %s (error: %+v)", tt.wantStatus, resp.Status, resp.StatusText, resp.Error)
+			}
+			if (resp.Error == nil) != (tt.wantStatus == codes.OK) {
+				t.Errorf("Expected error only for non-OK status, got %+v", resp.Error)
+			}
+
+			if tt.wantBody == "" {
+				if resp.JSONBody != "" {
+					t.Errorf("Expected no body, got %s", resp.JSONBody)
+				}
+				return
+			}
+			// protojson randomizes whitespace, so compare decoded values
+			var got, want any
+			if err := json.Unmarshal([]byte(resp.JSONBody), &got); err != nil {
+				t.Fatalf("Expected JSON body, got %q: %v", resp.JSONBody, err)
+			}
+			json.Unmarshal([]byte(tt.wantBody), &want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected body %s, got %s", tt.wantBody, resp.JSONBody)
+			}
+		})
+	}
+}
+
+func TestGRPCInvokerBuildRequestClientStreaming(t *testing.T) {
+	out, err := NewGRPCInvoker().BuildRequest(streamRequest("localhost:50051", "Echo", `["a", "bc"]`))
+	if err != nil {
+		t.Fatalf("BuildRequest() error = %v", err)
+	}
+
+	// Each message is framed as a compression flag, a 4-byte length, and the payload
+	var values []string
+	for body := out.Body; len(body) > 0; {
+		if len(body) < 5 {
+			t.Fatalf("Truncated frame header in %x", out.Body)
+		}
+		size := int(binary.BigEndian.Uint32(body[1:5]))
+		var msg wrapperspb.StringValue
+		if err := proto.Unmarshal(body[5:5+size], &msg); err != nil {
+			t.Fatalf("Failed to unmarshal frame: %v", err)
+		}
+		values = append(values, msg.GetValue())
+		body = body[5+size:]
+	}
+	if !reflect.DeepEqual(values, []string{"a", "bc"}) {
+		t.Errorf("Expected framed messages [a bc], got %v", values)
+	}
+}
+
+// streamTestFile describes test.v1.StreamService, whose client-streaming
+// Count method returns the number of strings received and whose bidi Echo
+// method echoes each string back. Both fail on the string "fail".
+var streamTestFile = &descriptorpb.FileDescriptorProto{
+	Name:       proto.String("test/v1/stream.proto"),
+	Package:    proto.String("test.v1"),
+	Dependency: []string{"google/protobuf/wrappers.proto"},
+	Syntax:     proto.String("proto3"),
+	Service: []*descriptorpb.ServiceDescriptorProto{{
+		Name: proto.String("StreamService"),
+		Method: []*descriptorpb.MethodDescriptorProto{
+			{
+				Name:            proto.String("Count"),
+				InputType:       proto.String(".google.protobuf.StringValue"),
+				OutputType:      proto.String(".google.protobuf.Int32Value"),
+				ClientStreaming: proto.Bool(true),
+			},
+			{
+				Name:            proto.String("Echo"),
+				InputType:       proto.String(".google.protobuf.StringValue"),
+				OutputType:      proto.String(".google.protobuf.StringValue"),
+				ClientStreaming: proto.Bool(true),
+				ServerStreaming: proto.Bool(true),
+			},
+		},
+	}},
+}
+
+// streamRequest builds a Try It request for a test.v1.StreamService method.
+func streamRequest(target, method, body string) *Request {
+	file, err := protodesc.NewFile(streamTestFile, protoregistry.GlobalFiles)
+	if err != nil {
+		panic(err)
+	}
+	return &Request{
+		Environment:      "test",
+		MethodDescriptor: file.Services().ByName("StreamService").Methods().ByName(protoreflect.Name(method)),
+		JSONBody:         body,
+		BaseURL:          "http://" + target,
+		Timeout:          5 * time.Second,
+	}
+}
+
+// startStreamServer starts a plaintext gRPC server exposing the stub
+// test.v1.StreamService and returns its address.
+func startStreamServer(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	s := grpc.NewServer()
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "test.v1.StreamService",
+		HandlerType: (*any)(nil),
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    "Count",
+				ClientStreams: true,
+				Handler: func(_ any, stream grpc.ServerStream) error {
+					var count int32
+					for {
+						var msg wrapperspb.StringValue
+						if err := stream.RecvMsg(&msg); err == io.EOF {
+							return stream.SendMsg(wrapperspb.Int32(count))
+						} else if err != nil {
+							return err
+						}
+						if msg.GetValue() == "fail" {
+							return status.Error(codes.InvalidArgument, "fail requested")
+						}
+						count++
+					}
+				},
+			},
+			{
+				StreamName:    "Echo",
+				ClientStreams: true,
+				ServerStreams: true,
+				Handler: func(_ any, stream grpc.ServerStream) error {
+					for {
+						var msg wrapperspb.StringValue
+						if err := stream.RecvMsg(&msg); err == io.EOF {
+							return nil
+						} else if err != nil {
+							return err
+						}
+						if msg.GetValue() == "fail" {
+							return status.Error(codes.InvalidArgument, "fail requested")
+						}
+						if err := stream.SendMsg(&msg); err != nil {
+							return err
+						}
+					}
+				},
+			},
+		},
+	}, struct{}{})
+
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	return lis.Addr().String()
+}
+
+func healthCheckRequest(target string, headers map[string]string) *Request {
+	return &Request{
+		Environment:      "test",
+		MethodDescriptor: healthpb.File_grpc_health_v1_health_proto.Services().ByName("Health").Methods().ByName("Check"),
+		Headers:          headers,
+		BaseURL:          "http://" + target,
+		Timeout:          5 * time.Second,
+	}
+}
+
+// startHealthServer starts a plaintext gRPC server exposing the health service
+// and returns its address.
+func startHealthServer(t *testing.T, opts ...grpc.ServerOption) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	s := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(s, health.NewServer())
+
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	return lis.Addr().String()
+}
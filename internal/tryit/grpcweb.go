@@ -3,20 +3,18 @@ package tryit
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
-	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"google.golang.org/grpc/codes"
-	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/dynamicpb"
 )
@@ -29,40 +27,25 @@ type GRPCWebInvoker struct {
 // NewGRPCWebInvoker creates a new gRPC-Web invoker.
 func NewGRPCWebInvoker() *GRPCWebInvoker {
 	return &GRPCWebInvoker{
-		client: &http.Client{
-			// Client timeout is controlled per-request via context
-			Timeout: 0,
-		},
+		client: newHTTPClient(nil),
 	}
 }
 
-// Invoke executes a gRPC-Web RPC.
-// gRPC-Web uses HTTP POST with binary protobuf encoding to {baseURL}/{package.Service/Method}.
-func (g *GRPCWebInvoker) Invoke(ctx context.Context, req *Request) (*Response, error) {
-	start := time.Now()
-
-	// Validate request
+// BuildRequest builds the gRPC-Web request: a POST to
+// {baseURL}/{package.Service/Method} whose body is a single framed binary
+// protobuf message.
+func (g *GRPCWebInvoker) BuildRequest(req *Request) (*OutgoingRequest, error) {
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
-
-	// Create HTTP client with TLS configuration
-	client := g.getHTTPClient(req.InsecureSkipVerify)
+	if _, ok := req.UnixSocketPath(); ok {
+		return nil, &BuildError{Err: fmt.Errorf("unix:// base URLs are not supported by the grpc-web transport")}
+	}
 
 	// Parse JSON into dynamic protobuf message
-	inputMsg := dynamicpb.NewMessage(req.InputMessageDescriptor())
-	if req.JSONBody != "" {
-		if err := protojson.Unmarshal([]byte(req.JSONBody), inputMsg); err != nil {
-			return &Response{
-				Status:     int(codes.InvalidArgument),
-				StatusText: "Invalid Argument",
-				Latency:    time.Since(start),
-				Error: &InvocationError{
-					Code:    int(codes.InvalidArgument),
-					Message: fmt.Sprintf("failed to parse JSON request: %v", err),
-				},
-			}, nil
-		}
+	inputMsg, err := ParseJSONBody(req.InputMessageDescriptor(), req.JSONBody, nil)
+	if err != nil {
+		return nil, &BuildError{Err: fmt.Errorf("failed to parse JSON request: %w", err)}
 	}
 
 	// Marshal to binary protobuf
@@ -86,33 +69,80 @@ func (g *GRPCWebInvoker) Invoke(ctx context.Context, req *Request) (*Response, e
 	// Message data
 	frameBuffer.Write(requestBytes)
 
-	// Build gRPC-Web URL: {baseURL}/{package.Service/Method}
-	url := g.buildGRPCWebURL(req.BaseURL, req.MethodFullName())
-
-	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, frameBuffer)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
-	}
-
 	// Set gRPC-Web protocol headers
-	httpReq.Header.Set("Content-Type", "application/grpc-web+proto")
+	header := make(http.Header)
+	header.Set("Content-Type", "application/grpc-web+proto")
 	// Accept both binary and text formats
-	httpReq.Header.Set("Accept", "application/grpc-web+proto, application/grpc-web-text+proto")
-	httpReq.Header.Set("X-Grpc-Web", "1")
-	httpReq.Header.Set("X-User-Agent", "grpc-web-reflect/1.0")
+	header.Set("Accept", "application/grpc-web+proto, application/grpc-web-text+proto")
+	header.Set("X-Grpc-Web", "1")
+	header.Set("X-User-Agent", "grpc-web-reflect/1.0")
 
 	// Add user-provided headers (as gRPC metadata)
 	for key, value := range req.Headers {
-		httpReq.Header.Set(key, value)
+		header.Set(key, value)
 	}
 
+	return &OutgoingRequest{
+		Method: http.MethodPost,
+		URL:    g.buildGRPCWebURL(req.BaseURL, req.MethodFullName()),
+		Header: header,
+		Body:   frameBuffer.Bytes(),
+	}, nil
+}
+
+// Invoke executes a gRPC-Web RPC.
+// gRPC-Web uses HTTP POST with binary protobuf encoding to {baseURL}/{package.Service/Method}.
+func (g *GRPCWebInvoker) Invoke(ctx context.Context, req *Request) (*Response, error) {
+	start := time.Now()
+	logger := req.logger()
+
+	// Validate request
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	// Create HTTP client with TLS and proxy configuration
+	client, err := g.getHTTPClient(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	// Build the outgoing request
+	out, err := g.BuildRequest(req)
+	if err != nil {
+		var buildErr *BuildError
+		if errors.As(err, &buildErr) {
+			return &Response{
+				Status:     int(codes.InvalidArgument),
+				StatusText: "Invalid Argument",
+				Latency:    time.Since(start),
+				Error: &InvocationError{
+					Code:    int(codes.InvalidArgument),
+					Message: buildErr.Error(),
+				},
+			}, nil
+		}
+		return nil, err
+	}
+
+	// The base URL is an SSRF allowlist: never send the request elsewhere
+	if err := checkTargetURL(out.URL, req.BaseURL); err != nil {
+		return nil, fmt.Errorf("refusing request: %w", err)
+	}
+
+	// Create HTTP request
+	httpReq, err := http.NewRequestWithContext(ctx, out.Method, out.URL, bytes.NewReader(out.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header = out.Header
+
 	// Log the outgoing request
-	slog.Info("Sending gRPC-Web request",
-		"url", url,
+	logger.Debug("Sending gRPC-Web request",
+		"url", out.URL,
 		"method", httpReq.Method,
 		"contentType", httpReq.Header.Get("Content-Type"),
-		"bodyLength", frameBuffer.Len())
+		"bodyLength", len(out.Body))
 
 	// Execute request
 	httpResp, err := client.Do(httpReq)
@@ -150,7 +180,7 @@ func (g *GRPCWebInvoker) Invoke(ctx context.Context, req *Request) (*Response, e
 		debugLen = 64
 	}
 	contentType := httpResp.Header.Get("Content-Type")
-	slog.Debug("gRPC-Web response received",
+	logger.Debug("gRPC-Web response received",
 		"bodyLength", len(respBody),
 		"contentType", contentType,
 		"hexDump", hex.EncodeToString(respBody[:debugLen]))
@@ -164,16 +194,16 @@ func (g *GRPCWebInvoker) Invoke(ctx context.Context, req *Request) (*Response, e
 	looksLikeBase64 := len(respBody) > 0 && respBody[0] >= 0x20 && respBody[0] <= 0x7E
 
 	if isTextFormat || looksLikeBase64 {
-		slog.Info("Detected text/base64 format response", "contentType", contentType, "firstByte", respBody[0])
+		logger.Debug("Detected text/base64 format response", "contentType", contentType, "firstByte", respBody[0])
 		// Decode base64 response
 		decoded, err := base64.StdEncoding.DecodeString(string(respBody))
 		if err != nil {
 			// Log the actual response body to see what we're dealing with
-			slog.Warn("Failed to decode base64 response, trying as binary",
+			logger.Warn("Failed to decode base64 response, trying as binary",
 				"error", err,
 				"responseBody", string(respBody))
 		} else {
-			slog.Info("Successfully decoded base64 response", "originalLen", len(respBody), "decodedLen", len(decoded))
+			logger.Debug("Successfully decoded base64 response", "originalLen", len(respBody), "decodedLen", len(decoded))
 			respBody = decoded
 		}
 	}
@@ -185,6 +215,7 @@ func (g *GRPCWebInvoker) Invoke(ctx context.Context, req *Request) (*Response, e
 	// Parse the response frame
 	outputMsg := dynamicpb.NewMessage(req.OutputMessageDescriptor())
 	var jsonBody string
+	var compact bool
 
 	if len(respBody) > 0 {
 		// Try to parse the gRPC-Web frame
@@ -218,13 +249,10 @@ func (g *GRPCWebInvoker) Invoke(ctx context.Context, req *Request) (*Response, e
 			}
 
 			// Marshal to JSON for display
-			formattedJSON, err := protojson.MarshalOptions{
-				Multiline:       true,
-				Indent:          "  ",
-				EmitUnpopulated: false,
-			}.Marshal(outputMsg)
+			formattedJSON, compactJSON, err := marshalDisplay(req, outputMsg)
 			if err == nil {
 				jsonBody = string(formattedJSON)
+				compact = compactJSON
 			}
 		}
 	}
@@ -236,6 +264,7 @@ func (g *GRPCWebInvoker) Invoke(ctx context.Context, req *Request) (*Response, e
 			StatusText: codes.Code(grpcStatus).String(),
 			Headers:    httpResp.Header,
 			JSONBody:   jsonBody,
+			Compact:    compact,
 			Latency:    time.Since(start),
 			Error: &InvocationError{
 				Code:    grpcStatus,
@@ -249,6 +278,7 @@ func (g *GRPCWebInvoker) Invoke(ctx context.Context, req *Request) (*Response, e
 		StatusText: codes.OK.String(),
 		Headers:    httpResp.Header,
 		JSONBody:   jsonBody,
+		Compact:    compact,
 		Latency:    time.Since(start),
 	}, nil
 }
@@ -267,20 +297,17 @@ func (g *GRPCWebInvoker) buildGRPCWebURL(baseURL, methodFullName string) string
 	return baseURL + methodFullName
 }
 
-// getHTTPClient returns an HTTP client with the appropriate TLS configuration.
-func (g *GRPCWebInvoker) getHTTPClient(insecureSkipVerify bool) *http.Client {
-	if !insecureSkipVerify {
-		return g.client
+// getHTTPClient returns an HTTP client with the appropriate TLS and proxy configuration.
+func (g *GRPCWebInvoker) getHTTPClient(req *Request) (*http.Client, error) {
+	if !req.InsecureSkipVerify && req.Proxy == "" && !req.BlockPrivateNetworks {
+		return g.client, nil
 	}
 
-	// Create a client with TLS verification disabled
-	return &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
+	transport, err := sharedHTTPTransport(req.InsecureSkipVerify, req.Proxy, req.BlockPrivateNetworks)
+	if err != nil {
+		return nil, err
 	}
+	return newHTTPClient(transport), nil
 }
 
 // parseGRPCWebFrame parses a gRPC-Web response frame.
@@ -3,14 +3,23 @@ package tryit
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
 	"time"
 
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // Invoker represents a transport-agnostic RPC invoker.
 type Invoker interface {
-	// Invoke executes an RPC and returns the response.
+	// BuildRequest builds the outgoing request without sending it.
+	// Errors caused by the user's input are returned as *BuildError.
+	BuildRequest(req *Request) (*OutgoingRequest, error)
+
+	// Invoke builds the outgoing request, sends it, and returns the response.
 	Invoke(ctx context.Context, req *Request) (*Response, error)
 }
 
@@ -27,9 +36,14 @@ type Request struct {
 
 	// Headers are additional HTTP/metadata headers to include with the request.
 	// These should already be filtered through the header allowlist.
+	// The gRPC invoker lowercases keys as required for metadata and rejects
+	// illegal keys; the Connect and gRPC-Web invokers send them as HTTP headers
+	// with their case preserved.
 	Headers map[string]string
 
 	// BaseURL is the base URL of the upstream service (from environment config).
+	// A unix:///path/to/socket URL reaches a server on a Unix domain socket
+	// (connect and grpc transports only).
 	BaseURL string
 
 	// Timeout is the maximum duration for the request.
@@ -37,6 +51,36 @@ type Request struct {
 
 	// InsecureSkipVerify indicates whether to skip TLS certificate verification.
 	InsecureSkipVerify bool
+
+	// Proxy is an optional proxy URL (http, https, socks5, or socks5h) for
+	// reaching the upstream. If empty, HTTP_PROXY/HTTPS_PROXY are respected.
+	Proxy string
+
+	// BlockPrivateNetworks refuses upstreams that resolve to loopback,
+	// private, or link-local addresses. Unix sockets are exempt.
+	BlockPrivateNetworks bool
+
+	// UseProtoNames renders JSON with proto field names (e.g. user_id)
+	// instead of lowerCamelCase JSON names, both in Connect request bodies
+	// and in displayed responses. Request bodies accept either form.
+	UseProtoNames bool
+
+	// PrettyPrintMaxBytes is the largest response message, in bytes of
+	// compact JSON, that is indented for display. Larger messages are
+	// returned compact. Zero means always indent.
+	PrettyPrintMaxBytes int64
+
+	// Logger receives the invoker's diagnostic logs. If nil, slog.Default()
+	// is used.
+	Logger *slog.Logger
+}
+
+// logger returns the logger for the request.
+func (r *Request) logger() *slog.Logger {
+	if r.Logger != nil {
+		return r.Logger
+	}
+	return slog.Default()
 }
 
 // Response represents the result of an RPC invocation.
@@ -54,6 +98,10 @@ type Response struct {
 	// JSONBody is the response body converted to JSON for display.
 	JSONBody string
 
+	// Compact reports that JSONBody was left unindented because a message
+	// exceeded PrettyPrintMaxBytes.
+	Compact bool
+
 	// Latency is the total time taken for the request (including network and processing).
 	Latency time.Duration
 
@@ -61,6 +109,37 @@ type Response struct {
 	Error *InvocationError
 }
 
+// OutgoingRequest is the request an invoker sends upstream.
+type OutgoingRequest struct {
+	// Method is the HTTP method.
+	Method string
+
+	// URL is the full URL of the RPC. For gRPC, this is the base URL joined
+	// with the method path.
+	URL string
+
+	// Header holds the protocol headers and user-provided headers. For gRPC,
+	// these are the request metadata.
+	Header http.Header
+
+	// Body is the encoded request body exactly as sent on the wire.
+	Body []byte
+}
+
+// BuildError reports that a request could not be built from the user's input,
+// such as a malformed JSON body or an illegal header name.
+type BuildError struct {
+	Err error
+}
+
+func (e *BuildError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
 // InvocationError represents detailed error information from an invocation.
 type InvocationError struct {
 	// Code is the error code (gRPC code or HTTP status code).
@@ -87,6 +166,20 @@ const (
 	TransportGRPCWeb Transport = "grpc-web"
 )
 
+// NewInvoker returns the invoker for a transport.
+func NewInvoker(t Transport) (Invoker, error) {
+	switch t {
+	case TransportConnect:
+		return NewConnectInvoker(), nil
+	case TransportGRPC:
+		return NewGRPCInvoker(), nil
+	case TransportGRPCWeb:
+		return NewGRPCWebInvoker(), nil
+	default:
+		return nil, fmt.Errorf("unsupported transport: %s", t)
+	}
+}
+
 // ParseTransport converts a string to a Transport type.
 func ParseTransport(s string) (Transport, error) {
 	switch s {
@@ -114,15 +207,35 @@ func (r *Request) Validate() error {
 	if r.MethodDescriptor == nil {
 		return fmt.Errorf("method descriptor is required")
 	}
+	return r.validateTarget()
+}
+
+// validateTarget validates the fields that locate and reach the upstream,
+// which are all a ping needs.
+func (r *Request) validateTarget() error {
 	if r.BaseURL == "" {
 		return fmt.Errorf("base URL is required")
 	}
 	if r.Timeout <= 0 {
 		return fmt.Errorf("timeout must be positive")
 	}
+	if r.Proxy != "" {
+		if _, err := parseProxyURL(r.Proxy); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// UnixSocketPath returns the socket path if BaseURL uses the unix:// scheme.
+func (r *Request) UnixSocketPath() (string, bool) {
+	socketPath, ok := strings.CutPrefix(r.BaseURL, "unix://")
+	if !ok || socketPath == "" {
+		return "", false
+	}
+	return socketPath, true
+}
+
 // MethodFullName returns the fully-qualified method name in the format "package.Service/Method".
 func (r *Request) MethodFullName() string {
 	if r.MethodDescriptor == nil {
@@ -147,3 +260,24 @@ func (r *Request) OutputMessageDescriptor() protoreflect.MessageDescriptor {
 	}
 	return r.MethodDescriptor.Output()
 }
+
+// marshalDisplay renders a response message as JSON for display. It is
+// indented unless its compact form exceeds req.PrettyPrintMaxBytes, since
+// indenting large payloads is slow and memory-heavy; compact reports which.
+func marshalDisplay(req *Request, msg proto.Message) (data []byte, compact bool, err error) {
+	opts := protojson.MarshalOptions{UseProtoNames: req.UseProtoNames}
+	if req.PrettyPrintMaxBytes > 0 {
+		data, err = opts.Marshal(msg)
+		if err != nil {
+			return nil, false, err
+		}
+		if int64(len(data)) > req.PrettyPrintMaxBytes {
+			return data, true, nil
+		}
+	}
+
+	opts.Multiline = true
+	opts.Indent = "  "
+	data, err = opts.Marshal(msg)
+	return data, false, err
+}
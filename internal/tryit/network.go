@@ -0,0 +1,186 @@
+package tryit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// lookupIPAddr resolves upstream host names. Tests replace it to simulate
+// hosts that resolve to private addresses.
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// PrivateNetworkError is returned when an upstream resolves to a loopback,
+// private, or link-local address and the environment does not allow private
+// networks.
+type PrivateNetworkError struct {
+	Host string
+	IP   net.IP
+}
+
+func (e *PrivateNetworkError) Error() string {
+	if e.Host == e.IP.String() {
+		return fmt.Sprintf("upstream address %s is in a private network range; set allowPrivateNetworks: true on the environment to allow it", e.IP)
+	}
+	return fmt.Sprintf("upstream host %q resolves to %s, which is in a private network range; set allowPrivateNetworks: true on the environment to allow it", e.Host, e.IP)
+}
+
+// isPrivateIP reports whether ip is a loopback, private, link-local, or
+// unspecified address.
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsUnspecified()
+}
+
+// networkGuard dials upstreams only after checking that every address their
+// host resolves to is public. It connects to the checked address itself, so
+// a second lookup cannot swap in a private one.
+type networkGuard struct {
+	dialer net.Dialer
+
+	// proxies holds the host:port of the proxies the transport may select.
+	// They are dialed unchecked; the upstream host behind them is checked
+	// when the proxy is selected.
+	proxies map[string]bool
+}
+
+// newNetworkGuard returns a guard for a transport that sends requests through
+// proxyURL, or through the HTTP_PROXY/HTTPS_PROXY proxies if it is nil.
+func newNetworkGuard(proxyURL *url.URL) *networkGuard {
+	guard := &networkGuard{proxies: make(map[string]bool)}
+	if proxyURL != nil {
+		guard.proxies[canonicalProxyAddr(proxyURL)] = true
+		return guard
+	}
+
+	env := httpproxy.FromEnvironment()
+	for _, proxy := range []string{env.HTTPProxy, env.HTTPSProxy} {
+		if proxyURL := parseEnvProxy(proxy); proxyURL != nil {
+			guard.proxies[canonicalProxyAddr(proxyURL)] = true
+		}
+	}
+	return guard
+}
+
+// parseEnvProxy parses a proxy from the environment as net/http does, where a
+// bare host:port means an HTTP proxy. It returns nil for an empty or invalid
+// value.
+func parseEnvProxy(proxy string) *url.URL {
+	if proxy == "" {
+		return nil
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+		if proxyURL, err = url.Parse("http://" + proxy); err != nil {
+			return nil
+		}
+	}
+	return proxyURL
+}
+
+// resolve returns the addresses host resolves to, or a *PrivateNetworkError
+// if any of them is private.
+func (g *networkGuard) resolve(ctx context.Context, host string) ([]net.IPAddr, error) {
+	addrs, err := lookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs {
+		if isPrivateIP(addr.IP) {
+			return nil, &PrivateNetworkError{Host: host, IP: addr.IP}
+		}
+	}
+	return addrs, nil
+}
+
+// DialContext connects to address after checking where its host resolves.
+func (g *networkGuard) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if g.proxies[address] {
+		return g.dialer.DialContext(ctx, network, address)
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := g.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, addr := range addrs {
+		conn, err := g.dialer.DialContext(ctx, network, net.JoinHostPort(addr.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no addresses found for %s", host)
+	}
+	return nil, lastErr
+}
+
+// proxy wraps a transport's proxy selection. A proxy connects to the upstream
+// on our behalf, so the upstream host is checked here instead of at dial time.
+func (g *networkGuard) proxy(next func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, err := next(req)
+		if err != nil || proxyURL == nil {
+			return proxyURL, err
+		}
+		if _, err := g.resolve(req.Context(), req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+		return proxyURL, nil
+	}
+}
+
+// grpcDialer returns a gRPC context dialer that checks the upstream address,
+// then connects directly or through proxy.
+func (g *networkGuard) grpcDialer(proxy string) (func(ctx context.Context, addr string) (net.Conn, error), error) {
+	if proxy == "" {
+		return func(ctx context.Context, addr string) (net.Conn, error) {
+			return g.DialContext(ctx, "tcp", addr)
+		}, nil
+	}
+
+	dial, err := proxyDialer(proxy)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := g.resolve(ctx, host); err != nil {
+			return nil, err
+		}
+		return dial(ctx, addr)
+	}, nil
+}
+
+// canonicalProxyAddr returns the host:port the transport dials for proxyURL.
+func canonicalProxyAddr(proxyURL *url.URL) string {
+	if proxyURL.Port() != "" {
+		return proxyURL.Host
+	}
+	port := "80"
+	switch proxyURL.Scheme {
+	case "https":
+		port = "443"
+	case "socks5", "socks5h":
+		port = "1080"
+	}
+	return net.JoinHostPort(proxyURL.Hostname(), port)
+}
@@ -0,0 +1,126 @@
+package tryit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// fakeLookup makes every host resolve to ip for the duration of the test.
+func fakeLookup(t *testing.T, ip string) {
+	t.Helper()
+	orig := lookupIPAddr
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+	}
+	t.Cleanup(func() { lookupIPAddr = orig })
+}
+
+func TestInvokersBlockPrivateNetworks(t *testing.T) {
+	fakeLookup(t, "10.0.0.1")
+
+	invokers := map[string]Invoker{
+		"connect":  NewConnectInvoker(),
+		"grpc":     NewGRPCInvoker(),
+		"grpc-web": NewGRPCWebInvoker(),
+	}
+	for name, invoker := range invokers {
+		t.Run(name, func(t *testing.T) {
+			req := healthCheckRequest("internal.example.com:8080", nil)
+			req.BlockPrivateNetworks = true
+
+			resp, err := invoker.Invoke(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if resp.Error == nil || !strings.Contains(resp.Error.Message, "resolves to 10.0.0.1") {
+				t.Errorf("Expected the private address to be refused, got %+v", resp.Error)
+			}
+		})
+	}
+}
+
+func TestNetworkGuardDialContext(t *testing.T) {
+	fakeLookup(t, "10.0.0.1")
+
+	guard := &networkGuard{}
+	_, err := guard.DialContext(context.Background(), "tcp", "internal.example.com:443")
+
+	var privateErr *PrivateNetworkError
+	if !errors.As(err, &privateErr) {
+		t.Fatalf("DialContext() error = %v, want *PrivateNetworkError", err)
+	}
+	if privateErr.Host != "internal.example.com" || !privateErr.IP.Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("Unexpected error details: %+v", privateErr)
+	}
+	if !strings.Contains(err.Error(), "allowPrivateNetworks: true") {
+		t.Errorf("Expected the error to name the setting that allows it, got %q", err)
+	}
+}
+
+func TestNetworkGuardChecksUpstreamBehindProxy(t *testing.T) {
+	fakeLookup(t, "10.0.0.1")
+
+	transport, err := newHTTPTransport(false, "http://proxy.example.com:3128", true)
+	if err != nil {
+		t.Fatalf("newHTTPTransport() error = %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://internal.example.com/pkg.Svc/Method", nil)
+	if _, err := transport.Proxy(req); err == nil {
+		t.Error("Expected the private upstream behind the proxy to be refused")
+	}
+}
+
+func TestNetworkGuardProxies(t *testing.T) {
+	fakeLookup(t, "10.0.0.1")
+
+	proxyURL, err := parseProxyURL("http://proxy.example.com:3128")
+	if err != nil {
+		t.Fatalf("parseProxyURL() error = %v", err)
+	}
+	guard := newNetworkGuard(proxyURL)
+	if !guard.proxies["proxy.example.com:3128"] || len(guard.proxies) != 1 {
+		t.Errorf("Expected only the configured proxy to be trusted, got %v", guard.proxies)
+	}
+
+	// Any other address is checked, even on the proxy's port
+	_, err = guard.DialContext(context.Background(), "tcp", "other.example.com:3128")
+	var privateErr *PrivateNetworkError
+	if !errors.As(err, &privateErr) {
+		t.Errorf("DialContext() error = %v, want *PrivateNetworkError", err)
+	}
+
+	for _, name := range []string{"HTTP_PROXY", "http_proxy", "https_proxy"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("HTTPS_PROXY", "proxy.internal:8080")
+	if guard := newNetworkGuard(nil); !guard.proxies["proxy.internal:8080"] || len(guard.proxies) != 1 {
+		t.Errorf("Expected the environment's proxy to be trusted, got %v", guard.proxies)
+	}
+}
+
+func TestIsPrivateIP(t *testing.T) {
+	tests := map[string]bool{
+		"10.0.0.1":        true,
+		"172.16.5.4":      true,
+		"192.168.1.1":     true,
+		"127.0.0.1":       true,
+		"169.254.169.254": true,
+		"0.0.0.0":         true,
+		"::1":             true,
+		"fe80::1":         true,
+		"fd00::1":         true,
+		"203.0.113.10":    false,
+		"8.8.8.8":         false,
+		"2001:4860::1":    false,
+	}
+	for ip, want := range tests {
+		if got := isPrivateIP(net.ParseIP(ip)); got != want {
+			t.Errorf("isPrivateIP(%s) = %v, want %v", ip, got, want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package tryit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Pinger is implemented by invokers that can check an upstream is reachable
+// and speaks their protocol without invoking a method.
+type Pinger interface {
+	// Ping probes the upstream named by req.BaseURL. Only the target fields
+	// of req are required; MethodDescriptor, if set, names a method the
+	// probe may address without invoking it.
+	Ping(ctx context.Context, req *Request) (*PingResult, error)
+}
+
+// PingResult is the outcome of a ping.
+type PingResult struct {
+	// Reachable reports whether the upstream answered at all.
+	Reachable bool
+
+	// Protocol is the protocol the upstream answered with, or empty when the
+	// answer did not identify one.
+	Protocol Transport
+
+	// Message describes the answer, or why there was none.
+	Message string
+
+	// Latency is the time the probe took.
+	Latency time.Duration
+}
+
+// pingMethod is a method no upstream implements. Probing it exercises the
+// protocol without running a handler.
+const pingMethod = "reflect.ping.v1.PingService/Ping"
+
+// pingContentType is a content type no upstream accepts. Connect servers
+// reject it for a known method, listing the codecs they accept, before any
+// handler runs.
+const pingContentType = "application/x-reflect-ping"
+
+// maxPingBodyBytes bounds how much of a probe's response body is read.
+const maxPingBodyBytes = 4096
+
+// Ping posts an unsupported content type to the request's method, or to a
+// placeholder method if none is set. A Connect server answers 415 with an
+// Accept-Post header, or a Connect error body.
+func (c *ConnectInvoker) Ping(ctx context.Context, req *Request) (*PingResult, error) {
+	if err := req.validateTarget(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	client, err := c.getHTTPClient(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	method := pingMethod
+	if req.MethodDescriptor != nil {
+		method = req.MethodFullName()
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.buildConnectURL(connectBaseURL(req), method), strings.NewReader("{}"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+	httpReq.Header.Set("Content-Type", pingContentType)
+
+	return pingHTTP(client, httpReq), nil
+}
+
+// Ping posts an empty message to a placeholder method. A gRPC-Web server
+// answers with a gRPC status, normally Unimplemented.
+func (g *GRPCWebInvoker) Ping(ctx context.Context, req *Request) (*PingResult, error) {
+	if err := req.validateTarget(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if _, ok := req.UnixSocketPath(); ok {
+		return nil, fmt.Errorf("unix:// base URLs are not supported by the grpc-web transport")
+	}
+	client, err := g.getHTTPClient(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	// An empty, uncompressed message frame
+	frame := []byte{0, 0, 0, 0, 0}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, g.buildGRPCWebURL(req.BaseURL, pingMethod), bytes.NewReader(frame))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+	httpReq.Header.Set("Content-Type", "application/grpc-web+proto")
+	httpReq.Header.Set("X-Grpc-Web", "1")
+
+	return pingHTTP(client, httpReq), nil
+}
+
+// Ping calls a placeholder method. A gRPC server answers Unimplemented; any
+// status but a connection failure means the upstream speaks gRPC.
+func (g *GRPCInvoker) Ping(ctx context.Context, req *Request) (*PingResult, error) {
+	start := time.Now()
+	if err := req.validateTarget(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	md, err := metadataFromHeaders(req.Headers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request headers: %w", err)
+	}
+
+	target, dialOpts, err := dialTarget(req)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.Dial(target, dialOpts...)
+	if err != nil {
+		return &PingResult{
+			Message: fmt.Sprintf("failed to connect to gRPC server: %v", err),
+			Latency: time.Since(start),
+		}, nil
+	}
+	defer conn.Close()
+
+	ctx = metadata.NewOutgoingContext(ctx, md)
+	err = conn.Invoke(ctx, "/"+pingMethod, &emptypb.Empty{}, &emptypb.Empty{})
+	latency := time.Since(start)
+
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return &PingResult{Message: st.Message(), Latency: latency}, nil
+	default:
+		return &PingResult{
+			Reachable: true,
+			Protocol:  TransportGRPC,
+			Message:   fmt.Sprintf("gRPC status %s", st.Code()),
+			Latency:   latency,
+		}, nil
+	}
+}
+
+// pingHTTP sends an HTTP probe and identifies the protocol of the answer.
+func pingHTTP(client *http.Client, httpReq *http.Request) *PingResult {
+	start := time.Now()
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return &PingResult{Message: err.Error(), Latency: time.Since(start)}
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxPingBodyBytes))
+
+	result := &PingResult{
+		Reachable: true,
+		Protocol:  detectProtocol(resp.Header, body),
+		Message:   fmt.Sprintf("HTTP %s", resp.Status),
+		Latency:   time.Since(start),
+	}
+	if grpcStatus := resp.Header.Get("Grpc-Status"); grpcStatus != "" {
+		result.Message += ", grpc-status " + grpcStatus
+	}
+	return result
+}
+
+// detectProtocol identifies the RPC protocol of an HTTP answer from its
+// headers and body, returning "" for a plain HTTP answer.
+func detectProtocol(header http.Header, body []byte) Transport {
+	contentType := header.Get("Content-Type")
+	acceptPost := header.Get("Accept-Post")
+	switch {
+	case strings.HasPrefix(contentType, "application/grpc-web"):
+		return TransportGRPCWeb
+	case strings.HasPrefix(contentType, "application/grpc"):
+		return TransportGRPC
+	case header.Get("Grpc-Status") != "":
+		// A gRPC status over HTTP/1.1 comes from a gRPC-Web gateway
+		return TransportGRPCWeb
+	case strings.Contains(acceptPost, "application/json") || strings.Contains(acceptPost, "application/proto"):
+		return TransportConnect
+	case strings.HasPrefix(contentType, "application/json") && isConnectError(body):
+		return TransportConnect
+	default:
+		return ""
+	}
+}
+
+// isConnectError reports whether body is a Connect error: a JSON object with
+// a string "code".
+func isConnectError(body []byte) bool {
+	var connectErr struct {
+		Code string `json:"code"`
+	}
+	return json.Unmarshal(body, &connectErr) == nil && connectErr.Code != ""
+}
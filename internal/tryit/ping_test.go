@@ -0,0 +1,95 @@
+package tryit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestPing(t *testing.T) {
+	// connectStub answers like connect-go: an unsupported content type is
+	// rejected with the codecs it accepts
+	connectStub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.Header().Set("Accept-Post", "application/json, application/proto")
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer connectStub.Close()
+
+	// grpcWebStub answers like a gRPC-Web gateway without the method
+	grpcWebStub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc-web+proto")
+		w.Header().Set("Grpc-Status", "12")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer grpcWebStub.Close()
+
+	plainHTTP := httptest.NewServer(http.NotFoundHandler())
+	defer plainHTTP.Close()
+
+	// A gRPC server with no services answers every method Unimplemented
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+
+	// A port nothing listens on
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	closedURL := "http://" + closed.Addr().String()
+	closed.Close()
+
+	tests := []struct {
+		name      string
+		pinger    Pinger
+		baseURL   string
+		reachable bool
+		protocol  Transport
+	}{
+		{"connect", NewConnectInvoker(), connectStub.URL, true, TransportConnect},
+		{"grpc-web", NewGRPCWebInvoker(), grpcWebStub.URL, true, TransportGRPCWeb},
+		{"grpc", NewGRPCInvoker(), "http://" + lis.Addr().String(), true, TransportGRPC},
+		{"connect against plain HTTP", NewConnectInvoker(), plainHTTP.URL, true, ""},
+		{"grpc-web against connect", NewGRPCWebInvoker(), connectStub.URL, true, TransportConnect},
+		{"connect unreachable", NewConnectInvoker(), closedURL, false, ""},
+		{"grpc unreachable", NewGRPCInvoker(), closedURL, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			result, err := tt.pinger.Ping(ctx, &Request{BaseURL: tt.baseURL, Timeout: 5 * time.Second})
+			if err != nil {
+				t.Fatalf("Ping() error = %v", err)
+			}
+			if result.Reachable != tt.reachable {
+				t.Errorf("Expected reachable=%v, got %v (%s)", tt.reachable, result.Reachable, result.Message)
+			}
+			if result.Protocol != tt.protocol {
+				t.Errorf("Expected protocol %q, got %q (%s)", tt.protocol, result.Protocol, result.Message)
+			}
+		})
+	}
+
+	t.Run("invalid target", func(t *testing.T) {
+		if _, err := NewConnectInvoker().Ping(context.Background(), &Request{}); err == nil {
+			t.Error("Expected an error without a base URL")
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package tryit
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	xproxy "golang.org/x/net/proxy"
+)
+
+// transportKey identifies the settings an upstream HTTP transport is built with.
+type transportKey struct {
+	insecureSkipVerify   bool
+	proxy                string
+	blockPrivateNetworks bool
+}
+
+// sharedTransports holds one transport per setting, so Try It calls with the
+// same settings reuse connections instead of leaving a transport behind each.
+var sharedTransports = struct {
+	sync.Mutex
+	byKey map[transportKey]*http.Transport
+}{byKey: make(map[transportKey]*http.Transport)}
+
+// sharedHTTPTransport returns the transport for the given settings, building
+// it with newHTTPTransport on first use.
+func sharedHTTPTransport(insecureSkipVerify bool, proxy string, blockPrivateNetworks bool) (*http.Transport, error) {
+	key := transportKey{insecureSkipVerify: insecureSkipVerify, proxy: proxy, blockPrivateNetworks: blockPrivateNetworks}
+
+	sharedTransports.Lock()
+	defer sharedTransports.Unlock()
+	if transport, ok := sharedTransports.byKey[key]; ok {
+		return transport, nil
+	}
+	transport, err := newHTTPTransport(insecureSkipVerify, proxy, blockPrivateNetworks)
+	if err != nil {
+		return nil, err
+	}
+	sharedTransports.byKey[key] = transport
+	return transport, nil
+}
+
+// newHTTPTransport builds an HTTP transport for upstream requests. When proxy
+// is empty, the HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment variables are used.
+// With blockPrivateNetworks, upstreams resolving to private addresses are
+// refused with a *PrivateNetworkError.
+func newHTTPTransport(insecureSkipVerify bool, proxy string, blockPrivateNetworks bool) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	transport.Proxy = http.ProxyFromEnvironment
+	var proxyURL *url.URL
+	if proxy != "" {
+		var err error
+		proxyURL, err = parseProxyURL(proxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if blockPrivateNetworks {
+		guard := newNetworkGuard(proxyURL)
+		transport.Proxy = guard.proxy(transport.Proxy)
+		transport.DialContext = guard.DialContext
+	}
+
+	if insecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: true,
+		}
+	}
+
+	return transport, nil
+}
+
+// newUnixTransport builds an HTTP transport that sends every request over the
+// Unix domain socket at socketPath, ignoring the request URL's host.
+func newUnixTransport(socketPath string) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", socketPath)
+	}
+	return transport
+}
+
+// parseProxyURL parses and validates a proxy URL.
+// Supported schemes are http, https, socks5, and socks5h.
+func parseProxyURL(proxy string) (*url.URL, error) {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q, must be one of: http, https, socks5, socks5h", proxyURL.Scheme)
+	}
+
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("proxy URL must include a host")
+	}
+
+	return proxyURL, nil
+}
+
+// proxyDialer returns a gRPC context dialer that connects through the given
+// proxy, using SOCKS5 or HTTP CONNECT depending on the proxy scheme.
+func proxyDialer(proxy string) (func(ctx context.Context, addr string) (net.Conn, error), error) {
+	proxyURL, err := parseProxyURL(proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	if proxyURL.Scheme == "socks5" || proxyURL.Scheme == "socks5h" {
+		dialer, err := xproxy.FromURL(proxyURL, xproxy.Direct)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SOCKS dialer: %w", err)
+		}
+		contextDialer, ok := dialer.(xproxy.ContextDialer)
+		if !ok {
+			return nil, fmt.Errorf("SOCKS dialer does not support contexts")
+		}
+		return func(ctx context.Context, addr string) (net.Conn, error) {
+			return contextDialer.DialContext(ctx, "tcp", addr)
+		}, nil
+	}
+
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		return dialHTTPConnect(ctx, proxyURL, addr)
+	}, nil
+}
+
+// dialHTTPConnect opens a tunnel to addr through an HTTP proxy using CONNECT.
+func dialHTTPConnect(ctx context.Context, proxyURL *url.URL, addr string) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", proxyURL.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy: %w", err)
+	}
+
+	if proxyURL.Scheme == "https" {
+		conn = tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	connectReq := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		connectReq.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := connectReq.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send CONNECT request: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, connectReq)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read CONNECT response: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy refused CONNECT to %s: %s", addr, resp.Status)
+	}
+
+	return &bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+// bufferedConn is a net.Conn that first drains bytes buffered while reading
+// the CONNECT response.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
@@ -0,0 +1,115 @@
+package tryit
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestNewHTTPTransportProxy(t *testing.T) {
+	transport, err := newHTTPTransport(false, "http://proxy.example.com:3128", false)
+	if err != nil {
+		t.Fatalf("newHTTPTransport() error = %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "https://api.example.com/echo.v1.EchoService/Echo", nil)
+	proxyURL, err := transport.Proxy(req)
+	if err != nil {
+		t.Fatalf("Proxy() error = %v", err)
+	}
+	if proxyURL == nil || proxyURL.String() != "http://proxy.example.com:3128" {
+		t.Errorf("Expected proxy URL %q, got %v", "http://proxy.example.com:3128", proxyURL)
+	}
+}
+
+func TestNewHTTPTransportInvalidProxy(t *testing.T) {
+	tests := []string{
+		"ftp://proxy.example.com",
+		"http://",
+		"://bad",
+	}
+
+	for _, proxy := range tests {
+		if _, err := newHTTPTransport(false, proxy, false); err == nil {
+			t.Errorf("Expected error for proxy %q", proxy)
+		}
+	}
+}
+
+func TestGRPCInvokerThroughHTTPProxy(t *testing.T) {
+	target := startHealthServer(t)
+
+	var connects atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "expected CONNECT", http.StatusMethodNotAllowed)
+			return
+		}
+		connects.Add(1)
+
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer upstream.Close()
+
+		client, buffered, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer client.Close()
+
+		if _, err := io.WriteString(client, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+			return
+		}
+
+		go io.Copy(upstream, buffered)
+		io.Copy(client, upstream)
+	}))
+	defer proxy.Close()
+
+	req := healthCheckRequest(target, nil)
+	req.Proxy = proxy.URL
+
+	resp, err := NewGRPCInvoker().Invoke(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if resp.Error != nil {
+		t.Fatalf("Invoke() returned error response: %+v", resp.Error)
+	}
+	if connects.Load() == 0 {
+		t.Error("Expected the RPC to be tunneled through the proxy")
+	}
+}
+
+func TestSharedHTTPTransport(t *testing.T) {
+	first, err := sharedHTTPTransport(false, "http://proxy.example.com:3128", true)
+	if err != nil {
+		t.Fatalf("sharedHTTPTransport() error = %v", err)
+	}
+	second, err := sharedHTTPTransport(false, "http://proxy.example.com:3128", true)
+	if err != nil {
+		t.Fatalf("sharedHTTPTransport() error = %v", err)
+	}
+	if first != second {
+		t.Error("Expected calls with the same settings to share a transport")
+	}
+
+	other, err := sharedHTTPTransport(false, "http://proxy.example.com:3128", false)
+	if err != nil {
+		t.Fatalf("sharedHTTPTransport() error = %v", err)
+	}
+	if other == first {
+		t.Error("Expected different settings to get a different transport")
+	}
+
+	if _, err := sharedHTTPTransport(false, "ftp://proxy.example.com", true); err == nil {
+		t.Error("Expected error for an invalid proxy")
+	}
+}
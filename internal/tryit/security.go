@@ -1,10 +1,78 @@
 package tryit
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
 )
 
+// maxRedirects is how many redirects Try It follows, as net/http does.
+const maxRedirects = 10
+
+// newHTTPClient returns a client for Try It requests. The base URL of the
+// environment acts as an SSRF allowlist, so redirects are only followed to
+// the same scheme, host, and port as the original request.
+func newHTTPClient(transport http.RoundTripper) *http.Client {
+	return &http.Client{
+		Transport:     transport,
+		CheckRedirect: checkRedirect,
+		// Client timeout is controlled per-request via context
+		Timeout: 0,
+	}
+}
+
+// checkRedirect is the http.Client CheckRedirect policy for Try It requests.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if !sameOrigin(req.URL, via[0].URL) {
+		return fmt.Errorf("redirect to %s refused: only redirects to the environment's base URL host are followed", req.URL.Redacted())
+	}
+	return nil
+}
+
+// checkTargetURL verifies that a request URL built from an environment's
+// base URL still points at the base URL's scheme, host, and port, so a
+// method name or other input cannot send the request elsewhere.
+func checkTargetURL(target, baseURL string) error {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("invalid request URL: %w", err)
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %w", err)
+	}
+	if targetURL.User != nil || !sameOrigin(targetURL, base) {
+		return errors.New("request URL does not match the environment's base URL host")
+	}
+	return nil
+}
+
+// sameOrigin reports whether two URLs have the same scheme, host, and port,
+// with default ports made explicit.
+func sameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(hostPort(a), hostPort(b))
+}
+
+// hostPort returns the URL's host and port, using the scheme's default port
+// when none is given.
+func hostPort(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		switch strings.ToLower(u.Scheme) {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		}
+	}
+	return u.Hostname() + ":" + port
+}
+
 // SensitiveHeaders is a list of headers that should never be logged or displayed.
 var SensitiveHeaders = []string{
 	"authorization",
@@ -88,13 +156,20 @@ func RedactSensitiveHeadersSingle(headers map[string]string) map[string]string {
 }
 
 // MergeHeaders merges two header maps, with override taking precedence.
-// Case is preserved from the override map.
+// Header names match case-insensitively; case is preserved from the override map.
 func MergeHeaders(base, override map[string]string) map[string]string {
 	result := make(map[string]string)
 
-	// Copy base headers
+	overridden := make(map[string]bool, len(override))
+	for k := range override {
+		overridden[strings.ToLower(k)] = true
+	}
+
+	// Copy base headers not replaced by the override
 	for k, v := range base {
-		result[k] = v
+		if !overridden[strings.ToLower(k)] {
+			result[k] = v
+		}
 	}
 
 	// Override with provided headers
@@ -0,0 +1,114 @@
+package tryit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestInvokersRefuseCrossHostRedirects(t *testing.T) {
+	var otherHits atomic.Int32
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherHits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status": "SERVING"}`))
+	}))
+	defer other.Close()
+
+	var sameHostHits atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/moved/") {
+			sameHostHits.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"status": "SERVING"}`))
+			return
+		}
+		if r.URL.Query().Get("local") == "" {
+			http.Redirect(w, r, other.URL+r.URL.Path, http.StatusTemporaryRedirect)
+			return
+		}
+		http.Redirect(w, r, "/moved"+r.URL.Path, http.StatusTemporaryRedirect)
+	}))
+	defer upstream.Close()
+
+	invokers := map[string]Invoker{
+		"connect":  NewConnectInvoker(),
+		"grpc-web": NewGRPCWebInvoker(),
+	}
+	for name, invoker := range invokers {
+		t.Run(name, func(t *testing.T) {
+			req := healthCheckRequest("", nil)
+			req.BaseURL = upstream.URL
+			req.JSONBody = `{"service": "users"}`
+
+			resp, err := invoker.Invoke(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if resp.Error == nil || !strings.Contains(resp.Error.Message, "refused") {
+				t.Errorf("Expected the redirect to another host to be refused, got %+v", resp)
+			}
+			if otherHits.Load() != 0 {
+				t.Errorf("Expected no request to the other host, got %d", otherHits.Load())
+			}
+		})
+	}
+
+	t.Run("same host", func(t *testing.T) {
+		client := newHTTPClient(nil)
+		httpReq, _ := http.NewRequest(http.MethodPost, upstream.URL+"/grpc.health.v1.Health/Check?local=1", nil)
+		resp, err := client.Do(httpReq)
+		if err != nil {
+			t.Fatalf("Expected a redirect within the host to be followed, got %v", err)
+		}
+		resp.Body.Close()
+		if sameHostHits.Load() != 1 {
+			t.Errorf("Expected the redirect target on the same host to be requested once, got %d", sameHostHits.Load())
+		}
+	})
+}
+
+func TestCheckTargetURL(t *testing.T) {
+	tests := []struct {
+		target, baseURL string
+		wantErr         bool
+	}{
+		{"https://api.example.com/pkg.Svc/Method", "https://api.example.com", false},
+		{"https://api.example.com:443/pkg.Svc/Method", "https://API.example.com", false},
+		{"http://localhost:8080/v1/pkg.Svc/Method", "http://localhost:8080/v1", false},
+		{"https://evil.example.com/pkg.Svc/Method", "https://api.example.com", true},
+		{"https://api.example.com:8443/pkg.Svc/Method", "https://api.example.com", true},
+		{"http://api.example.com/pkg.Svc/Method", "https://api.example.com", true},
+		{"https://user@api.example.com/pkg.Svc/Method", "https://api.example.com", true},
+	}
+	for _, tt := range tests {
+		err := checkTargetURL(tt.target, tt.baseURL)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkTargetURL(%q, %q) error = %v, wantErr %v", tt.target, tt.baseURL, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDialTargetUsesBaseURLHost(t *testing.T) {
+	tests := []struct {
+		baseURL, want string
+	}{
+		{"https://api.example.com", "api.example.com"},
+		{"http://localhost:9090/", "localhost:9090"},
+		{"https://user@api.example.com:8443/ignored", "api.example.com:8443"},
+		{"localhost:9090", "localhost:9090"},
+	}
+	for _, tt := range tests {
+		target, _, err := dialTarget(&Request{BaseURL: tt.baseURL})
+		if err != nil {
+			t.Errorf("dialTarget(%q) error = %v", tt.baseURL, err)
+			continue
+		}
+		if target != tt.want {
+			t.Errorf("dialTarget(%q) = %q, want %q", tt.baseURL, target, tt.want)
+		}
+	}
+}
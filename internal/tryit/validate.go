@@ -0,0 +1,268 @@
+package tryit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// TypeResolver resolves message and extension types while parsing JSON
+// (e.g. to expand google.protobuf.Any values).
+type TypeResolver interface {
+	protoregistry.MessageTypeResolver
+	protoregistry.ExtensionTypeResolver
+}
+
+// ValidationError describes why a JSON body could not be parsed into a message.
+type ValidationError struct {
+	// Path is the location of the offending value (e.g. "profile.tags[1]").
+	// It is empty when the error is not tied to a specific field.
+	Path string
+
+	// Message is the underlying parse error.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ParseJSONBody parses a JSON request body into a dynamic message of the given
+// type. An empty body yields an empty message. If resolver is nil, the global
+// registry is used. Parse failures are returned as *ValidationError.
+func ParseJSONBody(md protoreflect.MessageDescriptor, body string, resolver TypeResolver) (*dynamicpb.Message, error) {
+	msg := dynamicpb.NewMessage(md)
+	if body == "" {
+		return msg, nil
+	}
+
+	opts := protojson.UnmarshalOptions{}
+	if resolver != nil {
+		opts.Resolver = resolver
+	}
+
+	if err := opts.Unmarshal([]byte(body), msg); err != nil {
+		return nil, &ValidationError{
+			Path:    locateJSONError(md, []byte(body)),
+			Message: err.Error(),
+		}
+	}
+	return msg, nil
+}
+
+// ParseJSONMessages parses a JSON array body into a sequence of dynamic
+// messages, as sent by client-streaming RPCs. An empty body yields no
+// messages. Parse failures are returned as *ValidationError, with the path
+// prefixed by the element index (e.g. "[1].user_id").
+func ParseJSONMessages(md protoreflect.MessageDescriptor, body string, resolver TypeResolver) ([]*dynamicpb.Message, error) {
+	if strings.TrimSpace(body) == "" {
+		return nil, nil
+	}
+
+	var elements []json.RawMessage
+	if err := json.Unmarshal([]byte(body), &elements); err != nil {
+		return nil, &ValidationError{Message: fmt.Sprintf("request body must be a JSON array of messages: %v", err)}
+	}
+
+	messages := make([]*dynamicpb.Message, 0, len(elements))
+	for i, element := range elements {
+		msg, err := ParseJSONBody(md, string(element), resolver)
+		if err != nil {
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				validationErr.Path = joinIndexPath(fmt.Sprintf("[%d]", i), validationErr.Path)
+			}
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// joinIndexPath prefixes a field path with an element index.
+func joinIndexPath(index, path string) string {
+	if path == "" || strings.HasPrefix(path, "[") {
+		return index + path
+	}
+	return index + "." + path
+}
+
+// locateJSONError finds the path of the first value in data that does not fit
+// the message schema. protojson errors only carry a byte offset, so the JSON is
+// walked separately against the descriptor. Returns "" if no path is found.
+func locateJSONError(md protoreflect.MessageDescriptor, data []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return ""
+	}
+
+	path, _ := findMessageError(md, value, "")
+	return path
+}
+
+// findMessageError checks a decoded JSON value against a message descriptor.
+func findMessageError(md protoreflect.MessageDescriptor, value any, path string) (string, bool) {
+	// Well-known types have special JSON mappings; leave them to protojson.
+	if strings.HasPrefix(string(md.FullName()), "google.protobuf.") {
+		return "", false
+	}
+
+	object, ok := value.(map[string]any)
+	if !ok {
+		return path, value != nil
+	}
+
+	// Sort keys so the reported path is deterministic.
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fieldPath := joinFieldPath(path, key)
+
+		field := md.Fields().ByJSONName(key)
+		if field == nil {
+			field = md.Fields().ByName(protoreflect.Name(key))
+		}
+		if field == nil {
+			return fieldPath, true
+		}
+
+		if p, found := findFieldError(field, object[key], fieldPath); found {
+			return p, true
+		}
+	}
+
+	return "", false
+}
+
+// findFieldError checks a decoded JSON value against a field descriptor.
+func findFieldError(field protoreflect.FieldDescriptor, value any, path string) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+
+	switch {
+	case field.IsMap():
+		entries, ok := value.(map[string]any)
+		if !ok {
+			return path, true
+		}
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			entryPath := fmt.Sprintf("%s[%q]", path, key)
+			if p, found := findSingularError(field.MapValue(), entries[key], entryPath); found {
+				return p, true
+			}
+		}
+		return "", false
+
+	case field.IsList():
+		items, ok := value.([]any)
+		if !ok {
+			return path, true
+		}
+		for i, item := range items {
+			if p, found := findSingularError(field, item, fmt.Sprintf("%s[%d]", path, i)); found {
+				return p, true
+			}
+		}
+		return "", false
+
+	default:
+		return findSingularError(field, value, path)
+	}
+}
+
+// findSingularError checks a single (non-repeated) JSON value against a field's kind.
+func findSingularError(field protoreflect.FieldDescriptor, value any, path string) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return findMessageError(field.Message(), value, path)
+
+	case protoreflect.BoolKind:
+		_, ok := value.(bool)
+		return path, !ok
+
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		_, ok := value.(string)
+		return path, !ok
+
+	case protoreflect.EnumKind:
+		switch v := value.(type) {
+		case string:
+			return path, field.Enum().Values().ByName(protoreflect.Name(v)) == nil
+		case json.Number:
+			_, err := v.Int64()
+			return path, err != nil
+		}
+		return path, true
+
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		switch v := value.(type) {
+		case json.Number:
+			return "", false
+		case string:
+			if v == "NaN" || v == "Infinity" || v == "-Infinity" {
+				return "", false
+			}
+			_, err := strconv.ParseFloat(v, 64)
+			return path, err != nil
+		}
+		return path, true
+
+	default:
+		// Integer kinds accept numbers or numeric strings.
+		var s string
+		switch v := value.(type) {
+		case json.Number:
+			s = v.String()
+		case string:
+			s = v
+		default:
+			return path, true
+		}
+		if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return "", false
+		}
+		if _, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return "", false
+		}
+		// protojson also accepts integral values in exponent form (e.g. 1e3).
+		f, err := strconv.ParseFloat(s, 64)
+		return path, err != nil || f != float64(int64(f))
+	}
+}
+
+// joinFieldPath appends a field name to a dotted path.
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,85 @@
+// Package version reports build metadata for the Reflect binary.
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Build metadata, set at link time:
+//
+//	go build -ldflags "-X github.com/bnprtr/reflect/internal/version.Version=v1.2.3 \
+//	  -X github.com/bnprtr/reflect/internal/version.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/bnprtr/reflect/internal/version.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/reflect
+//
+// Values left unset are filled from the module build info where possible.
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+// Info describes the running build.
+type Info struct {
+	// Version is the release version, or "dev" for untagged builds.
+	Version string `json:"version"`
+
+	// Commit is the git commit the binary was built from, if known.
+	Commit string `json:"commit"`
+
+	// Date is the build (or commit) time in RFC 3339 format, if known.
+	Date string `json:"date"`
+
+	// GoVersion is the Go toolchain version used for the build.
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build metadata, preferring link-time values over those
+// recorded by the Go toolchain.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+	}
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	// go install module@version records the module version
+	if info.Version == "dev" && buildInfo.Main.Version != "" && buildInfo.Main.Version != "(devel)" {
+		info.Version = buildInfo.Main.Version
+	}
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.Date == "" {
+				info.Date = setting.Value
+			}
+		}
+	}
+	return info
+}
+
+// String formats the build metadata for logging.
+func (i Info) String() string {
+	s := i.Version
+	if i.Commit != "" {
+		commit := i.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		s += " (" + commit + ")"
+	}
+	if i.Date != "" {
+		s += " built " + i.Date
+	}
+	return s + " " + i.GoVersion
+}
@@ -2,7 +2,7 @@ package watcher
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,15 +11,21 @@ import (
 	"github.com/fsnotify/fsnotify"
 )
 
-// ReloadFunc is called when proto files change
-type ReloadFunc func()
+// ReloadFunc is called when watched files change. An error is logged and the
+// watcher keeps running, so the caller can keep its previous state and retry
+// on the next change.
+type ReloadFunc func() error
 
-// Watcher monitors a directory for .proto file changes
+// Watcher monitors a directory for .proto file changes, or a single file
 type Watcher struct {
 	watcher    *fsnotify.Watcher
 	root       string
 	reloadFunc ReloadFunc
 	debounce   time.Duration
+	match      func(name string) bool // reports whether an event path is watched
+	label      string                 // what is reloaded, for logging
+	recursive  bool                   // whether new subdirectories are watched
+	dirs       map[string]bool        // directories added to the watcher
 }
 
 // New creates a new file watcher for the given directory
@@ -34,10 +40,16 @@ func New(root string, reloadFunc ReloadFunc) (*Watcher, error) {
 		root:       root,
 		reloadFunc: reloadFunc,
 		debounce:   300 * time.Millisecond,
+		match: func(name string) bool {
+			return strings.HasSuffix(strings.ToLower(name), ".proto")
+		},
+		label:     "proto files",
+		recursive: true,
+		dirs:      make(map[string]bool),
 	}
 
 	// Add the root directory and all subdirectories
-	if err := w.addRecursive(root); err != nil {
+	if _, err := w.addRecursive(root); err != nil {
 		fsw.Close()
 		return nil, err
 	}
@@ -45,25 +57,104 @@ func New(root string, reloadFunc ReloadFunc) (*Watcher, error) {
 	return w, nil
 }
 
-// addRecursive adds the directory and all subdirectories to the watcher
-func (w *Watcher) addRecursive(path string) error {
-	return filepath.Walk(path, func(walkPath string, info os.FileInfo, err error) error {
+// NewFile creates a watcher for a single file. The parent directory is
+// watched so that editors which save by replacing the file are still seen.
+func NewFile(path string, reloadFunc ReloadFunc) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	base := filepath.Base(path)
+	w := &Watcher{
+		watcher:    fsw,
+		root:       dir,
+		reloadFunc: reloadFunc,
+		debounce:   300 * time.Millisecond,
+		match: func(name string) bool {
+			return filepath.Base(name) == base
+		},
+		label: base,
+	}
+
+	if err := fsw.Add(dir); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// addRecursive adds the directory and all subdirectories to the watcher,
+// skipping directories already watched. It reports whether it found a
+// watched file, which is how files created along with a new directory,
+// before its watch was added, are noticed.
+func (w *Watcher) addRecursive(path string) (bool, error) {
+	found := false
+	err := filepath.Walk(path, func(walkPath string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 		// Add directories to watch (not files, since fsnotify watches dirs)
-		if info != nil && info.IsDir() {
-			if err := w.watcher.Add(walkPath); err != nil {
-				return err
-			}
+		if info == nil || !info.IsDir() {
+			found = found || w.match(walkPath)
+			return nil
 		}
+		if w.dirs[walkPath] {
+			return nil
+		}
+		if err := w.watcher.Add(walkPath); err != nil {
+			return err
+		}
+		w.dirs[walkPath] = true
 		return nil
 	})
+	return found, err
+}
+
+// watchNewDir starts watching a directory created under the root, and its
+// subdirectories. It reports whether the directory already holds watched
+// files.
+func (w *Watcher) watchNewDir(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	found, err := w.addRecursive(path)
+	if err != nil {
+		slog.Error("Failed to watch new directory", "dir", path, "error", err)
+	}
+	if found {
+		slog.Info("Directory created", "dir", path)
+	}
+	return found
+}
+
+// forgetDir marks a removed directory and its subdirectories as unwatched.
+func (w *Watcher) forgetDir(path string) {
+	for dir := range w.dirs {
+		if dir == path || strings.HasPrefix(dir, path+string(filepath.Separator)) {
+			delete(w.dirs, dir)
+		}
+	}
 }
 
 // Start begins watching for file changes
 func (w *Watcher) Start(ctx context.Context) {
 	var debounceTimer *time.Timer
+	reload := func() {
+		// Debounce: reset timer on each event
+		if debounceTimer != nil {
+			debounceTimer.Stop()
+		}
+		debounceTimer = time.AfterFunc(w.debounce, func() {
+			slog.Info("Reloading", "target", w.label)
+			if err := w.reloadFunc(); err != nil {
+				slog.Error("Reload failed", "target", w.label, "error", err)
+			}
+		})
+	}
 
 	for {
 		select {
@@ -73,29 +164,31 @@ func (w *Watcher) Start(ctx context.Context) {
 			if !ok {
 				return
 			}
-			// Only care about .proto files
-			if !strings.HasSuffix(strings.ToLower(event.Name), ".proto") {
+			// New subdirectories are watched too, so packages added
+			// after startup are picked up
+			if w.recursive && event.Op&fsnotify.Create != 0 && w.watchNewDir(event.Name) {
+				reload()
+				continue
+			}
+			// fsnotify drops the watch of a removed directory, so it is
+			// added again if the directory comes back
+			if event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				w.forgetDir(event.Name)
+			}
+			// Only care about watched files
+			if !w.match(event.Name) {
 				continue
 			}
 			// Watch for create, write, remove, rename operations
 			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0 {
-				log.Printf("Proto file changed: %s (%s)", event.Name, event.Op)
-
-				// Debounce: reset timer on each event
-				if debounceTimer != nil {
-					debounceTimer.Stop()
-				}
-				debounceTimer = time.AfterFunc(w.debounce, func() {
-					log.Println("Reloading proto files...")
-					w.reloadFunc()
-					log.Println("Proto files reloaded successfully")
-				})
+				slog.Info("File changed", "file", event.Name, "op", event.Op.String())
+				reload()
 			}
 		case err, ok := <-w.watcher.Errors:
 			if !ok {
 				return
 			}
-			log.Printf("Watcher error: %v", err)
+			slog.Error("Watcher error", "error", err)
 		}
 	}
 }
@@ -0,0 +1,139 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcherNewSubdirectory(t *testing.T) {
+	root := t.TempDir()
+	reloaded := make(chan struct{}, 1)
+	w, err := New(root, func() error {
+		select {
+		case reloaded <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+	w.debounce = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Start(ctx)
+
+	// A package folder created after Start, with a file in a nested directory
+	dir := filepath.Join(root, "billing", "v1")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "billing.proto"), []byte(`syntax = "proto3";`), 0o644); err != nil {
+		t.Fatalf("Failed to write proto file: %v", err)
+	}
+
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a reload after adding a proto file in a new directory")
+	}
+
+	// Later changes in the new directory are seen too
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-reloaded:
+	default:
+	}
+	if err := os.WriteFile(filepath.Join(dir, "billing.proto"), []byte(`syntax = "proto3"; package billing.v1;`), 0o644); err != nil {
+		t.Fatalf("Failed to write proto file: %v", err)
+	}
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a reload after editing a proto file in a new directory")
+	}
+}
+
+func TestWatcherAddRecursiveSkipsWatched(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "a", "b"), 0o755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "a", "notes.txt"), nil, 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	w, err := New(root, func() error { return nil })
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	if len(w.dirs) != 3 {
+		t.Errorf("Expected 3 watched directories, got %d", len(w.dirs))
+	}
+	found, err := w.addRecursive(root)
+	if err != nil {
+		t.Fatalf("addRecursive() error = %v", err)
+	}
+	if found {
+		t.Error("Expected no proto files found")
+	}
+	if len(w.dirs) != 3 || len(w.watcher.WatchList()) != 3 {
+		t.Errorf("Expected directories to be watched once, got %d", len(w.watcher.WatchList()))
+	}
+
+	// Files are not watched as directories
+	if w.watchNewDir(filepath.Join(root, "a", "notes.txt")) {
+		t.Error("Expected a file not to be watched as a directory")
+	}
+	if len(w.dirs) != 3 {
+		t.Errorf("Expected 3 watched directories after a file event, got %d", len(w.dirs))
+	}
+}
+
+func TestWatcherReloadError(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "broken.proto")
+	calls := make(chan int, 4)
+	count := 0
+	w, err := New(root, func() error {
+		count++
+		calls <- count
+		if count == 1 {
+			return errors.New("syntax error")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+	w.debounce = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Start(ctx)
+
+	// A failed reload is logged and the watcher keeps running, so the next
+	// change is reloaded again
+	for want := 1; want <= 2; want++ {
+		if err := os.WriteFile(path, []byte(`syntax = "proto3";`), 0o644); err != nil {
+			t.Fatalf("Failed to write proto file: %v", err)
+		}
+		select {
+		case got := <-calls:
+			if got != want {
+				t.Errorf("Expected reload %d, got %d", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected reload %d", want)
+		}
+		time.Sleep(150 * time.Millisecond)
+	}
+}